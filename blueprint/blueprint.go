@@ -27,6 +27,15 @@ type Blueprint struct {
 	// TargetRowCount is the number of rows that each table of each branch is targeting. The first map is the branch name,
 	// while the second map is the table name.
 	TargetRowCount map[string]map[string]uint64
+	// TableRowBuckets is the row bucket that each table uses, from Amounts.Row_Buckets. The map is the table name, and
+	// a bucket of -1 means that the table uses Amounts.Rows. Each table keeps its bucket across all branches.
+	TableRowBuckets map[string]int
+	// EmptyTables are the tables that target zero rows on every branch. The map is the table name.
+	EmptyTables map[string]struct{}
+	// BranchWrites is the number of writes that each table of each branch must still receive, regardless of its row
+	// count, so that every branch diverges from the branch that it was created from. The first map is the branch name,
+	// while the second map is the table name. The main branch does not have any.
+	BranchWrites map[string]map[string]uint64
 	// SQLStatementsExecuted is the total number of SQL statements executed.
 	SQLStatementsExecuted uint64
 }
@@ -0,0 +1,136 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// Ancestors handles commit ancestry testing.
+type Ancestors struct{}
+
+var _ Command = (*Ancestors)(nil)
+var _ CommandMetadata = (*Ancestors)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Ancestors{})
+}
+
+// Name implements the interface Command.
+func (a *Ancestors) Name() string {
+	return "ancestors"
+}
+
+// Description implements the interface Command.
+func (a *Ancestors) Description() string {
+	return "Tests dolt's commit ancestry system table."
+}
+
+// Category implements the interface CommandMetadata.
+func (a *Ancestors) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (a *Ancestors) LongDescription() string {
+	return `This command verifies that the "dolt_commit_ancestors" system table matches the commit graph that the fuzzer
+built. Unlike the log, which follows the first parent of a single branch, the table holds every edge of the graph at
+once. Every commit reachable from any branch must be listed with the same parents, in the same order, and the table must
+not list any commits that the fuzzer did not create.`
+}
+
+// ParseArgs implements the interface Command.
+func (a *Ancestors) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's commit ancestry system table",
+		LongDesc:  a.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (a *Ancestors) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (a *Ancestors) Register(hooks *run.Hooks) {
+	hooks.RepositoryFinished(a.BeginAncestors)
+}
+
+// BeginAncestors starts the ancestry process.
+func (a *Ancestors) BeginAncestors(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Ancestors Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	c.QueueAction(a.Run)
+	return nil
+}
+
+// Run verifies the entire commit graph at once, as the ancestry table is shared by every branch.
+func (a *Ancestors) Run(c *run.Cycle) error {
+	doltAncestors, err := run.GetDoltCommitAncestors(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return compareCommitAncestors(run.CommitAncestors(c), doltAncestors)
+}
+
+// compareCommitAncestors returns an error describing the first difference between the expected and actual parents of
+// each commit. Commits are checked in sorted order, so that the same difference is always reported first.
+func compareCommitAncestors(expected map[string][]string, actual map[string][]string) error {
+	hashes := make([]string, 0, len(expected))
+	for hash := range expected {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		actualParents, ok := actual[hash]
+		if !ok {
+			return errors.New(fmt.Sprintf("dolt_commit_ancestors is missing commit %s, which has parents [%s]",
+				hash, strings.Join(expected[hash], ", ")))
+		}
+		if strings.Join(actualParents, ",") != strings.Join(expected[hash], ",") {
+			return errors.New(fmt.Sprintf("dolt_commit_ancestors has parents [%s] for commit %s but expected [%s]",
+				strings.Join(actualParents, ", "), hash, strings.Join(expected[hash], ", ")))
+		}
+	}
+	hashes = hashes[:0]
+	for hash := range actual {
+		if _, ok := expected[hash]; !ok {
+			hashes = append(hashes, hash)
+		}
+	}
+	if len(hashes) > 0 {
+		sort.Strings(hashes)
+		return errors.New(fmt.Sprintf("dolt_commit_ancestors contains commit %s with parents [%s], which is not in the commit graph",
+			hashes[0], strings.Join(actual[hashes[0]], ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareCommitAncestors(t *testing.T) {
+	expected := map[string][]string{
+		"root":  {},
+		"a":     {"root"},
+		"b":     {"root"},
+		"merge": {"a", "b"},
+	}
+	require.NoError(t, compareCommitAncestors(expected, map[string][]string{
+		"root":  {},
+		"a":     {"root"},
+		"b":     {"root"},
+		"merge": {"a", "b"},
+	}))
+	// Merge parents are ordered
+	require.Error(t, compareCommitAncestors(expected, map[string][]string{
+		"root":  {},
+		"a":     {"root"},
+		"b":     {"root"},
+		"merge": {"b", "a"},
+	}))
+	require.Error(t, compareCommitAncestors(expected, map[string][]string{
+		"root": {},
+		"a":    {"root"},
+		"b":    {"root"},
+	}))
+	require.Error(t, compareCommitAncestors(expected, map[string][]string{
+		"root":  {},
+		"a":     {"root"},
+		"b":     {"root"},
+		"merge": {"a", "b"},
+		"extra": {"merge"},
+	}))
+}
@@ -0,0 +1,156 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const (
+	autoTimestampStatementsParam = "statements"
+	autoTimestampToleranceParam  = "tolerance"
+)
+
+// AutoTimestamp handles testing columns that are declared with ON UPDATE CURRENT_TIMESTAMP.
+type AutoTimestamp struct {
+	statementCount    uint64
+	tolerance         int64
+	remainingBranches []string
+}
+
+var _ Command = (*AutoTimestamp)(nil)
+var _ CommandMetadata = (*AutoTimestamp)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&AutoTimestamp{})
+}
+
+// Name implements the interface Command.
+func (at *AutoTimestamp) Name() string {
+	return "auto-timestamp"
+}
+
+// Description implements the interface Command.
+func (at *AutoTimestamp) Description() string {
+	return "Tests columns that are declared with ON UPDATE CURRENT_TIMESTAMP."
+}
+
+// Category implements the interface CommandMetadata.
+func (at *AutoTimestamp) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (at *AutoTimestamp) LongDescription() string {
+	return `This command verifies TIMESTAMP and DATETIME columns that are declared with DEFAULT CURRENT_TIMESTAMP and
+ON UPDATE CURRENT_TIMESTAMP. On every branch, a table with such a column is created, and random statements are run
+against it. Inserting a row without a timestamp, or updating any of a row's other columns, must set the timestamp to the
+current time, which must fall between the times measured immediately before and after the statement, within the
+tolerance. Assigning a timestamp explicitly must store that exact timestamp, and an UPDATE that leaves a row unchanged
+must not change its timestamp. The table is validated after every statement, and is dropped once the branch is done.`
+}
+
+// ParseArgs implements the interface Command.
+func (at *AutoTimestamp) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests columns that are declared with ON UPDATE CURRENT_TIMESTAMP",
+		LongDesc:  at.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(autoTimestampStatementsParam, "", "count", "The number of statements run on each branch. Defaults to 50.")
+	ap.SupportsUint(autoTimestampToleranceParam, "", "seconds",
+		"The number of seconds that an automatic timestamp may fall outside of its expected window. Defaults to 2.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	at.statementCount = 50
+	if statements, ok := apr.GetUint(autoTimestampStatementsParam); ok {
+		at.statementCount = statements
+	}
+	at.tolerance = 2
+	if tolerance, ok := apr.GetUint(autoTimestampToleranceParam); ok {
+		at.tolerance = int64(tolerance)
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (at *AutoTimestamp) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (at *AutoTimestamp) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(at.Reset)
+	hooks.RepositoryFinished(at.BeginAutoTimestamp)
+}
+
+// Reset resets the state of AutoTimestamp.
+func (at *AutoTimestamp) Reset(c *run.Cycle) error {
+	at.remainingBranches = nil
+	return nil
+}
+
+// BeginAutoTimestamp starts the auto timestamp testing process.
+func (at *AutoTimestamp) BeginAutoTimestamp(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Auto Timestamp Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	at.remainingBranches = c.GetBranchNames()
+	c.QueueAction(at.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and runs statements against a new auto timestamp table on it.
+func (at *AutoTimestamp) Run(c *run.Cycle) error {
+	if len(at.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := at.remainingBranches[0]
+	at.remainingBranches = at.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	table, err := run.NewAutoTimestampTable(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = table.Create(c); err != nil {
+		return errors.Wrap(err)
+	}
+	for i := uint64(0); i < at.statementCount; i++ {
+		if err = table.RunStatement(c); err != nil {
+			return errors.Wrap(err)
+		}
+		if err = table.Validate(c, at.tolerance); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	// The table is not part of the internal data, so it must not be left behind for the remaining validation
+	if err = table.Drop(c); err != nil {
+		return errors.Wrap(err)
+	}
+	c.QueueAction(at.Run)
+	return nil
+}
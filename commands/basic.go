@@ -15,16 +15,38 @@
 package commands
 
 import (
+	"fmt"
+
+	"github.com/dolthub/fuzzer/errors"
 	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/ranges"
 	"github.com/dolthub/fuzzer/run"
 	"github.com/dolthub/fuzzer/utils/argparser"
 	"github.com/dolthub/fuzzer/utils/cli"
 )
 
+const (
+	profileParam = "profile"
+)
+
+// basicProfiles are the built-in profiles that may be selected for Basic. Each profile overrides the loaded config.
+var basicProfiles = map[string]func(config *parameters.Base){
+	// fast uses a single branch with a few small tables, which is enough to reproduce most bugs while running quickly
+	"fast": func(config *parameters.Base) {
+		config.Amounts.Branches = ranges.NewInt([]int64{1, 1})
+		config.Amounts.Tables = ranges.NewInt([]int64{1, 2})
+		config.Amounts.Rows = ranges.NewInt([]int64{10, 50})
+		config.Amounts.RowBuckets = nil
+	},
+}
+
 // Basic handles basic repository validation.
-type Basic struct{}
+type Basic struct {
+	profile string
+}
 
 var _ Command = (*Basic)(nil)
+var _ CommandMetadata = (*Basic)(nil)
 
 // init adds the command to the map.
 func init() {
@@ -44,21 +66,42 @@ func (b *Basic) Description() string {
 	return "Performs basic validation of dolt repositories."
 }
 
+// Category implements the interface CommandMetadata.
+func (b *Basic) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (b *Basic) LongDescription() string {
+	return `This command performs a basic validation for created dolt repositories by internally comparing them against a generated
+valid repository. Many other commands will perform this validation, although they may change variables to ensure that
+the repository conforms to their required parameters (such as merge enforcing at least two branches). Running this
+command validates repositories that are constrained only by the configuration file as loaded, unless a profile is given.
+The "fast" profile uses a single branch with one or two small tables, which makes for a quick smoke test.`
+}
+
 // ParseArgs implements the interface Command.
 func (b *Basic) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
 	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
 		ShortDesc: "Validates created dolt repositories",
-		LongDesc: `This command performs a basic validation for created dolt repositories by internally comparing them against a generated
-valid repository. Many other commands will perform this validation, although they may change variables to ensure that
-the repository conforms to their required parameters (such as merge enforcing at least two branches). Running this
-command validates repositories that are constrained only by the configuration file as loaded.`,
-		Synopsis: nil,
+		LongDesc:  b.LongDescription(),
+		Synopsis:  nil,
 	}, ap))
-	_ = cli.ParseArgsOrDie(ap, args, help)
+	ap.SupportsString(profileParam, "", "name", `A built-in profile that overrides the loaded config. Only "fast" is available.`)
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	if profile, ok := apr.GetValue(profileParam); ok {
+		if _, ok = basicProfiles[profile]; !ok {
+			return errors.New(fmt.Sprintf(`"%s" is not a valid profile`, profile))
+		}
+		b.profile = profile
+	}
 	return nil
 }
 
 // AdjustConfig implements the interface Command.
 func (b *Basic) AdjustConfig(config *parameters.Base) error {
+	if b.profile != "" {
+		basicProfiles[b.profile](config)
+	}
 	return nil
 }
@@ -0,0 +1,188 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// Blame handles blame testing.
+type Blame struct {
+	remainingBranches []string
+}
+
+var _ Command = (*Blame)(nil)
+var _ CommandMetadata = (*Blame)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Blame{})
+}
+
+// Name implements the interface Command.
+func (b *Blame) Name() string {
+	return "blame"
+}
+
+// Description implements the interface Command.
+func (b *Blame) Description() string {
+	return "Tests dolt's blame functionality."
+}
+
+// Category implements the interface CommandMetadata.
+func (b *Blame) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (b *Blame) LongDescription() string {
+	return `This command verifies that the "dolt_blame_<table>" system tables attribute each row to the commit that last
+wrote it. Every table on every branch is checked, with the expected commit determined by walking backwards through the
+branch's history until a commit that changed the row is found.`
+}
+
+// ParseArgs implements the interface Command.
+func (b *Blame) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's blame functionality",
+		LongDesc:  b.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (b *Blame) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (b *Blame) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(b.Reset)
+	hooks.RepositoryFinished(b.BeginBlame)
+}
+
+// Reset resets the state of Blame.
+func (b *Blame) Reset(c *run.Cycle) error {
+	b.remainingBranches = nil
+	return nil
+}
+
+// BeginBlame starts the blame process.
+func (b *Blame) BeginBlame(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Blame Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	b.remainingBranches = c.GetBranchNames()
+	c.QueueAction(b.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch and verifies the blame of each of its tables.
+func (b *Blame) Run(c *run.Cycle) error {
+	if len(b.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := b.remainingBranches[0]
+	b.remainingBranches = b.remainingBranches[1:]
+
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf(`Checking blame on "%s": %s`, branchName, time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// Blame reads from the current branch, and switching branches commits the working set, so HEAD is up to date
+	err = c.SwitchCurrentBranch(branchName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if _, err = c.GetCurrentBranch().Commit(c, false); err != nil {
+		return errors.Wrap(err)
+	}
+	head := c.GetCurrentBranch().GetWorkingSet().Parents[0]
+	for _, table := range head.Tables {
+		// Blame requires a primary key to identify each row
+		if len(table.PKCols) == 0 {
+			continue
+		}
+		err = b.Verify(c, branchName, head, table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	c.QueueAction(b.Run)
+	return nil
+}
+
+// Verify checks that Dolt's blame of the given table matches the expected commit for every row. Dolt's blame is read
+// directly from the server, so this does nothing when validation is skipped.
+func (b *Blame) Verify(c *run.Cycle, branchName string, head *run.Commit, table *run.Table) error {
+	if c.Planner.Base.Arguments.SkipValidation {
+		return nil
+	}
+	expected, err := run.BlameTable(head, table.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	cursor, err := table.GetDoltBlameCursor(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer cursor.Close()
+
+	for {
+		row, ok, err := cursor.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if !ok {
+			break
+		}
+		keyRow := run.Row{Values: row.Key(), PkColsLen: row.PkColsLen}
+		commitValue, ok := row.Value()[0].(types.VarcharValue)
+		if !ok {
+			return errors.New(fmt.Sprintf("Blame on table `%s` returned an invalid commit: %s",
+				table.Name, row.Value()[0].MySQLString()))
+		}
+		doltCommit := string(commitValue.StringValue)
+		key := keyRow.Hash()
+		expectedCommit, ok := expected[key]
+		if !ok {
+			return errors.New(fmt.Sprintf("Blame on table `%s` on branch `%s` returned an unexpected row: %s",
+				table.Name, branchName, keyRow.MySQLString()))
+		}
+		if expectedCommit != doltCommit {
+			return errors.New(fmt.Sprintf("Blame on table `%s` on branch `%s` attributed row %s to commit %s but expected %s",
+				table.Name, branchName, keyRow.MySQLString(), doltCommit, expectedCommit))
+		}
+		delete(expected, key)
+	}
+	if len(expected) > 0 {
+		return errors.New(fmt.Sprintf("Blame on table `%s` on branch `%s` is missing %d rows",
+			table.Name, branchName, len(expected)))
+	}
+	return nil
+}
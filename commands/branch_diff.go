@@ -0,0 +1,222 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// BranchDiff handles testing the diff between two branch heads, which is read from the `dolt_commit_diff_<table>`
+// system table using the branch names rather than commit hashes.
+type BranchDiff struct {
+	combinations []branchDiffCombination
+}
+
+// branchDiffCombination is a single diff between two branches. The "from" side is either the head of the "from" branch
+// for a two-dot diff, or the merge base of both branches for a three-dot diff. The commits are used to compute the
+// expected diff.
+type branchDiffCombination struct {
+	fromBranch string
+	toBranch   string
+	threeDot   bool
+	from       *run.Commit
+	to         *run.Commit
+}
+
+var _ Command = (*BranchDiff)(nil)
+var _ CommandMetadata = (*BranchDiff)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&BranchDiff{})
+}
+
+// Name implements the interface Command.
+func (bd *BranchDiff) Name() string {
+	return "branch-diff"
+}
+
+// Description implements the interface Command.
+func (bd *BranchDiff) Description() string {
+	return "Tests the diff system tables between two branch heads."
+}
+
+// Category implements the interface CommandMetadata.
+func (bd *BranchDiff) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (bd *BranchDiff) LongDescription() string {
+	return `This command verifies the "dolt_commit_diff_<table>" system table between every pair of branches, where
+both commits are given by branch name, so that Dolt must resolve each branch to its head. A two-dot diff compares the
+heads of both branches directly using HASHOF, while a three-dot diff compares the merge base of both branches against the
+second branch, using DOLT_MERGE_BASE. The merge base that Dolt resolves must match the one found in the internal data,
+and the rows of both diffs must match the diff computed from the internal data.`
+}
+
+// ParseArgs implements the interface Command.
+func (bd *BranchDiff) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests the diff system tables between two branch heads",
+		LongDesc:  bd.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (bd *BranchDiff) AdjustConfig(config *parameters.Base) error {
+	// Diffs are read using the column names of the newer branch, which would not exist on older commits after a rename
+	config.SchemaChanges = parameters.SchemaChanges{}
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (bd *BranchDiff) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(bd.Reset)
+	hooks.CycleStarted(bd.VerifyCycle)
+	hooks.RepositoryFinished(bd.BeginBranchDiff)
+}
+
+// Reset resets the state of BranchDiff.
+func (bd *BranchDiff) Reset(c *run.Cycle) error {
+	bd.combinations = nil
+	return nil
+}
+
+// VerifyCycle verifies that the run.Cycle will support branch diff testing, and will modify the run.Cycle to guarantee
+// that there are at least two branches to diff.
+func (bd *BranchDiff) VerifyCycle(c *run.Cycle) error {
+	if c.Blueprint.BranchCount < 2 {
+		c.Blueprint.BranchCount = 2
+	}
+	return nil
+}
+
+// BeginBranchDiff gathers every pair of branches and starts the branch diff process.
+func (bd *BranchDiff) BeginBranchDiff(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Branch Diff Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	branches := c.GetBranchNames()
+	heads := make(map[string]*run.Commit)
+	for _, branchName := range branches {
+		branch := c.GetBranch(branchName)
+		if branch == nil {
+			return errors.New(fmt.Sprintf("unable to get branch: %s", branchName))
+		}
+		// The working set is guaranteed to be empty, so its parent is the head of the branch
+		heads[branchName] = branch.GetWorkingSet().Parents[0]
+	}
+	for _, fromBranch := range branches {
+		for _, toBranch := range branches {
+			if fromBranch == toBranch {
+				continue
+			}
+			base, err := run.MergeBase(heads[fromBranch], heads[toBranch])
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			bd.combinations = append(bd.combinations,
+				branchDiffCombination{
+					fromBranch: fromBranch,
+					toBranch:   toBranch,
+					from:       heads[fromBranch],
+					to:         heads[toBranch],
+				},
+				branchDiffCombination{
+					fromBranch: fromBranch,
+					toBranch:   toBranch,
+					threeDot:   true,
+					from:       base,
+					to:         heads[toBranch],
+				})
+		}
+	}
+	c.QueueAction(bd.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch diff combination and processes it.
+func (bd *BranchDiff) Run(c *run.Cycle) error {
+	if len(bd.combinations) == 0 { // We've tested all branch diff combinations
+		return nil
+	}
+	combination := bd.combinations[0]
+	bd.combinations = bd.combinations[1:]
+
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf(`Diffing branches "%s": %s`, combination.Revisions(), time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// The system table takes its schema from the current branch, so the "to" branch must be checked out
+	if err = c.SwitchCurrentBranch(combination.toBranch); err != nil {
+		return errors.Wrap(err)
+	}
+	fromExpr := fmt.Sprintf("HASHOF('%s')", combination.fromBranch)
+	if combination.threeDot {
+		mergeBase, err := run.GetDoltMergeBase(c, combination.fromBranch, combination.toBranch)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if mergeBase != combination.from.Hash {
+			return errors.New(fmt.Sprintf("Merge base of \"%s\" and \"%s\" does not match:\nExpected: %s\nDolt: %s",
+				combination.fromBranch, combination.toBranch, combination.from.Hash, mergeBase))
+		}
+		fromExpr = fmt.Sprintf("DOLT_MERGE_BASE('%s', '%s')", combination.fromBranch, combination.toBranch)
+	}
+	toExpr := fmt.Sprintf("HASHOF('%s')", combination.toBranch)
+
+	tableDiffs, err := run.DiffCommits(combination.from, combination.to)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, tableDiff := range tableDiffs {
+		// The system table only exists for tables that are on the checked out branch
+		table := combination.to.GetTable(tableDiff.TableName)
+		if table == nil {
+			continue
+		}
+		cursor, err := table.GetDoltCommitDiffCursor(c, fromExpr, toExpr)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = verifyDiffCursor(combination.Revisions(), table, cursor, tableDiff.Rows); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	c.QueueAction(bd.Run)
+	return nil
+}
+
+// Revisions returns the diff written in Dolt's revision syntax, such as "a..b" or "a...b".
+func (bdc branchDiffCombination) Revisions() string {
+	if bdc.threeDot {
+		return fmt.Sprintf("%s...%s", bdc.fromBranch, bdc.toBranch)
+	}
+	return fmt.Sprintf("%s..%s", bdc.fromBranch, bdc.toBranch)
+}
@@ -0,0 +1,216 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const (
+	branchStressBranchesParam = "new-branches"
+	branchStressSwitchesParam = "switches"
+)
+
+// BranchStress handles testing of Dolt's refs by rapidly creating and switching between many branches.
+type BranchStress struct {
+	branchCount       uint64
+	switchCount       uint64
+	remainingBranches uint64
+	remainingSwitches uint64
+	sourceBranch      string
+	expectedHash      string
+	createdBranches   []string
+}
+
+var _ Command = (*BranchStress)(nil)
+var _ CommandMetadata = (*BranchStress)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&BranchStress{})
+}
+
+// Name implements the interface Command.
+func (b *BranchStress) Name() string {
+	return "branch-stress"
+}
+
+// Description implements the interface Command.
+func (b *BranchStress) Description() string {
+	return "Tests dolt's refs by creating and switching between many branches."
+}
+
+// Category implements the interface CommandMetadata.
+func (b *BranchStress) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (b *BranchStress) LongDescription() string {
+	return `This command creates many branches off of a single commit in rapid succession once the repository has been
+generated, and then switches between them at random. Every new branch must point to the commit that it was created from,
+and after each switch, every new branch must still point to that commit, while the data on the branch must match the
+internal data. This targets the performance and correctness of dolt's refs when there are many of them, independently of
+the number of rows.`
+}
+
+// ParseArgs implements the interface Command.
+func (b *BranchStress) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's refs by creating and switching between many branches",
+		LongDesc:  b.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(branchStressBranchesParam, "", "count", "The number of branches to create. Defaults to 1000.")
+	ap.SupportsUint(branchStressSwitchesParam, "", "count", "The number of times to switch to a random new branch. Defaults to 100.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	b.branchCount = 1000
+	if branches, ok := apr.GetUint(branchStressBranchesParam); ok {
+		b.branchCount = branches
+	}
+	b.switchCount = 100
+	if switches, ok := apr.GetUint(branchStressSwitchesParam); ok {
+		b.switchCount = switches
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (b *BranchStress) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (b *BranchStress) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(b.Reset)
+	hooks.RepositoryFinished(b.BeginBranchStress)
+}
+
+// Reset resets the state of BranchStress.
+func (b *BranchStress) Reset(c *run.Cycle) error {
+	b.remainingBranches = 0
+	b.remainingSwitches = 0
+	b.sourceBranch = ""
+	b.expectedHash = ""
+	b.createdBranches = nil
+	return nil
+}
+
+// BeginBranchStress starts the branch stress process. The current branch is committed, so that every new branch is
+// created from the same commit.
+func (b *BranchStress) BeginBranchStress(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Branch Stress: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	sourceBranch := c.GetCurrentBranch()
+	workingSet, err := sourceBranch.Commit(c, false)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	b.sourceBranch = sourceBranch.Name
+	b.expectedHash = workingSet.Parents[0].Hash
+	b.remainingBranches = b.branchCount
+	b.remainingSwitches = b.switchCount
+	c.QueueAction(b.CreateBranch)
+	return nil
+}
+
+// CreateBranch creates a single branch from the source branch. Branches are created one at a time, as each new branch
+// queues a hook.
+func (b *BranchStress) CreateBranch(c *run.Cycle) error {
+	if b.remainingBranches == 0 {
+		err := c.Logger.WriteLine(run.LogType_INFO,
+			fmt.Sprintf("Created %d branches: %s", len(b.createdBranches), time.Now().Format("2006-01-02 15:04:05")))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = b.VerifyHashes(c); err != nil {
+			return errors.Wrap(err)
+		}
+		c.QueueAction(b.Switch)
+		return nil
+	}
+	b.remainingBranches--
+	branch, err := c.GetBranch(b.sourceBranch).NewBranch(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	b.createdBranches = append(b.createdBranches, branch.Name)
+	c.QueueAction(b.CreateBranch)
+	return nil
+}
+
+// Switch switches to a random new branch, and verifies both the branch's data and the commits of every new branch.
+func (b *BranchStress) Switch(c *run.Cycle) error {
+	if b.remainingSwitches == 0 || len(b.createdBranches) == 0 {
+		return nil
+	}
+	b.remainingSwitches--
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	branchName := b.createdBranches[roll%uint64(len(b.createdBranches))]
+	err = c.SwitchCurrentBranch(branchName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	currentBranchName, err := c.CliQuery("branch", "--show-current")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if currentBranchName != branchName {
+		return errors.New(fmt.Sprintf("Switched to branch `%s` but dolt is on branch `%s`", branchName, currentBranchName))
+	}
+	// Validation has already been run by the switch when this option is enabled
+	if !c.Planner.Base.Options.ValidateOnSwitch {
+		if err = c.ValidateCurrentBranch(); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	if err = b.VerifyHashes(c); err != nil {
+		return errors.Wrap(err)
+	}
+	c.QueueAction(b.Switch)
+	return nil
+}
+
+// VerifyHashes checks that every new branch exists and points to the commit that it was created from.
+func (b *BranchStress) VerifyHashes(c *run.Cycle) error {
+	hashes, err := run.GetDoltBranchHashes(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, branchName := range b.createdBranches {
+		hash, ok := hashes[branchName]
+		if !ok {
+			return errors.New(fmt.Sprintf("Branch `%s` does not exist", branchName))
+		}
+		if hash != b.expectedHash {
+			return errors.New(fmt.Sprintf("Branch `%s` points to commit %s but expected %s", branchName, hash, b.expectedHash))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// Campaign handles running a weighted mix of commands, choosing a single command for each cycle.
+type Campaign struct {
+	commands []*campaignCommand
+	cycle    *run.Cycle
+	chosen   *campaignCommand
+}
+
+// campaignCommand is a command that may be chosen by a campaign. Each command registers with its own hooks, which are
+// only run for the cycles that the command is chosen for.
+type campaignCommand struct {
+	cmd    Command
+	weight int64
+	hooks  *run.Hooks
+}
+
+var _ Command = (*Campaign)(nil)
+var _ CommandMetadata = (*Campaign)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Campaign{})
+}
+
+// Name implements the interface Command.
+func (cp *Campaign) Name() string {
+	return "campaign"
+}
+
+// Description implements the interface Command.
+func (cp *Campaign) Description() string {
+	return "Runs a weighted mix of commands, choosing one for each cycle."
+}
+
+// Category implements the interface CommandMetadata.
+func (cp *Campaign) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (cp *Campaign) LongDescription() string {
+	return `This command allows a single run to exercise many commands. For every cycle, one of the commands listed in the
+"Campaign" section of the config file is chosen, with the chance of each command being its weight divided by the sum of
+all weights. The chosen command then runs exactly as it would on its own for that cycle. Each command uses its default
+arguments, so commands that require their own arguments cannot be listed. The config adjustments of every listed command
+are applied before any cycles begin, so that the config suits whichever command is chosen.`
+}
+
+// ParseArgs implements the interface Command.
+func (cp *Campaign) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Runs a weighted mix of commands, choosing one for each cycle",
+		LongDesc:  cp.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command. The commands are only known once the config has been loaded, so this
+// is where each listed command parses its default arguments, adjusts the config, and registers its hooks.
+func (cp *Campaign) AdjustConfig(config *parameters.Base) error {
+	names := make([]string, 0, len(config.Campaign))
+	for name := range config.Campaign {
+		names = append(names, name)
+	}
+	// The commands are sorted so that the same roll always chooses the same command, such as when using a seed file
+	sort.Strings(names)
+	cp.commands = nil
+	totalWeight := int64(0)
+	for _, name := range names {
+		weight := config.Campaign[name]
+		if weight == 0 {
+			continue
+		}
+		cmd, ok := Commands[name]
+		if !ok || cmd == Command(cp) {
+			return errors.New(fmt.Sprintf("Campaign.%s is not a command that may be used by a campaign", name))
+		}
+		err := cmd.ParseArgs("fuzzer "+cmd.Name(), argparser.NewArgParser(), []string{cmd.Name()})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Campaign.%s cannot be used by a campaign: %s", name, err.Error()))
+		}
+		if err = cmd.AdjustConfig(config); err != nil {
+			return errors.Wrap(err)
+		}
+		hooks := &run.Hooks{}
+		cmd.Register(hooks)
+		cp.commands = append(cp.commands, &campaignCommand{cmd: cmd, weight: weight, hooks: hooks})
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return errors.New("the campaign command requires at least one command with a non-zero weight in the Campaign section of the config")
+	}
+	return nil
+}
+
+// Register implements the HookRegistrant interface. Every hook is forwarded to the command chosen for the cycle.
+func (cp *Campaign) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(func(c *run.Cycle) error {
+		return cp.forward(run.Hook{Type: run.HookType_CycleInitialized, Cycle: c})
+	})
+	hooks.CycleStarted(func(c *run.Cycle) error {
+		return cp.forward(run.Hook{Type: run.HookType_CycleStarted, Cycle: c})
+	})
+	hooks.CycleEnded(func(c *run.Cycle) error {
+		// A cycle may end before any other hooks have run, in which case no command was chosen
+		if cp.cycle != c {
+			return nil
+		}
+		err := cp.forward(run.Hook{Type: run.HookType_CycleEnded, Cycle: c})
+		cp.cycle = nil
+		cp.chosen = nil
+		return err
+	})
+	hooks.RepositoryFinished(func(c *run.Cycle) error {
+		return cp.forward(run.Hook{Type: run.HookType_RepositoryFinished, Cycle: c})
+	})
+	hooks.BranchCreated(func(c *run.Cycle, branch *run.Branch) error {
+		return cp.forward(run.Hook{Type: run.HookType_BranchCreated, Cycle: c, Param1: branch})
+	})
+	hooks.BranchSwitched(func(c *run.Cycle, prevBranch *run.Branch, branch *run.Branch) error {
+		return cp.forward(run.Hook{Type: run.HookType_BranchSwitched, Cycle: c, Param1: prevBranch, Param2: branch})
+	})
+	hooks.CommitCreated(func(c *run.Cycle, commit *run.Commit) error {
+		return cp.forward(run.Hook{Type: run.HookType_CommitCreated, Cycle: c, Param1: commit})
+	})
+	hooks.CommitPreCreation(func(c *run.Cycle, branch *run.Branch, workingSet *run.Commit) error {
+		return cp.forward(run.Hook{Type: run.HookType_CommitPreCreation, Cycle: c, Param1: branch, Param2: workingSet})
+	})
+	hooks.CommitPostCreation(func(c *run.Cycle, branch *run.Branch, commit *run.Commit) error {
+		return cp.forward(run.Hook{Type: run.HookType_CommitPostCreation, Cycle: c, Param1: branch, Param2: commit})
+	})
+	hooks.TableCreated(func(c *run.Cycle, table *run.Table) error {
+		return cp.forward(run.Hook{Type: run.HookType_TableCreated, Cycle: c, Param1: table})
+	})
+	hooks.IndexCreated(func(c *run.Cycle, table *run.Table, index *run.Index) error {
+		return cp.forward(run.Hook{Type: run.HookType_IndexCreated, Cycle: c, Param1: table, Param2: index})
+	})
+	hooks.ForeignKeyCreated(func(c *run.Cycle, commit *run.Commit, foreignKey *run.ForeignKey) error {
+		return cp.forward(run.Hook{Type: run.HookType_ForeignKeyCreated, Cycle: c, Param1: commit, Param2: foreignKey})
+	})
+	hooks.SQLStatementPreExecution(func(c *run.Cycle, statement string) error {
+		return cp.forward(run.Hook{Type: run.HookType_SqlStatementPreExecution, Cycle: c, Param1: statement})
+	})
+	hooks.SQLStatementPostExecution(func(c *run.Cycle, statement string) error {
+		return cp.forward(run.Hook{Type: run.HookType_SqlStatementPostExecution, Cycle: c, Param1: statement})
+	})
+}
+
+// forward runs the given hook on the command chosen for the hook's cycle. A command is chosen by the first hook of each
+// cycle.
+func (cp *Campaign) forward(hook run.Hook) error {
+	if cp.cycle != hook.Cycle {
+		chosen, err := cp.choose()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		cp.cycle = hook.Cycle
+		cp.chosen = chosen
+		err = hook.Cycle.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("Campaign chose command: %s", chosen.cmd.Name()))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return cp.chosen.hooks.RunHook(hook)
+}
+
+// choose returns a random command, using the weight of each command.
+func (cp *Campaign) choose() (*campaignCommand, error) {
+	totalWeight := uint64(0)
+	for _, cc := range cp.commands {
+		totalWeight += uint64(cc.weight)
+	}
+	if totalWeight == 0 {
+		return nil, errors.New("the campaign does not have any commands to choose from")
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	roll %= totalWeight
+	for _, cc := range cp.commands {
+		if roll < uint64(cc.weight) {
+			return cc, nil
+		}
+		roll -= uint64(cc.weight)
+	}
+	return cp.commands[len(cp.commands)-1], nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/parameters"
+)
+
+func TestCampaignCommands(t *testing.T) {
+	cp := &Campaign{}
+	require.Error(t, cp.AdjustConfig(&parameters.Base{Campaign: map[string]int64{}}))
+	require.Error(t, cp.AdjustConfig(&parameters.Base{Campaign: map[string]int64{"log": 0}}))
+	require.Error(t, cp.AdjustConfig(&parameters.Base{Campaign: map[string]int64{"not-a-command": 1}}))
+	require.Error(t, cp.AdjustConfig(&parameters.Base{Campaign: map[string]int64{Commands["campaign"].Name(): 1}}))
+
+	require.NoError(t, cp.AdjustConfig(&parameters.Base{Campaign: map[string]int64{"log": 3, "blame": 1, "diff": 0}}))
+	require.Len(t, cp.commands, 2)
+	require.Equal(t, "blame", cp.commands[0].cmd.Name())
+	require.Equal(t, "log", cp.commands[1].cmd.Name())
+	chosen := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		cc, err := cp.choose()
+		require.NoError(t, err)
+		chosen[cc.cmd.Name()]++
+	}
+	require.Len(t, chosen, 2)
+	require.Greater(t, chosen["log"], chosen["blame"])
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const checkoutTableStatementsParam = "statements"
+
+// CheckoutTable handles testing of `dolt checkout <table>`, which discards the working set's changes to a single table.
+type CheckoutTable struct {
+	statementCount    uint64
+	remainingBranches []string
+}
+
+var _ Command = (*CheckoutTable)(nil)
+var _ CommandMetadata = (*CheckoutTable)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&CheckoutTable{})
+}
+
+// Name implements the interface Command.
+func (ct *CheckoutTable) Name() string {
+	return "checkout-table"
+}
+
+// Description implements the interface Command.
+func (ct *CheckoutTable) Description() string {
+	return "Tests dolt's ability to discard the changes to a single table."
+}
+
+// Category implements the interface CommandMetadata.
+func (ct *CheckoutTable) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (ct *CheckoutTable) LongDescription() string {
+	return `This command verifies that "dolt checkout <table>" discards the working set's changes to only that table. On
+every branch, the branch is committed, and then random statements are run against every table. A random table is then
+checked out, which must restore it to the last commit, while every other table must keep its changes. Both the rows of
+every table and the output of "dolt status" are checked against the internal data.`
+}
+
+// ParseArgs implements the interface Command.
+func (ct *CheckoutTable) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's ability to discard the changes to a single table",
+		LongDesc:  ct.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(checkoutTableStatementsParam, "", "count", "The number of statements run against each table before the checkout. Defaults to 20.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	ct.statementCount = 20
+	if statements, ok := apr.GetUint(checkoutTableStatementsParam); ok {
+		ct.statementCount = statements
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (ct *CheckoutTable) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (ct *CheckoutTable) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(ct.Reset)
+	hooks.RepositoryFinished(ct.BeginCheckoutTable)
+}
+
+// Reset resets the state of CheckoutTable.
+func (ct *CheckoutTable) Reset(c *run.Cycle) error {
+	ct.remainingBranches = nil
+	return nil
+}
+
+// BeginCheckoutTable starts the checkout process.
+func (ct *CheckoutTable) BeginCheckoutTable(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Checkout Table Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	ct.remainingBranches = c.GetBranchNames()
+	c.QueueAction(ct.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, dirties each of its tables, and then checks out a single table.
+func (ct *CheckoutTable) Run(c *run.Cycle) error {
+	if len(ct.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := ct.remainingBranches[0]
+	ct.remainingBranches = ct.remainingBranches[1:]
+
+	// Switching branches commits the previous branch, but this branch must also be committed, so that nothing is staged
+	// and every change to the working set is one that the checkout may discard
+	err := c.SwitchCurrentBranch(branchName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	branch := c.GetCurrentBranch()
+	workingSet, err := branch.Commit(c, false)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if len(workingSet.Tables) == 0 {
+		c.QueueAction(ct.Run)
+		return nil
+	}
+	for _, table := range workingSet.Tables {
+		for i := uint64(0); i < ct.statementCount; i++ {
+			statement, err := c.NewStatement(table)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			if err = c.SqlServer(statement); err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	tableName := workingSet.Tables[roll%uint64(len(workingSet.Tables))].Name
+	err = c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Checking out table `%s` on \"%s\": %s", tableName, branchName, time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if _, err = c.CliQuery("checkout", tableName); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = branch.RestoreTable(tableName); err != nil {
+		return errors.Wrap(err)
+	}
+
+	// The restored table must match the last commit, while the other tables must still match their modified data
+	for _, table := range branch.GetWorkingSet().Tables {
+		if err = run.ValidateTableRows(c, branchName, table); err != nil {
+			return errors.WrapWithMessage(err, fmt.Sprintf("After checking out table `%s`:", tableName))
+		}
+	}
+	if err = run.ValidateStatus(c); err != nil {
+		return errors.WrapWithMessage(err, fmt.Sprintf("After checking out table `%s`:", tableName))
+	}
+	c.QueueAction(ct.Run)
+	return nil
+}
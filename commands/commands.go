@@ -37,9 +37,49 @@ type Command interface {
 	AdjustConfig(config *parameters.Base) error
 }
 
+// CommandMetadata is an optional interface that a Command may implement to provide additional information for the
+// command catalog, such as when listing all commands.
+type CommandMetadata interface {
+	// Category returns the category that the command belongs to, which is used to group commands together.
+	Category() CommandCategory
+	// LongDescription returns the full description of the command.
+	LongDescription() string
+}
+
+// CommandCategory is the group that a command belongs to.
+type CommandCategory string
+
+const (
+	CommandCategory_General       CommandCategory = "general"
+	CommandCategory_Merge         CommandCategory = "merge-family"
+	CommandCategory_Schema        CommandCategory = "schema"
+	CommandCategory_Query         CommandCategory = "query"
+	CommandCategory_Replay        CommandCategory = "replay"
+	CommandCategory_Debug         CommandCategory = "debug"
+	CommandCategory_Uncategorized CommandCategory = "uncategorized"
+)
+
 var Commands = make(map[string]Command)
 
 // addCommand adds the given command to the command map, allowing its usage from the command line.
 func addCommand(cmd Command) {
 	Commands[strings.ToLower(cmd.Name())] = cmd
 }
+
+// GetCategory returns the category of the given command. Commands that do not implement CommandMetadata are
+// uncategorized.
+func GetCategory(cmd Command) CommandCategory {
+	if metadata, ok := cmd.(CommandMetadata); ok {
+		return metadata.Category()
+	}
+	return CommandCategory_Uncategorized
+}
+
+// GetLongDescription returns the long description of the given command. Commands that do not implement
+// CommandMetadata return their short description.
+func GetLongDescription(cmd Command) string {
+	if metadata, ok := cmd.(CommandMetadata); ok {
+		return metadata.LongDescription()
+	}
+	return cmd.Description()
+}
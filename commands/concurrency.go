@@ -0,0 +1,466 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const (
+	concurrencySessionsParam    = "sessions"
+	concurrencyRoundsParam      = "rounds"
+	concurrencyStatementsParam  = "statements"
+	concurrencyLockTimeoutParam = "lock-timeout"
+)
+
+// Concurrency handles testing of Dolt's transactions by interleaving transactions from several concurrent sessions.
+type Concurrency struct {
+	sessionCount    uint64
+	roundCount      uint64
+	statementCount  uint64
+	lockTimeout     time.Duration
+	remainingRounds uint64
+}
+
+var _ Command = (*Concurrency)(nil)
+var _ CommandMetadata = (*Concurrency)(nil)
+
+// concurrencyTransaction is a transaction on a single session, along with the changes that it has made.
+type concurrencyTransaction struct {
+	id        int
+	session   *sql.Conn
+	remaining uint64
+	// internal contains the statements that apply the transaction's changes to the internal data.
+	internal []string
+	// cells contains each written cell, identified by its row's key and its column.
+	cells map[string]struct{}
+	// rows contains the key of each written row.
+	rows map[string]struct{}
+}
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Concurrency{})
+}
+
+// Name implements the interface Command.
+func (cc *Concurrency) Name() string {
+	return "concurrency"
+}
+
+// Description implements the interface Command.
+func (cc *Concurrency) Description() string {
+	return "Tests dolt's transactions by interleaving transactions from concurrent sessions."
+}
+
+// Category implements the interface CommandMetadata.
+func (cc *Concurrency) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (cc *Concurrency) LongDescription() string {
+	return `This command opens several sessions on the server once the repository has been generated, and runs rounds of
+interleaved transactions that update a small set of rows on a single table, so that the transactions overlap. Each update
+writes a value that no other transaction in the round writes to the same cell, and the transactions are committed in a
+random order. A transaction that writes a cell written by an earlier successful transaction must fail to commit, as it
+would otherwise lose that update, while a transaction that shares no rows with an earlier successful transaction must
+commit. Transactions that only share rows, but not cells, may do either. Once every round has finished, the table must
+match the internal data, which applies only the successful transactions in the order that they were committed. Any
+statement that does not complete within the lock timeout is reported as blocked.`
+}
+
+// ParseArgs implements the interface Command.
+func (cc *Concurrency) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's transactions by interleaving transactions from concurrent sessions",
+		LongDesc:  cc.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(concurrencySessionsParam, "", "count", "The number of concurrent sessions. Defaults to 4.")
+	ap.SupportsUint(concurrencyRoundsParam, "", "count", "The number of rounds of transactions. Defaults to 10.")
+	ap.SupportsUint(concurrencyStatementsParam, "", "count", "The number of statements in each transaction. Defaults to 5.")
+	ap.SupportsString(concurrencyLockTimeoutParam, "", "duration",
+		"How long a statement may run before it is reported as blocked. Defaults to 10s.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	cc.sessionCount = 4
+	if sessions, ok := apr.GetUint(concurrencySessionsParam); ok {
+		cc.sessionCount = sessions
+	}
+	if cc.sessionCount < 2 {
+		return errors.New("at least two sessions are required")
+	}
+	cc.roundCount = 10
+	if rounds, ok := apr.GetUint(concurrencyRoundsParam); ok {
+		cc.roundCount = rounds
+	}
+	cc.statementCount = 5
+	if statements, ok := apr.GetUint(concurrencyStatementsParam); ok {
+		cc.statementCount = statements
+	}
+	cc.lockTimeout = 10 * time.Second
+	if lockTimeout, ok := apr.GetValue(concurrencyLockTimeoutParam); ok {
+		var err error
+		cc.lockTimeout, err = time.ParseDuration(lockTimeout)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (cc *Concurrency) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (cc *Concurrency) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(cc.Reset)
+	hooks.RepositoryFinished(cc.BeginConcurrency)
+}
+
+// Reset resets the state of Concurrency.
+func (cc *Concurrency) Reset(c *run.Cycle) error {
+	cc.remainingRounds = 0
+	return nil
+}
+
+// BeginConcurrency starts the rounds of concurrent transactions.
+func (cc *Concurrency) BeginConcurrency(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Concurrency: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	cc.remainingRounds = cc.roundCount
+	c.QueueAction(cc.Round)
+	return nil
+}
+
+// Round runs a single round of interleaved transactions on a random table. Once all rounds have finished, the current
+// branch is validated.
+func (cc *Concurrency) Round(c *run.Cycle) error {
+	if cc.remainingRounds == 0 {
+		return c.ValidateCurrentBranch()
+	}
+	cc.remainingRounds--
+	table, columns, err := cc.chooseTable(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if table == nil {
+		return c.Logger.WriteLine(run.LogType_INFO, "Concurrency: no table has rows and updatable columns, skipping")
+	}
+	// Every transaction updates rows from the same small set, so that the transactions overlap
+	hotRows := make([]run.Row, cc.sessionCount)
+	for i := range hotRows {
+		hotRows[i], _, err = table.Data.GetRandomRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	transactions := make([]*concurrencyTransaction, cc.sessionCount)
+	defer func() {
+		for _, transaction := range transactions {
+			if transaction != nil {
+				_ = transaction.session.Close()
+			}
+		}
+	}()
+	for i := range transactions {
+		session, err := dc.NewSession(context.Background())
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		transactions[i] = &concurrencyTransaction{
+			id:        i,
+			session:   session,
+			remaining: cc.statementCount,
+			cells:     make(map[string]struct{}),
+			rows:      make(map[string]struct{}),
+		}
+		if err = cc.exec(c, transactions[i], "START TRANSACTION;"); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	// Each cell's written values are tracked, so that no two writes to a cell are identical, as identical changes
+	// merge cleanly and would hide a lost update
+	writtenValues := make(map[string]map[string]struct{})
+	for pending := transactions; len(pending) > 0; {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		transaction := pending[roll%uint64(len(pending))]
+		if transaction.remaining == 0 {
+			pending = removeTransaction(pending, transaction)
+			continue
+		}
+		transaction.remaining--
+		row := hotRows[(roll/uint64(len(pending)))%uint64(len(hotRows))]
+		column := columns[(roll/uint64(len(pending)*len(hotRows)))%uint64(len(columns))]
+		if err = cc.update(c, transaction, table, row, column, writtenValues); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	order := make([]*concurrencyTransaction, len(transactions))
+	copy(order, transactions)
+	for i := len(order) - 1; i > 0; i-- {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		j := roll % uint64(i+1)
+		order[i], order[j] = order[j], order[i]
+	}
+	committedCells := make(map[string]struct{})
+	committedRows := make(map[string]struct{})
+	for _, transaction := range order {
+		cellConflict := overlaps(transaction.cells, committedCells)
+		rowConflict := overlaps(transaction.rows, committedRows)
+		committed, err := cc.commit(c, transaction)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if committed && cellConflict {
+			return errors.New(fmt.Sprintf("Session %d committed on table `%s` after another session had committed "+
+				"a write to the same cell, losing that update", transaction.id, table.Name))
+		}
+		if !committed && !rowConflict {
+			return errors.New(fmt.Sprintf("Session %d failed to commit on table `%s`, but no other session had "+
+				"committed a write to the same rows", transaction.id, table.Name))
+		}
+		if !committed {
+			continue
+		}
+		for _, statement := range transaction.internal {
+			if err = table.Data.Exec(statement); err != nil {
+				return errors.Wrap(err)
+			}
+		}
+		for cell := range transaction.cells {
+			committedCells[cell] = struct{}{}
+		}
+		for row := range transaction.rows {
+			committedRows[row] = struct{}{}
+		}
+	}
+	c.QueueAction(cc.Round)
+	return nil
+}
+
+// chooseTable returns a random table on the current branch that has rows, along with the columns that may be updated.
+// Columns in unique indexes are excluded, as are types whose values may compare as equal while being stored
+// differently. Returns a nil table if no table qualifies.
+func (cc *Concurrency) chooseTable(c *run.Cycle) (*run.Table, []*run.Column, error) {
+	var candidates []*run.Table
+	var candidateColumns [][]*run.Column
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if len(table.PKCols) == 0 {
+			continue
+		}
+		rowCount, err := table.Data.GetRowCount()
+		if err != nil {
+			return nil, nil, errors.Wrap(err)
+		}
+		if rowCount == 0 {
+			continue
+		}
+		uniqueColumns := make(map[string]struct{})
+		for _, index := range table.Indexes {
+			if index.IsUnique {
+				for _, colName := range index.Columns {
+					uniqueColumns[colName] = struct{}{}
+				}
+			}
+		}
+		var columns []*run.Column
+		for _, column := range table.NonPKCols {
+			if _, ok := uniqueColumns[column.Name]; !ok && isConcurrencyColumn(column) {
+				columns = append(columns, column)
+			}
+		}
+		if len(columns) > 0 {
+			candidates = append(candidates, table)
+			candidateColumns = append(candidateColumns, columns)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, nil, errors.Wrap(err)
+	}
+	idx := roll % uint64(len(candidates))
+	return candidates[idx], candidateColumns[idx], nil
+}
+
+// update writes a new value to the given row and column within the transaction. The update is skipped if a value that
+// has not already been written to the cell cannot be found.
+func (cc *Concurrency) update(c *run.Cycle, transaction *concurrencyTransaction, table *run.Table, row run.Row,
+	column *run.Column, writtenValues map[string]map[string]struct{}) error {
+	rowKey := run.Row{Values: row.Key()}.MySQLString()
+	cellKey := rowKey + "\x00" + column.Name
+	written, ok := writtenValues[cellKey]
+	if !ok {
+		colIdx := len(table.PKCols)
+		for i, nonPKCol := range table.NonPKCols {
+			if nonPKCol == column {
+				colIdx += i
+			}
+		}
+		written = map[string]struct{}{row.Values[colIdx].MySQLString(): {}}
+		writtenValues[cellKey] = written
+	}
+	var value types.Value
+	for attempt := 0; attempt < 100 && value == nil; attempt++ {
+		candidate, err := column.Type.Get()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if _, ok := written[candidate.MySQLString()]; !ok {
+			value = candidate
+		}
+	}
+	if value == nil {
+		return nil
+	}
+	written[value.MySQLString()] = struct{}{}
+
+	wheres, err := run.GenerateColumnEquals(table.PKCols, row.Key())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	wheresInternal, err := table.Data.ColumnEquals(table.PKCols, row.Key())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	statement := fmt.Sprintf("UPDATE `%s` SET `%s` = %s WHERE %s;",
+		table.Name, column.Name, value.MySQLString(), strings.Join(wheres, " AND "))
+	if err = cc.exec(c, transaction, statement); err != nil {
+		return errors.Wrap(err)
+	}
+	transaction.internal = append(transaction.internal, fmt.Sprintf("UPDATE `%s` SET `%s` = %s WHERE %s;", table.Name,
+		column.Name, table.Data.Dialect(value.MySQLString(), value.SQLiteString()), strings.Join(wheresInternal, " AND ")))
+	transaction.cells[cellKey] = struct{}{}
+	transaction.rows[rowKey] = struct{}{}
+	return nil
+}
+
+// commit commits the transaction, returning whether it was committed. A transaction that conflicts with another
+// transaction is rolled back.
+func (cc *Concurrency) commit(c *run.Cycle, transaction *concurrencyTransaction) (bool, error) {
+	err := cc.exec(c, transaction, "COMMIT;")
+	if err == nil {
+		return true, nil
+	}
+	if !isTransactionConflict(err) {
+		return false, errors.Wrap(err)
+	}
+	if err = c.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("Session %d: %s", transaction.id, err.Error())); err != nil {
+		return false, errors.Wrap(err)
+	}
+	if err = cc.exec(c, transaction, "ROLLBACK;"); err != nil {
+		return false, errors.Wrap(err)
+	}
+	return false, nil
+}
+
+// exec runs the statement on the transaction's session. Statements are logged along with their session, as the log
+// cannot be replayed on a single session. The original error is returned, so that conflicts may be identified.
+func (cc *Concurrency) exec(c *run.Cycle, transaction *concurrencyTransaction, statement string) error {
+	err := c.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("Session %d: %s", transaction.id, statement))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cc.lockTimeout)
+	defer cancel()
+	_, err = transaction.session.ExecContext(ctx, statement)
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.New(fmt.Sprintf("Session %d was blocked for longer than %s: %s",
+			transaction.id, cc.lockTimeout.String(), statement))
+	}
+	return err
+}
+
+// isConcurrencyColumn returns whether the column's values are always stored exactly as they are compared, so that
+// distinct values are guaranteed to be distinct changes. VARCHAR columns must use a collation that compares their
+// exact bytes, as values such as 'a' and 'A ' are otherwise equal.
+func isConcurrencyColumn(column *run.Column) bool {
+	switch colType := column.Type.(type) {
+	case *types.TinyintInstance, *types.SmallintInstance, *types.MediumintInstance, *types.IntInstance,
+		*types.BigintInstance, *types.TinyintUnsignedInstance, *types.SmallintUnsignedInstance,
+		*types.MediumintUnsignedInstance, *types.IntUnsignedInstance, *types.BigintUnsignedInstance,
+		*types.VarbinaryInstance:
+		return true
+	case *types.VarcharInstance:
+		return colType.ComparesExactly()
+	default:
+		return false
+	}
+}
+
+// isTransactionConflict returns whether the error is caused by the transaction conflicting with another transaction.
+func isTransactionConflict(err error) bool {
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok && (mysqlErr.Number == 1213 || mysqlErr.Number == 1205) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "serialization failure")
+}
+
+// overlaps returns whether the two sets share any keys.
+func overlaps(a map[string]struct{}, b map[string]struct{}) bool {
+	for key := range a {
+		if _, ok := b[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTransaction returns the transactions without the given transaction.
+func removeTransaction(transactions []*concurrencyTransaction, transaction *concurrencyTransaction) []*concurrencyTransaction {
+	remaining := make([]*concurrencyTransaction, 0, len(transactions)-1)
+	for _, t := range transactions {
+		if t != transaction {
+			remaining = append(remaining, t)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,341 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// Diff handles diff testing, covering both two-dot and three-dot diffs.
+type Diff struct {
+	diffCombinations []diffCombination
+}
+
+// diffCombination is a single diff to test. The revisions are given exactly as they are passed to Dolt, while the
+// commits are used to compute the expected diff. When a branch is given, the diff is instead read from the
+// `dolt_diff_<table>` system table on that branch, which requires the "to" commit to be the branch head and the "from"
+// commit to be its parent.
+type diffCombination struct {
+	revisions string
+	branch    string
+	from      *run.Commit
+	to        *run.Commit
+}
+
+var _ Command = (*Diff)(nil)
+var _ CommandMetadata = (*Diff)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Diff{})
+}
+
+// Name implements the interface Command.
+func (d *Diff) Name() string {
+	return "diff"
+}
+
+// Description implements the interface Command.
+func (d *Diff) Description() string {
+	return "Tests dolt's two-dot and three-dot diff functionality."
+}
+
+// Category implements the interface CommandMetadata.
+func (d *Diff) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (d *Diff) LongDescription() string {
+	return `This command verifies that the two-dot diff "a..b" and the three-dot diff "a...b" return the expected rows for
+every pair of branches. A two-dot diff compares the two commits directly, while a three-dot diff compares the merge base
+of both commits against "b". Each branch is also diffed against its own parent commit, where the three-dot diff from the
+branch to its parent must be empty. The same change is also read from the "dolt_diff_<table>" system table, where each
+row's "diff_type" must match the change found between the two commits. For tables with a primary key, the number of
+rows added, deleted, and modified that "dolt diff --stat" reports must match the number of each change as well.`
+}
+
+// ParseArgs implements the interface Command.
+func (d *Diff) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's two-dot and three-dot diff functionality",
+		LongDesc:  d.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (d *Diff) AdjustConfig(config *parameters.Base) error {
+	// Diffs are read using the column names of the newer commit, which would not exist on older commits after a rename
+	config.SchemaChanges = parameters.SchemaChanges{}
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (d *Diff) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(d.Reset)
+	hooks.CycleStarted(d.VerifyCycle)
+	hooks.RepositoryFinished(d.BeginDiff)
+}
+
+// Reset resets the state of Diff.
+func (d *Diff) Reset(c *run.Cycle) error {
+	d.diffCombinations = nil
+	return nil
+}
+
+// VerifyCycle verifies that the run.Cycle will support diff testing, and will modify the run.Cycle to guarantee that
+// diffs between branches can be tested.
+func (d *Diff) VerifyCycle(c *run.Cycle) error {
+	if c.Blueprint.BranchCount < 2 {
+		c.Blueprint.BranchCount = 2
+	}
+	return nil
+}
+
+// BeginDiff gathers every diff combination and starts the diff process.
+func (d *Diff) BeginDiff(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Diff Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	branches := c.GetBranchNames()
+	heads := make(map[string]*run.Commit)
+	for _, branchName := range branches {
+		branch := c.GetBranch(branchName)
+		if branch == nil {
+			return errors.New(fmt.Sprintf("unable to get branch: %s", branchName))
+		}
+		// We're getting the working set, so we always backtrack to the last commit as the working set is guaranteed to be empty.
+		heads[branchName] = branch.GetWorkingSet().Parents[0]
+	}
+
+	for i := 0; i < len(branches); i++ {
+		for j := 0; j < len(branches); j++ {
+			if i == j {
+				continue
+			}
+			from := heads[branches[i]]
+			to := heads[branches[j]]
+			base, err := run.MergeBase(from, to)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			d.diffCombinations = append(d.diffCombinations,
+				diffCombination{
+					revisions: fmt.Sprintf("%s..%s", branches[i], branches[j]),
+					from:      from,
+					to:        to,
+				},
+				diffCombination{
+					revisions: fmt.Sprintf("%s...%s", branches[i], branches[j]),
+					from:      base,
+					to:        to,
+				})
+		}
+	}
+
+	// A branch diffed against its own ancestor is the same for both two-dot and three-dot diffs, while the reverse
+	// three-dot diff is always empty as the merge base is the ancestor itself.
+	for _, branchName := range branches {
+		head := heads[branchName]
+		if len(head.Parents) == 0 {
+			continue
+		}
+		parent := head.Parents[0]
+		d.diffCombinations = append(d.diffCombinations,
+			diffCombination{
+				revisions: fmt.Sprintf("%s..%s", parent.Hash, branchName),
+				from:      parent,
+				to:        head,
+			},
+			diffCombination{
+				revisions: fmt.Sprintf("%s...%s", parent.Hash, branchName),
+				from:      parent,
+				to:        head,
+			},
+			diffCombination{
+				revisions: fmt.Sprintf("%s...%s", branchName, parent.Hash),
+				from:      parent,
+				to:        parent,
+			},
+			diffCombination{
+				revisions: fmt.Sprintf("dolt_diff (%s..%s)", parent.Hash, head.Hash),
+				branch:    branchName,
+				from:      parent,
+				to:        head,
+			})
+	}
+	c.QueueAction(d.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a diff combination and processes it.
+func (d *Diff) Run(c *run.Cycle) error {
+	if len(d.diffCombinations) == 0 { // We've tested all diff combinations
+		return nil
+	}
+	combination := d.diffCombinations[0]
+	d.diffCombinations = d.diffCombinations[1:]
+
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf(`Diffing "%s": %s`, combination.revisions, time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if combination.branch != "" {
+		err = c.SwitchCurrentBranch(combination.branch)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	tableDiffs, err := run.DiffCommits(combination.from, combination.to)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, tableDiff := range tableDiffs {
+		// The diff uses the schema of the "to" table, falling back to the "from" table when it has been removed
+		table := combination.to.GetTable(tableDiff.TableName)
+		if table == nil && combination.branch != "" {
+			// The system table only exists for tables that are on the branch
+			continue
+		} else if table == nil {
+			table = combination.from.GetTable(tableDiff.TableName)
+		}
+		err = combination.Verify(c, table, tableDiff.Rows)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		err = combination.VerifyStat(c, tableDiff)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	c.QueueAction(d.Run)
+	return nil
+}
+
+// VerifyStat checks that Dolt's diff statistics of the given table match the number of expected row differences of
+// each type. The statistics are only checked for tables with a primary key that exist on both sides, as those are the
+// tables whose rows Dolt counts in the same way as the fuzzer. The system table has no statistics of its own.
+func (dc diffCombination) VerifyStat(c *run.Cycle, tableDiff run.TableDiff) error {
+	if dc.branch != "" {
+		return nil
+	}
+	table := dc.to.GetTable(tableDiff.TableName)
+	if table == nil || table.IsKeyless() || dc.from.GetTable(tableDiff.TableName) == nil {
+		return nil
+	}
+	expected := run.CountDiffStat(tableDiff.Rows)
+	actual, err := table.GetDoltDiffStat(c, dc.revisions)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if expected != actual {
+		return errors.New(fmt.Sprintf("Diff stat `%s` on table `%s` does not match:\nExpected: %s\nDolt: %s",
+			dc.revisions, table.Name, expected.String(), actual.String()))
+	}
+	return nil
+}
+
+// Verify checks that Dolt's diff of the given table matches the expected row differences.
+func (dc diffCombination) Verify(c *run.Cycle, table *run.Table, expectedDiffs []run.RowDiff) error {
+	var cursor *run.DoltDataCursor
+	var err error
+	if dc.branch != "" {
+		cursor, err = table.GetDoltDiffSystemTableCursor(c, dc.from, dc.to)
+	} else {
+		cursor, err = table.GetDoltDiffCursor(c, dc.revisions)
+	}
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return verifyDiffCursor(dc.revisions, table, cursor, expectedDiffs)
+}
+
+// verifyDiffCursor checks that the diff rows from the cursor match the expected row differences of the given table,
+// closing the cursor once it has been read. The revisions describe the diff in any errors.
+func verifyDiffCursor(revisions string, table *run.Table, cursor *run.DoltDataCursor, expectedDiffs []run.RowDiff) error {
+	defer cursor.Close()
+	// Keyless tables may have several identical differences for a row that is duplicated, so each key holds a list
+	expected := make(map[utils.Hash][]run.RowDiff)
+	for _, rowDiff := range expectedDiffs {
+		expected[rowDiff.Key()] = append(expected[rowDiff.Key()], rowDiff)
+	}
+
+	for {
+		row, ok, err := cursor.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if !ok {
+			break
+		}
+		doltDiff, err := table.DiffRowFromDolt(row)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		key := doltDiff.Key()
+		if len(expected[key]) == 0 {
+			return errors.New(fmt.Sprintf("Diff `%s` on table `%s` returned an unexpected %s row:\nFrom: %s\nTo: %s",
+				revisions, table.Name, doltDiff.Type, doltDiff.From.MySQLString(), doltDiff.To.MySQLString()))
+		}
+		expectedDiff := expected[key][0]
+		if expectedDiff.Type != doltDiff.Type || !expectedDiff.From.Equals(doltDiff.From) || !expectedDiff.To.Equals(doltDiff.To) {
+			return errors.New(fmt.Sprintf("Diff `%s` on table `%s` has a mismatched row:\nExpected %s\nFrom: %s\nTo: %s\n\nDolt %s\nFrom: %s\nTo: %s",
+				revisions, table.Name, expectedDiff.Type, expectedDiff.From.MySQLString(), expectedDiff.To.MySQLString(),
+				doltDiff.Type, doltDiff.From.MySQLString(), doltDiff.To.MySQLString()))
+		}
+		if len(expected[key]) == 1 {
+			delete(expected, key)
+		} else {
+			expected[key] = expected[key][1:]
+		}
+	}
+	missing := describeMissingDiffs(expectedDiffs, expected)
+	if len(missing) > 0 {
+		return errors.New(fmt.Sprintf("Diff `%s` on table `%s` is missing rows:\n%s",
+			revisions, table.Name, strings.Join(missing, "\n\n")))
+	}
+	return nil
+}
+
+// describeMissingDiffs describes each difference that remains in the map of expected differences, which was built from
+// the given slice. They are described in the order of the slice, as iterating the map would give a random order.
+func describeMissingDiffs(expectedDiffs []run.RowDiff, expected map[utils.Hash][]run.RowDiff) []string {
+	var missing []string
+	for _, rowDiff := range expectedDiffs {
+		remaining := expected[rowDiff.Key()]
+		if len(remaining) == 0 {
+			continue
+		}
+		missing = append(missing, fmt.Sprintf("%s row:\nFrom: %s\nTo: %s",
+			remaining[0].Type, remaining[0].From.MySQLString(), remaining[0].To.MySQLString()))
+		expected[rowDiff.Key()] = remaining[1:]
+	}
+	return missing
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils"
+)
+
+func TestDescribeMissingDiffs(t *testing.T) {
+	added := func(pk int64) run.RowDiff {
+		return run.RowDiff{Type: run.DiffType_Added,
+			To: run.Row{Values: []types.Value{types.BigintValue{Int64Value: types.Int64Value(pk)}}, PkColsLen: 1}}
+	}
+	expectedDiffs := make([]run.RowDiff, 20)
+	for i := range expectedDiffs {
+		expectedDiffs[i] = added(int64(i))
+	}
+	// Every run must describe the missing differences in the same order, which is the order that they were expected
+	var first []string
+	for i := 0; i < 10; i++ {
+		expected := make(map[utils.Hash][]run.RowDiff)
+		for _, rowDiff := range expectedDiffs {
+			expected[rowDiff.Key()] = append(expected[rowDiff.Key()], rowDiff)
+		}
+		delete(expected, expectedDiffs[3].Key())
+		missing := describeMissingDiffs(expectedDiffs, expected)
+		require.Len(t, missing, 19)
+		require.Equal(t, "added row:\nFrom: \nTo: 0", missing[0])
+		if first == nil {
+			first = missing
+		}
+		require.Equal(t, first, missing)
+	}
+}
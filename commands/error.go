@@ -32,6 +32,7 @@ type Error struct {
 }
 
 var _ Command = (*Error)(nil)
+var _ CommandMetadata = (*Error)(nil)
 
 // init adds the command to the map.
 func init() {
@@ -57,13 +58,23 @@ func (e *Error) Description() string {
 	return "Forces each cycle to error."
 }
 
+// Category implements the interface CommandMetadata.
+func (e *Error) Category() CommandCategory {
+	return CommandCategory_Debug
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (e *Error) LongDescription() string {
+	return `This command causes each cycle to error rather than succeed. This is primarily useful for setting up automated workflows
+that need to handle the condition of a cycle finishing unsuccessfully.`
+}
+
 // ParseArgs implements the interface Command.
 func (e *Error) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
 	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
 		ShortDesc: "Forces each cycle to error",
-		LongDesc: `This command causes each cycle to error rather than succeed. This is primarily useful for setting up automated workflows
-that need to handle the condition of a cycle finishing unsuccessfully.`,
-		Synopsis: nil,
+		LongDesc:  e.LongDescription(),
+		Synopsis:  nil,
 	}, ap))
 	ap.SupportsString(errorMessageParam, "", "contents", "The error message to output.")
 	apr := cli.ParseArgsOrDie(ap, args, help)
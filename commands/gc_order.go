@@ -0,0 +1,232 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const gcOrderStatementsParam = "statements"
+
+// GCOrder handles testing that `dolt gc` preserves the content and stored order of every table's rows.
+type GCOrder struct {
+	statementCount    uint64
+	remainingBranches []string
+}
+
+var _ Command = (*GCOrder)(nil)
+var _ CommandMetadata = (*GCOrder)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&GCOrder{})
+}
+
+// Name implements the interface Command.
+func (gco *GCOrder) Name() string {
+	return "gc-order"
+}
+
+// Description implements the interface Command.
+func (gco *GCOrder) Description() string {
+	return "Tests that dolt's garbage collection preserves the content and order of rows."
+}
+
+// Category implements the interface CommandMetadata.
+func (gco *GCOrder) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (gco *GCOrder) LongDescription() string {
+	return `This command verifies that "dolt gc" does not change the rows of any table, nor the order that they are
+stored in. On every branch, random statements are run against every table, and the branch is committed. Every table is
+then read in primary key order, which is kept as a snapshot, before running "dolt gc". Afterward, every table must return
+the exact same rows in the exact same order. Tables with a primary key must also return the same order from a scan
+without an ORDER BY, as their rows are stored in primary key order.`
+}
+
+// ParseArgs implements the interface Command.
+func (gco *GCOrder) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that dolt's garbage collection preserves the content and order of rows",
+		LongDesc:  gco.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(gcOrderStatementsParam, "", "count", "The number of statements run against each table before the GC. Defaults to 50.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	gco.statementCount = 50
+	if statements, ok := apr.GetUint(gcOrderStatementsParam); ok {
+		gco.statementCount = statements
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (gco *GCOrder) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (gco *GCOrder) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(gco.Reset)
+	hooks.RepositoryFinished(gco.BeginGCOrder)
+}
+
+// Reset resets the state of GCOrder.
+func (gco *GCOrder) Reset(c *run.Cycle) error {
+	gco.remainingBranches = nil
+	return nil
+}
+
+// BeginGCOrder starts the GC order process.
+func (gco *GCOrder) BeginGCOrder(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning GC Order Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	gco.remainingBranches = c.GetBranchNames()
+	c.QueueAction(gco.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, writes to and commits its tables, and then compares every table
+// before and after running GC.
+func (gco *GCOrder) Run(c *run.Cycle) error {
+	if len(gco.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := gco.remainingBranches[0]
+	gco.remainingBranches = gco.remainingBranches[1:]
+
+	err := c.SwitchCurrentBranch(branchName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	branch := c.GetCurrentBranch()
+	for _, table := range branch.GetWorkingSet().Tables {
+		for i := uint64(0); i < gco.statementCount; i++ {
+			statement, err := c.NewStatement(table)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			if err = c.SqlServer(statement); err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+	workingSet, err := branch.Commit(c, false)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	// The snapshot is only meaningful once the rows are known to be correct
+	snapshots := make([][]run.Row, len(workingSet.Tables))
+	for i, table := range workingSet.Tables {
+		if err = run.ValidateTableRows(c, branchName, table); err != nil {
+			return errors.WrapWithMessage(err, "Before running GC:")
+		}
+		cursor, err := table.GetDoltCursor(c)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		snapshots[i], err = readDoltRows(cursor)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	err = c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Running GC on \"%s\": %s", branchName, time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if _, err = c.CliQuery("gc"); err != nil {
+		return errors.Wrap(err)
+	}
+
+	for i, table := range workingSet.Tables {
+		cursor, err := table.GetDoltCursor(c)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = compareGCSnapshot(table, snapshots[i], cursor, "ordered"); err != nil {
+			return errors.Wrap(err)
+		}
+		if !table.IsKeyless() {
+			cursor, err = table.GetDoltProjectionCursor(c, fmt.Sprintf("SELECT %s FROM `%s`;", table.SelectColumns(), table.Name),
+				table.Data.ConstructTemplateRow())
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			if err = compareGCSnapshot(table, snapshots[i], cursor, "unordered"); err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+	if err = run.ValidateStatus(c); err != nil {
+		return errors.WrapWithMessage(err, "After running GC:")
+	}
+	c.QueueAction(gco.Run)
+	return nil
+}
+
+// readDoltRows reads every row from the cursor, closing it once it has been exhausted.
+func readDoltRows(cursor *run.DoltDataCursor) ([]run.Row, error) {
+	defer cursor.Close()
+	var rows []run.Row
+	for {
+		row, ok, err := cursor.NextRow()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if !ok {
+			return rows, nil
+		}
+		rows = append(rows, row)
+	}
+}
+
+// compareGCSnapshot returns an error if the rows from the cursor differ from the snapshot, in either content or order.
+// The scan describes how the cursor read the table.
+func compareGCSnapshot(table *run.Table, snapshot []run.Row, cursor *run.DoltDataCursor, scan string) error {
+	rows, err := readDoltRows(cursor)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for i := 0; i < len(snapshot) || i < len(rows); i++ {
+		if i >= len(rows) {
+			return errors.New(fmt.Sprintf("After running GC, an %s scan of table `%s` returned %d rows but expected %d",
+				scan, table.Name, len(rows), len(snapshot)))
+		}
+		if i >= len(snapshot) {
+			return errors.New(fmt.Sprintf("After running GC, an %s scan of table `%s` returned an additional row: [%s]",
+				scan, table.Name, rows[i].MySQLString()))
+		}
+		if !snapshot[i].Equals(rows[i]) {
+			return errors.New(fmt.Sprintf("After running GC, an %s scan of table `%s` differs at row %d\nBefore GC: [%s]\nAfter GC: [%s]",
+				scan, table.Name, i, snapshot[i].MySQLString(), rows[i].MySQLString()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const (
+	importRowsParam = "rows"
+	// importNullProbability is the probability that a non-key value in an imported row is NULL.
+	importNullProbability = 0.125
+	// importMaxDuplicateKeys is the number of consecutive duplicate keys after which no more rows are generated.
+	importMaxDuplicateKeys = 1000
+)
+
+// importMode is the mode that `dolt table import` is run with.
+type importMode string
+
+const (
+	importMode_Create  importMode = "-c"
+	importMode_Update  importMode = "-u"
+	importMode_Replace importMode = "-r"
+)
+
+// importModes are all of the modes that may be chosen for an import.
+var importModes = []importMode{importMode_Create, importMode_Update, importMode_Replace}
+
+// Import handles testing of `dolt table import`, by importing generated CSV and JSON files into existing tables.
+type Import struct {
+	rowCount          uint64
+	remainingBranches []string
+}
+
+var _ Command = (*Import)(nil)
+var _ CommandMetadata = (*Import)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Import{})
+}
+
+// Name implements the interface Command.
+func (imp *Import) Name() string {
+	return "import"
+}
+
+// Description implements the interface Command.
+func (imp *Import) Description() string {
+	return "Tests dolt's ability to import tables from CSV and JSON files."
+}
+
+// Category implements the interface CommandMetadata.
+func (imp *Import) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (imp *Import) LongDescription() string {
+	return `This command verifies that "dolt table import" parses and writes rows exactly as inserting them would. On
+every branch, a random table is chosen, and a CSV or JSON file of random rows is generated for it. The file is imported
+using a random mode: creating the table from its schema (-c), updating the table (-u), or replacing the table's rows
+(-r). The same rows are applied to the internal data, which the imported table is then validated against. Some values
+are NULL, and strings are always quoted, so that NULL, empty strings, and embedded quotes are all exercised.`
+}
+
+// ParseArgs implements the interface Command.
+func (imp *Import) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's ability to import tables from CSV and JSON files",
+		LongDesc:  imp.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(importRowsParam, "", "count", "The number of rows written to each imported file. Defaults to 50.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	imp.rowCount = 50
+	if rows, ok := apr.GetUint(importRowsParam); ok {
+		imp.rowCount = rows
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (imp *Import) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (imp *Import) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(imp.Reset)
+	hooks.RepositoryFinished(imp.BeginImport)
+}
+
+// Reset resets the state of Import.
+func (imp *Import) Reset(c *run.Cycle) error {
+	imp.remainingBranches = nil
+	return nil
+}
+
+// BeginImport starts the import process.
+func (imp *Import) BeginImport(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Import Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	imp.remainingBranches = c.GetBranchNames()
+	c.QueueAction(imp.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and then imports a generated file into one of its tables.
+func (imp *Import) Run(c *run.Cycle) error {
+	if len(imp.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := imp.remainingBranches[0]
+	imp.remainingBranches = imp.remainingBranches[1:]
+
+	err := c.SwitchCurrentBranch(branchName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	workingSet := c.GetCurrentBranch().GetWorkingSet()
+	var tables []*run.Table
+	for _, table := range workingSet.Tables {
+		if isImportableTable(workingSet, table) {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) == 0 {
+		c.QueueAction(imp.Run)
+		return nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	table := tables[roll%uint64(len(tables))]
+	mode := importModes[roll/uint64(len(tables))%uint64(len(importModes))]
+	useJSON := roll/uint64(len(tables)*len(importModes))%2 == 0
+
+	rows, err := imp.newImportRows(table, mode)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	var fileContents string
+	var fileExtension string
+	if useJSON {
+		fileContents, err = importJSON(table.ColumnNames(), rows)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		fileExtension = "json"
+	} else {
+		fileContents = importCSV(table.ColumnNames(), rows)
+		fileExtension = "csv"
+	}
+	filePath, err := writeImportFile(fileExtension, fileContents)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer os.Remove(filePath)
+	err = c.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("Importing %d rows into table `%s` on \"%s\" using %s and %s: %s",
+		len(rows), table.Name, branchName, mode, fileExtension, time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	args := []string{"table", "import", string(mode)}
+	if mode == importMode_Create {
+		// The table already exists, so it is forcibly recreated from its own schema
+		schemaPath, err := writeImportFile("sql", table.CreateString(false, false))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		defer os.Remove(schemaPath)
+		args = append(args, "-f", "--schema", schemaPath)
+	}
+	args = append(args, table.Name, filePath)
+	if _, err = c.CliQuery(args...); err != nil {
+		return errors.Wrap(err)
+	}
+
+	if mode != importMode_Update {
+		if err = table.Data.Exec(fmt.Sprintf("DELETE FROM `%s`;", table.Name)); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	for _, row := range rows {
+		if _, err = table.Data.Replace(row); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	if err = run.ValidateTableRows(c, branchName, table); err != nil {
+		return errors.WrapWithMessage(err, fmt.Sprintf("After importing into table `%s` using %s and %s:",
+			table.Name, mode, fileExtension))
+	}
+	c.QueueAction(imp.Run)
+	return nil
+}
+
+// newImportRows returns the rows that will be imported into the table. Updates also modify some of the table's existing
+// rows, so that the import must overwrite them. Keyed tables never receive the same key twice, as the file would
+// otherwise be ambiguous.
+func (imp *Import) newImportRows(table *run.Table, mode importMode) ([]run.Row, error) {
+	rows := make([]run.Row, 0, imp.rowCount)
+	usedKeys := make(map[utils.Hash]struct{})
+	duplicateKeys := 0
+	for uint64(len(rows)) < imp.rowCount {
+		var row run.Row
+		useExisting, err := rand.Bool()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if mode == importMode_Update && useExisting && !table.IsKeyless() {
+			existingRow, ok, err := table.Data.GetRandomRow()
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			if ok {
+				row, err = existingRow.NewRowValue(table)
+				if err != nil {
+					return nil, errors.Wrap(err)
+				}
+			}
+		}
+		if row.IsEmpty() {
+			row, err = run.NewRow(table)
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+		}
+		if !table.IsKeyless() {
+			keyHash := run.Row{Values: row.Key()}.Hash()
+			if _, ok := usedKeys[keyHash]; ok {
+				// Small key ranges may saturate, so the file is shortened rather than searching for unused keys forever
+				duplicateKeys++
+				if duplicateKeys >= importMaxDuplicateKeys {
+					break
+				}
+				continue
+			}
+			duplicateKeys = 0
+			usedKeys[keyHash] = struct{}{}
+		}
+		for i := row.PkColsLen; i < int32(len(row.Values)); i++ {
+			roll, err := rand.Uint64()
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			if float64(roll) < importNullProbability*math.MaxUint64 {
+				row.Values[i] = types.NilValue{}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// isImportableTable returns whether the table may be imported into. Tables involved in foreign keys are excluded, as
+// replacing their rows may violate the foreign keys. ENUM, SET, and BIT values are written as integers, which the
+// importer does not interpret the same way as an INSERT, and spatial values have no text representation.
+func isImportableTable(workingSet *run.Commit, table *run.Table) bool {
+	for _, fk := range workingSet.ForeignKeys {
+		if fk.TableName == table.Name || fk.ReferencedTableName == table.Name {
+			return false
+		}
+	}
+	for _, col := range append(append([]*run.Column{}, table.PKCols...), table.NonPKCols...) {
+		if types.IsSpatial(col.Type) {
+			return false
+		}
+		switch col.Type.(type) {
+		case *types.EnumInstance, *types.SetInstance, *types.BitInstance:
+			return false
+		}
+	}
+	return true
+}
+
+// importValue returns the value as the text that is written to an import file, along with whether the text must be
+// quoted. NULL values return false for both.
+func importValue(val types.Value) (string, bool, bool) {
+	if _, ok := val.(types.NilValue); ok {
+		return "", false, false
+	}
+	switch primitive := val.Primitive().(type) {
+	case types.StringValue:
+		return string(primitive), true, true
+	case types.Float32Value:
+		return strconv.FormatFloat(float64(primitive), 'g', -1, 32), false, true
+	case types.Float64Value:
+		return strconv.FormatFloat(float64(primitive), 'g', -1, 64), false, true
+	default:
+		return val.CSVString(), false, true
+	}
+}
+
+// importCSV returns the rows as a CSV file with a header. NULL values are written as empty fields, while strings are
+// always quoted, so that empty strings remain distinguishable from NULL.
+func importCSV(columnNames []string, rows []run.Row) string {
+	sb := strings.Builder{}
+	sb.WriteString(strings.Join(columnNames, ","))
+	sb.WriteString("\n")
+	for _, row := range rows {
+		for i, val := range row.Values {
+			if i > 0 {
+				sb.WriteRune(',')
+			}
+			text, quoted, _ := importValue(val)
+			if quoted {
+				sb.WriteRune('"')
+				sb.WriteString(strings.ReplaceAll(text, `"`, `""`))
+				sb.WriteRune('"')
+			} else {
+				sb.WriteString(text)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// importJSON returns the rows as a JSON file in the format that Dolt expects. Every value is written as a string, as
+// JSON numbers are decoded as floats, which would lose the precision of large integers and decimals.
+func importJSON(columnNames []string, rows []run.Row) (string, error) {
+	jsonRows := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		jsonRow := make(map[string]interface{}, len(columnNames))
+		for j, val := range row.Values {
+			if text, _, ok := importValue(val); ok {
+				jsonRow[columnNames[j]] = text
+			} else {
+				jsonRow[columnNames[j]] = nil
+			}
+		}
+		jsonRows[i] = jsonRow
+	}
+	out, err := json.Marshal(map[string]interface{}{"rows": jsonRows})
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return string(out), nil
+}
+
+// writeImportFile writes the contents to a new temporary file with the given extension, returning the file's path. The
+// file is written outside of the repository so that it does not appear in the repository's status.
+func writeImportFile(extension string, contents string) (string, error) {
+	file, err := os.CreateTemp("", "fuzzer_import_*."+extension)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	_, err = file.WriteString(contents)
+	if cErr := file.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", errors.Wrap(err)
+	}
+	return file.Name(), nil
+}
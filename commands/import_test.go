@@ -0,0 +1,43 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestImportFiles(t *testing.T) {
+	columnNames := []string{"pk", "v1", "v2", "v3"}
+	rows := []run.Row{
+		{Values: []types.Value{types.IntValue{Int32Value: 1}, types.VarcharValue{StringValue: `say "hi"`},
+			types.NilValue{}, types.DoubleValue{Float64Value: 0.1}}, PkColsLen: 1},
+		{Values: []types.Value{types.IntValue{Int32Value: -2}, types.VarcharValue{StringValue: ""},
+			types.DecimalValue{StringValue: "12345678901234567890.5"}, types.FloatValue{Float32Value: 1.5}}, PkColsLen: 1},
+	}
+
+	require.Equal(t, "pk,v1,v2,v3\n"+
+		`1,"say ""hi""",,0.1`+"\n"+
+		`-2,"","12345678901234567890.5",1.5`+"\n", importCSV(columnNames, rows))
+
+	jsonFile, err := importJSON(columnNames, rows)
+	require.NoError(t, err)
+	require.Equal(t, `{"rows":[{"pk":"1","v1":"say \"hi\"","v2":null,"v3":"0.1"},`+
+		`{"pk":"-2","v1":"","v2":"12345678901234567890.5","v3":"1.5"}]}`, jsonFile)
+}
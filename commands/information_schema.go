@@ -0,0 +1,123 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// InformationSchema handles testing that information_schema describes every table's columns and indexes.
+type InformationSchema struct {
+	remainingBranches []string
+}
+
+var _ Command = (*InformationSchema)(nil)
+var _ CommandMetadata = (*InformationSchema)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&InformationSchema{})
+}
+
+// Name implements the interface Command.
+func (is *InformationSchema) Name() string {
+	return "information-schema"
+}
+
+// Description implements the interface Command.
+func (is *InformationSchema) Description() string {
+	return "Tests that information_schema describes the columns and indexes of every table."
+}
+
+// Category implements the interface CommandMetadata.
+func (is *InformationSchema) Category() CommandCategory {
+	return CommandCategory_Schema
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (is *InformationSchema) LongDescription() string {
+	return `This command verifies that "information_schema.columns" and "information_schema.statistics" match the
+schema of every table on every branch. Each column must have the expected data type, signedness, nullability, key
+flag, and maximum length for fixed and variable-length string and binary types. Each index, including the primary key,
+must list the expected columns in order, along with whether it is unique, the prefix length of each column, and whether
+each column is sorted in descending order.`
+}
+
+// ParseArgs implements the interface Command.
+func (is *InformationSchema) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that information_schema describes the columns and indexes of every table",
+		LongDesc:  is.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (is *InformationSchema) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (is *InformationSchema) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(is.Reset)
+	hooks.RepositoryFinished(is.BeginInformationSchema)
+}
+
+// Reset resets the state of InformationSchema.
+func (is *InformationSchema) Reset(c *run.Cycle) error {
+	is.remainingBranches = nil
+	return nil
+}
+
+// BeginInformationSchema starts the information_schema testing process.
+func (is *InformationSchema) BeginInformationSchema(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Information Schema Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	is.remainingBranches = c.GetBranchNames()
+	c.QueueAction(is.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and validates information_schema against every table on it.
+func (is *InformationSchema) Run(c *run.Cycle) error {
+	if len(is.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := is.remainingBranches[0]
+	is.remainingBranches = is.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if err := run.ValidateInformationSchema(c, table); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	c.QueueAction(is.Run)
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// InterfaceParity handles testing that `dolt sql` and `dolt sql-server` return the same rows.
+type InterfaceParity struct {
+	remainingBranches []string
+}
+
+var _ Command = (*InterfaceParity)(nil)
+var _ CommandMetadata = (*InterfaceParity)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&InterfaceParity{})
+}
+
+// Name implements the interface Command.
+func (ip *InterfaceParity) Name() string {
+	return "interface-parity"
+}
+
+// Description implements the interface Command.
+func (ip *InterfaceParity) Description() string {
+	return "Tests that dolt sql and dolt sql-server return the same rows."
+}
+
+// Category implements the interface CommandMetadata.
+func (ip *InterfaceParity) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (ip *InterfaceParity) LongDescription() string {
+	return `This command verifies that the CLI and the server agree on the contents of every table, which catches bugs in
+how either interface serializes values. Once the repository has been generated, every table on every branch is read in
+primary key order using "dolt sql -q" with the "json" format, and using the server connection. Every value must be
+identical between both interfaces, except that numbers match if they represent the same number, as JSON may write them
+differently. Binary, bit, and spatial columns are not compared, as JSON encodes their bytes.`
+}
+
+// ParseArgs implements the interface Command.
+func (ip *InterfaceParity) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that dolt sql and dolt sql-server return the same rows",
+		LongDesc:  ip.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (ip *InterfaceParity) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (ip *InterfaceParity) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(ip.Reset)
+	hooks.RepositoryFinished(ip.BeginInterfaceParity)
+}
+
+// Reset resets the state of InterfaceParity.
+func (ip *InterfaceParity) Reset(c *run.Cycle) error {
+	ip.remainingBranches = nil
+	return nil
+}
+
+// BeginInterfaceParity starts the interface parity testing process.
+func (ip *InterfaceParity) BeginInterfaceParity(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Interface Parity Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	ip.remainingBranches = c.GetBranchNames()
+	c.QueueAction(ip.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and compares how both interfaces read every table on it.
+func (ip *InterfaceParity) Run(c *run.Cycle) error {
+	if len(ip.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := ip.remainingBranches[0]
+	ip.remainingBranches = ip.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if err := run.ValidateInterfaceParity(c, table); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	c.QueueAction(ip.Run)
+	return nil
+}
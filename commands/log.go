@@ -0,0 +1,179 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// Log handles log testing.
+type Log struct {
+	remainingBranches []string
+}
+
+var _ Command = (*Log)(nil)
+var _ CommandMetadata = (*Log)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Log{})
+}
+
+// Name implements the interface Command.
+func (l *Log) Name() string {
+	return "log"
+}
+
+// Description implements the interface Command.
+func (l *Log) Description() string {
+	return "Tests dolt's commit log."
+}
+
+// Category implements the interface CommandMetadata.
+func (l *Log) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (l *Log) LongDescription() string {
+	return `This command verifies that the commit graph reported by Dolt matches the graph that the fuzzer built. For every
+branch, each commit in the log must match the internal history in order, with the same hash and parents. Commits
+created by the fuzzer must also have the same message and author.`
+}
+
+// ParseArgs implements the interface Command.
+func (l *Log) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's commit log",
+		LongDesc:  l.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (l *Log) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (l *Log) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(l.Reset)
+	hooks.RepositoryFinished(l.BeginLog)
+}
+
+// Reset resets the state of Log.
+func (l *Log) Reset(c *run.Cycle) error {
+	l.remainingBranches = nil
+	return nil
+}
+
+// BeginLog starts the log process.
+func (l *Log) BeginLog(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Log Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	l.remainingBranches = c.GetBranchNames()
+	c.QueueAction(l.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch and verifies its log.
+func (l *Log) Run(c *run.Cycle) error {
+	if len(l.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := l.remainingBranches[0]
+	l.remainingBranches = l.remainingBranches[1:]
+
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf(`Checking log on "%s": %s`, branchName, time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// The log reads from the current branch, and switching branches commits the working set, so HEAD is up to date
+	err = c.SwitchCurrentBranch(branchName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if _, err = c.GetCurrentBranch().Commit(c, false); err != nil {
+		return errors.Wrap(err)
+	}
+	err = l.Verify(c, branchName, c.GetCurrentBranch().GetWorkingSet().Parents[0])
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	c.QueueAction(l.Run)
+	return nil
+}
+
+// Verify checks that Dolt's log of the current branch matches the internal history, starting from the given head.
+func (l *Log) Verify(c *run.Cycle, branchName string, head *run.Commit) error {
+	entries, err := run.GetDoltLog(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	commit := head
+	for i, entry := range entries {
+		if commit == nil {
+			return errors.New(fmt.Sprintf("Log on branch `%s` has %d commits but expected %d",
+				branchName, len(entries), i))
+		}
+		if entry.Hash != commit.Hash {
+			return errors.New(fmt.Sprintf("Log on branch `%s` has commit %s at position %d but expected %s",
+				branchName, entry.Hash, i, commit.Hash))
+		}
+		expectedParents := make([]string, len(commit.Parents))
+		for j, parent := range commit.Parents {
+			expectedParents[j] = parent.Hash
+		}
+		if strings.Join(entry.Parents, ",") != strings.Join(expectedParents, ",") {
+			return errors.New(fmt.Sprintf("Log on branch `%s` has parents [%s] for commit %s but expected [%s]",
+				branchName, strings.Join(entry.Parents, ", "), entry.Hash, strings.Join(expectedParents, ", ")))
+		}
+		// Metadata is only known for commits that the fuzzer created
+		if commit.Author != "" {
+			if entry.Message != commit.Message {
+				return errors.New(fmt.Sprintf("Log on branch `%s` has message %q for commit %s but expected %q",
+					branchName, entry.Message, entry.Hash, commit.Message))
+			}
+			name, email := run.SplitCommitAuthor(commit.Author)
+			if entry.Committer != name || entry.Email != email {
+				return errors.New(fmt.Sprintf("Log on branch `%s` has author %q for commit %s but expected %q",
+					branchName, fmt.Sprintf("%s <%s>", entry.Committer, entry.Email), entry.Hash, commit.Author))
+			}
+		}
+		if len(commit.Parents) > 0 {
+			commit = commit.Parents[0]
+		} else {
+			commit = nil
+		}
+	}
+	if commit != nil {
+		return errors.New(fmt.Sprintf("Log on branch `%s` is missing commit %s", branchName, commit.Hash))
+	}
+	return nil
+}
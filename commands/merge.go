@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,9 +32,20 @@ import (
 	"github.com/dolthub/fuzzer/utils/cli"
 )
 
+const (
+	mergeSquashParam          = "squash"
+	mergeMaxCombinationsParam = "max-combinations"
+	mergeSkipMinimalParam     = "skip-minimal"
+	mergeValidateAbortParam   = "validate-abort"
+)
+
 // Merge handles merge testing.
 type Merge struct {
 	mergeCombinations map[mergeCombination]bool
+	squash            bool
+	maxCombinations   uint64
+	skipMinimal       bool
+	validateAbort     bool
 }
 
 // mergeCombination is the combination of branches representing a specific merge.
@@ -65,16 +77,24 @@ type mergeConflict struct {
 	theirs run.Row
 }
 
+// mergeConflicts holds the conflicts of a merged table in the Oracle, so that they may be streamed in the same order
+// that Dolt returns its conflicts, without every conflict being held in memory.
+type mergeConflicts struct {
+	data  *run.TableData
+	count int64
+}
+
 // mergeTableWithConflicts is a merged table with its associated conflicts.
 type mergeTableWithConflicts struct {
 	base      *run.Table
 	ours      *run.Table
 	theirs    *run.Table
 	final     *run.Table
-	conflicts []run.Row
+	conflicts *mergeConflicts
 }
 
 var _ Command = (*Merge)(nil)
+var _ CommandMetadata = (*Merge)(nil)
 
 // init adds the command to the map.
 func init() {
@@ -91,20 +111,48 @@ func (m *Merge) Description() string {
 	return "Tests dolt's merge functionality."
 }
 
+// Category implements the interface CommandMetadata.
+func (m *Merge) Category() CommandCategory {
+	return CommandCategory_Merge
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (m *Merge) LongDescription() string {
+	return `This command verifies that "dolt merge" functions as expected under randomly constructed scenarios.
+This also performs a validation step before testing merge, which is the same as the "basic" command. With --squash,
+every merge uses "dolt merge --squash", which must produce the same working set as a regular merge, while leaving HEAD
+on our commit without a merge in progress. Every ordered pair of branches is merged, unless --max-combinations limits
+each cycle to a random sample of the pairs. Every cycle also merges a fixed set of minimal scenarios, where each table is
+empty or holds a single row on the base, and each side makes at most one change, unless --skip-minimal is given. With
+--validate-abort, every merge that is aborted must leave the working set exactly as it was before the merge.`
+}
+
 // ParseArgs implements the interface Command.
 func (m *Merge) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
 	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
 		ShortDesc: "Tests dolt's merge functionality",
-		LongDesc: `This command verifies that "dolt merge" functions as expected under randomly constructed scenarios.
-This also performs a validation step before testing merge, which is the same as the "basic" command.`,
-		Synopsis: nil,
+		LongDesc:  m.LongDescription(),
+		Synopsis:  nil,
 	}, ap))
-	_ = cli.ParseArgsOrDie(ap, args, help)
+	ap.SupportsFlag(mergeSquashParam, "", "Merges using --squash, and verifies that no merge commit is created.")
+	ap.SupportsUint(mergeMaxCombinationsParam, "", "count", "The maximum number of merge combinations tested per cycle, which are randomly sampled from every pair of branches. Defaults to 0, which tests every combination.")
+	ap.SupportsFlag(mergeSkipMinimalParam, "", "Skips the minimal merge scenarios, which are otherwise merged on every cycle.")
+	ap.SupportsFlag(mergeValidateAbortParam, "", "Verifies that aborting each merge leaves the working set exactly as it was before the merge.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	m.squash = apr.Contains(mergeSquashParam)
+	m.skipMinimal = apr.Contains(mergeSkipMinimalParam)
+	m.validateAbort = apr.Contains(mergeValidateAbortParam)
+	m.maxCombinations = 0
+	if maxCombinations, ok := apr.GetUint(mergeMaxCombinationsParam); ok {
+		m.maxCombinations = maxCombinations
+	}
 	return nil
 }
 
 // AdjustConfig implements the interface Command.
 func (m *Merge) AdjustConfig(config *parameters.Base) error {
+	// The expected merge results are computed from the rows alone, so schema changes would diverge from Dolt's merge
+	config.SchemaChanges = parameters.SchemaChanges{}
 	return nil
 }
 
@@ -138,37 +186,89 @@ func (m *Merge) BeginMerge(c *run.Cycle) error {
 		return errors.Wrap(err)
 	}
 	branches := c.GetBranchNames()
+	var combinations []mergeCombination
 	for i := 0; i < len(branches); i++ {
 		for j := i + 1; j < len(branches); j++ {
-			m.mergeCombinations[mergeCombination{
+			combinations = append(combinations, mergeCombination{
 				ours:   branches[i],
 				theirs: branches[j],
-			}] = false
-			m.mergeCombinations[mergeCombination{
+			}, mergeCombination{
 				ours:   branches[j],
 				theirs: branches[i],
-			}] = false
+			})
+		}
+	}
+	if m.maxCombinations > 0 && uint64(len(combinations)) > m.maxCombinations {
+		totalCombinations := len(combinations)
+		combinations, err = sampleMergeCombinations(c, combinations, m.maxCombinations)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		err = c.Logger.WriteLine(run.LogType_INFO,
+			fmt.Sprintf("Testing %d of %d merge combinations", len(combinations), totalCombinations))
+		if err != nil {
+			return errors.Wrap(err)
 		}
 	}
+	// The minimal scenarios are never sampled, as they're meant to be covered on every cycle
+	if !m.skipMinimal {
+		minimalCombinations, err := createMinimalMergeScenarios(c)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		combinations = append(combinations, minimalCombinations...)
+	}
+	for _, combination := range combinations {
+		m.mergeCombinations[combination] = false
+	}
 	c.QueueAction(m.Run)
 	return nil
 }
 
+// sampleMergeCombinations returns a random selection of the given combinations, containing at most the given count. The
+// given slice is reordered in place.
+func sampleMergeCombinations(c *run.Cycle, combinations []mergeCombination, count uint64) ([]mergeCombination, error) {
+	if uint64(len(combinations)) <= count {
+		return combinations, nil
+	}
+	// A partial Fisher-Yates shuffle, as only the first count combinations need to be chosen
+	for i := uint64(0); i < count; i++ {
+		offset, err := c.DecideIndex("merge-sample", len(combinations)-int(i))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		j := i + uint64(offset)
+		combinations[i], combinations[j] = combinations[j], combinations[i]
+	}
+	return combinations[:count], nil
+}
+
 // Run is the primary loop that selects a merge combination and processes it.
 func (m *Merge) Run(c *run.Cycle) error {
-	var combination mergeCombination
+	var remaining []mergeCombination
 	for mc, visited := range m.mergeCombinations {
 		if visited == false {
-			combination = mc
-			m.mergeCombinations[mc] = true
-			break
+			remaining = append(remaining, mc)
 		}
 	}
-	if combination.ours == "" || combination.theirs == "" { // We've tested all merge combinations
+	if len(remaining) == 0 { // We've tested all merge combinations
 		return nil
 	}
+	// The map's order is random, so the combinations are sorted to make the choice a replayable decision
+	sort.Slice(remaining, func(i, j int) bool {
+		if remaining[i].ours != remaining[j].ours {
+			return remaining[i].ours < remaining[j].ours
+		}
+		return remaining[i].theirs < remaining[j].theirs
+	})
+	combinationIdx, err := c.DecideIndex("merge", len(remaining))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	combination := remaining[combinationIdx]
+	m.mergeCombinations[combination] = true
 
-	err := c.Logger.WriteLine(run.LogType_INFO,
+	err = c.Logger.WriteLine(run.LogType_INFO,
 		fmt.Sprintf(`Merging "%s" into "%s": %s`, combination.theirs, combination.ours, time.Now().Format("2006-01-02 15:04:05")))
 	if err != nil {
 		return errors.Wrap(err)
@@ -199,6 +299,7 @@ func (m *Merge) Run(c *run.Cycle) error {
 	defer func() {
 		for _, finalTable := range finalTables {
 			finalTable.final.Data.Close()
+			finalTable.conflicts.Close()
 		}
 	}()
 	for _, mt := range allMergeTables {
@@ -209,10 +310,24 @@ func (m *Merge) Run(c *run.Cycle) error {
 		finalTables = append(finalTables, mtc)
 	}
 
-	_, err = c.CliQuery("merge", combination.theirs)
+	if m.squash {
+		_, err = c.CliQuery("merge", "--squash", combination.theirs)
+	} else {
+		_, err = c.CliQuery("merge", combination.theirs)
+	}
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	if m.squash {
+		hasConflicts := false
+		for _, finalTable := range finalTables {
+			hasConflicts = hasConflicts || finalTable.conflicts.count > 0
+		}
+		err = m.VerifySquash(c, combination, commits, hasConflicts)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
 	for _, finalTable := range finalTables {
 		err = finalTable.Verify(c)
 		if err != nil {
@@ -228,6 +343,12 @@ func (m *Merge) Run(c *run.Cycle) error {
 	if err != nil && !strings.Contains(err.Error(), "no merge to abort") {
 		return errors.Wrap(err)
 	}
+	// Merges without conflicts are committed, so only a merge that was actually aborted has a state to verify
+	if m.validateAbort && err == nil {
+		if err = m.VerifyAbort(c, combination); err != nil {
+			return errors.Wrap(err)
+		}
+	}
 	_, err = c.CliQuery("reset", "--hard")
 	if err != nil {
 		return errors.Wrap(err)
@@ -236,6 +357,70 @@ func (m *Merge) Run(c *run.Cycle) error {
 	return nil
 }
 
+// VerifySquash verifies that a squash merge only modified the working set. HEAD must still be our commit, and unless
+// there are conflicts to resolve, there must not be a merge in progress, as the merged changes are committed as a
+// regular commit with a single parent.
+func (m *Merge) VerifySquash(c *run.Cycle, combination mergeCombination, commits mergeCommits, hasConflicts bool) error {
+	entries, err := run.GetDoltLog(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if len(entries) == 0 || entries[0].Hash != commits.ours.Hash {
+		head := ""
+		if len(entries) > 0 {
+			head = entries[0].Hash
+		}
+		return errors.New(fmt.Sprintf("Squash merging \"%s\" into \"%s\" moved HEAD to %s, but it must remain on %s",
+			combination.theirs, combination.ours, head, commits.ours.Hash))
+	}
+	if hasConflicts {
+		return nil
+	}
+	isMerging, err := run.IsDoltMerging(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if isMerging {
+		return errors.New(fmt.Sprintf("Squash merging \"%s\" into \"%s\" left a merge in progress",
+			combination.theirs, combination.ours))
+	}
+	return nil
+}
+
+// VerifyAbort verifies that aborting a merge restored the working set to our commit, which the current branch still
+// holds in the internal data. There must not be a merge in progress nor any remaining conflicts, while every table must
+// hold exactly the rows of our commit, and `dolt status` must not report any changes.
+func (m *Merge) VerifyAbort(c *run.Cycle, combination mergeCombination) error {
+	isMerging, err := run.IsDoltMerging(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if isMerging {
+		return errors.New(fmt.Sprintf("Aborting the merge of \"%s\" into \"%s\" left a merge in progress",
+			combination.theirs, combination.ours))
+	}
+	conflictedTables, err := run.GetDoltConflictedTables(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if len(conflictedTables) > 0 {
+		return errors.New(fmt.Sprintf("Aborting the merge of \"%s\" into \"%s\" left conflicts on the tables: %s",
+			combination.theirs, combination.ours, strings.Join(conflictedTables, ", ")))
+	}
+	branch := c.GetCurrentBranch()
+	for _, table := range branch.GetWorkingSet().Tables {
+		if err = run.ValidateTableRows(c, branch.Name, table); err != nil {
+			return errors.WrapWithMessage(err, fmt.Sprintf("After aborting the merge of \"%s\" into \"%s\":",
+				combination.theirs, combination.ours))
+		}
+	}
+	if err = run.ValidateStatus(c); err != nil {
+		return errors.WrapWithMessage(err, fmt.Sprintf("After aborting the merge of \"%s\" into \"%s\":",
+			combination.theirs, combination.ours))
+	}
+	return nil
+}
+
 // UniqueBranchName returns the unique branch name that will be used for this merge combination.
 func (mc mergeCombination) UniqueBranchName() string {
 	return fmt.Sprintf("__merge_%s_%s", mc.ours, mc.theirs)
@@ -255,26 +440,10 @@ func (mc mergeCombination) GetCommits(c *run.Cycle) (mergeCommits, error) {
 	ourCommit := ourBranch.GetWorkingSet().Parents[0]
 	theirCommit := theirBranch.GetWorkingSet().Parents[0]
 
-	var baseCommit *run.Commit
-	parent1 := ourCommit
-	parent2 := theirCommit
-	for {
-		if parent1.Hash == parent2.Hash {
-			baseCommit = parent1
-			break
-		}
-		// TODO: Whenever we can generate commits with multiple parents, we'll need to update this logic
-		if len(parent2.Parents) == 0 {
-			if len(parent1.Parents) == 0 {
-				return mergeCommits{},
-					errors.New(fmt.Sprintf("the following branches do not have a common ancestor: %s, %s", mc.ours, mc.theirs))
-			} else {
-				parent1 = parent1.Parents[0]
-				parent2 = theirCommit
-			}
-		} else {
-			parent2 = parent2.Parents[0]
-		}
+	baseCommit, err := run.MergeBase(ourCommit, theirCommit)
+	if err != nil {
+		return mergeCommits{}, errors.New(fmt.Sprintf("the following branches do not have a common ancestor: %s, %s",
+			mc.ours, mc.theirs))
 	}
 
 	return mergeCommits{
@@ -357,16 +526,26 @@ func (mc mergeCommits) GetTables() ([]mergeTables, error) {
 // ProcessMerge processes the called tables by merging them using our internal data.
 func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 	if mt.final != nil {
+		conflicts, err := newMergeConflicts(mt.final)
+		if err != nil {
+			return mergeTableWithConflicts{}, errors.Wrap(err)
+		}
 		return mergeTableWithConflicts{
 			ours:      mt.ours,
 			theirs:    mt.theirs,
 			base:      mt.base,
 			final:     mt.final,
-			conflicts: nil,
+			conflicts: conflicts,
 		}, nil
 	}
+	if mt.ours.IsKeyless() {
+		return mt.processKeylessMerge()
+	}
 	final, err := mt.ours.Copy()
-	var conflicts []run.Row
+	if err != nil {
+		return mergeTableWithConflicts{}, errors.Wrap(err)
+	}
+	conflicts, err := newMergeConflicts(final)
 	if err != nil {
 		return mergeTableWithConflicts{}, errors.Wrap(err)
 	}
@@ -416,11 +595,14 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 					}
 				case 0: // same row, check for equivalence
 					if !ourRow.Equals(theirRow) { // both modified, conflict
-						conflicts = append(conflicts, mergeConflict{
+						err = conflicts.Add(mergeConflict{
 							base:   run.Row{},
 							ours:   ourRow,
 							theirs: theirRow,
-						}.ToRow(final))
+						}, final)
+						if err != nil {
+							return mergeTableWithConflicts{}, errors.Wrap(err)
+						}
 					}
 					ourRow, ourRowExists, err = ourCursor.NextRow()
 					if err != nil {
@@ -431,7 +613,7 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 						return mergeTableWithConflicts{}, errors.Wrap(err)
 					}
 				case 1: // theirs is new
-					err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, theirRow.SQLiteString()))
+					err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, final.Data.RowString(theirRow)))
 					if err != nil {
 						return mergeTableWithConflicts{}, errors.Wrap(err)
 					}
@@ -454,7 +636,7 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 		case 0:
 			switch theirRow.PKCompare(baseRow) {
 			case -1: // theirs is new
-				err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, theirRow.SQLiteString()))
+				err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, final.Data.RowString(theirRow)))
 				if err != nil {
 					return mergeTableWithConflicts{}, errors.Wrap(err)
 				}
@@ -465,7 +647,7 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 			case 0: // check for updates
 				if !ourRow.Equals(theirRow) {
 					if ourRow.Equals(baseRow) { // theirs modified
-						err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, theirRow.SQLiteString()))
+						err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, final.Data.RowString(theirRow)))
 						if err != nil {
 							return mergeTableWithConflicts{}, errors.Wrap(err)
 						}
@@ -489,12 +671,15 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 							}
 						}
 						if conflict == nil {
-							err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, mergedRow.SQLiteString()))
+							err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, final.Data.RowString(mergedRow)))
 							if err != nil {
 								return mergeTableWithConflicts{}, errors.Wrap(err)
 							}
 						} else {
-							conflicts = append(conflicts, conflict.ToRow(final))
+							err = conflicts.Add(*conflict, final)
+							if err != nil {
+								return mergeTableWithConflicts{}, errors.Wrap(err)
+							}
 						}
 					}
 				}
@@ -512,17 +697,20 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 				}
 			case 1: // check for updates, deleted in theirs
 				if !ourRow.Equals(baseRow) { // modified ours, conflict
-					conflicts = append(conflicts, mergeConflict{
+					err = conflicts.Add(mergeConflict{
 						base:   baseRow,
 						ours:   ourRow,
 						theirs: run.Row{},
-					}.ToRow(final))
+					}, final)
+					if err != nil {
+						return mergeTableWithConflicts{}, errors.Wrap(err)
+					}
 				} else { // ours unmodified, valid deletion
-					wheresSQLite, err := run.GenerateColumnEqualsSQLite(final.PKCols, ourRow.Key())
+					wheresInternal, err := final.Data.ColumnEquals(final.PKCols, ourRow.Key())
 					if err != nil {
 						return mergeTableWithConflicts{}, errors.Wrap(err)
 					}
-					err = final.Data.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE %s;", final.Name, strings.Join(wheresSQLite, " AND ")))
+					err = final.Data.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE %s;", final.Name, strings.Join(wheresInternal, " AND ")))
 					if err != nil {
 						return mergeTableWithConflicts{}, errors.Wrap(err)
 					}
@@ -539,7 +727,7 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 		case 1:
 			switch theirRow.PKCompare(baseRow) {
 			case -1: // theirs is new
-				err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, theirRow.SQLiteString()))
+				err = final.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", final.Name, final.Data.RowString(theirRow)))
 				if err != nil {
 					return mergeTableWithConflicts{}, errors.Wrap(err)
 				}
@@ -549,11 +737,14 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 				}
 			case 0: // check for updates, deleted in ours
 				if !theirRow.Equals(baseRow) { // modified theirs, conflict
-					conflicts = append(conflicts, mergeConflict{
+					err = conflicts.Add(mergeConflict{
 						base:   baseRow,
 						ours:   run.Row{},
 						theirs: theirRow,
-					}.ToRow(final))
+					}, final)
+					if err != nil {
+						return mergeTableWithConflicts{}, errors.Wrap(err)
+					}
 				}
 				baseRow, baseRowExists, err = baseCursor.NextRow()
 				if err != nil {
@@ -572,9 +763,86 @@ func (mt mergeTables) ProcessMerge() (mergeTableWithConflicts, error) {
 		}
 	}
 
-	sort.Slice(conflicts, func(i, j int) bool {
-		return conflicts[i].Compare(conflicts[j]) == -1
-	})
+	return mergeTableWithConflicts{
+		ours:      mt.ours,
+		theirs:    mt.theirs,
+		base:      mt.base,
+		final:     final,
+		conflicts: conflicts,
+	}, nil
+}
+
+// processKeylessMerge is ProcessMerge for tables without a primary key. Just as in Dolt, each distinct row is its own
+// key, and the number of times that it appears is the value that is merged. When both sides change the count of a row
+// to different counts, the row is a conflict, and our count is kept.
+func (mt mergeTables) processKeylessMerge() (mergeTableWithConflicts, error) {
+	final, err := mt.ours.Copy()
+	if err != nil {
+		return mergeTableWithConflicts{}, errors.Wrap(err)
+	}
+	conflicts, err := newMergeConflicts(final)
+	if err != nil {
+		return mergeTableWithConflicts{}, errors.Wrap(err)
+	}
+
+	var cursors []*run.TableDataCursor
+	defer func() {
+		for _, cursor := range cursors {
+			cursor.Close()
+		}
+	}()
+	for _, data := range []*run.TableData{mt.base.Data, mt.ours.Data, mt.theirs.Data} {
+		cursor, err := data.GetRowCursor()
+		if err != nil {
+			return mergeTableWithConflicts{}, errors.Wrap(err)
+		}
+		cursors = append(cursors, cursor)
+	}
+	counts, err := run.NewRowCountCursor(final.Name, final.PKCols, final.NonPKCols, cursors[0], cursors[1], cursors[2])
+	if err != nil {
+		return mergeTableWithConflicts{}, errors.Wrap(err)
+	}
+	defer counts.Close()
+
+	// Every row has been copied by the count cursor, so the final data is rebuilt from the merged count of each row
+	err = final.Data.Exec(fmt.Sprintf("DELETE FROM `%s`;", final.Name))
+	if err != nil {
+		return mergeTableWithConflicts{}, errors.Wrap(err)
+	}
+	row, rowCounts, ok, err := counts.NextRow()
+	for ; err == nil && ok; row, rowCounts, ok, err = counts.NextRow() {
+		baseCount, ourCount, theirCount := rowCounts[0], rowCounts[1], rowCounts[2]
+		finalCount := ourCount
+		switch {
+		case ourCount == baseCount:
+			finalCount = theirCount
+		case theirCount != baseCount && ourCount != theirCount:
+			conflict := mergeConflict{}
+			if baseCount > 0 {
+				conflict.base = row
+			}
+			if ourCount > 0 {
+				conflict.ours = row
+			}
+			if theirCount > 0 {
+				conflict.theirs = row
+			}
+			err = conflicts.Add(conflict, final)
+			if err != nil {
+				return mergeTableWithConflicts{}, errors.Wrap(err)
+			}
+		}
+		for i := int64(0); i < finalCount; i++ {
+			err = final.Data.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", final.Name, final.Data.RowString(row)))
+			if err != nil {
+				return mergeTableWithConflicts{}, errors.Wrap(err)
+			}
+		}
+	}
+	if err != nil {
+		return mergeTableWithConflicts{}, errors.Wrap(err)
+	}
+
 	return mergeTableWithConflicts{
 		ours:      mt.ours,
 		theirs:    mt.theirs,
@@ -623,6 +891,46 @@ func (mc mergeConflict) ToRow(table *run.Table) run.Row {
 	}
 }
 
+// newMergeConflicts returns an empty set of conflicts for the given merged table. Each conflict has a column for every
+// column of the table on each side (base, ours, theirs), matching the columns of Dolt's conflict table.
+func newMergeConflicts(final *run.Table) (*mergeConflicts, error) {
+	var cols []*run.Column
+	for _, prefix := range []string{"base_", "our_", "their_"} {
+		for _, col := range append(append([]*run.Column{}, final.PKCols...), final.NonPKCols...) {
+			cols = append(cols, &run.Column{Name: prefix + col.Name, Type: col.Type})
+		}
+	}
+	data, err := run.CreateTableData("conflicts", nil, cols)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &mergeConflicts{data: data}, nil
+}
+
+// Add writes the given conflict of the merged table to the Oracle.
+func (mc *mergeConflicts) Add(conflict mergeConflict, final *run.Table) error {
+	err := mc.data.Exec(fmt.Sprintf("INSERT INTO `conflicts` VALUES (%s);", mc.data.RowString(conflict.ToRow(final))))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	mc.count++
+	return nil
+}
+
+// GetCursor returns a cursor over the conflicts, ordered by every column just as Dolt's conflicts are ordered.
+func (mc *mergeConflicts) GetCursor() (*run.TableDataCursor, error) {
+	orderBy := make([]string, len(mc.data.ConstructTemplateRow().Values))
+	for i := range orderBy {
+		orderBy[i] = strconv.Itoa(i + 1)
+	}
+	return mc.data.GetQueryCursor(fmt.Sprintf("SELECT * FROM `conflicts` ORDER BY %s;", strings.Join(orderBy, ", ")))
+}
+
+// Close frees the conflicts held by the Oracle.
+func (mc *mergeConflicts) Close() {
+	mc.data.Close()
+}
+
 // Export writes all four internal tables (base, ours, theirs, merged) involved in the merge, the conflicts, and a shell
 // script to set up and import all the data into a Dolt instance.
 func (mtc mergeTableWithConflicts) Export(c *run.Cycle) error {
@@ -664,92 +972,8 @@ func (mtc mergeTableWithConflicts) Export(c *run.Cycle) error {
 
 // exportConflictsToCSV writes the conflict data to a CSV in the working directory.
 func (mtc mergeTableWithConflicts) exportConflictsToCSV(c *run.Cycle) error {
-	file, err := os.OpenFile(fmt.Sprintf("%s%s/internal_data/conflicts.csv", c.Planner.Base.Arguments.RepoWorkingPath, c.Name),
-		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0777)
-	if err != nil {
-		return errors.Wrap(err)
-	}
-	defer func() {
-		fErr := file.Close()
-		if fErr != nil && err == nil {
-			err = errors.Wrap(fErr)
-		}
-	}()
-
-	// Write the column header row
-	firstItem := true
-	for _, pkCol := range mtc.base.PKCols {
-		if firstItem {
-			firstItem = false
-		} else {
-			_, err = file.WriteString(",")
-			if err != nil {
-				return errors.Wrap(err)
-			}
-		}
-		_, err = file.WriteString("base_" + pkCol.Name)
-		if err != nil {
-			return errors.Wrap(err)
-		}
-	}
-	for _, nonPkCol := range mtc.base.NonPKCols {
-		if firstItem {
-			firstItem = false
-		} else {
-			_, err = file.WriteString(",")
-			if err != nil {
-				return errors.Wrap(err)
-			}
-		}
-		_, err = file.WriteString("base_" + nonPkCol.Name)
-		if err != nil {
-			return errors.Wrap(err)
-		}
-	}
-	for _, pkCol := range mtc.ours.PKCols {
-		_, err = file.WriteString(",our_" + pkCol.Name)
-		if err != nil {
-			return errors.Wrap(err)
-		}
-	}
-	for _, nonPkCol := range mtc.ours.NonPKCols {
-		_, err = file.WriteString(",our_" + nonPkCol.Name)
-		if err != nil {
-			return errors.Wrap(err)
-		}
-	}
-	for _, pkCol := range mtc.theirs.PKCols {
-		_, err = file.WriteString(",their_" + pkCol.Name)
-		if err != nil {
-			return errors.Wrap(err)
-		}
-	}
-	for _, nonPkCol := range mtc.theirs.NonPKCols {
-		_, err = file.WriteString(",their_" + nonPkCol.Name)
-		if err != nil {
-			return errors.Wrap(err)
-		}
-	}
-	_, err = file.WriteString("\n")
-	if err != nil {
-		return errors.Wrap(err)
-	}
-
-	// Write the rows
-	for _, conflictsRow := range mtc.conflicts {
-		_, err = file.WriteString(conflictsRow.CSVString())
-		if err != nil {
-			return errors.Wrap(err)
-		}
-		_, err = file.WriteString("\n")
-		if err != nil {
-			return errors.Wrap(err)
-		}
-	}
-	if err != nil {
-		return errors.Wrap(err)
-	}
-	return nil
+	return mtc.conflicts.data.ExportToCSV(fmt.Sprintf("%s%s/internal_data/conflicts.csv",
+		c.Planner.Base.Arguments.RepoWorkingPath, c.Name))
 }
 
 // exportShellSetup writes a shell setup file that will import the four tables and conflict data into a Dolt instance.
@@ -841,6 +1065,17 @@ func (mtc mergeTableWithConflicts) exportShellSetup(c *run.Cycle) error {
 	return nil
 }
 
+// conflictColumnNames returns the column names of the conflicts, in the same order as a conflict row.
+func (mtc mergeTableWithConflicts) conflictColumnNames() []string {
+	var names []string
+	for _, prefix := range []string{"base_", "our_", "their_"} {
+		for _, name := range mtc.final.ColumnNames() {
+			names = append(names, prefix+name)
+		}
+	}
+	return names
+}
+
 // createConflictsTable returns a CREATE TABLE string that may be used to import the conflict data.
 func (mtc mergeTableWithConflicts) createConflictsTable() string {
 	sb1 := strings.Builder{}
@@ -871,53 +1106,23 @@ func (mtc mergeTableWithConflicts) createConflictsTable() string {
 
 // Verify verifies that the table merged as expected, including checking for conflicts.
 func (mtc mergeTableWithConflicts) Verify(c *run.Cycle) error {
-	internalCursor, err := mtc.final.Data.GetRowCursor()
-	if err != nil {
-		return errors.Wrap(err)
-	}
-	defer internalCursor.Close()
-	doltCursor, err := mtc.final.GetDoltCursor(c)
-	if err != nil {
-		return errors.Wrap(err)
-	}
-	defer func() {
-		_ = doltCursor.Close()
-	}()
-
-	var iRow run.Row
-	var ok bool
-	for iRow, ok, err = internalCursor.NextRow(); ok && err == nil; iRow, ok, err = internalCursor.NextRow() {
-		dRow, ok, err := doltCursor.NextRow()
-		if !ok {
-			return errors.New(fmt.Sprintf("On table `%s`, internal data contains more rows than Dolt", mtc.final.Name))
-		}
-		if err != nil {
-			return errors.Wrap(err)
-		}
-		if !iRow.Equals(dRow) {
-			return errors.New(fmt.Sprintf("On table `%s`, internal data contains [%s]\nDolt contains [%s]",
-				mtc.final.Name, iRow.MySQLString(), dRow.MySQLString()))
-		}
-	}
-	if err != nil {
-		return errors.Wrap(err)
-	}
-
-	_, ok, err = doltCursor.NextRow()
-	if ok {
-		return errors.New(fmt.Sprintf("On table `%s`, Dolt contains more rows than internal data", mtc.final.Name))
-	}
+	branchName := c.GetCurrentBranch().Name
+	err := run.ValidateTableRows(c, branchName, mtc.final)
 	if err != nil {
 		return errors.Wrap(err)
 	}
-	_ = doltCursor.Close()
 
-	if ok, err = mtc.final.DoltTableHasConflicts(c); err != nil {
+	if ok, err := mtc.final.DoltTableHasConflicts(c); err != nil {
 		return errors.Wrap(err)
 	} else if ok {
-		if len(mtc.conflicts) == 0 {
+		if mtc.conflicts.count == 0 {
 			return errors.New(fmt.Sprintf("On table `%s`, Dolt contains conflicts while internal data does not", mtc.final.Name))
 		}
+		// Dolt reports keyless conflicts using cardinality columns rather than the rows of each side, so only the
+		// presence of conflicts is checked for keyless tables
+		if mtc.final.IsKeyless() {
+			return nil
+		}
 		doltConflictsCursor, err := mtc.final.GetDoltConflictsCursor(c)
 		if err != nil {
 			return errors.Wrap(err)
@@ -925,20 +1130,25 @@ func (mtc mergeTableWithConflicts) Verify(c *run.Cycle) error {
 		defer func() {
 			_ = doltConflictsCursor.Close()
 		}()
-		conflictIdx := 0
-		var dConflictRow run.Row
-		for dConflictRow, ok, err = doltConflictsCursor.NextRow(); ok && err == nil; dConflictRow, ok, err = doltConflictsCursor.NextRow() {
-			if conflictIdx >= len(mtc.conflicts) {
-				return errors.New(fmt.Sprintf("On table `%s`, internal conflicts contain more conflicts than Dolt", mtc.final.Name))
-			}
-			iConflictRow := mtc.conflicts[conflictIdx]
-			conflictIdx++
-			if !iConflictRow.Equals(dConflictRow) {
-				return errors.New(fmt.Sprintf("On table `%s`, internal conflict contains [%s]\nDolt contains [%s]",
-					mtc.final.Name, iConflictRow.MySQLString(), dConflictRow.MySQLString()))
-			}
+		internalConflictsCursor, err := mtc.conflicts.GetCursor()
+		if err != nil {
+			return errors.Wrap(err)
 		}
-	} else if len(mtc.conflicts) > 0 {
+		defer internalConflictsCursor.Close()
+		conflictRecorder := run.NewDivergenceRecorder("dolt_conflicts_"+mtc.final.Name, branchName, mtc.conflictColumnNames())
+		return run.CompareRowIterators(c, conflictRecorder, internalConflictsCursor, doltConflictsCursor,
+			func(kind run.DivergenceKind, iConflictRow run.Row, dConflictRow run.Row) error {
+				switch kind {
+				case run.DivergenceKind_MissingFromDolt:
+					return errors.New(fmt.Sprintf("On table `%s`, internal conflicts contain more conflicts than Dolt", mtc.final.Name))
+				case run.DivergenceKind_MissingFromInternal:
+					return errors.New(fmt.Sprintf("On table `%s`, Dolt contains more conflicts than internal conflicts", mtc.final.Name))
+				default:
+					return errors.New(fmt.Sprintf("On table `%s`, internal conflict contains [%s]\nDolt contains [%s]",
+						mtc.final.Name, iConflictRow.MySQLString(), dConflictRow.MySQLString()))
+				}
+			})
+	} else if mtc.conflicts.count > 0 {
 		return errors.New(fmt.Sprintf("On table `%s`, Dolt does not contain conflicts while internal data does", mtc.final.Name))
 	}
 	return nil
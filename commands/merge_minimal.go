@@ -0,0 +1,283 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run"
+)
+
+// minimalMergeChange is the change that one side of a minimal merge scenario makes to every table.
+type minimalMergeChange byte
+
+const (
+	minimalMergeChange_None           minimalMergeChange = iota // Leaves the table unchanged
+	minimalMergeChange_Add                                      // Adds a row of its own
+	minimalMergeChange_AddShared                                // Adds the same row as the other side
+	minimalMergeChange_AddConflicting                           // Adds a row with the same key as the other side, but a different value
+	minimalMergeChange_Modify                                   // Changes the value of the base row
+	minimalMergeChange_Delete                                   // Deletes the base row
+)
+
+// minimalMergeScenario is a merge of two branches that make at most one change to each table, starting from a base
+// where every table is either empty or holds a single row. These trivial cases are easy to miss when every branch
+// holds many random rows, so they are constructed on every cycle rather than left to chance.
+type minimalMergeScenario struct {
+	name    string
+	baseRow bool
+	ours    minimalMergeChange
+	theirs  minimalMergeChange
+}
+
+// minimalMergeScenarios are all of the scenarios that are tested during every merge cycle.
+var minimalMergeScenarios = []minimalMergeScenario{
+	{"empty base, no changes", false, minimalMergeChange_None, minimalMergeChange_None},
+	{"empty base, ours adds", false, minimalMergeChange_Add, minimalMergeChange_None},
+	{"empty base, theirs adds", false, minimalMergeChange_None, minimalMergeChange_Add},
+	{"empty base, both add different rows", false, minimalMergeChange_Add, minimalMergeChange_Add},
+	{"empty base, both add the same row", false, minimalMergeChange_AddShared, minimalMergeChange_AddShared},
+	{"empty base, both add the same key", false, minimalMergeChange_AddConflicting, minimalMergeChange_AddConflicting},
+	{"single row, no changes", true, minimalMergeChange_None, minimalMergeChange_None},
+	{"single row, ours deletes", true, minimalMergeChange_Delete, minimalMergeChange_None},
+	{"single row, theirs deletes", true, minimalMergeChange_None, minimalMergeChange_Delete},
+	{"single row, both delete", true, minimalMergeChange_Delete, minimalMergeChange_Delete},
+	{"single row, ours modifies", true, minimalMergeChange_Modify, minimalMergeChange_None},
+	{"single row, theirs modifies", true, minimalMergeChange_None, minimalMergeChange_Modify},
+	{"single row, both modify", true, minimalMergeChange_Modify, minimalMergeChange_Modify},
+	{"single row, ours modifies and theirs deletes", true, minimalMergeChange_Modify, minimalMergeChange_Delete},
+	{"single row, ours deletes and theirs modifies", true, minimalMergeChange_Delete, minimalMergeChange_Modify},
+}
+
+// createMinimalMergeScenarios creates the branches of every minimal merge scenario from main, returning the
+// combinations that merge them. Tables with foreign keys are left untouched, as emptying them could violate their
+// constraints.
+func createMinimalMergeScenarios(c *run.Cycle) ([]mergeCombination, error) {
+	emptyBase, err := createMinimalMergeBase(c, "__minimal_empty", false)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	singleRowBase, err := createMinimalMergeBase(c, "__minimal_single", true)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	combinations := make([]mergeCombination, len(minimalMergeScenarios))
+	for i, scenario := range minimalMergeScenarios {
+		base := emptyBase
+		if scenario.baseRow {
+			base = singleRowBase
+		}
+		combinations[i], err = scenario.create(c, base, fmt.Sprintf("__minimal_%d", i))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+	}
+	return combinations, nil
+}
+
+// createMinimalMergeBase creates a branch from main where every table is emptied, and then given a single random row
+// when baseRow is true. The branch is committed, so that it may serve as the base of a minimal merge scenario.
+func createMinimalMergeBase(c *run.Cycle, branchName string, baseRow bool) (string, error) {
+	err := c.SwitchCurrentBranch("main")
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if _, err = c.GetCurrentBranch().Commit(c, false); err != nil {
+		return "", errors.Wrap(err)
+	}
+	if _, err = c.GetCurrentBranch().NewCustomBranch(c, branchName); err != nil {
+		return "", errors.Wrap(err)
+	}
+	if err = c.SwitchCurrentBranch(branchName); err != nil {
+		return "", errors.Wrap(err)
+	}
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if table.HasForeignKeys() {
+			continue
+		}
+		statement := fmt.Sprintf("DELETE FROM `%s`;", table.Name)
+		if err = table.Data.Exec(statement); err != nil {
+			return "", errors.Wrap(err)
+		}
+		if err = c.SqlServer(statement); err != nil {
+			return "", errors.Wrap(err)
+		}
+		if baseRow {
+			row, err := run.NewRow(table)
+			if err != nil {
+				return "", errors.Wrap(err)
+			}
+			if err = applyMinimalMergeChange(c, table, minimalMergeChange_Add, row); err != nil {
+				return "", errors.Wrap(err)
+			}
+		}
+	}
+	if _, err = c.GetCurrentBranch().Commit(c, false); err != nil {
+		return "", errors.Wrap(err)
+	}
+	return branchName, nil
+}
+
+// create creates the branches for both sides of the scenario from the given base branch. Each branch name begins with
+// the given prefix.
+func (s minimalMergeScenario) create(c *run.Cycle, baseBranch string, prefix string) (mergeCombination, error) {
+	err := c.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("Creating minimal merge scenario: %s", s.name))
+	if err != nil {
+		return mergeCombination{}, errors.Wrap(err)
+	}
+	if err = c.SwitchCurrentBranch(baseBranch); err != nil {
+		return mergeCombination{}, errors.Wrap(err)
+	}
+	// Both sides must decide on their rows together, as they may share a row or a key
+	ourRows := make(map[string]run.Row)
+	theirRows := make(map[string]run.Row)
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if table.HasForeignKeys() {
+			continue
+		}
+		ourRows[table.Name], theirRows[table.Name], err = s.newRows(table)
+		if err != nil {
+			return mergeCombination{}, errors.Wrap(err)
+		}
+	}
+	combination := mergeCombination{
+		ours:   prefix + "_ours",
+		theirs: prefix + "_theirs",
+	}
+	sides := []struct {
+		branchName string
+		change     minimalMergeChange
+		rows       map[string]run.Row
+	}{
+		{combination.ours, s.ours, ourRows},
+		{combination.theirs, s.theirs, theirRows},
+	}
+	for _, side := range sides {
+		if err = c.SwitchCurrentBranch(baseBranch); err != nil {
+			return mergeCombination{}, errors.Wrap(err)
+		}
+		if _, err = c.GetCurrentBranch().NewCustomBranch(c, side.branchName); err != nil {
+			return mergeCombination{}, errors.Wrap(err)
+		}
+		if err = c.SwitchCurrentBranch(side.branchName); err != nil {
+			return mergeCombination{}, errors.Wrap(err)
+		}
+		for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+			if table.HasForeignKeys() {
+				continue
+			}
+			if err = applyMinimalMergeChange(c, table, side.change, side.rows[table.Name]); err != nil {
+				return mergeCombination{}, errors.Wrap(err)
+			}
+		}
+		if _, err = c.GetCurrentBranch().Commit(c, false); err != nil {
+			return mergeCombination{}, errors.Wrap(err)
+		}
+	}
+	return combination, nil
+}
+
+// newRows returns the rows that each side writes to the given table, which is read from the base of the scenario.
+// Sides that do not write a row are given an empty row.
+func (s minimalMergeScenario) newRows(table *run.Table) (ours run.Row, theirs run.Row, err error) {
+	var base run.Row
+	if s.baseRow {
+		base, _, err = table.Data.GetRandomRow()
+		if err != nil {
+			return run.Row{}, run.Row{}, errors.Wrap(err)
+		}
+	}
+	shared, err := run.NewRow(table)
+	if err != nil {
+		return run.Row{}, run.Row{}, errors.Wrap(err)
+	}
+	ours, err = s.ours.newRow(table, base, shared)
+	if err != nil {
+		return run.Row{}, run.Row{}, errors.Wrap(err)
+	}
+	theirs, err = s.theirs.newRow(table, base, shared)
+	if err != nil {
+		return run.Row{}, run.Row{}, errors.Wrap(err)
+	}
+	if s.ours == minimalMergeChange_AddConflicting && s.theirs == minimalMergeChange_AddConflicting {
+		theirs, err = shared.NewRowValue(table)
+		if err != nil {
+			return run.Row{}, run.Row{}, errors.Wrap(err)
+		}
+	}
+	return ours, theirs, nil
+}
+
+// newRow returns the row that the change writes, given the base row and the row shared by both sides.
+func (mc minimalMergeChange) newRow(table *run.Table, base run.Row, shared run.Row) (run.Row, error) {
+	switch mc {
+	case minimalMergeChange_None, minimalMergeChange_Delete:
+		return run.Row{}, nil
+	case minimalMergeChange_Add:
+		return run.NewRow(table)
+	case minimalMergeChange_AddShared, minimalMergeChange_AddConflicting:
+		return shared, nil
+	case minimalMergeChange_Modify:
+		return base.NewRowValue(table)
+	default:
+		return run.Row{}, errors.New(fmt.Sprintf("unknown minimal merge change: %d", mc))
+	}
+}
+
+// applyMinimalMergeChange applies the change to both the internal data and Dolt, writing the given row if the change
+// calls for one.
+func applyMinimalMergeChange(c *run.Cycle, table *run.Table, change minimalMergeChange, row run.Row) error {
+	statements, err := change.apply(table, row)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, statement := range statements {
+		if err = c.SqlServer(statement); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// apply applies the change to the table's internal data, returning the statements that apply the same change to Dolt.
+// Changes to the base row expect it to be the only row in the table, so a modification deletes it before inserting
+// the new row, which works the same whether or not the table has a primary key.
+func (mc minimalMergeChange) apply(table *run.Table, row run.Row) ([]string, error) {
+	var internal []string
+	var statements []string
+	switch mc {
+	case minimalMergeChange_None:
+	case minimalMergeChange_Add, minimalMergeChange_AddShared, minimalMergeChange_AddConflicting:
+		internal = append(internal, fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, table.Data.RowString(row)))
+		statements = append(statements, fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, row.ValueListString()))
+	case minimalMergeChange_Modify:
+		internal = append(internal, fmt.Sprintf("DELETE FROM `%s`;", table.Name),
+			fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, table.Data.RowString(row)))
+		statements = append(statements, fmt.Sprintf("DELETE FROM `%s`;", table.Name),
+			fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, row.ValueListString()))
+	case minimalMergeChange_Delete:
+		internal = append(internal, fmt.Sprintf("DELETE FROM `%s`;", table.Name))
+		statements = append(statements, fmt.Sprintf("DELETE FROM `%s`;", table.Name))
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown minimal merge change: %d", mc))
+	}
+	for _, statement := range internal {
+		if err := table.Data.Exec(statement); err != nil {
+			return nil, errors.Wrap(err)
+		}
+	}
+	return statements, nil
+}
@@ -16,6 +16,7 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
@@ -218,3 +219,204 @@ func rowsToInsertString(tableName string, rows []run.Row) string {
 	}
 	return sb.String()
 }
+
+func TestKeylessMerge(t *testing.T) {
+	tableName := "keyless"
+	nonPKCols := []*run.Column{{Name: "v1", Type: &types.IntInstance{}}, {Name: "v2", Type: &types.IntInstance{}}}
+	newRows := func(vals ...int32) []run.Row {
+		rows := make([]run.Row, len(vals))
+		for i, val := range vals {
+			rows[i] = run.Row{Values: []types.Value{types.IntValue{Int32Value: types.Int32Value(val)},
+				types.IntValue{Int32Value: types.Int32Value(val * 10)}}}
+		}
+		return rows
+	}
+	mt := &mergeTables{
+		tableName: tableName,
+		ours:      mustTable(t, nil, tableName, nil, nonPKCols, nil),
+		theirs:    mustTable(t, nil, tableName, nil, nonPKCols, nil),
+		base:      mustTable(t, nil, tableName, nil, nonPKCols, nil),
+		final:     nil,
+	}
+	// Row 1 and 3 have their counts changed differently on both sides, so they conflict and keep our count. Row 2 is
+	// unchanged, 4 and 5 are each added on one side, 6 is deleted on ours, and 7 is added identically on both sides.
+	require.NoError(t, mt.base.Data.Exec(rowsToInsertString(tableName, newRows(1, 1, 2, 3, 6))))
+	require.NoError(t, mt.ours.Data.Exec(rowsToInsertString(tableName, newRows(1, 2, 3, 3, 4, 7, 7))))
+	require.NoError(t, mt.theirs.Data.Exec(rowsToInsertString(tableName, newRows(1, 1, 1, 2, 5, 6, 7, 7))))
+	mtc, err := mt.ProcessMerge()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), mtc.conflicts.count)
+	conflictsCursor, err := mtc.conflicts.GetCursor()
+	require.NoError(t, err)
+	defer conflictsCursor.Close()
+	var previous run.Row
+	for i := 0; i < 2; i++ {
+		conflict, ok, err := conflictsCursor.NextRow()
+		require.NoError(t, err)
+		require.True(t, ok)
+		if i > 0 { // Conflicts are returned in ascending order
+			require.Equal(t, -1, previous.Compare(conflict))
+		}
+		previous = conflict
+	}
+	_, ok, err := conflictsCursor.NextRow()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	finalCursor, err := mtc.final.Data.GetRowCursor()
+	require.NoError(t, err)
+	defer finalCursor.Close()
+	finalCounts, err := run.NewRowCountCursor(mtc.final.Name, mtc.final.PKCols, mtc.final.NonPKCols, finalCursor)
+	require.NoError(t, err)
+	defer finalCounts.Close()
+	// Distinct rows are returned in ascending order, along with the number of times that they appear
+	for _, expected := range []struct {
+		val   int32
+		count int64
+	}{{1, 1}, {2, 1}, {3, 2}, {4, 1}, {5, 1}, {7, 2}} {
+		row, counts, ok, err := finalCounts.NextRow()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.True(t, newRows(expected.val)[0].Equals(row), "row %d", expected.val)
+		require.Equal(t, []int64{expected.count}, counts, "row %d", expected.val)
+	}
+	_, _, ok, err = finalCounts.NextRow()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestSampleMergeCombinations(t *testing.T) {
+	var combinations []mergeCombination
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			if i != j {
+				combinations = append(combinations, mergeCombination{ours: fmt.Sprint(i), theirs: fmt.Sprint(j)})
+			}
+		}
+	}
+	all := make(map[mergeCombination]bool)
+	for _, combination := range combinations {
+		all[combination] = true
+	}
+
+	sampled, err := sampleMergeCombinations(&run.Cycle{}, combinations, 7)
+	require.NoError(t, err)
+	require.Len(t, sampled, 7)
+	seen := make(map[mergeCombination]bool)
+	for _, combination := range sampled {
+		require.True(t, all[combination])
+		require.False(t, seen[combination])
+		seen[combination] = true
+	}
+
+	sampled, err = sampleMergeCombinations(&run.Cycle{}, combinations[:3], 7)
+	require.NoError(t, err)
+	require.Len(t, sampled, 3)
+}
+
+func TestMinimalMergeScenarios(t *testing.T) {
+	newRow := func(pkColsLen int32, key int32, val int32) run.Row {
+		return run.Row{Values: []types.Value{types.IntValue{Int32Value: types.Int32Value(key)},
+			types.IntValue{Int32Value: types.Int32Value(val)}}, PkColsLen: pkColsLen}
+	}
+	// The rows that each side writes, mirroring minimalMergeScenario.newRows with fixed values. Rows are written as
+	// {key, value}, with the base row being {1, 10}.
+	sideRow := func(change minimalMergeChange, ours bool) [2]int32 {
+		switch change {
+		case minimalMergeChange_Add:
+			if ours {
+				return [2]int32{3, 30}
+			}
+			return [2]int32{4, 40}
+		case minimalMergeChange_AddShared:
+			return [2]int32{2, 20}
+		case minimalMergeChange_AddConflicting:
+			if ours {
+				return [2]int32{2, 20}
+			}
+			return [2]int32{2, 21}
+		case minimalMergeChange_Modify:
+			if ours {
+				return [2]int32{1, 11}
+			}
+			return [2]int32{1, 12}
+		}
+		return [2]int32{}
+	}
+	type expectation struct {
+		rows      [][2]int32
+		conflicts int
+	}
+	expected := map[string]struct {
+		keyed   expectation
+		keyless expectation
+	}{
+		"empty base, no changes":                       {expectation{nil, 0}, expectation{nil, 0}},
+		"empty base, ours adds":                        {expectation{[][2]int32{{3, 30}}, 0}, expectation{[][2]int32{{3, 30}}, 0}},
+		"empty base, theirs adds":                      {expectation{[][2]int32{{4, 40}}, 0}, expectation{[][2]int32{{4, 40}}, 0}},
+		"empty base, both add different rows":          {expectation{[][2]int32{{3, 30}, {4, 40}}, 0}, expectation{[][2]int32{{3, 30}, {4, 40}}, 0}},
+		"empty base, both add the same row":            {expectation{[][2]int32{{2, 20}}, 0}, expectation{[][2]int32{{2, 20}}, 0}},
+		"empty base, both add the same key":            {expectation{[][2]int32{{2, 20}}, 1}, expectation{[][2]int32{{2, 20}, {2, 21}}, 0}},
+		"single row, no changes":                       {expectation{[][2]int32{{1, 10}}, 0}, expectation{[][2]int32{{1, 10}}, 0}},
+		"single row, ours deletes":                     {expectation{nil, 0}, expectation{nil, 0}},
+		"single row, theirs deletes":                   {expectation{nil, 0}, expectation{nil, 0}},
+		"single row, both delete":                      {expectation{nil, 0}, expectation{nil, 0}},
+		"single row, ours modifies":                    {expectation{[][2]int32{{1, 11}}, 0}, expectation{[][2]int32{{1, 11}}, 0}},
+		"single row, theirs modifies":                  {expectation{[][2]int32{{1, 12}}, 0}, expectation{[][2]int32{{1, 12}}, 0}},
+		"single row, both modify":                      {expectation{[][2]int32{{1, 11}}, 1}, expectation{[][2]int32{{1, 11}, {1, 12}}, 0}},
+		"single row, ours modifies and theirs deletes": {expectation{[][2]int32{{1, 11}}, 1}, expectation{[][2]int32{{1, 11}}, 0}},
+		"single row, ours deletes and theirs modifies": {expectation{nil, 1}, expectation{[][2]int32{{1, 12}}, 0}},
+	}
+	require.Len(t, expected, len(minimalMergeScenarios))
+
+	for _, scenario := range minimalMergeScenarios {
+		for _, keyless := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s, keyless %t", scenario.name, keyless), func(t *testing.T) {
+				tableName := "minimal"
+				pkCols := []*run.Column{{Name: "pk", Type: &types.IntInstance{}}}
+				nonPKCols := []*run.Column{{Name: "v1", Type: &types.IntInstance{}}}
+				pkColsLen := int32(1)
+				exp := expected[scenario.name].keyed
+				if keyless {
+					nonPKCols = append(pkCols, nonPKCols...)
+					pkCols = nil
+					pkColsLen = 0
+					exp = expected[scenario.name].keyless
+				}
+				mt := &mergeTables{
+					tableName: tableName,
+					ours:      mustTable(t, nil, tableName, pkCols, nonPKCols, nil),
+					theirs:    mustTable(t, nil, tableName, pkCols, nonPKCols, nil),
+					base:      mustTable(t, nil, tableName, pkCols, nonPKCols, nil),
+				}
+				if scenario.baseRow {
+					for _, table := range []*run.Table{mt.base, mt.ours, mt.theirs} {
+						_, err := minimalMergeChange_Add.apply(table, newRow(pkColsLen, 1, 10))
+						require.NoError(t, err)
+					}
+				}
+				ourRow := sideRow(scenario.ours, true)
+				_, err := scenario.ours.apply(mt.ours, newRow(pkColsLen, ourRow[0], ourRow[1]))
+				require.NoError(t, err)
+				theirRow := sideRow(scenario.theirs, false)
+				_, err = scenario.theirs.apply(mt.theirs, newRow(pkColsLen, theirRow[0], theirRow[1]))
+				require.NoError(t, err)
+
+				mtc, err := mt.ProcessMerge()
+				require.NoError(t, err)
+				require.Equal(t, int64(exp.conflicts), mtc.conflicts.count)
+				allRows, err := mtc.final.Data.GetAllRows()
+				require.NoError(t, err)
+				require.Len(t, allRows, len(exp.rows))
+				// Keyless rows are not returned in the order of their values
+				sort.Slice(allRows, func(i, j int) bool {
+					return allRows[i].Compare(allRows[j]) == -1
+				})
+				for i, row := range allRows {
+					require.True(t, newRow(pkColsLen, exp.rows[i][0], exp.rows[i][1]).Equals(row),
+						"expected [%d,%d], got [%s]", exp.rows[i][0], exp.rows[i][1], row.MySQLString())
+				}
+			})
+		}
+	}
+}
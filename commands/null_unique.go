@@ -0,0 +1,190 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const nullUniqueStatementsParam = "statements"
+
+// NullUnique handles testing that unique indexes permit any number of NULLs, while rejecting duplicate values.
+type NullUnique struct {
+	statementCount    uint64
+	remainingBranches []string
+}
+
+var _ Command = (*NullUnique)(nil)
+var _ CommandMetadata = (*NullUnique)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&NullUnique{})
+}
+
+// Name implements the interface Command.
+func (nu *NullUnique) Name() string {
+	return "null-unique"
+}
+
+// Description implements the interface Command.
+func (nu *NullUnique) Description() string {
+	return "Tests that unique indexes permit multiple NULLs while rejecting duplicate values."
+}
+
+// Category implements the interface CommandMetadata.
+func (nu *NullUnique) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (nu *NullUnique) LongDescription() string {
+	return `This command verifies that a UNIQUE index permits any number of rows holding NULL, as NULLs are never equal
+to each other, while still rejecting duplicate non-NULL values. On every branch, a random table with a primary key is
+given a new nullable column, which every existing row holds as NULL, before a UNIQUE index is created over it. Rows are
+then inserted with NULL in the column, while existing rows are set to distinct values or back to NULL. Rows are also set
+to a value held by another row, which must be rejected. The internal data holds the same index, and the table must match
+it throughout. The index and column are dropped once the branch has been tested.`
+}
+
+// ParseArgs implements the interface Command.
+func (nu *NullUnique) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that unique indexes permit multiple NULLs while rejecting duplicate values",
+		LongDesc:  nu.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(nullUniqueStatementsParam, "", "count", "The number of statements run on each branch. Defaults to 50.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	nu.statementCount = 50
+	if statements, ok := apr.GetUint(nullUniqueStatementsParam); ok {
+		nu.statementCount = statements
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (nu *NullUnique) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (nu *NullUnique) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(nu.Reset)
+	hooks.RepositoryFinished(nu.BeginNullUnique)
+}
+
+// Reset resets the state of NullUnique.
+func (nu *NullUnique) Reset(c *run.Cycle) error {
+	nu.remainingBranches = nil
+	return nil
+}
+
+// BeginNullUnique starts the NULL uniqueness process.
+func (nu *NullUnique) BeginNullUnique(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning NULL Unique Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	nu.remainingBranches = c.GetBranchNames()
+	c.QueueAction(nu.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and tests a unique index over a nullable column on one of its tables.
+func (nu *NullUnique) Run(c *run.Cycle) error {
+	if len(nu.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := nu.remainingBranches[0]
+	nu.remainingBranches = nu.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	var candidates []*run.Table
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if !table.IsKeyless() && !table.HasForeignKeys() {
+			candidates = append(candidates, table)
+		}
+	}
+	if len(candidates) > 0 {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = nu.test(c, candidates[roll%uint64(len(candidates))]); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	c.QueueAction(nu.Run)
+	return nil
+}
+
+// test adds a unique index over a new nullable column to the table, and runs statements that write NULLs, distinct
+// values, and duplicate values to the column.
+func (nu *NullUnique) test(c *run.Cycle, table *run.Table) error {
+	nullUnique, err := run.NewNullUniqueIndex(c, table)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = c.ValidateCurrentBranch(); err != nil {
+		return errors.New(fmt.Sprintf("After creating unique index `%s`: %s", nullUnique.Index.Name, err.Error()))
+	}
+	for i := uint64(0); i < nu.statementCount; i++ {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		switch roll % 4 {
+		case 0:
+			err = nullUnique.InsertNull(c)
+		case 1:
+			err = nullUnique.SetDistinct(c)
+		case 2:
+			err = nullUnique.SetNull(c)
+		default:
+			err = nullUnique.Collide(c)
+		}
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	nullCount, err := nullUnique.NullCount()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	err = c.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("Unique index `%s` on table `%s` holds %d NULLs",
+		nullUnique.Index.Name, table.Name, nullCount))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = c.ValidateCurrentBranch(); err != nil {
+		return errors.New(fmt.Sprintf("With unique index `%s`: %s", nullUnique.Index.Name, err.Error()))
+	}
+	if err = nullUnique.Drop(c); err != nil {
+		return errors.Wrap(err)
+	}
+	return c.ValidateCurrentBranch()
+}
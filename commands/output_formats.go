@@ -0,0 +1,125 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+// OutputFormats handles testing that every result format of `dolt sql` represents the same rows.
+type OutputFormats struct {
+	remainingBranches []string
+}
+
+var _ Command = (*OutputFormats)(nil)
+var _ CommandMetadata = (*OutputFormats)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&OutputFormats{})
+}
+
+// Name implements the interface Command.
+func (of *OutputFormats) Name() string {
+	return "output-formats"
+}
+
+// Description implements the interface Command.
+func (of *OutputFormats) Description() string {
+	return "Tests that every result format of dolt sql represents the same rows."
+}
+
+// Category implements the interface CommandMetadata.
+func (of *OutputFormats) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (of *OutputFormats) LongDescription() string {
+	return `This command verifies that the result formats of "dolt sql -q" agree with each other, which catches bugs in
+how individual formats serialize values. Once the repository has been generated, every table on every branch is read
+using the "json", "csv", and "tabular" formats, and each value from CSV and tabular output must match the JSON output.
+Each format is only held to what it can represent: CSV cannot distinguish NULL from an empty string, and tabular output
+cannot distinguish NULL from the string "NULL", nor keep whitespace at either end of a value. Numbers match if they
+represent the same number. Binary, bit, and spatial columns are not compared, and tabular output is skipped for tables
+with values that do not line up with the grid, such as values with line breaks.`
+}
+
+// ParseArgs implements the interface Command.
+func (of *OutputFormats) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that every result format of dolt sql represents the same rows",
+		LongDesc:  of.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	_ = cli.ParseArgsOrDie(ap, args, help)
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (of *OutputFormats) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (of *OutputFormats) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(of.Reset)
+	hooks.RepositoryFinished(of.BeginOutputFormats)
+}
+
+// Reset resets the state of OutputFormats.
+func (of *OutputFormats) Reset(c *run.Cycle) error {
+	of.remainingBranches = nil
+	return nil
+}
+
+// BeginOutputFormats starts the output format testing process.
+func (of *OutputFormats) BeginOutputFormats(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Output Format Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	of.remainingBranches = c.GetBranchNames()
+	c.QueueAction(of.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and compares the output formats of every table on it.
+func (of *OutputFormats) Run(c *run.Cycle) error {
+	if len(of.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := of.remainingBranches[0]
+	of.remainingBranches = of.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if err := run.ValidateOutputFormats(c, table); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	c.QueueAction(of.Run)
+	return nil
+}
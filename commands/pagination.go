@@ -0,0 +1,162 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const paginationPageSizeParam = "page-size"
+
+// Pagination handles testing that paging through a table with LIMIT and OFFSET returns every row exactly once.
+type Pagination struct {
+	pageSize          uint64
+	remainingBranches []string
+}
+
+var _ Command = (*Pagination)(nil)
+var _ CommandMetadata = (*Pagination)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Pagination{})
+}
+
+// Name implements the interface Command.
+func (p *Pagination) Name() string {
+	return "pagination"
+}
+
+// Description implements the interface Command.
+func (p *Pagination) Description() string {
+	return "Tests that paging through tables with LIMIT and OFFSET returns every row exactly once, in order."
+}
+
+// Category implements the interface CommandMetadata.
+func (p *Pagination) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (p *Pagination) LongDescription() string {
+	return `This command verifies that reading a table one page at a time, using LIMIT and OFFSET along with an ORDER BY
+over the primary key, returns the same rows as a full scan. Once the repository has been generated, every table with a
+primary key on every branch is paged through from the first row until a page holds fewer rows than the page size. The
+concatenation of all pages must match the internal data exactly, in primary key order, so any row that is skipped
+between pages, or returned by two pages, is reported along with the page that it came from.`
+}
+
+// ParseArgs implements the interface Command.
+func (p *Pagination) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that paging through tables with LIMIT and OFFSET returns every row exactly once, in order",
+		LongDesc:  p.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(paginationPageSizeParam, "", "count",
+		"The number of rows in each page. Defaults to 0, which chooses a random page size for each table.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	p.pageSize = 0
+	if pageSize, ok := apr.GetUint(paginationPageSizeParam); ok {
+		p.pageSize = pageSize
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (p *Pagination) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (p *Pagination) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(p.Reset)
+	hooks.RepositoryFinished(p.BeginPagination)
+}
+
+// Reset resets the state of Pagination.
+func (p *Pagination) Reset(c *run.Cycle) error {
+	p.remainingBranches = nil
+	return nil
+}
+
+// BeginPagination starts the pagination testing process.
+func (p *Pagination) BeginPagination(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Pagination Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	p.remainingBranches = c.GetBranchNames()
+	c.QueueAction(p.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and pages through every table on it.
+func (p *Pagination) Run(c *run.Cycle) error {
+	if len(p.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := p.remainingBranches[0]
+	p.remainingBranches = p.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		pageSize, err := p.tablePageSize(table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		err = c.Logger.WriteLine(run.LogType_INFO,
+			fmt.Sprintf("Paging through table `%s` with a page size of %d", table.Name, pageSize))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = run.ValidatePagination(c, branchName, table, pageSize); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	c.QueueAction(p.Run)
+	return nil
+}
+
+// tablePageSize returns the page size used for the given table. Without a page size from the arguments, a random size
+// is chosen between 1 and the table's row count, so that most tables are read over several pages.
+func (p *Pagination) tablePageSize(table *run.Table) (uint64, error) {
+	if p.pageSize > 0 {
+		return p.pageSize, nil
+	}
+	rowCount, err := table.Data.GetRowCount()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	if rowCount <= 1 {
+		return 1, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	return (roll % uint64(rowCount)) + 1, nil
+}
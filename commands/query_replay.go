@@ -0,0 +1,146 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const (
+	queryReplayQueriesParam = "queries"
+)
+
+// QueryReplay handles testing of read queries, using the internal data as an oracle.
+type QueryReplay struct {
+	queriesPerTable   uint64
+	remainingBranches []string
+}
+
+var _ Command = (*QueryReplay)(nil)
+var _ CommandMetadata = (*QueryReplay)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&QueryReplay{})
+}
+
+// Name implements the interface Command.
+func (q *QueryReplay) Name() string {
+	return "query-replay"
+}
+
+// Description implements the interface Command.
+func (q *QueryReplay) Description() string {
+	return "Tests dolt's query engine using random SELECT statements."
+}
+
+// Category implements the interface CommandMetadata.
+func (q *QueryReplay) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (q *QueryReplay) LongDescription() string {
+	return `This command runs random SELECT statements against every table on every branch once the repository has been
+generated. Each query is also run against the fuzzer's internal data, which is stored in SQLite, and the results of both
+must match. Queries contain WHERE clauses, ORDER BY clauses with LIMIT and OFFSET, and the aggregates COUNT, SUM, MIN,
+and MAX. Only integer columns are filtered, ordered, and aggregated, as strings and floating-point values behave
+differently between MySQL and SQLite.`
+}
+
+// ParseArgs implements the interface Command.
+func (q *QueryReplay) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests dolt's query engine using random SELECT statements",
+		LongDesc:  q.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(queryReplayQueriesParam, "", "count", "The number of queries to run against each table. Defaults to 100.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	q.queriesPerTable = 100
+	if queries, ok := apr.GetUint(queryReplayQueriesParam); ok {
+		q.queriesPerTable = queries
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (q *QueryReplay) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (q *QueryReplay) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(q.Reset)
+	hooks.RepositoryFinished(q.BeginQueryReplay)
+}
+
+// Reset resets the state of QueryReplay.
+func (q *QueryReplay) Reset(c *run.Cycle) error {
+	q.remainingBranches = nil
+	return nil
+}
+
+// BeginQueryReplay starts the query replay process.
+func (q *QueryReplay) BeginQueryReplay(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Query Replay: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	q.remainingBranches = c.GetBranchNames()
+	c.QueueAction(q.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch and runs queries against each of its tables.
+func (q *QueryReplay) Run(c *run.Cycle) error {
+	if len(q.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := q.remainingBranches[0]
+	q.remainingBranches = q.remainingBranches[1:]
+
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf(`Running queries on "%s": %s`, branchName, time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	err = c.SwitchCurrentBranch(branchName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		for i := uint64(0); i < q.queriesPerTable; i++ {
+			query, err := run.NewQuery(table)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			err = table.CompareQuery(c, query)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+	c.QueueAction(q.Run)
+	return nil
+}
@@ -50,6 +50,7 @@ type ReplayValidate struct {
 }
 
 var _ Command = (*ReplayValidate)(nil)
+var _ CommandMetadata = (*ReplayValidate)(nil)
 
 // init adds the command to the map.
 func init() {
@@ -72,14 +73,24 @@ func (rv *ReplayValidate) Description() string {
 	return "Replays a log file to validate data."
 }
 
+// Category implements the interface CommandMetadata.
+func (rv *ReplayValidate) Category() CommandCategory {
+	return CommandCategory_Replay
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (rv *ReplayValidate) LongDescription() string {
+	return `This command takes a log file and a folder location containing internal data that was output from a
+previous failure, and replays the log file to check if the operations now produce the expected result when compared to
+the internal data.`
+}
+
 // ParseArgs implements the interface Command.
 func (rv *ReplayValidate) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
 	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
 		ShortDesc: "Replays a log file to validate data.",
-		LongDesc: `This command takes a log file and a folder location containing internal data that was output from a
-previous failure, and replays the log file to check if the operations now produce the expected result when compared to
-the internal data.`,
-		Synopsis: nil,
+		LongDesc:  rv.LongDescription(),
+		Synopsis:  nil,
 	}, ap))
 	ap.SupportsString(logfileParam, "", "location", "The log file to read.")
 	ap.SupportsString(dataParam, "", "location",
@@ -142,8 +153,11 @@ func (rv *ReplayValidate) MainLoop(c *run.Cycle) error {
 		if lineContents == "dolt init" {
 			break
 		}
-		args := strings.Split(lineContents[5:], " ")
-		_, err := c.CliQuery(args...)
+		args, err := run.ParseCliArgs(lineContents[5:])
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		_, err = c.CliQuery(args...)
 		if err != nil {
 			return errors.Wrap(err)
 		}
@@ -219,7 +233,8 @@ func (rv *ReplayValidate) Validate(c *run.Cycle) error {
 			}()
 			dataScanner := bufio.NewScanner(dataFile)
 			dataScanner.Buffer(fileBuffer, replayBufferSize)
-			_ = dataScanner.Scan() // First line will always contain the column names, so we skip it
+			dataScanner.Split(run.ScanCSVRecords)
+			_ = dataScanner.Scan() // First record will always contain the column names, so we skip it
 
 			tableName := entry.Name()[:len(entry.Name())-4]
 			table := c.GetCurrentBranch().GetWorkingSet().GetTable(tableName)
@@ -234,6 +249,10 @@ func (rv *ReplayValidate) Validate(c *run.Cycle) error {
 				_ = doltCursor.Close()
 			}()
 
+			// Keyless tables are not returned in a comparable order, so Dolt's rows are ordered as the CSV was written
+			if table.IsKeyless() {
+				return validateKeylessCSV(table, dataScanner, doltCursor)
+			}
 			for dataScanner.Scan() {
 				csvRow := dataScanner.Text()
 				dRow, ok, err := doltCursor.NextRow()
@@ -245,8 +264,8 @@ func (rv *ReplayValidate) Validate(c *run.Cycle) error {
 				}
 				dRowCSVString := dRow.CSVString()
 				if csvRow != dRowCSVString {
-					return errors.New(fmt.Sprintf("On table `%s`, internal data contains [%s]\nDolt contains [%s]",
-						table.Name, csvRow, dRowCSVString))
+					return errors.New(fmt.Sprintf("On table `%s`, internal data contains [%s]\nDolt contains [%s]%s",
+						table.Name, csvRow, dRowCSVString, describeCSVNullMismatches(table.ColumnNames(), csvRow, dRowCSVString)))
 				}
 			}
 			return nil
@@ -277,3 +296,85 @@ func (rv *ReplayValidate) CycleEnded(c *run.Cycle) error {
 	rv.logfileScanner = nil
 	return nil
 }
+
+// validateKeylessCSV validates that the CSV rows from the scanner, which were written in the order of
+// TableData.GetFullyOrderedCursor, match the Dolt cursor's rows once they have been ordered in the same way.
+func validateKeylessCSV(table *run.Table, dataScanner *bufio.Scanner, doltCursor *run.DoltDataCursor) error {
+	doltData, err := run.CreateTableData(table.Name, table.PKCols, table.NonPKCols)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer doltData.Close()
+	if err = doltData.InsertRows(doltCursor); err != nil {
+		return errors.Wrap(err)
+	}
+	doltOrdered, err := doltData.GetFullyOrderedCursor()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer doltOrdered.Close()
+	for dataScanner.Scan() {
+		csvRow := dataScanner.Text()
+		dRow, ok, err := doltOrdered.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if !ok {
+			return errors.New(fmt.Sprintf("On table `%s`, internal data contains more rows than Dolt", table.Name))
+		}
+		if dRowCSVString := dRow.CSVString(); csvRow != dRowCSVString {
+			return errors.New(fmt.Sprintf("On table `%s`, internal data contains [%s]\nDolt contains [%s]%s",
+				table.Name, csvRow, dRowCSVString, describeCSVNullMismatches(table.ColumnNames(), csvRow, dRowCSVString)))
+		}
+	}
+	if _, ok, err := doltOrdered.NextRow(); err != nil {
+		return errors.Wrap(err)
+	} else if ok {
+		return errors.New(fmt.Sprintf("On table `%s`, Dolt contains more rows than internal data", table.Name))
+	}
+	return nil
+}
+
+// describeCSVNullMismatches describes each column where one CSV row is NULL while the other holds an empty string. In
+// the CSV files, NULL is the only value that is written as an empty field, as every string is written within quotes,
+// so an empty string is always written as "". Returns an empty string when there are no such columns.
+func describeCSVNullMismatches(columns []string, internalCSVRow string, doltCSVRow string) string {
+	internalFields := splitCSVRow(internalCSVRow)
+	doltFields := splitCSVRow(doltCSVRow)
+	if len(internalFields) != len(columns) || len(doltFields) != len(columns) {
+		return ""
+	}
+	sb := strings.Builder{}
+	for i, colName := range columns {
+		if internalFields[i] == "" && doltFields[i] == `""` {
+			sb.WriteString(fmt.Sprintf("\nColumn `%s` is NULL in the internal data but an empty string in Dolt", colName))
+		} else if internalFields[i] == `""` && doltFields[i] == "" {
+			sb.WriteString(fmt.Sprintf("\nColumn `%s` is an empty string in the internal data but NULL in Dolt", colName))
+		}
+	}
+	return sb.String()
+}
+
+// splitCSVRow splits a row written by Row.CSVString into its fields, keeping the quotes of each quoted field. Commas
+// within quotes do not split the field, and a doubled quote within quotes is an escaped quote.
+func splitCSVRow(csvRow string) []string {
+	var fields []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(csvRow); i++ {
+		switch csvRow[i] {
+		case '"':
+			if inQuotes && i+1 < len(csvRow) && csvRow[i+1] == '"' {
+				i++
+			} else {
+				inQuotes = !inQuotes
+			}
+		case ',':
+			if !inQuotes {
+				fields = append(fields, csvRow[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(fields, csvRow[start:])
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCSVRow(t *testing.T) {
+	require.Equal(t, []string{"1", "", `""`, `"a,b"`, `"say ""hi"""`}, splitCSVRow(`1,,"","a,b","say ""hi"""`))
+	require.Equal(t, []string{""}, splitCSVRow(""))
+
+	columns := []string{"pk", "v1", "v2"}
+	require.Equal(t, "\nColumn `v1` is NULL in the internal data but an empty string in Dolt",
+		describeCSVNullMismatches(columns, `1,,"x"`, `1,"","x"`))
+	require.Equal(t, "\nColumn `v2` is an empty string in the internal data but NULL in Dolt",
+		describeCSVNullMismatches(columns, `1,"a,b",""`, `1,"a,b",`))
+	require.Empty(t, describeCSVNullMismatches(columns, `1,"a","b"`, `1,"a","c"`))
+}
@@ -0,0 +1,295 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const (
+	snapshotRoundsParam     = "rounds"
+	snapshotStatementsParam = "statements"
+)
+
+// Snapshot handles testing that a reader's open transaction keeps seeing the same snapshot while another session writes.
+type Snapshot struct {
+	roundCount      uint64
+	statementCount  uint64
+	remainingRounds uint64
+}
+
+var _ Command = (*Snapshot)(nil)
+var _ CommandMetadata = (*Snapshot)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Snapshot{})
+}
+
+// Name implements the interface Command.
+func (s *Snapshot) Name() string {
+	return "snapshot"
+}
+
+// Description implements the interface Command.
+func (s *Snapshot) Description() string {
+	return "Tests that readers see a consistent snapshot while another session writes."
+}
+
+// Category implements the interface CommandMetadata.
+func (s *Snapshot) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (s *Snapshot) LongDescription() string {
+	return `This command verifies Dolt's snapshot isolation once the repository has been generated. Each round chooses a
+random table, and opens a transaction on a reader session that reads the whole table, which must match the internal
+data. A writer session then runs random statements against the table, each of which commits immediately. The reader
+reads the table again halfway through the writes and once they have finished, and must see the exact same rows as its
+first read. Once the reader commits, it must see every write. Tables with foreign keys are skipped, as the writer
+session always enforces them.`
+}
+
+// ParseArgs implements the interface Command.
+func (s *Snapshot) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that readers see a consistent snapshot while another session writes",
+		LongDesc:  s.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(snapshotRoundsParam, "", "count", "The number of rounds, each with its own reader. Defaults to 10.")
+	ap.SupportsUint(snapshotStatementsParam, "", "count", "The number of statements written during each round. Defaults to 20.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	s.roundCount = 10
+	if rounds, ok := apr.GetUint(snapshotRoundsParam); ok {
+		s.roundCount = rounds
+	}
+	s.statementCount = 20
+	if statements, ok := apr.GetUint(snapshotStatementsParam); ok {
+		s.statementCount = statements
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (s *Snapshot) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (s *Snapshot) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(s.Reset)
+	hooks.RepositoryFinished(s.BeginSnapshot)
+}
+
+// Reset resets the state of Snapshot.
+func (s *Snapshot) Reset(c *run.Cycle) error {
+	s.remainingRounds = 0
+	return nil
+}
+
+// BeginSnapshot starts the rounds of snapshot testing.
+func (s *Snapshot) BeginSnapshot(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Snapshot Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	s.remainingRounds = s.roundCount
+	c.QueueAction(s.Round)
+	return nil
+}
+
+// Round runs a single reader against a random table while the writer modifies it. Once all rounds have finished, the
+// current branch is validated.
+func (s *Snapshot) Round(c *run.Cycle) error {
+	if s.remainingRounds == 0 {
+		return c.ValidateCurrentBranch()
+	}
+	s.remainingRounds--
+	table, err := s.chooseTable(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if table == nil {
+		return c.Logger.WriteLine(run.LogType_INFO, "Snapshot: no table without foreign keys has a primary key, skipping")
+	}
+
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	reader, err := dc.NewSession(context.Background())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	writer, err := dc.NewSession(context.Background())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer func() {
+		_ = writer.Close()
+	}()
+
+	if err = s.exec(c, "Reader", reader, "START TRANSACTION;"); err != nil {
+		return errors.Wrap(err)
+	}
+	snapshot, err := s.read(c, table, reader)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = s.compareInternal(c, table, snapshot, "before any writes"); err != nil {
+		return errors.Wrap(err)
+	}
+
+	for i := uint64(0); i < s.statementCount; i++ {
+		statement, err := c.NewStatement(table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = s.exec(c, "Writer", writer, statement); err != nil {
+			return errors.Wrap(err)
+		}
+		if i == s.statementCount/2 || i == s.statementCount-1 {
+			if err = s.compareSnapshot(c, table, reader, snapshot, i+1); err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+
+	if err = s.exec(c, "Reader", reader, "COMMIT;"); err != nil {
+		return errors.Wrap(err)
+	}
+	rows, err := s.read(c, table, reader)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = s.compareInternal(c, table, rows, "after the reader committed"); err != nil {
+		return errors.Wrap(err)
+	}
+	c.QueueAction(s.Round)
+	return nil
+}
+
+// chooseTable returns a random table on the current branch that has a primary key and no foreign keys. Returns nil if
+// no table qualifies.
+func (s *Snapshot) chooseTable(c *run.Cycle) (*run.Table, error) {
+	var candidates []*run.Table
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if !table.IsKeyless() && !table.HasForeignKeys() {
+			candidates = append(candidates, table)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return candidates[roll%uint64(len(candidates))], nil
+}
+
+// read returns every row of the table as seen by the session, in primary key order.
+func (s *Snapshot) read(c *run.Cycle, table *run.Table, session *sql.Conn) ([]run.Row, error) {
+	err := c.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("Reader: reading table `%s`", table.Name))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	cursor, err := table.GetDoltSessionCursor(session)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return readDoltRows(cursor)
+}
+
+// compareInternal returns an error if the rows read by the reader differ from the internal data. The moment describes
+// when the rows were read.
+func (s *Snapshot) compareInternal(c *run.Cycle, table *run.Table, rows []run.Row, moment string) error {
+	internalCursor, err := table.Data.GetRowCursor()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer internalCursor.Close()
+	recorder := run.NewDivergenceRecorder(table.Name, c.GetCurrentBranch().Name, table.ColumnNames())
+	return run.CompareRowIterators(c, recorder, internalCursor, run.NewRowSliceIterator(rows),
+		func(kind run.DivergenceKind, iRow run.Row, dRow run.Row) error {
+			switch kind {
+			case run.DivergenceKind_MissingFromDolt:
+				return errors.New(fmt.Sprintf("On table `%s` %s, internal data contains more rows than the reader",
+					table.Name, moment))
+			case run.DivergenceKind_MissingFromInternal:
+				return errors.New(fmt.Sprintf("On table `%s` %s, the reader contains more rows than internal data",
+					table.Name, moment))
+			default:
+				return errors.New(fmt.Sprintf("On table `%s` %s, internal data contains [%s]\nThe reader contains [%s]",
+					table.Name, moment, iRow.MySQLString(), dRow.MySQLString()))
+			}
+		})
+}
+
+// compareSnapshot reads the table using the reader, and returns an error if the rows differ from the reader's
+// snapshot. The written count is the number of statements that the writer has committed so far.
+func (s *Snapshot) compareSnapshot(c *run.Cycle, table *run.Table, reader *sql.Conn, snapshot []run.Row,
+	written uint64) error {
+	rows, err := s.read(c, table, reader)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	recorder := run.NewDivergenceRecorder(table.Name, c.GetCurrentBranch().Name, table.ColumnNames())
+	return run.CompareRowIterators(c, recorder, run.NewRowSliceIterator(snapshot), run.NewRowSliceIterator(rows),
+		func(kind run.DivergenceKind, sRow run.Row, rRow run.Row) error {
+			switch kind {
+			case run.DivergenceKind_MissingFromDolt:
+				return errors.New(fmt.Sprintf("On table `%s`, the reader lost a row from its snapshot after %d writes "+
+					"were committed: [%s]", table.Name, written, sRow.MySQLString()))
+			case run.DivergenceKind_MissingFromInternal:
+				return errors.New(fmt.Sprintf("On table `%s`, the reader saw a row missing from its snapshot after %d "+
+					"writes were committed: [%s]", table.Name, written, rRow.MySQLString()))
+			default:
+				return errors.New(fmt.Sprintf("On table `%s`, the reader's snapshot changed after %d writes were "+
+					"committed\nSnapshot: [%s]\nRead: [%s]", table.Name, written, sRow.MySQLString(), rRow.MySQLString()))
+			}
+		})
+}
+
+// exec runs the statement on the given session. Statements are logged along with their session, as the log cannot be
+// replayed on a single session.
+func (s *Snapshot) exec(c *run.Cycle, sessionName string, session *sql.Conn, statement string) error {
+	err := c.Logger.WriteLine(run.LogType_INFO, fmt.Sprintf("%s: %s", sessionName, statement))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	_, err = session.ExecContext(context.Background(), statement)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const (
+	triggersRoundsParam      = "rounds"
+	triggersStatementsParam  = "statements"
+	triggersProbabilityParam = "probability"
+)
+
+// Triggers handles testing that triggers fire for the same rows in Dolt as in the internal data.
+type Triggers struct {
+	roundCount        uint64
+	statementCount    uint64
+	probability       float64
+	remainingBranches []string
+}
+
+var _ Command = (*Triggers)(nil)
+var _ CommandMetadata = (*Triggers)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Triggers{})
+}
+
+// Name implements the interface Command.
+func (t *Triggers) Name() string {
+	return "triggers"
+}
+
+// Description implements the interface Command.
+func (t *Triggers) Description() string {
+	return "Tests that triggers fire for the same rows as the internal data."
+}
+
+// Category implements the interface CommandMetadata.
+func (t *Triggers) Category() CommandCategory {
+	return CommandCategory_General
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (t *Triggers) LongDescription() string {
+	return `This command verifies triggers once the repository has been generated. On every branch, each round chooses
+a random table without foreign keys, and creates triggers on it for a random selection of BEFORE and AFTER, along with
+INSERT, UPDATE, and DELETE. Each trigger counts the number of times that it fires in an audit table. The same triggers
+and audit table are created within the internal data, which fires them for the same rows. Random statements are then
+run against the table, after which every trigger must have fired the same number of times, and the table must match the
+internal data. "dolt_schemas" must list every trigger while it exists, and none of them once they have been dropped.`
+}
+
+// ParseArgs implements the interface Command.
+func (t *Triggers) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that triggers fire for the same rows as the internal data",
+		LongDesc:  t.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(triggersRoundsParam, "", "count", "The number of rounds on each branch, each with its own triggers. Defaults to 5.")
+	ap.SupportsUint(triggersStatementsParam, "", "count", "The number of statements run during each round. Defaults to 20.")
+	ap.SupportsString(triggersProbabilityParam, "", "probability",
+		"The probability (from 0 to 1) that each trigger is created in a round, while at least one is always created. Defaults to 0.5.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	t.roundCount = 5
+	if rounds, ok := apr.GetUint(triggersRoundsParam); ok {
+		t.roundCount = rounds
+	}
+	t.statementCount = 20
+	if statements, ok := apr.GetUint(triggersStatementsParam); ok {
+		t.statementCount = statements
+	}
+	t.probability = 0.5
+	if probabilityStr, ok := apr.GetValue(triggersProbabilityParam); ok {
+		probability, err := strconv.ParseFloat(probabilityStr, 64)
+		if err != nil || probability < 0 || probability > 1 {
+			return errors.New(fmt.Sprintf("--%s must be a number from 0 to 1, but is '%s'",
+				triggersProbabilityParam, probabilityStr))
+		}
+		t.probability = probability
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (t *Triggers) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (t *Triggers) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(t.Reset)
+	hooks.RepositoryFinished(t.BeginTriggers)
+}
+
+// Reset resets the state of Triggers.
+func (t *Triggers) Reset(c *run.Cycle) error {
+	t.remainingBranches = nil
+	return nil
+}
+
+// BeginTriggers starts the trigger testing process.
+func (t *Triggers) BeginTriggers(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning Trigger Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	t.remainingBranches = c.GetBranchNames()
+	c.QueueAction(t.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and runs every round of triggers on it.
+func (t *Triggers) Run(c *run.Cycle) error {
+	if len(t.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := t.remainingBranches[0]
+	t.remainingBranches = t.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	var candidates []*run.Table
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if !table.HasForeignKeys() {
+			candidates = append(candidates, table)
+		}
+	}
+	for i := uint64(0); i < t.roundCount && len(candidates) > 0; i++ {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = t.round(c, candidates[roll%uint64(len(candidates))]); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	c.QueueAction(t.Run)
+	return nil
+}
+
+// round creates triggers on the table, runs statements against it, and compares the number of times that each trigger
+// fired before dropping them.
+func (t *Triggers) round(c *run.Cycle, table *run.Table) error {
+	audit, err := run.NewTriggerAudit(c, table, t.probability)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = audit.Create(c); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = audit.ValidateDoltSchemas(c, true); err != nil {
+		return errors.Wrap(err)
+	}
+	for i := uint64(0); i < t.statementCount; i++ {
+		statement, err := c.NewStatement(table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = c.SqlServer(statement); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	if err = audit.Compare(c); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = run.ValidateTableRows(c, c.GetCurrentBranch().Name, table); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = audit.Drop(c); err != nil {
+		return errors.Wrap(err)
+	}
+	return audit.ValidateDoltSchemas(c, false)
+}
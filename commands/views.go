@@ -0,0 +1,153 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/utils/argparser"
+	"github.com/dolthub/fuzzer/utils/cli"
+)
+
+const viewsCountParam = "views"
+
+// Views handles testing that views return the same rows as their queries, and are listed in dolt_schemas.
+type Views struct {
+	viewCount         uint64
+	remainingBranches []string
+}
+
+var _ Command = (*Views)(nil)
+var _ CommandMetadata = (*Views)(nil)
+
+// init adds the command to the map.
+func init() {
+	addCommand(&Views{})
+}
+
+// Name implements the interface Command.
+func (v *Views) Name() string {
+	return "views"
+}
+
+// Description implements the interface Command.
+func (v *Views) Description() string {
+	return "Tests that views return the same rows as their queries, and are listed in dolt_schemas."
+}
+
+// Category implements the interface CommandMetadata.
+func (v *Views) Category() CommandCategory {
+	return CommandCategory_Query
+}
+
+// LongDescription implements the interface CommandMetadata.
+func (v *Views) LongDescription() string {
+	return `This command verifies views once the repository has been generated. On every branch, views are created over
+random tables, with each view defined by a random query, which may filter, order, limit, or aggregate the table's rows.
+Selecting every row from a view must return the same rows as running its query against the internal data, in any
+order. While a view exists, "dolt_schemas" must list it exactly once, and once the view is dropped, "dolt_schemas" must
+no longer list it.`
+}
+
+// ParseArgs implements the interface Command.
+func (v *Views) ParseArgs(commandStr string, ap *argparser.ArgParser, args []string) error {
+	help, _ := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, cli.CommandDocumentationContent{
+		ShortDesc: "Tests that views return the same rows as their queries, and are listed in dolt_schemas",
+		LongDesc:  v.LongDescription(),
+		Synopsis:  nil,
+	}, ap))
+	ap.SupportsUint(viewsCountParam, "", "count", "The number of views created on each branch. Defaults to 10.")
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	v.viewCount = 10
+	if views, ok := apr.GetUint(viewsCountParam); ok {
+		v.viewCount = views
+	}
+	return nil
+}
+
+// AdjustConfig implements the interface Command.
+func (v *Views) AdjustConfig(config *parameters.Base) error {
+	return nil
+}
+
+// Register implements the HookRegistrant interface.
+func (v *Views) Register(hooks *run.Hooks) {
+	hooks.CycleInitialized(v.Reset)
+	hooks.RepositoryFinished(v.BeginViews)
+}
+
+// Reset resets the state of Views.
+func (v *Views) Reset(c *run.Cycle) error {
+	v.remainingBranches = nil
+	return nil
+}
+
+// BeginViews starts the view testing process.
+func (v *Views) BeginViews(c *run.Cycle) error {
+	err := c.Logger.WriteLine(run.LogType_INFO,
+		fmt.Sprintf("Beginning View Testing: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	v.remainingBranches = c.GetBranchNames()
+	c.QueueAction(v.Run)
+	return nil
+}
+
+// Run is the primary loop that selects a branch, and creates, compares, and drops views over its tables.
+func (v *Views) Run(c *run.Cycle) error {
+	if len(v.remainingBranches) == 0 { // We've tested all branches
+		return nil
+	}
+	branchName := v.remainingBranches[0]
+	v.remainingBranches = v.remainingBranches[1:]
+
+	if err := c.SwitchCurrentBranch(branchName); err != nil {
+		return errors.Wrap(err)
+	}
+	tables := c.GetCurrentBranch().GetWorkingSet().Tables
+	for i := uint64(0); i < v.viewCount && len(tables) > 0; i++ {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		view, err := run.NewView(c, tables[roll%uint64(len(tables))])
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = c.SqlServer(view.CreateString()); err != nil {
+			return errors.Wrap(err)
+		}
+		if err = view.Compare(c); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+		if err = view.ValidateDoltSchemas(c, true); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+		if err = c.SqlServer(view.DropString()); err != nil {
+			return errors.Wrap(err)
+		}
+		if err = view.ValidateDoltSchemas(c, false); err != nil {
+			return errors.New(fmt.Sprintf("On branch \"%s\": %s", branchName, err.Error()))
+		}
+	}
+	c.QueueAction(v.Run)
+	return nil
+}
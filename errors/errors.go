@@ -29,8 +29,12 @@ type Error struct {
 	nestedErr  error
 	stackTrace errors.StackTrace
 	ignored    bool
+	reason     string
 }
 
+// IgnoreReason_Unspecified is the reason returned by IgnoreReason for ignorable errors that did not specify a reason.
+const IgnoreReason_Unspecified = "unspecified"
+
 var _ error = Error{}
 var _ fmt.Formatter = Error{}
 
@@ -71,13 +75,15 @@ func (e Error) Format(s fmt.State, verb rune) {
 // the way to the main function that controls the number of cycles that are to be run, then the cycle that returned this
 // error will essentially be skipped from the perspective of metrics and the number of cycles to run. Duration-based
 // limits will still apply, meaning that it's possible for zero cycles to report having run. If any nested error is
-// ignorable, then all encapsulating errors are also ignorable.
-func (e Error) Ignorable() Error {
+// ignorable, then all encapsulating errors are also ignorable. The reason is a short tag (such as
+// "server-startup-timeout") that is used to group ignored cycles together in the metrics.
+func (e Error) Ignorable(reason string) Error {
 	return Error{
 		errStr:     e.errStr,
 		nestedErr:  e.nestedErr,
 		stackTrace: e.stackTrace,
 		ignored:    true,
+		reason:     reason,
 	}
 }
 
@@ -105,6 +111,23 @@ func Wrap(err error) Error {
 	}
 }
 
+// WrapWithMessage wraps the given error, returning a new Error that reports the given message before the wrapped error.
+func WrapWithMessage(err error, errStr string) Error {
+	if _, ok := err.(Error); ok {
+		return Error{
+			errStr:    errStr,
+			nestedErr: err,
+			ignored:   false,
+		}
+	}
+	return Error{
+		errStr:     errStr,
+		nestedErr:  err,
+		stackTrace: stackTrace(1),
+		ignored:    false,
+	}
+}
+
 // ShouldIgnore returns whether the given error should be ignored.
 func ShouldIgnore(err error) bool {
 	fuzzerErr, ok := err.(Error)
@@ -122,6 +145,27 @@ func ShouldIgnore(err error) bool {
 	return false
 }
 
+// IgnoreReason returns the reason that the given error should be ignored, using the outermost ignorable error. Returns
+// an empty string if the error should not be ignored, and IgnoreReason_Unspecified if no reason was given.
+func IgnoreReason(err error) string {
+	fuzzerErr, ok := err.(Error)
+	if !ok {
+		return ""
+	}
+	for true {
+		if fuzzerErr.ignored {
+			if fuzzerErr.reason == "" {
+				return IgnoreReason_Unspecified
+			}
+			return fuzzerErr.reason
+		}
+		if fuzzerErr, ok = fuzzerErr.nestedErr.(Error); !ok {
+			break
+		}
+	}
+	return ""
+}
+
 // stackTrace returns the current stack trace, skipping the number of frames given.
 func stackTrace(skip uint32) errors.StackTrace {
 	var pcs [32]uintptr
@@ -15,9 +15,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"time"
@@ -25,23 +28,51 @@ import (
 	"github.com/dolthub/fuzzer/commands"
 	"github.com/dolthub/fuzzer/errors"
 	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/ranges"
 	"github.com/dolthub/fuzzer/run"
+	"github.com/dolthub/fuzzer/types"
 	"github.com/dolthub/fuzzer/utils/argparser"
 	"github.com/dolthub/fuzzer/utils/cli"
 )
 
 const (
-	configPathParam   = "config"
-	cyclesParam       = "cycles"
-	firstErrorParam   = "first-error"
-	metricsPathParam  = "metrics"
-	repoDonePathParam = "repo-finished"
-	repoWorkPathParam = "repo-working"
-	timeoutParam      = "timeout"
+	configPathParam      = "config"
+	configPrintParam     = "config-print"
+	cyclesParam          = "cycles"
+	dumpGraphParam       = "dump-commit-graph"
+	dumpSchemaParam      = "dump-schema-on-failure"
+	emitSQLParam         = "emit-sql"
+	firstErrorParam      = "first-error"
+	forceCollationParam  = "force-collation"
+	keepParam            = "keep"
+	listCommandsParam    = "list-commands"
+	listTypesParam       = "list-types"
+	maxKeptParam         = "max-kept"
+	metricsPathParam     = "metrics"
+	repoDonePathParam    = "repo-finished"
+	repoWorkPathParam    = "repo-working"
+	repeatSeedParam      = "repeat-seed"
+	replayDecisionsParam = "replay-decisions"
+	seedFileParam        = "seed-file"
+	seedRepoParam        = "seed-repo"
+	stmtDelayParam       = "statement-delay"
+	structureSeedParam   = "structure-seed"
+	timeoutParam         = "timeout"
+	tablesParam          = "tables"
+	branchesParam        = "branches"
 )
 
 func main() {
 	ap, apr := getArgParser()
+	if apr.Contains(listCommandsParam) {
+		printCommandCatalog()
+		os.Exit(0)
+	}
+	if apr.Contains(listTypesParam) {
+		printTypeCatalog()
+		os.Exit(0)
+	}
 	args := apr.Args()
 	if len(args) < 1 {
 		usageFunc()()
@@ -60,6 +91,38 @@ func main() {
 		cli.PrintErrln("%v", err)
 		os.Exit(1)
 	}
+	// When emitting SQL, Dolt is never run and stdout is reserved for the statements, so all other output goes to stderr
+	emitSQL := apr.Contains(emitSQLParam)
+	configPrint := apr.Contains(configPrintParam)
+	sqlOut := cli.CliOut
+	// Every other command tests the repository through Dolt once it has been generated, which cannot be written as SQL
+	if emitSQL && cmd.Name() != "basic" {
+		cli.PrintErrf("error: --%s may only be used with the `basic` command\n", emitSQLParam)
+		os.Exit(1)
+	}
+	if emitSQL {
+		cli.CliOut = cli.CliErr
+	} else if !configPrint {
+		doltVersion, err := preflight()
+		if err != nil {
+			cli.PrintErrf("error: %v\n", err)
+			os.Exit(1)
+		}
+		cli.Printf("Using %s\n", doltVersion)
+	}
+	if seedFile, ok := apr.GetValue(seedFileParam); ok {
+		err = rand.SetSeedFile(strings.ReplaceAll(seedFile, `\`, `/`), apr.Contains(repeatSeedParam))
+		if err != nil {
+			cli.PrintErrf("error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if apr.Contains(repeatSeedParam) {
+		cli.PrintErrf("error: --%s requires --%s\n", repeatSeedParam, seedFileParam)
+		os.Exit(1)
+	}
+	if structureSeed, ok := apr.GetInt(structureSeedParam); ok {
+		rand.SetStructureSeed(int64(structureSeed))
+	}
 
 	configPath := "./config.toml"
 	if readParam, ok := apr.GetValue(configPathParam); ok {
@@ -76,12 +139,19 @@ func main() {
 		os.Exit(1)
 	}
 	cmd.Register(planner.Hooks)
+	if emitSQL {
+		planner.Interface = run.NewWriterInterface(sqlOut)
+	}
 
 	base.Arguments.ConfigPath = configPath
 	base.Arguments.NumOfCycles = -1
+	if emitSQL {
+		base.Arguments.NumOfCycles = 1
+	}
 	if readParam, ok := apr.GetInt(cyclesParam); ok {
 		base.Arguments.NumOfCycles = int64(readParam)
 	}
+	base.Arguments.SkipValidation = emitSQL
 	base.Arguments.Timeout = 0
 	if readParam, ok := apr.GetValue(timeoutParam); ok {
 		base.Arguments.Timeout, err = time.ParseDuration(readParam)
@@ -90,7 +160,28 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	base.Arguments.StatementDelay = 0
+	if readParam, ok := apr.GetValue(stmtDelayParam); ok {
+		base.Arguments.StatementDelay, err = time.ParseDuration(readParam)
+		if err != nil {
+			cli.PrintErrf("%+v\n", err)
+			os.Exit(1)
+		}
+		if base.Arguments.StatementDelay < 0 {
+			cli.PrintErrf("error: --%s must not be negative, but is %s\n", stmtDelayParam, readParam)
+			os.Exit(1)
+		}
+	}
 	base.Arguments.FirstError = apr.Contains(firstErrorParam)
+	base.Arguments.DumpSchemaOnFailure = apr.Contains(dumpSchemaParam)
+	base.Arguments.DumpCommitGraph = parameters.CommitGraphPolicy_None
+	if readParam, ok := apr.GetValue(dumpGraphParam); ok {
+		base.Arguments.DumpCommitGraph, err = parameters.ParseCommitGraphPolicy(readParam)
+		if err != nil {
+			cli.PrintErrf("error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	base.Arguments.RepoWorkingPath = "./"
 	if readParam, ok := apr.GetValue(repoWorkPathParam); ok {
 		readParam = strings.ReplaceAll(readParam, `\`, `/`)
@@ -101,6 +192,48 @@ func main() {
 		readParam = strings.ReplaceAll(readParam, `\`, `/`)
 		base.Arguments.RepoFinishedPath = readParam
 	}
+	// Without an explicit policy, the config file decides whether successful cycles are deleted
+	base.Arguments.Retention = parameters.RetentionPolicy_All
+	if base.Options.DeleteSuccesses {
+		base.Arguments.Retention = parameters.RetentionPolicy_Failures
+	}
+	if readParam, ok := apr.GetValue(keepParam); ok {
+		base.Arguments.Retention, err = parameters.ParseRetentionPolicy(readParam)
+		if err != nil {
+			cli.PrintErrf("error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	base.Arguments.MaxRetained = 0
+	if readParam, ok := apr.GetInt(maxKeptParam); ok {
+		if readParam < 0 {
+			cli.PrintErrf("error: --%s must not be negative, but is %d\n", maxKeptParam, readParam)
+			os.Exit(1)
+		}
+		base.Arguments.MaxRetained = int64(readParam)
+	}
+	base.Arguments.SeedRepo = ""
+	if readParam, ok := apr.GetValue(seedRepoParam); ok {
+		if emitSQL {
+			cli.PrintErrf("error: --%s cannot be used with --%s, as Dolt is never started\n", seedRepoParam, emitSQLParam)
+			os.Exit(1)
+		}
+		// Each cycle changes the working directory, so the seed must be found using an absolute path
+		base.Arguments.SeedRepo = expandPath(strings.ReplaceAll(readParam, `\`, `/`))
+	}
+	base.Arguments.ReplayDecisions = ""
+	if readParam, ok := apr.GetValue(replayDecisionsParam); ok {
+		// Each cycle changes the working directory, so the decision log must be found using an absolute path
+		base.Arguments.ReplayDecisions = expandPath(strings.ReplaceAll(readParam, `\`, `/`))
+	}
+	base.Arguments.ForceCollation = ""
+	if readParam, ok := apr.GetValue(forceCollationParam); ok {
+		base.Arguments.ForceCollation = readParam
+	}
+	if err = types.SetForcedCollation(base.Arguments.ForceCollation); err != nil {
+		cli.PrintErrf("error: --%s must name a valid collation: %v\n", forceCollationParam, err)
+		os.Exit(1)
+	}
 	base.Arguments.MetricsPath = ""
 	if readParam, ok := apr.GetValue(metricsPathParam); ok {
 		readParam = strings.ReplaceAll(readParam, `\`, `/`)
@@ -120,10 +253,41 @@ func main() {
 		cli.PrintErrln("%v", err)
 		os.Exit(1)
 	}
+	// The overrides are applied after the command has adjusted the config, so that they also take precedence over any
+	// profile that the command applies
+	if readParam, ok := apr.GetInt(tablesParam); ok {
+		if readParam < 1 {
+			cli.PrintErrf("error: --%s must be at least 1, but is %d\n", tablesParam, readParam)
+			os.Exit(1)
+		}
+		base.Amounts.Tables = ranges.NewInt([]int64{int64(readParam), int64(readParam)})
+	}
+	if readParam, ok := apr.GetInt(branchesParam); ok {
+		if readParam < 1 {
+			cli.PrintErrf("error: --%s must be at least 1, but is %d\n", branchesParam, readParam)
+			os.Exit(1)
+		}
+		base.Amounts.Branches = ranges.NewInt([]int64{int64(readParam), int64(readParam)})
+	}
+	if configPrint {
+		config, err := base.Serialize()
+		if err != nil {
+			cli.PrintErrf("%+v\n", err)
+			os.Exit(1)
+		}
+		cli.Println(string(config))
+		os.Exit(0)
+	}
 
 	i := int64(0)
 	cycleCount := int64(0)
 	failures := int64(0)
+	ignored := int64(0)
+	ignoredReasons := make(map[string]int64)
+	// A broken environment, rather than Dolt, is the likely cause when every cycle is ignored
+	consecutiveIgnored := int64(0)
+	var environmentErr error
+	var storageMetrics []*run.StorageMetrics
 	startTime := time.Now()
 	for ; (base.Arguments.NumOfCycles < 0 && time.Since(startTime) < base.Arguments.Timeout) || i < base.Arguments.NumOfCycles; i++ {
 		cycle, err := planner.NewCycle()
@@ -148,6 +312,15 @@ func main() {
 				if errors.ShouldIgnore(err) {
 					cycleCount--
 					i--
+					ignored++
+					ignoredReasons[errors.IgnoreReason(err)]++
+					consecutiveIgnored++
+					if maxIgnored := base.Options.MaxConsecutiveIgnored; maxIgnored > 0 && consecutiveIgnored >= maxIgnored {
+						environmentErr = errors.New(fmt.Sprintf("%d consecutive cycles were ignored, which indicates that "+
+							"the environment is broken rather than Dolt. The last cycle was ignored due to: %s",
+							consecutiveIgnored, errors.IgnoreReason(err)))
+					}
+					return
 				} else {
 					cli.PrintErrf("%+v\n", err)
 					failures++
@@ -156,7 +329,18 @@ func main() {
 					}
 				}
 			}
+			consecutiveIgnored = 0
+			if cycle.Storage != nil {
+				storageMetrics = append(storageMetrics, cycle.Storage)
+			}
 		}()
+		if environmentErr != nil {
+			break
+		}
+	}
+	printIgnoredReasons(ignored, ignoredReasons)
+	if environmentErr != nil {
+		cli.PrintErrf("error: %s\n", environmentErr.Error())
 	}
 	if base.Arguments.MetricsPath != "" {
 		metricsFile, err := os.OpenFile(fmt.Sprintf("%s%s.txt", base.Arguments.MetricsPath, time.Now().Format("20060102150405")),
@@ -168,13 +352,30 @@ func main() {
 		defer func() {
 			_ = metricsFile.Close()
 		}()
-		_, err = metricsFile.WriteString(fmt.Sprintf(`{"Runs":%d,"Successful":%d,"Failed":%d}`,
-			cycleCount, cycleCount-failures, failures))
+		ignoredReasonsJSON, err := json.Marshal(ignoredReasons)
+		if err != nil {
+			cli.PrintErrf("%+v\n", err)
+			os.Exit(1)
+		}
+		storageJSON := ""
+		if base.Options.StorageMetrics {
+			storageMetricsJSON, err := json.Marshal(storageMetrics)
+			if err != nil {
+				cli.PrintErrf("%+v\n", err)
+				os.Exit(1)
+			}
+			storageJSON = fmt.Sprintf(`,"Storage":%s`, storageMetricsJSON)
+		}
+		_, err = metricsFile.WriteString(fmt.Sprintf(`{"Runs":%d,"Successful":%d,"Failed":%d,"Ignored":%d,"IgnoredReasons":%s%s}`,
+			cycleCount, cycleCount-failures, failures, ignored, ignoredReasonsJSON, storageJSON))
 		if err != nil {
 			cli.PrintErrf("%+v\n", err)
 			os.Exit(1)
 		}
 	}
+	if environmentErr != nil {
+		os.Exit(1)
+	}
 }
 
 func getArgParser() (*argparser.ArgParser, *argparser.ArgParseResults) {
@@ -184,12 +385,50 @@ func getArgParser() (*argparser.ArgParser, *argparser.ArgParseResults) {
 	ap.SupportsString(timeoutParam, "", "duration",
 		`Stops starting new cycles once the timeout has been reached. The specified cycle count overrides this parameter.
 Uses time.ParseDuration, so refer to Go's documentation on allowed strings: https://pkg.go.dev/time#ParseDuration`)
+	ap.SupportsString(stmtDelayParam, "", "duration",
+		`Sleeps for the given duration before each generated statement, which may help to reproduce timing-sensitive bugs.
+Uses time.ParseDuration, such as "50ms". Defaults to no delay.`)
+	ap.SupportsString(seedRepoParam, "", "path",
+		`Starts every cycle from a copy of the Dolt database at the given path, rather than an empty repository. The tables
+on the seed's main branch are loaded as the initial data, which must not have uncommitted changes or foreign keys.`)
+	ap.SupportsString(replayDecisionsParam, "", "location",
+		`Drives every cycle using the decisions recorded in the given decision log, which is the "decisions.txt" file of an
+earlier cycle, rather than making them randomly. The same command and config should be used as the earlier cycle.`)
+	ap.SupportsString(forceCollationParam, "", "collation",
+		`Uses the given collation for every string column, in place of the collations configured for each type, such as
+"utf8mb4_0900_bin". This allows a bug that is specific to a single collation to be reproduced.`)
+	ap.SupportsFlag(configPrintParam, "",
+		`If specified, prints the effective config as JSON and then exits. This reflects the normalized config file, along
+with all arguments and any adjustments made by the command.`)
+	ap.SupportsFlag(emitSQLParam, "",
+		`If specified, the generated statements are written to stdout rather than run, and Dolt is never started. Each cycle
+creates its own database, so the output may be run using "dolt sql". Nothing is validated, and runs a single cycle unless
+a cycle count is given.`)
 	ap.SupportsFlag(firstErrorParam, "", "If specified, immediately stops the fuzzer when the first error is encountered.")
+	ap.SupportsFlag(dumpSchemaParam, "",
+		`If specified, a failed cycle writes every table's schema from Dolt alongside the fuzzer's own schema to "schemas.txt".`)
+	ap.SupportsString(dumpGraphParam, "", "when",
+		`Writes the internal commit graph of every branch to "commits.dot" as a Graphviz file: "failures" for failed cycles,
+or "always" for every cycle.`)
+	ap.SupportsString(keepParam, "", "policy",
+		`Determines which finished cycles are kept: "all", "failures", or "none". Defaults to "failures" when
+Options.Delete_Successful_Runs is enabled, and "all" otherwise. Ignored cycles are never kept.`)
+	ap.SupportsInt(maxKeptParam, "", "count",
+		"The largest number of finished cycles that are kept, deleting the oldest beyond it. Defaults to 0, which never deletes.")
+	ap.SupportsFlag(listCommandsParam, "", "Lists all commands grouped by category, along with their full descriptions, and then exits.")
+	ap.SupportsFlag(listTypesParam, "", "Lists all column types, along with the config keys that control each type, and then exits.")
 	ap.SupportsString(repoDonePathParam, "", "location",
 		"Specifies a custom location for completed repositories. Defaults to the working path if not specified.")
 	ap.SupportsString(repoWorkPathParam, "", "location", "Specifies a custom location for repositories as they're being worked on.")
 	ap.SupportsString(metricsPathParam, "", "location",
 		"Specifies a custom location for where metric logs are stored. Metrics are not created if a location is not specified.")
+	ap.SupportsString(seedFileParam, "", "location",
+		"Specifies a file that random bytes are read from. Once the file is exhausted, random bytes are generated as usual.")
+	ap.SupportsFlag(repeatSeedParam, "", "If specified, the seed file is rewound and read again whenever it is exhausted.")
+	ap.SupportsInt(structureSeedParam, "", "seed",
+		"Seeds the structural decisions, such as type and statement choices, separately from the generated values.")
+	ap.SupportsInt(tablesParam, "", "count", "Overrides Amounts.Tables, so that every branch has exactly the given number of tables.")
+	ap.SupportsInt(branchesParam, "", "count", "Overrides Amounts.Branches, so that every cycle has exactly the given number of branches.")
 
 	// Argument parser requires all arguments to be defined upfront, which doesn't work when commands will later define
 	// more arguments. As a result, we remove any arguments that we don't know about here, and the commands will complain
@@ -240,9 +479,13 @@ action(s) on them. This is for the purpose of fuzzing Dolt. Those actions are se
 				}, ap))
 				help()
 				cli.Println("\bCOMMANDS")
-				cmds := sortedCommands()
-				for _, cmd := range cmds {
-					cli.Printf("\t%s - %s\n", cmd.Name(), cmd.Description())
+				for _, category := range sortedCategories() {
+					cli.Printf("\t%s:\n", category)
+					for _, cmd := range sortedCommands() {
+						if commands.GetCategory(cmd) == category {
+							cli.Printf("\t\t%s - %s\n", cmd.Name(), cmd.Description())
+						}
+					}
 				}
 				os.Exit(0)
 			}
@@ -251,16 +494,127 @@ action(s) on them. This is for the purpose of fuzzing Dolt. Those actions are se
 	return ap, apr
 }
 
+// preflight verifies that the external binaries used by the fuzzer are available, returning Dolt's version. A missing
+// `dolt` is an error, as every cycle depends on it. A missing `stty` only affects the width of help output, so it
+// results in a warning.
+func preflight() (string, error) {
+	if _, err := exec.LookPath("dolt"); err != nil {
+		return "", errors.New("could not find `dolt` on the PATH. Install Dolt (https://docs.dolthub.com/introduction/installation) " +
+			"or add the directory containing it to the PATH.")
+	}
+	out, err := exec.Command("dolt", "version").Output()
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("`dolt version` failed, so Dolt may be installed incorrectly: %v", err))
+	}
+	version := strings.TrimSpace(string(out))
+	if idx := strings.IndexByte(version, '\n'); idx != -1 {
+		version = strings.TrimSpace(version[:idx])
+	}
+	if runtime.GOOS != "windows" {
+		if _, err = exec.LookPath("stty"); err != nil {
+			cli.PrintErrf("warning: could not find `stty` on the PATH, so help output will not fit the terminal width\n")
+		}
+	}
+	return version, nil
+}
+
 func usageFunc() func() {
 	return func() {
 		cmds := sortedCommands()
 		cli.Println("Valid commands for fuzzer are")
+		for _, category := range sortedCategories() {
+			cli.Printf("  %s:\n", category)
+			for _, cmd := range cmds {
+				if commands.GetCategory(cmd) == category {
+					cli.Printf("    %16s - %s\n", cmd.Name(), cmd.Description())
+				}
+			}
+		}
+	}
+}
+
+func printIgnoredReasons(ignored int64, ignoredReasons map[string]int64) {
+	if ignored == 0 {
+		return
+	}
+	reasons := make([]string, 0, len(ignoredReasons))
+	for reason := range ignoredReasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if ignoredReasons[reasons[i]] != ignoredReasons[reasons[j]] {
+			return ignoredReasons[reasons[i]] > ignoredReasons[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	cli.Printf("Ignored %d cycles:\n", ignored)
+	for _, reason := range reasons {
+		count := ignoredReasons[reason]
+		cli.Printf("    %s: %d (%.1f%%)\n", reason, count, float64(count)*100/float64(ignored))
+	}
+}
+
+func printCommandCatalog() {
+	cmds := sortedCommands()
+	for _, category := range sortedCategories() {
+		cli.Println(strings.ToUpper(string(category)))
 		for _, cmd := range cmds {
-			cli.Printf("    %16s - %s\n", cmd.Name(), cmd.Description())
+			if commands.GetCategory(cmd) != category {
+				continue
+			}
+			cli.Printf("    %s - %s\n", cmd.Name(), cmd.Description())
+			for _, line := range strings.Split(commands.GetLongDescription(cmd), "\n") {
+				cli.Printf("        %s\n", line)
+			}
 		}
+		cli.Println()
 	}
 }
 
+func printTypeCatalog() {
+	pkTypes := make(map[string]struct{})
+	for _, t := range run.PrimaryKeyTypes(&parameters.Types{}) {
+		pkTypes[types.ConfigName(t)] = struct{}{}
+	}
+	for _, t := range run.NonPrimaryKeyTypes(&parameters.Types{}) {
+		name := types.ConfigName(t)
+		cli.Println(name)
+		if _, ok := pkTypes[name]; ok {
+			cli.Println("    Primary Key: allowed")
+		} else {
+			cli.Println("    Primary Key: not allowed")
+		}
+		cli.Printf("    Distribution: Types.Distribution.%s\n", name)
+		keys := parameters.TypeParameterKeys(name)
+		if len(keys) == 0 {
+			cli.Println("    Parameters: none")
+		}
+		for i, key := range keys {
+			if i == 0 {
+				cli.Printf("    Parameters: Types.Parameters.%s\n", key)
+			} else {
+				cli.Printf("                Types.Parameters.%s\n", key)
+			}
+		}
+	}
+}
+
+func sortedCategories() []commands.CommandCategory {
+	seen := make(map[commands.CommandCategory]struct{})
+	var categories []commands.CommandCategory
+	for _, cmd := range commands.Commands {
+		category := commands.GetCategory(cmd)
+		if _, ok := seen[category]; !ok {
+			seen[category] = struct{}{}
+			categories = append(categories, category)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i] < categories[j]
+	})
+	return categories
+}
+
 func sortedCommands() []commands.Command {
 	var cmds []commands.Command
 	for _, cmd := range commands.Commands {
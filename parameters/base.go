@@ -15,8 +15,14 @@
 package parameters
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
 	"github.com/dolthub/fuzzer/ranges"
 	"github.com/dolthub/fuzzer/types"
 )
@@ -26,11 +32,25 @@ type Base struct {
 	InvalidNameRegexes    InvalidNameRegexes
 	Amounts               Amounts
 	StatementDistribution StatementDistribution
+	SchemaChanges         SchemaChanges
+	Commits               Commits
 	Options               Options
+	SessionVariables      map[string][]string
+	Campaign              map[string]int64
 	Types                 Types
 	Arguments             Arguments
 }
 
+// Serialize returns the parameters as indented JSON. This reflects all normalization, command-line arguments, and
+// command adjustments that have been applied since the config file was loaded.
+func (b *Base) Serialize() ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return data, nil
+}
+
 // InvalidNameRegexes contains regexes that generated names are matched against for validity.
 type InvalidNameRegexes struct {
 	Branches    string
@@ -47,9 +67,102 @@ type Amounts struct {
 	PrimaryKeys           ranges.Int
 	Columns               ranges.Int
 	Indexes               ranges.Int
+	IndexPrefixLength     ranges.Int
 	ForeignKeyConstraints ranges.Int
 	Rows                  ranges.Int
-	IndexDelay            ranges.Int
+	// RowBuckets are alternative row ranges, which each table may use in place of Rows.
+	RowBuckets []RowBucket
+	IndexDelay ranges.Int
+	// WidePrimaryKeys gives tables a chance to use a wide composite primary key in place of PrimaryKeys.
+	WidePrimaryKeys WidePrimaryKeys
+}
+
+// WidePrimaryKeys is a primary key column range that a table uses in place of Amounts.PrimaryKeys, with the given
+// probability.
+type WidePrimaryKeys struct {
+	Probability float64
+	PrimaryKeys ranges.Int
+}
+
+// RandomCount returns the number of primary key columns of a new wide primary key. Returns 0 when the table should use
+// Amounts.PrimaryKeys.
+func (w *WidePrimaryKeys) RandomCount() (int64, error) {
+	if w.Probability <= 0 {
+		return 0, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	if float64(roll) >= w.Probability*math.MaxUint64 {
+		return 0, nil
+	}
+	return w.PrimaryKeys.StructureRandomValue()
+}
+
+// RowBucket is a row range that a table uses in place of Amounts.Rows, with the given probability.
+type RowBucket struct {
+	Probability float64
+	Rows        ranges.Int
+}
+
+// RandomRowBucket returns the index of a random row bucket, based on the probability of each bucket. Returns -1 when
+// the table should use Rows.
+func (a *Amounts) RandomRowBucket() (int, error) {
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return -1, errors.Wrap(err)
+	}
+	threshold := float64(0)
+	for i, bucket := range a.RowBuckets {
+		threshold += bucket.Probability
+		if float64(roll) < threshold*math.MaxUint64 {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// RowRange returns the row range of the bucket at the given index, or Rows when the index is -1.
+func (a *Amounts) RowRange(bucket int) ranges.Int {
+	if bucket < 0 || bucket >= len(a.RowBuckets) {
+		return a.Rows
+	}
+	return a.RowBuckets[bucket].Rows
+}
+
+// RowsUpperbound returns the largest number of rows that any table may target, across Rows and every bucket.
+func (a *Amounts) RowsUpperbound() int64 {
+	upperbound := a.Rows.Upperbound
+	for _, bucket := range a.RowBuckets {
+		if bucket.Rows.Upperbound > upperbound {
+			upperbound = bucket.Rows.Upperbound
+		}
+	}
+	return upperbound
+}
+
+// SchemaChanges specifies the probability of each schema change occurring in place of a statement.
+type SchemaChanges struct {
+	RenameColumn float64
+	AddColumn    float64
+	DropColumn   float64
+	ModifyColumn float64
+	// AddPrimaryKey and DropPrimaryKey add a primary key to a keyless table, and drop the primary key of a table.
+	AddPrimaryKey  float64
+	DropPrimaryKey float64
+	// ModifyColumnConversions are the type conversions that may be made by ModifyColumn, using the type names from the
+	// config file (such as "INT_UNSIGNED").
+	ModifyColumnConversions [][2]string
+}
+
+// Commits specifies the messages and authors that are used for each commit. Messages and authors are randomly
+// generated when their lists are empty.
+type Commits struct {
+	Messages      []string
+	MessageLength ranges.Int
+	// Authors are written as "Name <email>".
+	Authors []string
 }
 
 // StatementDistribution specifies the relative frequency of each statement in a cycle.
@@ -58,6 +171,8 @@ type StatementDistribution struct {
 	Replace ranges.Int
 	Update  ranges.Int
 	Delete  ranges.Int
+	// PreparedInsert is an INSERT that is run as a prepared statement, with its values bound as arguments.
+	PreparedInsert ranges.Int
 }
 
 // Options are directives for all cycles.
@@ -72,6 +187,94 @@ type Options struct {
 	Port              int64
 	ZipInternalData   bool
 	DeleteAfterZip    bool
+	DivergenceReport  bool
+	CLIReadRetries    int64
+	// BranchWriteFraction is the number of writes made to each table on a new branch, regardless of its row count, as a
+	// fraction of the table's target row count on that branch.
+	BranchWriteFraction float64
+	// MaxConsecutiveIgnored is the number of consecutive ignored cycles after which the run is aborted, as the
+	// environment is likely broken. A value of 0 never aborts the run.
+	MaxConsecutiveIgnored int64
+	// PKAttempts is the number of times that primary key types are chosen for a new table before the cycle is aborted.
+	PKAttempts int64
+	// PKSaturationDivisor is how many times larger a primary key's value space must be than the upper bound of the
+	// row count.
+	PKSaturationDivisor float64
+	// ValidateOnSwitch validates the data of the target branch after every branch switch.
+	ValidateOnSwitch bool
+	// RedundantIndexProbability is the probability that each index duplicates, or overlaps with, an earlier index.
+	RedundantIndexProbability float64
+	// DescendingIndexProbability is the probability that each column of an index is sorted in descending order.
+	DescendingIndexProbability float64
+	// InvisibleColumnProbability is the probability that each non-primary key column of a new table is INVISIBLE, which
+	// excludes it from `SELECT *`.
+	InvisibleColumnProbability float64
+	// InsertColumnListProbability is the probability that an INSERT lists the table's columns in a random order, rather
+	// than giving the values in the table's column order.
+	InsertColumnListProbability float64
+	// EnumNameProbability is the probability that each ENUM value within an INSERT is written using the name of its
+	// element, rather than its index.
+	EnumNameProbability float64
+	// EmptyTableProbability is the probability that a new table targets zero rows on every branch, which takes
+	// precedence over the table's row range.
+	EmptyTableProbability float64
+	// MaxIndexesPerColumn is the maximum number of indexes on a table for each of its indexable non-primary key columns.
+	// A value of 0 does not limit the number of indexes, although tables without such columns are never indexed.
+	MaxIndexesPerColumn float64
+	// SchemaLimitProbability is the probability that a table near or beyond the column count or row size limits is
+	// created before each new table, to verify that Dolt only rejects the tables that exceed a limit.
+	SchemaLimitProbability float64
+	// HotKeys is the number of primary keys on each table that UPDATE and REPLACE statements may concentrate their
+	// writes on. A value of 0 disables hot keys.
+	HotKeys int64
+	// HotKeyProbability is the probability that each UPDATE or REPLACE writes to one of the table's hot keys.
+	HotKeyProbability float64
+	// KeyCollisionProbability is the probability that each INSERT or REPLACE reuses the primary key of an existing row,
+	// rather than generating a key that is most likely new.
+	KeyCollisionProbability float64
+	// OverflowProbability is the probability that an INSERT holding an integer literal just outside of its column's
+	// range is run in place of each statement, which Dolt must either reject or clamp depending on the sql_mode.
+	OverflowProbability float64
+	// ModernCollationProbability is the probability that each new CHAR or VARCHAR column uses one of the newer utf8mb4
+	// collations, which are mostly case or accent insensitive, in place of its configured collations.
+	ModernCollationProbability float64
+	// CollationVariantProbability is the probability that each value of a case or accent insensitive CHAR or VARCHAR
+	// column only differs from one of the column's recent values by case or accents.
+	CollationVariantProbability float64
+	// TransactionProbability is the probability that the next statements are run within an explicit transaction, using
+	// BEGIN and either COMMIT or ROLLBACK.
+	TransactionProbability float64
+	// TransactionRollbackProbability is the probability that an explicit transaction ends with a ROLLBACK rather than a
+	// COMMIT.
+	TransactionRollbackProbability float64
+	// TransactionStatements is the maximum number of statements within each explicit transaction.
+	TransactionStatements int64
+	// StringEscapes allows generated strings to contain quotes, backslashes, newlines, and tabs.
+	StringEscapes bool
+	// MaxValueBytes is the largest size, in bytes, of any generated string or binary value, regardless of the maximum
+	// allowed by its type. A value of 0 only limits values by their type.
+	MaxValueBytes int64
+	// InlineBoundary is the size, in bytes, at which Dolt stores BLOB and TEXT values out-of-line rather than inline.
+	InlineBoundary int64
+	// InlineBoundaryProbability is the probability that each BLOB or TEXT value is generated with a length just below
+	// or just above InlineBoundary.
+	InlineBoundaryProbability float64
+	// StatusCheckInterval is the number of statements between each comparison of `dolt status` against the internal
+	// data. A value of 0 disables the comparison.
+	StatusCheckInterval int64
+	// StorageMetrics measures the size of the `.dolt` directory throughout each cycle, and adds it to the metrics.
+	StorageMetrics bool
+	// RestartValidation stops every Dolt process once the data has been written and garbage collected, and validates
+	// every branch again using a freshly started server.
+	RestartValidation bool
+	// ChecksumValidation compares an order-independent checksum of each table's rows in the internal data and in Dolt,
+	// before comparing the rows one at a time. The row by row comparison is skipped when both checksums match. Requires
+	// the Oracle to be "mysql".
+	ChecksumValidation bool
+	// Oracle is the name of the database that holds the internal data, which is either "sqlite" or "mysql".
+	Oracle string
+	// OracleDSN is the data source name that is used to connect to the Oracle when it is "mysql".
+	OracleDSN string
 }
 
 // Types represents all of the MySQL types available to the program.
@@ -90,12 +293,14 @@ type Types struct {
 	Float             types.Float
 	Int               types.Int
 	IntUnsigned       types.IntUnsigned
+	Linestring        types.Linestring
 	Longblob          types.Longblob
 	Longtext          types.Longtext
 	Mediumblob        types.Mediumblob
 	Mediumint         types.Mediumint
 	MediumintUnsigned types.MediumintUnsigned
 	Mediumtext        types.Mediumtext
+	Polygon           types.Polygon
 	Set               types.Set
 	Smallint          types.Smallint
 	SmallintUnsigned  types.SmallintUnsigned
@@ -121,4 +326,70 @@ type Arguments struct {
 	RepoWorkingPath   string
 	MetricsPath       string
 	DontGenRandomData bool
+	SkipValidation    bool
+	// Retention determines which finished cycles have their repositories kept.
+	Retention RetentionPolicy
+	// MaxRetained is the largest number of finished cycles that are kept, with the oldest deleted first. A value of 0
+	// keeps every cycle that the retention policy allows.
+	MaxRetained int64
+	// StatementDelay is how long to sleep before each generated statement is run. A value of 0 does not sleep.
+	StatementDelay time.Duration
+	// SeedRepo is the path of an existing Dolt database that each cycle starts from, rather than an empty repository.
+	SeedRepo string
+	// ReplayDecisions is the path of a decision log from an earlier cycle, which supplies each cycle's high-level
+	// decisions rather than making them randomly.
+	ReplayDecisions string
+	// ForceCollation is the name of the collation used by every string column, in place of the configured collations.
+	// An empty name uses the configured collations.
+	ForceCollation string
+	// DumpSchemaOnFailure writes the schema of every table from both Dolt and the internal data whenever a cycle fails.
+	DumpSchemaOnFailure bool
+	// DumpCommitGraph determines which finished cycles write their commit graph as a Graphviz file.
+	DumpCommitGraph CommitGraphPolicy
+}
+
+// RetentionPolicy determines which finished cycles have their repositories kept. Cycles that fail with an ignorable
+// error are always deleted.
+type RetentionPolicy string
+
+const (
+	// RetentionPolicy_All keeps every cycle.
+	RetentionPolicy_All RetentionPolicy = "all"
+	// RetentionPolicy_Failures keeps only the cycles that failed.
+	RetentionPolicy_Failures RetentionPolicy = "failures"
+	// RetentionPolicy_None keeps no cycles, which is useful when only the output and metrics are needed.
+	RetentionPolicy_None RetentionPolicy = "none"
+)
+
+// CommitGraphPolicy determines which finished cycles write the commit graph of the internal data as a Graphviz file.
+// Cycles that fail with an ignorable error never write it.
+type CommitGraphPolicy string
+
+const (
+	// CommitGraphPolicy_None never writes the commit graph.
+	CommitGraphPolicy_None CommitGraphPolicy = ""
+	// CommitGraphPolicy_Failures writes the commit graph of cycles that failed.
+	CommitGraphPolicy_Failures CommitGraphPolicy = "failures"
+	// CommitGraphPolicy_Always writes the commit graph of every cycle.
+	CommitGraphPolicy_Always CommitGraphPolicy = "always"
+)
+
+// ParseCommitGraphPolicy returns the CommitGraphPolicy with the given name.
+func ParseCommitGraphPolicy(name string) (CommitGraphPolicy, error) {
+	switch policy := CommitGraphPolicy(strings.ToLower(name)); policy {
+	case CommitGraphPolicy_Failures, CommitGraphPolicy_Always:
+		return policy, nil
+	default:
+		return "", errors.New(fmt.Sprintf("commit graph policy must be one of failures or always, but is '%s'", name))
+	}
+}
+
+// ParseRetentionPolicy returns the RetentionPolicy with the given name.
+func ParseRetentionPolicy(name string) (RetentionPolicy, error) {
+	switch policy := RetentionPolicy(strings.ToLower(name)); policy {
+	case RetentionPolicy_All, RetentionPolicy_Failures, RetentionPolicy_None:
+		return policy, nil
+	default:
+		return "", errors.New(fmt.Sprintf("retention policy must be one of all, failures, or none, but is '%s'", name))
+	}
 }
@@ -42,9 +42,20 @@ func convertConfigBase(cBase *configBase) (*Base, error) {
 	base.Amounts.PrimaryKeys = ranges.NewInt(cBase.Amounts.PrimaryKeys)
 	base.Amounts.Columns = ranges.NewInt(cBase.Amounts.Columns)
 	base.Amounts.Indexes = ranges.NewInt(cBase.Amounts.Indexes)
+	base.Amounts.IndexPrefixLength = ranges.NewInt(cBase.Amounts.IndexPrefixLength)
 	base.Amounts.ForeignKeyConstraints = ranges.NewInt(cBase.Amounts.ForeignKeyConstraints)
 	base.Amounts.Rows = ranges.NewInt(cBase.Amounts.Rows)
+	for _, bucket := range cBase.Amounts.RowBuckets {
+		base.Amounts.RowBuckets = append(base.Amounts.RowBuckets, RowBucket{
+			Probability: bucket.Probability,
+			Rows:        ranges.NewInt(bucket.Rows),
+		})
+	}
 	base.Amounts.IndexDelay = ranges.NewInt(cBase.Amounts.IndexDelay)
+	base.Amounts.WidePrimaryKeys = WidePrimaryKeys{
+		Probability: cBase.Amounts.WidePrimaryKeys.Probability,
+		PrimaryKeys: ranges.NewInt(cBase.Amounts.WidePrimaryKeys.PrimaryKeys),
+	}
 
 	// Statement_Distribution
 	if err := cBase.StatementDistribution.Normalize(); err != nil {
@@ -54,9 +65,33 @@ func convertConfigBase(cBase *configBase) (*Base, error) {
 	base.StatementDistribution.Replace = ranges.NewInt(cBase.StatementDistribution.Replace)
 	base.StatementDistribution.Update = ranges.NewInt(cBase.StatementDistribution.Update)
 	base.StatementDistribution.Delete = ranges.NewInt(cBase.StatementDistribution.Delete)
+	base.StatementDistribution.PreparedInsert = ranges.NewInt(cBase.StatementDistribution.PreparedInsert)
+
+	// Schema_Changes
+	if err := cBase.SchemaChanges.Validate(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	base.SchemaChanges.RenameColumn = cBase.SchemaChanges.RenameColumn
+	base.SchemaChanges.AddColumn = cBase.SchemaChanges.AddColumn
+	base.SchemaChanges.DropColumn = cBase.SchemaChanges.DropColumn
+	base.SchemaChanges.ModifyColumn = cBase.SchemaChanges.ModifyColumn
+	base.SchemaChanges.AddPrimaryKey = cBase.SchemaChanges.AddPrimaryKey
+	base.SchemaChanges.DropPrimaryKey = cBase.SchemaChanges.DropPrimaryKey
+	for _, conversion := range cBase.SchemaChanges.ModifyColumnConversions {
+		base.SchemaChanges.ModifyColumnConversions = append(base.SchemaChanges.ModifyColumnConversions,
+			[2]string{conversion[0], conversion[1]})
+	}
+
+	// Commits
+	if err := cBase.Commits.Normalize(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	base.Commits.Messages = append([]string{}, cBase.Commits.Messages...)
+	base.Commits.MessageLength = ranges.NewInt(cBase.Commits.MessageLength)
+	base.Commits.Authors = append([]string{}, cBase.Commits.Authors...)
 
 	// Options
-	if err := cBase.Options.Validate(); err != nil {
+	if err := cBase.Options.Normalize(); err != nil {
 		return nil, errors.Wrap(err)
 	}
 	base.Options.DoltVersion = cBase.Options.DoltVersion
@@ -64,43 +99,115 @@ func convertConfigBase(cBase *configBase) (*Base, error) {
 	base.Options.ManualGC = cBase.Options.ManualGC
 	base.Options.IncludeReadme = cBase.Options.IncludeReadme
 	base.Options.LowerRowsMainOnly = cBase.Options.LowerRowsMainOnly
+	base.Options.BranchWriteFraction = cBase.Options.BranchWrites
 	base.Options.Logging = cBase.Options.Logging
 	base.Options.DeleteSuccesses = cBase.Options.DeleteSuccesses
 	base.Options.Port = int64(cBase.Options.Port)
 	base.Options.ZipInternalData = cBase.Options.ZipInternalData
 	base.Options.DeleteAfterZip = cBase.Options.DeleteAfterZip
+	base.Options.DivergenceReport = cBase.Options.DivergenceReport
+	base.Options.CLIReadRetries = int64(cBase.Options.CLIReadRetries)
+	base.Options.MaxConsecutiveIgnored = int64(cBase.Options.MaxIgnored)
+	base.Options.PKAttempts = int64(*cBase.Options.PKAttempts)
+	base.Options.PKSaturationDivisor = *cBase.Options.PKDivisor
+	base.Options.ValidateOnSwitch = cBase.Options.ValidateOnSwitch
+	base.Options.RedundantIndexProbability = cBase.Options.RedundantIndex
+	base.Options.DescendingIndexProbability = cBase.Options.DescendingIndex
+	base.Options.InvisibleColumnProbability = cBase.Options.InvisibleColumn
+	base.Options.InsertColumnListProbability = cBase.Options.InsertColumnList
+	base.Options.EnumNameProbability = cBase.Options.EnumName
+	base.Options.EmptyTableProbability = cBase.Options.EmptyTable
+	base.Options.MaxIndexesPerColumn = cBase.Options.IndexesPerColumn
+	base.Options.SchemaLimitProbability = cBase.Options.SchemaLimit
+	base.Options.HotKeys = int64(cBase.Options.HotKeys)
+	base.Options.HotKeyProbability = cBase.Options.HotKey
+	base.Options.KeyCollisionProbability = cBase.Options.KeyCollision
+	base.Options.OverflowProbability = cBase.Options.Overflow
+	base.Options.ModernCollationProbability = cBase.Options.ModernCollation
+	base.Options.CollationVariantProbability = cBase.Options.CollationVariant
+	base.Options.TransactionProbability = cBase.Options.Transaction
+	base.Options.TransactionRollbackProbability = cBase.Options.Rollback
+	base.Options.TransactionStatements = int64(cBase.Options.TransactionSize)
+	base.Options.StringEscapes = cBase.Options.StringEscapes
+	base.Options.MaxValueBytes = int64(cBase.Options.MaxValueBytes)
+	base.Options.InlineBoundary = int64(cBase.Options.InlineBoundary)
+	base.Options.InlineBoundaryProbability = cBase.Options.InlineBoundaryProb
+	base.Options.StatusCheckInterval = int64(cBase.Options.StatusInterval)
+	base.Options.StorageMetrics = cBase.Options.StorageMetrics
+	base.Options.RestartValidation = cBase.Options.RestartValidation
+	base.Options.ChecksumValidation = cBase.Options.ChecksumValidation
+	base.Options.Oracle = cBase.Options.Oracle
+	base.Options.OracleDSN = cBase.Options.OracleDSN
+
+	// Session_Variables
+	if err := cBase.SessionVariables.Validate(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	base.SessionVariables = make(map[string][]string)
+	for name, values := range cBase.SessionVariables {
+		base.SessionVariables[name] = append([]string{}, values...)
+	}
+
+	// Campaign
+	if err := cBase.Campaign.Validate(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	base.Campaign = make(map[string]int64)
+	for name, weight := range cBase.Campaign {
+		base.Campaign[name] = weight
+	}
 
 	// Types.Parameters
 	if err := cBase.Types.Parameters.Normalize(); err != nil {
 		return nil, errors.Wrap(err)
 	}
 	base.Types.Binary.Length = ranges.NewInt(cBase.Types.Parameters.BinaryLength)
+	base.Types.Binary.UUIDProbability = cBase.Types.Parameters.BinaryUUIDProbability
 	base.Types.Bit.Width = ranges.NewInt(cBase.Types.Parameters.BitWidth)
 	base.Types.Blob.Length = ranges.NewInt(cBase.Types.Parameters.BlobLength)
 	base.Types.Char.Collations = cBase.Types.Parameters.CharCollations
 	base.Types.Char.Length = ranges.NewInt(cBase.Types.Parameters.CharLength)
+	base.Types.Char.UUIDProbability = cBase.Types.Parameters.CharUUIDProbability
 	base.Types.Decimal.Precision = ranges.NewInt(cBase.Types.Parameters.DecimalPrecision)
 	base.Types.Decimal.Scale = ranges.NewInt(cBase.Types.Parameters.DecimalScale)
 	base.Types.Enum.Collations = cBase.Types.Parameters.EnumCollations
 	base.Types.Enum.ElementNameLength = ranges.NewInt(cBase.Types.Parameters.EnumElementNameLength)
 	base.Types.Enum.NumberOfElements = ranges.NewInt(cBase.Types.Parameters.EnumNumberOfElements)
+	base.Types.Linestring.NumberOfPoints = ranges.NewInt(cBase.Types.Parameters.LinestringNumberOfPoints)
+	base.Types.Linestring.SRIDs = cBase.Types.Parameters.LinestringSRIDs
 	base.Types.Longblob.Length = ranges.NewInt(cBase.Types.Parameters.LongblobLength)
 	base.Types.Longtext.Collations = cBase.Types.Parameters.LongtextCollations
 	base.Types.Longtext.Length = ranges.NewInt(cBase.Types.Parameters.LongtextLength)
+	base.Types.Longtext.MaxLengthProbability = cBase.Types.Parameters.LongtextMaxLengthProbability
 	base.Types.Mediumblob.Length = ranges.NewInt(cBase.Types.Parameters.MediumblobLength)
 	base.Types.Mediumtext.Collations = cBase.Types.Parameters.MediumtextCollations
 	base.Types.Mediumtext.Length = ranges.NewInt(cBase.Types.Parameters.MediumtextLength)
+	base.Types.Mediumtext.MaxLengthProbability = cBase.Types.Parameters.MediumtextMaxLengthProbability
+	base.Types.Polygon.NumberOfPoints = ranges.NewInt(cBase.Types.Parameters.PolygonNumberOfPoints)
+	base.Types.Polygon.SRIDs = cBase.Types.Parameters.PolygonSRIDs
 	base.Types.Set.Collations = cBase.Types.Parameters.SetCollations
 	base.Types.Set.ElementNameLength = ranges.NewInt(cBase.Types.Parameters.SetElementNameLength)
 	base.Types.Set.NumberOfElements = ranges.NewInt(cBase.Types.Parameters.SetNumberOfElements)
 	base.Types.Text.Collations = cBase.Types.Parameters.TextCollations
 	base.Types.Text.Length = ranges.NewInt(cBase.Types.Parameters.TextLength)
+	base.Types.Text.MaxLengthProbability = cBase.Types.Parameters.TextMaxLengthProbability
 	base.Types.Tinyblob.Length = ranges.NewInt(cBase.Types.Parameters.TinyblobLength)
 	base.Types.Tinytext.Collations = cBase.Types.Parameters.TinytextCollations
 	base.Types.Tinytext.Length = ranges.NewInt(cBase.Types.Parameters.TinytextLength)
+	base.Types.Tinytext.MaxLengthProbability = cBase.Types.Parameters.TinytextMaxLengthProbability
 	base.Types.Varbinary.Length = ranges.NewInt(cBase.Types.Parameters.VarbinaryLength)
 	base.Types.Varchar.Collations = cBase.Types.Parameters.VarcharCollations
 	base.Types.Varchar.Length = ranges.NewInt(cBase.Types.Parameters.VarcharLength)
+	base.Types.Bigint.DisplayWidthProbability = cBase.Types.Parameters.BigintDisplayWidthProbability
+	base.Types.BigintUnsigned.ZerofillProbability = cBase.Types.Parameters.BigintUnsignedZerofillProbability
+	base.Types.Int.DisplayWidthProbability = cBase.Types.Parameters.IntDisplayWidthProbability
+	base.Types.IntUnsigned.ZerofillProbability = cBase.Types.Parameters.IntUnsignedZerofillProbability
+	base.Types.Mediumint.DisplayWidthProbability = cBase.Types.Parameters.MediumintDisplayWidthProbability
+	base.Types.MediumintUnsigned.ZerofillProbability = cBase.Types.Parameters.MediumintUnsignedZerofillProbability
+	base.Types.Smallint.DisplayWidthProbability = cBase.Types.Parameters.SmallintDisplayWidthProbability
+	base.Types.SmallintUnsigned.ZerofillProbability = cBase.Types.Parameters.SmallintUnsignedZerofillProbability
+	base.Types.Tinyint.DisplayWidthProbability = cBase.Types.Parameters.TinyintDisplayWidthProbability
+	base.Types.TinyintUnsigned.ZerofillProbability = cBase.Types.Parameters.TinyintUnsignedZerofillProbability
 
 	// Types.Distribution
 	if err := cBase.Types.Distribution.Normalize(); err != nil {
@@ -120,12 +227,14 @@ func convertConfigBase(cBase *configBase) (*Base, error) {
 	base.Types.Float.Distribution = ranges.NewInt(cBase.Types.Distribution.Float)
 	base.Types.Int.Distribution = ranges.NewInt(cBase.Types.Distribution.Int)
 	base.Types.IntUnsigned.Distribution = ranges.NewInt(cBase.Types.Distribution.IntUnsigned)
+	base.Types.Linestring.Distribution = ranges.NewInt(cBase.Types.Distribution.Linestring)
 	base.Types.Longblob.Distribution = ranges.NewInt(cBase.Types.Distribution.Longblob)
 	base.Types.Longtext.Distribution = ranges.NewInt(cBase.Types.Distribution.Longtext)
 	base.Types.Mediumblob.Distribution = ranges.NewInt(cBase.Types.Distribution.Mediumblob)
 	base.Types.Mediumint.Distribution = ranges.NewInt(cBase.Types.Distribution.Mediumint)
 	base.Types.MediumintUnsigned.Distribution = ranges.NewInt(cBase.Types.Distribution.MediumintUnsigned)
 	base.Types.Mediumtext.Distribution = ranges.NewInt(cBase.Types.Distribution.Mediumtext)
+	base.Types.Polygon.Distribution = ranges.NewInt(cBase.Types.Distribution.Polygon)
 	base.Types.Set.Distribution = ranges.NewInt(cBase.Types.Distribution.Set)
 	base.Types.Smallint.Distribution = ranges.NewInt(cBase.Types.Distribution.Smallint)
 	base.Types.SmallintUnsigned.Distribution = ranges.NewInt(cBase.Types.Distribution.SmallintUnsigned)
@@ -0,0 +1,53 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parameters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+func TestWidePrimaryKeysRandomCount(t *testing.T) {
+	never := WidePrimaryKeys{Probability: 0, PrimaryKeys: ranges.NewInt([]int64{8, 16})}
+	always := WidePrimaryKeys{Probability: 1, PrimaryKeys: ranges.NewInt([]int64{8, 16})}
+	for i := 0; i < 100; i++ {
+		count, err := never.RandomCount()
+		require.NoError(t, err)
+		require.Equal(t, int64(0), count)
+		count, err = always.RandomCount()
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, count, int64(8))
+		require.LessOrEqual(t, count, int64(16))
+	}
+
+	amounts := configAmounts{
+		Branches:              []int64{1},
+		Tables:                []int64{1},
+		PrimaryKeys:           []int64{1},
+		Columns:               []int64{1},
+		Indexes:               []int64{0},
+		IndexPrefixLength:     []int64{1},
+		ForeignKeyConstraints: []int64{0},
+		Rows:                  []int64{1},
+		IndexDelay:            []int64{0},
+		WidePrimaryKeys:       configWidePKs{Probability: 0.5, PrimaryKeys: []int64{8, 17}},
+	}
+	err := amounts.Normalize()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Amounts.Wide_Primary_Keys.Primary_Keys must not exceed 16")
+}
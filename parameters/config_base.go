@@ -17,9 +17,11 @@ package parameters
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/types"
 )
 
 var (
@@ -32,12 +34,22 @@ var (
 	errDistLowerbound = "%s needs at least one non-zero lower bound"
 )
 
+// commitAuthor matches the authors that are allowed for commits, which are written as "Name <email>".
+var commitAuthor = regexp.MustCompile(`^[^<>\s][^<>]* <[^<>\s]+@[^<>\s]+>$`)
+
+// sessionVariableName matches the names that are allowed for session variables.
+var sessionVariableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // configBase represents the root table in the config file.
 type configBase struct {
 	InvalidNameRegexes    configInvalidNameRegexes    `json:"Invalid_Name_Regexes"`
 	Amounts               configAmounts               `json:"Amounts"`
 	StatementDistribution configStatementDistribution `json:"Statement_Distribution"`
+	SchemaChanges         configSchemaChanges         `json:"Schema_Changes"`
+	Commits               configCommits               `json:"Commits"`
 	Options               configOptions               `json:"Options"`
+	SessionVariables      configSessionVariables      `json:"Session_Variables"`
+	Campaign              configCampaign              `json:"Campaign"`
 	Types                 configTypes                 `json:"Types"`
 }
 
@@ -92,14 +104,29 @@ func (c *configInvalidNameRegexes) Validate() error {
 
 // configAmounts represents the "Amounts" table in the config file.
 type configAmounts struct {
-	Branches              []int64 `json:"Branches"`
-	Tables                []int64 `json:"Tables"`
-	PrimaryKeys           []int64 `json:"Primary_Keys"`
-	Columns               []int64 `json:"Columns"`
-	Indexes               []int64 `json:"Indexes"`
-	ForeignKeyConstraints []int64 `json:"Foreign_Key_Constraints"`
-	Rows                  []int64 `json:"Rows"`
-	IndexDelay            []int64 `json:"Index_Delay"`
+	Branches              []int64           `json:"Branches"`
+	Tables                []int64           `json:"Tables"`
+	PrimaryKeys           []int64           `json:"Primary_Keys"`
+	Columns               []int64           `json:"Columns"`
+	Indexes               []int64           `json:"Indexes"`
+	IndexPrefixLength     []int64           `json:"Index_Prefix_Length"`
+	ForeignKeyConstraints []int64           `json:"Foreign_Key_Constraints"`
+	Rows                  []int64           `json:"Rows"`
+	RowBuckets            []configRowBucket `json:"Row_Buckets"`
+	IndexDelay            []int64           `json:"Index_Delay"`
+	WidePrimaryKeys       configWidePKs     `json:"Wide_Primary_Keys"`
+}
+
+// configRowBucket represents a single entry of "Row_Buckets" in the "Amounts" table.
+type configRowBucket struct {
+	Probability float64 `json:"Probability"`
+	Rows        []int64 `json:"Rows"`
+}
+
+// configWidePKs represents "Wide_Primary_Keys" in the "Amounts" table.
+type configWidePKs struct {
+	Probability float64 `json:"Probability"`
+	PrimaryKeys []int64 `json:"Primary_Keys"`
 }
 
 // Normalize checks if the read values are valid, while normalizing all values to their expected forms.
@@ -134,6 +161,18 @@ func (c *configAmounts) Normalize() error {
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	// Config files written before prefix lengths were configurable do not have this entry, so it defaults to the range
+	// that is shipped in config.toml
+	if len(c.IndexPrefixLength) == 0 {
+		c.IndexPrefixLength = []int64{1, 255}
+	}
+	c.IndexPrefixLength, err = normalizeIntRange(c.IndexPrefixLength, "Amounts.Index_Prefix_Length")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.IndexPrefixLength[0] < 1 {
+		return errors.New(fmt.Sprintf(errRangeMinimum1, "Amounts.Index_Prefix_Length"))
+	}
 	c.ForeignKeyConstraints, err = normalizeIntRange(c.ForeignKeyConstraints, "Amounts.Foreign_Key_Constraints")
 	if err != nil {
 		return errors.Wrap(err)
@@ -142,6 +181,41 @@ func (c *configAmounts) Normalize() error {
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	totalProbability := float64(0)
+	for i := range c.RowBuckets {
+		bucketName := fmt.Sprintf("Amounts.Row_Buckets[%d]", i)
+		if c.RowBuckets[i].Probability < 0 || c.RowBuckets[i].Probability > 1 {
+			return errors.New(fmt.Sprintf(errParameterInvalidProbability, bucketName+".Probability"))
+		}
+		totalProbability += c.RowBuckets[i].Probability
+		c.RowBuckets[i].Rows, err = normalizeIntRange(c.RowBuckets[i].Rows, bucketName+".Rows")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	if totalProbability > 1 {
+		return errors.New(fmt.Sprintf("Amounts.Row_Buckets probabilities must not sum to more than 1, but sum to %g",
+			totalProbability))
+	}
+	if c.WidePrimaryKeys.Probability < 0 || c.WidePrimaryKeys.Probability > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Amounts.Wide_Primary_Keys.Probability"))
+	}
+	// Config files written before wide primary keys were supported do not have this entry, so it defaults to never
+	if len(c.WidePrimaryKeys.PrimaryKeys) == 0 {
+		c.WidePrimaryKeys.PrimaryKeys = []int64{1}
+	}
+	c.WidePrimaryKeys.PrimaryKeys, err = normalizeIntRange(c.WidePrimaryKeys.PrimaryKeys, "Amounts.Wide_Primary_Keys.Primary_Keys")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.WidePrimaryKeys.PrimaryKeys[0] < 1 {
+		return errors.New(fmt.Sprintf(errRangeMinimum1, "Amounts.Wide_Primary_Keys.Primary_Keys"))
+	}
+	// MySQL allows at most 16 columns within a key
+	if c.WidePrimaryKeys.PrimaryKeys[1] > 16 {
+		return errors.New(fmt.Sprintf("Amounts.Wide_Primary_Keys.Primary_Keys must not exceed 16, but is %d",
+			c.WidePrimaryKeys.PrimaryKeys[1]))
+	}
 	c.IndexDelay, err = normalizeIntRange(c.IndexDelay, "Amounts.Index_Delay")
 	if err != nil {
 		return errors.Wrap(err)
@@ -151,10 +225,11 @@ func (c *configAmounts) Normalize() error {
 
 // configStatementDistribution represents the "Statement_Distribution" table in the config file.
 type configStatementDistribution struct {
-	Insert  []int64 `json:"INSERT"`
-	Replace []int64 `json:"REPLACE"`
-	Update  []int64 `json:"UPDATE"`
-	Delete  []int64 `json:"DELETE"`
+	Insert         []int64 `json:"INSERT"`
+	Replace        []int64 `json:"REPLACE"`
+	Update         []int64 `json:"UPDATE"`
+	Delete         []int64 `json:"DELETE"`
+	PreparedInsert []int64 `json:"PREPARED_INSERT"`
 }
 
 // Normalize checks if the read values are valid, while normalizing all values to their expected forms.
@@ -189,32 +264,301 @@ func (c *configStatementDistribution) Normalize() error {
 	if c.Delete[0] > 0 {
 		atLeastOneLowerbound = true
 	}
+	// Config files written before prepared statements were supported do not have this entry, so it defaults to never
+	if len(c.PreparedInsert) == 0 {
+		c.PreparedInsert = []int64{0}
+	}
+	c.PreparedInsert, err = normalizeIntRange(c.PreparedInsert, "Statement_Distribution.PREPARED_INSERT")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.PreparedInsert[0] > 0 {
+		atLeastOneLowerbound = true
+	}
 	if !atLeastOneLowerbound {
 		return errors.New(fmt.Sprintf(errDistLowerbound, "Statement_Distribution"))
 	}
 	return nil
 }
 
+// configSchemaChanges represents the "Schema_Changes" table in the config file.
+type configSchemaChanges struct {
+	RenameColumn            float64    `json:"Rename_Column"`
+	AddColumn               float64    `json:"Add_Column"`
+	DropColumn              float64    `json:"Drop_Column"`
+	ModifyColumn            float64    `json:"Modify_Column"`
+	ModifyColumnConversions [][]string `json:"Modify_Column_Conversions"`
+	AddPrimaryKey           float64    `json:"Add_Primary_Key"`
+	DropPrimaryKey          float64    `json:"Drop_Primary_Key"`
+}
+
+// Validate checks if the read values are valid.
+func (c *configSchemaChanges) Validate() error {
+	if c.RenameColumn < 0 || c.RenameColumn > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Schema_Changes.Rename_Column"))
+	}
+	if c.AddColumn < 0 || c.AddColumn > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Schema_Changes.Add_Column"))
+	}
+	if c.DropColumn < 0 || c.DropColumn > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Schema_Changes.Drop_Column"))
+	}
+	if c.ModifyColumn < 0 || c.ModifyColumn > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Schema_Changes.Modify_Column"))
+	}
+	if c.AddPrimaryKey < 0 || c.AddPrimaryKey > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Schema_Changes.Add_Primary_Key"))
+	}
+	if c.DropPrimaryKey < 0 || c.DropPrimaryKey > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Schema_Changes.Drop_Primary_Key"))
+	}
+	for _, conversion := range c.ModifyColumnConversions {
+		if len(conversion) != 2 {
+			return errors.New(fmt.Sprintf("Schema_Changes.Modify_Column_Conversions must only contain pairs of types, but has %v", conversion))
+		}
+		if !types.IsLosslessModification(conversion[0], conversion[1]) {
+			return errors.New(fmt.Sprintf("Schema_Changes.Modify_Column_Conversions cannot losslessly convert %s to %s",
+				conversion[0], conversion[1]))
+		}
+	}
+	return nil
+}
+
+// configCommits represents the "Commits" table in the config file.
+type configCommits struct {
+	Messages      []string `json:"Messages"`
+	MessageLength []int64  `json:"Message_Length"`
+	Authors       []string `json:"Authors"`
+}
+
+// Normalize checks if the read values are valid, while normalizing all values to their expected forms.
+func (c *configCommits) Normalize() error {
+	for _, message := range c.Messages {
+		if !isASCII(message) {
+			return errors.New(fmt.Sprintf(errASCIIOnly, "Commits.Messages"))
+		}
+		if len(strings.TrimSpace(message)) == 0 {
+			return errors.New("Commits.Messages cannot contain an empty message")
+		}
+	}
+	// Config files written before message lengths were configurable do not have this entry, so it defaults to the range
+	// that is shipped in config.toml
+	if len(c.MessageLength) == 0 {
+		c.MessageLength = []int64{1, 100}
+	}
+	var err error
+	c.MessageLength, err = normalizeIntRange(c.MessageLength, "Commits.Message_Length")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.MessageLength[0] < 1 {
+		return errors.New(fmt.Sprintf(errRangeMinimum1, "Commits.Message_Length"))
+	}
+	for _, author := range c.Authors {
+		if !isASCII(author) {
+			return errors.New(fmt.Sprintf(errASCIIOnly, "Commits.Authors"))
+		}
+		if !commitAuthor.MatchString(author) {
+			return errors.New(fmt.Sprintf(`Commits.Authors must be written as "Name <email>", but has "%s"`, author))
+		}
+	}
+	return nil
+}
+
 // configOptions represents the "Options" table in the config file.
 type configOptions struct {
-	DoltVersion       string `json:"Dolt_Version"`
-	AutoGC            bool   `json:"Auto_GC"`
-	ManualGC          bool   `json:"Manual_GC"`
-	IncludeReadme     bool   `json:"Include_README_Config"`
-	LowerRowsMainOnly bool   `json:"Enforce_Rows_Lower_Bound_on_Main_Only"`
-	Logging           bool   `json:"Logging"`
-	DeleteSuccesses   bool   `json:"Delete_Successful_Runs"`
-	Port              uint64 `json:"Port"`
-	ZipInternalData   bool   `json:"Zip_Internal_Data"`
-	DeleteAfterZip    bool   `json:"Delete_After_Zip"`
+	DoltVersion        string  `json:"Dolt_Version"`
+	AutoGC             bool    `json:"Auto_GC"`
+	ManualGC           bool    `json:"Manual_GC"`
+	IncludeReadme      bool    `json:"Include_README_Config"`
+	LowerRowsMainOnly  bool    `json:"Enforce_Rows_Lower_Bound_on_Main_Only"`
+	BranchWrites       float64 `json:"Branch_Write_Fraction"`
+	Logging            bool    `json:"Logging"`
+	DeleteSuccesses    bool    `json:"Delete_Successful_Runs"`
+	Port               uint64  `json:"Port"`
+	ZipInternalData    bool    `json:"Zip_Internal_Data"`
+	DeleteAfterZip     bool    `json:"Delete_After_Zip"`
+	DivergenceReport   bool    `json:"Divergence_Report"`
+	CLIReadRetries     uint64  `json:"CLI_Read_Retries"`
+	MaxIgnored         uint64  `json:"Max_Consecutive_Ignored"`
+	ValidateOnSwitch   bool    `json:"Validate_On_Switch"`
+	RedundantIndex     float64 `json:"Redundant_Index_Probability"`
+	DescendingIndex    float64 `json:"Descending_Index_Probability"`
+	InvisibleColumn    float64 `json:"Invisible_Column_Probability"`
+	InsertColumnList   float64 `json:"Insert_Column_List_Probability"`
+	EnumName           float64 `json:"Enum_Name_Probability"`
+	EmptyTable         float64 `json:"Empty_Table_Probability"`
+	SchemaLimit        float64 `json:"Schema_Limit_Probability"`
+	IndexesPerColumn   float64 `json:"Max_Indexes_Per_Column"`
+	HotKeys            uint64  `json:"Hot_Keys"`
+	HotKey             float64 `json:"Hot_Key_Probability"`
+	KeyCollision       float64 `json:"Key_Collision_Probability"`
+	Overflow           float64 `json:"Overflow_Probability"`
+	ModernCollation    float64 `json:"Modern_Collation_Probability"`
+	CollationVariant   float64 `json:"Collation_Variant_Probability"`
+	Transaction        float64 `json:"Transaction_Probability"`
+	Rollback           float64 `json:"Transaction_Rollback_Probability"`
+	TransactionSize    uint64  `json:"Transaction_Statements"`
+	StringEscapes      bool    `json:"String_Escapes"`
+	MaxValueBytes      uint64  `json:"Max_Value_Bytes"`
+	InlineBoundary     uint64  `json:"Inline_Boundary"`
+	InlineBoundaryProb float64 `json:"Inline_Boundary_Probability"`
+	StatusInterval     uint64  `json:"Status_Check_Interval"`
+	StorageMetrics     bool    `json:"Storage_Metrics"`
+	RestartValidation  bool    `json:"Restart_Validation"`
+	ChecksumValidation bool    `json:"Checksum_Validation"`
+	Oracle             string  `json:"Oracle"`
+	OracleDSN          string  `json:"Oracle_DSN"`
+
+	// These options are pointers so that a key that is absent can be told apart from an explicit zero, as absent keys
+	// are given their defaults
+	PKAttempts *uint64  `json:"PK_Attempts"`
+	PKDivisor  *float64 `json:"PK_Saturation_Divisor"`
 }
 
-// Validate checks if the read values are valid.
-func (c *configOptions) Validate() error {
+// Normalize checks if the read values are valid, while filling in the defaults of options that are absent from older
+// config files.
+func (c *configOptions) Normalize() error {
 	//TODO: verify that DoltVersion is either empty string, a version number, or valid hash
 	if c.Port > 65535 {
 		return errors.New(fmt.Sprintf("Options.Port must be <= 65535, but is %d", c.Port))
 	}
+	if c.CLIReadRetries > 10 { // Arbitrary limit, as anything higher is likely hiding a real issue
+		return errors.New(fmt.Sprintf("Options.CLI_Read_Retries must be <= 10, but is %d", c.CLIReadRetries))
+	}
+	if c.PKAttempts == nil {
+		defaultAttempts := uint64(100)
+		c.PKAttempts = &defaultAttempts
+	} else if *c.PKAttempts < 1 {
+		return errors.New(fmt.Sprintf("Options.PK_Attempts must be >= 1, but is %d", *c.PKAttempts))
+	}
+	if c.RedundantIndex < 0 || c.RedundantIndex > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Redundant_Index_Probability"))
+	}
+	if c.DescendingIndex < 0 || c.DescendingIndex > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Descending_Index_Probability"))
+	}
+	if c.InvisibleColumn < 0 || c.InvisibleColumn > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Invisible_Column_Probability"))
+	}
+	if c.InsertColumnList < 0 || c.InsertColumnList > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Insert_Column_List_Probability"))
+	}
+	if c.EnumName < 0 || c.EnumName > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Enum_Name_Probability"))
+	}
+	if c.EmptyTable < 0 || c.EmptyTable > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Empty_Table_Probability"))
+	}
+	if c.SchemaLimit < 0 || c.SchemaLimit > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Schema_Limit_Probability"))
+	}
+	if c.BranchWrites < 0 {
+		return errors.New(fmt.Sprintf("Options.Branch_Write_Fraction must be >= 0, but is %g", c.BranchWrites))
+	}
+	if c.InlineBoundaryProb < 0 || c.InlineBoundaryProb > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Inline_Boundary_Probability"))
+	}
+	if c.IndexesPerColumn < 0 {
+		return errors.New(fmt.Sprintf("Options.Max_Indexes_Per_Column must be >= 0, but is %g", c.IndexesPerColumn))
+	}
+	if c.HotKey < 0 || c.HotKey > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Hot_Key_Probability"))
+	}
+	if c.KeyCollision < 0 || c.KeyCollision > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Key_Collision_Probability"))
+	}
+	if c.Overflow < 0 || c.Overflow > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Overflow_Probability"))
+	}
+	if c.ModernCollation < 0 || c.ModernCollation > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Modern_Collation_Probability"))
+	}
+	if c.CollationVariant < 0 || c.CollationVariant > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Collation_Variant_Probability"))
+	}
+	// SQLite compares strings by their bytes, so it would disagree with Dolt on which variants are duplicates
+	if c.CollationVariant > 0 && strings.ToLower(c.Oracle) != "mysql" {
+		return errors.New(`Options.Collation_Variant_Probability requires Options.Oracle to be "mysql"`)
+	}
+	// SQLite does not have the functions of the checksum query, so it would have to read every row to compute it
+	if c.ChecksumValidation && strings.ToLower(c.Oracle) != "mysql" {
+		return errors.New(`Options.Checksum_Validation requires Options.Oracle to be "mysql"`)
+	}
+	if c.Transaction < 0 || c.Transaction > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Transaction_Probability"))
+	}
+	if c.Rollback < 0 || c.Rollback > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "Options.Transaction_Rollback_Probability"))
+	}
+	if c.Transaction > 0 && c.TransactionSize < 1 {
+		return errors.New(fmt.Sprintf("Options.Transaction_Statements must be >= 1 when transactions are enabled, but is %d",
+			c.TransactionSize))
+	}
+	if c.PKDivisor == nil {
+		defaultDivisor := float64(3)
+		c.PKDivisor = &defaultDivisor
+	} else if *c.PKDivisor < 1 {
+		return errors.New(fmt.Sprintf("Options.PK_Saturation_Divisor must be >= 1, but is %g", *c.PKDivisor))
+	}
+	switch strings.ToLower(c.Oracle) {
+	case "", "sqlite":
+	case "mysql":
+		if c.OracleDSN == "" {
+			return errors.New(`Options.Oracle_DSN must be set when Options.Oracle is "mysql"`)
+		}
+	default:
+		return errors.New(fmt.Sprintf(`Options.Oracle must be "sqlite" or "mysql", but is "%s"`, c.Oracle))
+	}
+	return nil
+}
+
+// configSessionVariables represents the "Session_Variables" table in the config file. Each key is a variable name, with
+// its values being the set of values that the variable may be set to.
+type configSessionVariables map[string][]string
+
+// Validate checks if the read values are valid.
+func (c configSessionVariables) Validate() error {
+	for name, values := range c {
+		if !sessionVariableName.MatchString(name) {
+			return errors.New(fmt.Sprintf("Session_Variables.%s is not a valid variable name", name))
+		}
+		if len(values) == 0 {
+			return errors.New(fmt.Sprintf("Session_Variables.%s must contain at least 1 value, but has none", name))
+		}
+		for _, value := range values {
+			if !isASCII(value) {
+				return errors.New(fmt.Sprintf(errASCIIOnly, "Session_Variables."+name))
+			}
+			if strings.Contains(value, "'") || strings.Contains(value, `\`) {
+				return errors.New(fmt.Sprintf("Session_Variables.%s cannot contain quotes or backslashes", name))
+			}
+		}
+		// These modes change how literals are parsed or how values are returned, which the internal data does not model
+		if strings.ToLower(name) == "sql_mode" {
+			for _, value := range values {
+				for _, mode := range strings.Split(strings.ToUpper(value), ",") {
+					if mode == "NO_BACKSLASH_ESCAPES" || mode == "PAD_CHAR_TO_FULL_LENGTH" {
+						return errors.New(fmt.Sprintf("Session_Variables.sql_mode does not support the mode %s", mode))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// configCampaign represents the "Campaign" table in the config file. Each key is a command name, with its value being
+// the weight that the command is chosen with for each cycle of the campaign command.
+type configCampaign map[string]int64
+
+// Validate checks if the read values are valid.
+func (c configCampaign) Validate() error {
+	for name, weight := range c {
+		if weight < 0 {
+			return errors.New(fmt.Sprintf("Campaign.%s cannot be negative, but is %d", name, weight))
+		}
+	}
 	return nil
 }
 
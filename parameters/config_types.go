@@ -16,15 +16,21 @@ package parameters
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/types"
 )
 
 var (
-	errNoCollationForCharset = "%s has collation %s that does not have a matching character set"
-	errParameterInvalidRange = "%s range bounds cannot be outside of %d-%d"
+	errNoCollationForCharset       = "%s has collation %s that does not have a matching character set"
+	errParameterInvalidRange       = "%s range bounds cannot be outside of %d-%d"
+	errParameterInvalidProbability = "%s must be between 0 and 1"
+	errParameterInvalidSRID        = "%s contains the unsupported SRID %d, the supported SRIDs are 0, 3857, and 4326"
 )
 
 // configTypes represents the "Types" table in the config file.
@@ -33,40 +39,111 @@ type configTypes struct {
 	Distribution configTypeDistribution `json:"Distribution"`
 }
 
+// TypeParameterKeys returns the keys under "Types.Parameters" that configure the type with the given config name (such
+// as "VARCHAR"), in sorted order. Each key belongs to the type whose name is its longest prefix, so that the keys of
+// "INT_UNSIGNED" do not also belong to "INT".
+func TypeParameterKeys(typeName string) []string {
+	typeNames := jsonKeys(reflect.TypeOf(configTypeDistribution{}))
+	var keys []string
+	for _, key := range jsonKeys(reflect.TypeOf(configTypeParameters{})) {
+		owner := ""
+		for _, name := range typeNames {
+			if strings.HasPrefix(key, name+"_") && len(name) > len(owner) {
+				owner = name
+			}
+		}
+		if owner == typeName {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonKeys returns the json keys of every field in the given struct type.
+func jsonKeys(t reflect.Type) []string {
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; key != "" && key != "-" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // configTypeParameters represents the "Parameters" table in the config file, under the "Types" table.
 type configTypeParameters struct {
-	BinaryLength          []int64  `json:"BINARY_Length"`
-	BitWidth              []int64  `json:"BIT_Width"`
-	BlobLength            []int64  `json:"BLOB_Length"`
-	CharCollations        []string `json:"CHAR_Collations"`
-	CharLength            []int64  `json:"CHAR_Length"`
-	DecimalPrecision      []int64  `json:"DECIMAL_Precision"`
-	DecimalScale          []int64  `json:"DECIMAL_Scale"`
-	EnumCollations        []string `json:"ENUM_Collations"`
-	EnumElementNameLength []int64  `json:"ENUM_ElementNameLength"`
-	EnumNumberOfElements  []int64  `json:"ENUM_NumberOfElements"`
-	LongblobLength        []int64  `json:"LONGBLOB_Length"`
-	LongtextCollations    []string `json:"LONGTEXT_Collations"`
-	LongtextLength        []int64  `json:"LONGTEXT_Length"`
-	MediumblobLength      []int64  `json:"MEDIUMBLOB_Length"`
-	MediumtextCollations  []string `json:"MEDIUMTEXT_Collations"`
-	MediumtextLength      []int64  `json:"MEDIUMTEXT_Length"`
-	SetCollations         []string `json:"SET_Collations"`
-	SetElementNameLength  []int64  `json:"SET_ElementNameLength"`
-	SetNumberOfElements   []int64  `json:"SET_NumberOfElements"`
-	TextCollations        []string `json:"TEXT_Collations"`
-	TextLength            []int64  `json:"TEXT_Length"`
-	TinyblobLength        []int64  `json:"TINYBLOB_Length"`
-	TinytextCollations    []string `json:"TINYTEXT_Collations"`
-	TinytextLength        []int64  `json:"TINYTEXT_Length"`
-	VarbinaryLength       []int64  `json:"VARBINARY_Length"`
-	VarcharCollations     []string `json:"VARCHAR_Collations"`
-	VarcharLength         []int64  `json:"VARCHAR_Length"`
+	BigintDisplayWidthProbability        float64  `json:"BIGINT_DisplayWidthProbability"`
+	BigintUnsignedZerofillProbability    float64  `json:"BIGINT_UNSIGNED_ZerofillProbability"`
+	BinaryLength                         []int64  `json:"BINARY_Length"`
+	BinaryUUIDProbability                float64  `json:"BINARY_UUIDProbability"`
+	BitWidth                             []int64  `json:"BIT_Width"`
+	BlobLength                           []int64  `json:"BLOB_Length"`
+	CharCollations                       []string `json:"CHAR_Collations"`
+	CharLength                           []int64  `json:"CHAR_Length"`
+	CharUUIDProbability                  float64  `json:"CHAR_UUIDProbability"`
+	DecimalPrecision                     []int64  `json:"DECIMAL_Precision"`
+	DecimalScale                         []int64  `json:"DECIMAL_Scale"`
+	EnumCollations                       []string `json:"ENUM_Collations"`
+	EnumElementNameLength                []int64  `json:"ENUM_ElementNameLength"`
+	EnumNumberOfElements                 []int64  `json:"ENUM_NumberOfElements"`
+	IntDisplayWidthProbability           float64  `json:"INT_DisplayWidthProbability"`
+	IntUnsignedZerofillProbability       float64  `json:"INT_UNSIGNED_ZerofillProbability"`
+	LinestringNumberOfPoints             []int64  `json:"LINESTRING_NumberOfPoints"`
+	LinestringSRIDs                      []int64  `json:"LINESTRING_SRIDs"`
+	LongblobLength                       []int64  `json:"LONGBLOB_Length"`
+	LongtextCollations                   []string `json:"LONGTEXT_Collations"`
+	LongtextLength                       []int64  `json:"LONGTEXT_Length"`
+	LongtextMaxLengthProbability         float64  `json:"LONGTEXT_MaxLengthProbability"`
+	MediumblobLength                     []int64  `json:"MEDIUMBLOB_Length"`
+	MediumintDisplayWidthProbability     float64  `json:"MEDIUMINT_DisplayWidthProbability"`
+	MediumintUnsignedZerofillProbability float64  `json:"MEDIUMINT_UNSIGNED_ZerofillProbability"`
+	MediumtextCollations                 []string `json:"MEDIUMTEXT_Collations"`
+	MediumtextLength                     []int64  `json:"MEDIUMTEXT_Length"`
+	MediumtextMaxLengthProbability       float64  `json:"MEDIUMTEXT_MaxLengthProbability"`
+	PolygonNumberOfPoints                []int64  `json:"POLYGON_NumberOfPoints"`
+	PolygonSRIDs                         []int64  `json:"POLYGON_SRIDs"`
+	SetCollations                        []string `json:"SET_Collations"`
+	SetElementNameLength                 []int64  `json:"SET_ElementNameLength"`
+	SetNumberOfElements                  []int64  `json:"SET_NumberOfElements"`
+	SmallintDisplayWidthProbability      float64  `json:"SMALLINT_DisplayWidthProbability"`
+	SmallintUnsignedZerofillProbability  float64  `json:"SMALLINT_UNSIGNED_ZerofillProbability"`
+	TextCollations                       []string `json:"TEXT_Collations"`
+	TextLength                           []int64  `json:"TEXT_Length"`
+	TextMaxLengthProbability             float64  `json:"TEXT_MaxLengthProbability"`
+	TinyblobLength                       []int64  `json:"TINYBLOB_Length"`
+	TinyintDisplayWidthProbability       float64  `json:"TINYINT_DisplayWidthProbability"`
+	TinyintUnsignedZerofillProbability   float64  `json:"TINYINT_UNSIGNED_ZerofillProbability"`
+	TinytextCollations                   []string `json:"TINYTEXT_Collations"`
+	TinytextLength                       []int64  `json:"TINYTEXT_Length"`
+	TinytextMaxLengthProbability         float64  `json:"TINYTEXT_MaxLengthProbability"`
+	VarbinaryLength                      []int64  `json:"VARBINARY_Length"`
+	VarcharCollations                    []string `json:"VARCHAR_Collations"`
+	VarcharLength                        []int64  `json:"VARCHAR_Length"`
 }
 
 // Normalize checks if the read values are valid, while normalizing all values to their expected forms.
 func (c *configTypeParameters) Normalize() error {
 	var err error
+	for _, probability := range []struct {
+		value float64
+		name  string
+	}{
+		{c.BigintDisplayWidthProbability, "BIGINT_DisplayWidthProbability"},
+		{c.BigintUnsignedZerofillProbability, "BIGINT_UNSIGNED_ZerofillProbability"},
+		{c.IntDisplayWidthProbability, "INT_DisplayWidthProbability"},
+		{c.IntUnsignedZerofillProbability, "INT_UNSIGNED_ZerofillProbability"},
+		{c.MediumintDisplayWidthProbability, "MEDIUMINT_DisplayWidthProbability"},
+		{c.MediumintUnsignedZerofillProbability, "MEDIUMINT_UNSIGNED_ZerofillProbability"},
+		{c.SmallintDisplayWidthProbability, "SMALLINT_DisplayWidthProbability"},
+		{c.SmallintUnsignedZerofillProbability, "SMALLINT_UNSIGNED_ZerofillProbability"},
+		{c.TinyintDisplayWidthProbability, "TINYINT_DisplayWidthProbability"},
+		{c.TinyintUnsignedZerofillProbability, "TINYINT_UNSIGNED_ZerofillProbability"},
+	} {
+		if probability.value < 0 || probability.value > 1 {
+			return errors.New(fmt.Sprintf(errParameterInvalidProbability, probability.name))
+		}
+	}
 	c.BinaryLength, err = normalizeIntRange(c.BinaryLength, "Types.Parameters.BINARY_Length")
 	if err != nil {
 		return errors.Wrap(err)
@@ -74,6 +151,9 @@ func (c *configTypeParameters) Normalize() error {
 	if c.BinaryLength[0] < 0 || c.BinaryLength[1] > 255 {
 		return errors.New(fmt.Sprintf(errParameterInvalidRange, "BINARY_Length", 0, 255))
 	}
+	if c.BinaryUUIDProbability < 0 || c.BinaryUUIDProbability > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "BINARY_UUIDProbability"))
+	}
 	c.BitWidth, err = normalizeIntRange(c.BitWidth, "Types.Parameters.BIT_Width")
 	if err != nil {
 		return errors.Wrap(err)
@@ -99,6 +179,9 @@ func (c *configTypeParameters) Normalize() error {
 	if c.CharLength[0] < 0 || c.CharLength[1] > 255 {
 		return errors.New(fmt.Sprintf(errParameterInvalidRange, "CHAR_Length", 0, 255))
 	}
+	if c.CharUUIDProbability < 0 || c.CharUUIDProbability > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "CHAR_UUIDProbability"))
+	}
 	c.DecimalPrecision, err = normalizeIntRange(c.DecimalPrecision, "Types.Parameters.DECIMAL_Precision")
 	if err != nil {
 		return errors.Wrap(err)
@@ -131,6 +214,22 @@ func (c *configTypeParameters) Normalize() error {
 	if c.EnumNumberOfElements[0] < 0 || c.EnumNumberOfElements[1] > 65535 {
 		return errors.New(fmt.Sprintf(errParameterInvalidRange, "ENUM_NumberOfElements", 0, 65535))
 	}
+	// Config files written before the spatial types were added do not have this entry, so it defaults to the range that
+	// is shipped in config.toml
+	if len(c.LinestringNumberOfPoints) == 0 {
+		c.LinestringNumberOfPoints = []int64{2, 16}
+	}
+	c.LinestringNumberOfPoints, err = normalizeIntRange(c.LinestringNumberOfPoints, "Types.Parameters.LINESTRING_NumberOfPoints")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.LinestringNumberOfPoints[0] < 2 || c.LinestringNumberOfPoints[1] > 1024 { // Arbitrary limit based on practicality
+		return errors.New(fmt.Sprintf(errParameterInvalidRange, "LINESTRING_NumberOfPoints", 2, 1024))
+	}
+	err = checkSRIDs(c.LinestringSRIDs, "Types.Parameters.LINESTRING_SRIDs")
+	if err != nil {
+		return errors.Wrap(err)
+	}
 	c.LongblobLength, err = normalizeIntRange(c.LongblobLength, "Types.Parameters.LONGBLOB_Length")
 	if err != nil {
 		return errors.Wrap(err)
@@ -149,6 +248,9 @@ func (c *configTypeParameters) Normalize() error {
 	if c.LongtextLength[0] < 0 || c.LongtextLength[1] > 4294967295 {
 		return errors.New(fmt.Sprintf(errParameterInvalidRange, "LONGTEXT_Length", 0, 4294967295))
 	}
+	if c.LongtextMaxLengthProbability < 0 || c.LongtextMaxLengthProbability > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "LONGTEXT_MaxLengthProbability"))
+	}
 	c.MediumblobLength, err = normalizeIntRange(c.MediumblobLength, "Types.Parameters.MEDIUMBLOB_Length")
 	if err != nil {
 		return errors.Wrap(err)
@@ -167,6 +269,25 @@ func (c *configTypeParameters) Normalize() error {
 	if c.MediumtextLength[0] < 0 || c.MediumtextLength[1] > 16777215 {
 		return errors.New(fmt.Sprintf(errParameterInvalidRange, "MEDIUMTEXT_Length", 0, 16777215))
 	}
+	if c.MediumtextMaxLengthProbability < 0 || c.MediumtextMaxLengthProbability > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "MEDIUMTEXT_MaxLengthProbability"))
+	}
+	// Config files written before the spatial types were added do not have this entry, so it defaults to the range that
+	// is shipped in config.toml
+	if len(c.PolygonNumberOfPoints) == 0 {
+		c.PolygonNumberOfPoints = []int64{3, 16}
+	}
+	c.PolygonNumberOfPoints, err = normalizeIntRange(c.PolygonNumberOfPoints, "Types.Parameters.POLYGON_NumberOfPoints")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.PolygonNumberOfPoints[0] < 3 || c.PolygonNumberOfPoints[1] > 1024 { // Arbitrary limit based on practicality
+		return errors.New(fmt.Sprintf(errParameterInvalidRange, "POLYGON_NumberOfPoints", 3, 1024))
+	}
+	err = checkSRIDs(c.PolygonSRIDs, "Types.Parameters.POLYGON_SRIDs")
+	if err != nil {
+		return errors.Wrap(err)
+	}
 	c.SetCollations, err = checkCollations(c.SetCollations, "Types.Parameters.SET_Collations")
 	if err != nil {
 		return errors.Wrap(err)
@@ -196,6 +317,9 @@ func (c *configTypeParameters) Normalize() error {
 	if c.TextLength[0] < 0 || c.TextLength[1] > 65535 {
 		return errors.New(fmt.Sprintf(errParameterInvalidRange, "TEXT_Length", 0, 65535))
 	}
+	if c.TextMaxLengthProbability < 0 || c.TextMaxLengthProbability > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "TEXT_MaxLengthProbability"))
+	}
 	c.TinyblobLength, err = normalizeIntRange(c.TinyblobLength, "Types.Parameters.TINYBLOB_Length")
 	if err != nil {
 		return errors.Wrap(err)
@@ -214,6 +338,9 @@ func (c *configTypeParameters) Normalize() error {
 	if c.TinytextLength[0] < 0 || c.TinytextLength[1] > 255 {
 		return errors.New(fmt.Sprintf(errParameterInvalidRange, "TINYTEXT_Length", 0, 255))
 	}
+	if c.TinytextMaxLengthProbability < 0 || c.TinytextMaxLengthProbability > 1 {
+		return errors.New(fmt.Sprintf(errParameterInvalidProbability, "TINYTEXT_MaxLengthProbability"))
+	}
 	c.VarbinaryLength, err = normalizeIntRange(c.VarbinaryLength, "Types.Parameters.VARBINARY_Length")
 	if err != nil {
 		return errors.Wrap(err)
@@ -251,12 +378,14 @@ type configTypeDistribution struct {
 	Float             []int64 `json:"FLOAT"`
 	Int               []int64 `json:"INT"`
 	IntUnsigned       []int64 `json:"INT_UNSIGNED"`
+	Linestring        []int64 `json:"LINESTRING"`
 	Longblob          []int64 `json:"LONGBLOB"`
 	Longtext          []int64 `json:"LONGTEXT"`
 	Mediumblob        []int64 `json:"MEDIUMBLOB"`
 	Mediumint         []int64 `json:"MEDIUMINT"`
 	MediumintUnsigned []int64 `json:"MEDIUMINT_UNSIGNED"`
 	Mediumtext        []int64 `json:"MEDIUMTEXT"`
+	Polygon           []int64 `json:"POLYGON"`
 	Set               []int64 `json:"SET"`
 	Smallint          []int64 `json:"SMALLINT"`
 	SmallintUnsigned  []int64 `json:"SMALLINT_UNSIGNED"`
@@ -374,6 +503,17 @@ func (c *configTypeDistribution) Normalize() error {
 	if c.IntUnsigned[0] > 0 {
 		atLeastOneLowerbound = true
 	}
+	// Config files written before the spatial types were added do not have this entry, so the type is never chosen
+	if len(c.Linestring) == 0 {
+		c.Linestring = []int64{0}
+	}
+	c.Linestring, err = normalizeIntRange(c.Linestring, "Types.Distribution.LINESTRING")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.Linestring[0] > 0 {
+		atLeastOneLowerbound = true
+	}
 	c.Longblob, err = normalizeIntRange(c.Longblob, "Types.Distribution.LONGBLOB")
 	if err != nil {
 		return errors.Wrap(err)
@@ -416,6 +556,17 @@ func (c *configTypeDistribution) Normalize() error {
 	if c.Mediumtext[0] > 0 {
 		atLeastOneLowerbound = true
 	}
+	// Config files written before the spatial types were added do not have this entry, so the type is never chosen
+	if len(c.Polygon) == 0 {
+		c.Polygon = []int64{0}
+	}
+	c.Polygon, err = normalizeIntRange(c.Polygon, "Types.Distribution.POLYGON")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if c.Polygon[0] > 0 {
+		atLeastOneLowerbound = true
+	}
 	c.Set, err = normalizeIntRange(c.Set, "Types.Distribution.SET")
 	if err != nil {
 		return errors.Wrap(err)
@@ -526,3 +677,13 @@ func checkCollations(collations []string, collationFieldName string) ([]string,
 	}
 	return collations, nil
 }
+
+// checkSRIDs checks that each SRID is supported by the spatial types.
+func checkSRIDs(srids []int64, sridFieldName string) error {
+	for _, srid := range srids {
+		if !types.IsSupportedSRID(srid) {
+			return errors.New(fmt.Sprintf(errParameterInvalidSRID, sridFieldName, srid))
+		}
+	}
+	return nil
+}
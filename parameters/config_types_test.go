@@ -0,0 +1,36 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parameters
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeParameterKeys(t *testing.T) {
+	require.Equal(t, []string{"INT_DisplayWidthProbability"}, TypeParameterKeys("INT"))
+	require.Equal(t, []string{"INT_UNSIGNED_ZerofillProbability"}, TypeParameterKeys("INT_UNSIGNED"))
+	require.Equal(t, []string{"VARCHAR_Collations", "VARCHAR_Length"}, TypeParameterKeys("VARCHAR"))
+	require.Empty(t, TypeParameterKeys("DATE"))
+
+	// Every parameter belongs to exactly one type
+	count := 0
+	for _, typeName := range jsonKeys(reflect.TypeOf(configTypeDistribution{})) {
+		count += len(TypeParameterKeys(typeName))
+	}
+	require.Equal(t, reflect.TypeOf(configTypeParameters{}).NumField(), count)
+}
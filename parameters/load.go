@@ -17,9 +17,13 @@ package parameters
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/dolthub/fuzzer/errors"
 
@@ -48,6 +52,14 @@ func loadFromReader(reader io.Reader) (*Base, error) {
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
+	// Unknown keys are otherwise ignored by json.Unmarshal, which would silently use the default for a misspelled key
+	var contents interface{}
+	if err = json.Unmarshal(jsonBytes, &contents); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if err = validateKeys(contents, reflect.TypeOf(configBase{}), ""); err != nil {
+		return nil, errors.Wrap(err)
+	}
 	base := &configBase{}
 	err = json.Unmarshal(jsonBytes, base)
 	if err != nil {
@@ -55,3 +67,115 @@ func loadFromReader(reader io.Reader) (*Base, error) {
 	}
 	return convertConfigBase(base)
 }
+
+// validateKeys returns an error for the first key in the decoded config that does not match a field of the given type.
+// The error contains the full path of the key (such as "Amounts.Rows"), along with the closest valid key when there is
+// a likely match. Keys are compared ignoring case, the same as json.Unmarshal. Maps accept any key, while values that
+// do not match the shape of the type are left for json.Unmarshal to report.
+func validateKeys(value interface{}, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := make(map[string]reflect.Type)
+		var names []string
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			fields[strings.ToLower(name)] = t.Field(i).Type
+			names = append(names, name)
+		}
+		for _, key := range sortedKeys(table) {
+			fieldType, ok := fields[strings.ToLower(key)]
+			if !ok {
+				msg := fmt.Sprintf("unknown config key `%s%s`", path, key)
+				if suggestion := closestKey(key, names); suggestion != "" {
+					msg += fmt.Sprintf(", did you mean `%s%s`?", path, suggestion)
+				}
+				return errors.New(msg)
+			}
+			if err := validateKeys(table[key], fieldType, path+key+"."); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(table) {
+			if err := validateKeys(table[key], t.Elem(), path+key+"."); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, element := range array {
+			if err := validateKeys(element, t.Elem(), fmt.Sprintf("%s[%d].", strings.TrimSuffix(path, "."), i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the table in sorted order, so that the same key is always reported first.
+func sortedKeys(table map[string]interface{}) []string {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// closestKey returns the valid key that is the fewest edits away from the given key, ignoring case. Returns an empty
+// string when no valid key is close enough to be a likely misspelling.
+func closestKey(key string, validKeys []string) string {
+	closest := ""
+	closestDistance := len(key)/3 + 1
+	for _, validKey := range validKeys {
+		if distance := editDistance(strings.ToLower(key), strings.ToLower(validKey)); distance <= closestDistance {
+			closest = validKey
+			closestDistance = distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between both strings.
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parameters
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownConfigKeys(t *testing.T) {
+	contents, err := os.ReadFile("../config.toml")
+	require.NoError(t, err)
+	_, err = LoadFromString(string(contents))
+	require.NoError(t, err)
+
+	_, err = LoadFromString("[Amonts]\nRows = [1, 2]\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown config key `Amonts`, did you mean `Amounts`?")
+
+	_, err = LoadFromString("[Options]\nLoging = true\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown config key `Options.Loging`, did you mean `Options.Logging`?")
+
+	_, err = LoadFromString("[Types.Parameters]\nVARCHAR_Lenght = [1, 10]\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown config key `Types.Parameters.VARCHAR_Lenght`, did you mean `Types.Parameters.VARCHAR_Length`?")
+
+	_, err = LoadFromString("[Options]\nNot_A_Real_Option = 1\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown config key `Options.Not_A_Real_Option`")
+	require.NotContains(t, err.Error(), "did you mean")
+}
+
+func TestMissingIndexPrefixLength(t *testing.T) {
+	contents, err := os.ReadFile("../config.toml")
+	require.NoError(t, err)
+	var lines []string
+	for _, line := range strings.Split(string(contents), "\n") {
+		if !strings.HasPrefix(line, "Index_Prefix_Length") {
+			lines = append(lines, line)
+		}
+	}
+	base, err := LoadFromString(strings.Join(lines, "\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), base.Amounts.IndexPrefixLength.Lowerbound)
+	require.Equal(t, int64(255), base.Amounts.IndexPrefixLength.Upperbound)
+}
+
+func TestMissingPrimaryKeyOptions(t *testing.T) {
+	contents, err := os.ReadFile("../config.toml")
+	require.NoError(t, err)
+	var lines []string
+	for _, line := range strings.Split(string(contents), "\n") {
+		if !strings.HasPrefix(line, "PK_Attempts") && !strings.HasPrefix(line, "PK_Saturation_Divisor") {
+			lines = append(lines, line)
+		}
+	}
+	base, err := LoadFromString(strings.Join(lines, "\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(100), base.Options.PKAttempts)
+	require.Equal(t, float64(3), base.Options.PKSaturationDivisor)
+
+	// Values that were set explicitly are still validated
+	explicit := strings.Replace(string(contents), "PK_Attempts = 100", "PK_Attempts = 0", 1)
+	_, err = LoadFromString(explicit)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Options.PK_Attempts must be >= 1")
+}
+
+func TestMissingMessageLength(t *testing.T) {
+	contents, err := os.ReadFile("../config.toml")
+	require.NoError(t, err)
+	var lines []string
+	for _, line := range strings.Split(string(contents), "\n") {
+		if !strings.HasPrefix(line, "Message_Length") {
+			lines = append(lines, line)
+		}
+	}
+	base, err := LoadFromString(strings.Join(lines, "\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), base.Commits.MessageLength.Lowerbound)
+	require.Equal(t, int64(100), base.Commits.MessageLength.Upperbound)
+}
+
+func TestBaselineConfig(t *testing.T) {
+	// Config files written before any of the optional entries were added must still load
+	contents, err := os.ReadFile("testdata/baseline_config.toml")
+	require.NoError(t, err)
+	base, err := LoadFromString(string(contents))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), base.Types.Linestring.NumberOfPoints.Lowerbound)
+	require.Equal(t, int64(16), base.Types.Linestring.NumberOfPoints.Upperbound)
+	require.Equal(t, int64(3), base.Types.Polygon.NumberOfPoints.Lowerbound)
+	require.Equal(t, int64(16), base.Types.Polygon.NumberOfPoints.Upperbound)
+	require.Equal(t, int64(0), base.Types.Linestring.Distribution.Upperbound)
+	require.Equal(t, int64(0), base.Types.Polygon.Distribution.Upperbound)
+}
@@ -20,7 +20,9 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"sync"
 
 	"github.com/dolthub/fuzzer/errors"
@@ -31,9 +33,13 @@ import (
 // offer a Read(int) function, calls are relatively expensive. We can amortize the cost by generating a large buffer
 // upfront, and reading from the buffer. Additionally, benchmarking on a Windows PC showed a marginal increase in
 // performance when using "crypto/rand" over "math/rand", which also gives better random results.
+//
+// A seed file may be given using SetSeedFile, in which case bytes are read from the file before "crypto/rand". Once the
+// file is exhausted, it is either rewound and read again, or bytes come from "crypto/rand" for the rest of the run.
 
 const (
 	allowedChars       = ` !#$%*+-.0123456789:=@abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ^_|~`
+	escapeChars        = "'\"`\\\n\t"
 	extAlphNumChars    = `0123456789_abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ`
 	allowedCharsLen    = byte(len(allowedChars))
 	extAlphNumCharsLen = byte(len(extAlphNumChars))
@@ -44,6 +50,14 @@ var (
 	buffer = make([]byte, 524288)
 	idx    = 0
 	mutex  = &sync.Mutex{}
+	// stringChars are the characters used by String. This is only modified by SetStringEscapes.
+	stringChars    = allowedChars
+	stringCharsLen = allowedCharsLen
+	// seedFile is the file that bytes are read from before "crypto/rand". This is nil when there is no seed file, or
+	// when it has been exhausted without being repeated.
+	seedFile *os.File
+	// repeatSeed is whether the seed file is rewound once it has been exhausted.
+	repeatSeed bool
 )
 
 func init() {
@@ -56,6 +70,84 @@ func init() {
 	}
 }
 
+// SetSeedFile sets the file that all random bytes are read from. When repeat is true, the file is rewound whenever it
+// has been exhausted, so that a finite file may deterministically feed an arbitrarily long run. Otherwise, bytes are
+// read from "crypto/rand" once the file has been exhausted. This is not safe for concurrent use, and should only be
+// called before any random values are generated, as the buffer is replaced with the beginning of the file. Any previous
+// seed file is closed.
+func SetSeedFile(path string, repeat bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return errors.Wrap(err)
+	}
+	if info.Size() == 0 {
+		_ = file.Close()
+		return errors.New(fmt.Sprintf("seed file '%s' is empty", path))
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if seedFile != nil {
+		_ = seedFile.Close()
+	}
+	seedFile = file
+	repeatSeed = repeat
+	buffer = make([]byte, len(buffer))
+	idx = 0
+	return fill(buffer)
+}
+
+// fill fills the given slice with random bytes, reading from the seed file before "crypto/rand".
+func fill(data []byte) error {
+	n, err := readBytesFromSeedFile(data)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if n == len(data) {
+		return nil
+	}
+	readBytes, err := rand.Read(data[n:])
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if len(data)-n != readBytes {
+		return errors.New(fmt.Sprintf("expected %d but got %d", len(data)-n, readBytes))
+	}
+	return nil
+}
+
+// readBytesFromSeedFile reads as many bytes as possible from the seed file into the given slice, returning the number
+// of bytes read. The seed file is rewound when it has been exhausted and repeatSeed is true, otherwise it is closed.
+func readBytesFromSeedFile(data []byte) (int, error) {
+	total := 0
+	rewound := false
+	for seedFile != nil && total < len(data) {
+		n, err := io.ReadFull(seedFile, data[total:])
+		total += n
+		if err == nil {
+			break
+		}
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			return total, errors.Wrap(err)
+		}
+		// A file that is empty immediately after being rewound would otherwise be rewound forever
+		if repeatSeed && !(rewound && n == 0) {
+			rewound = true
+			if _, err = seedFile.Seek(0, io.SeekStart); err != nil {
+				return total, errors.Wrap(err)
+			}
+		} else {
+			_ = seedFile.Close()
+			seedFile = nil
+		}
+	}
+	return total, nil
+}
+
 // allocateAndReturnBytes returns a slice of bytes with the given length. Each byte slice returned has an independent
 // underlying array, as the requested size may have been larger than the remaining bytes in the buffer.
 func allocateAndReturnBytes(length int) ([]byte, error) {
@@ -68,15 +160,9 @@ func allocateAndReturnBytes(length int) ([]byte, error) {
 		copiedBytes += n
 		if idx >= len(buffer) {
 			idx = 0
-			var createdLength int
 			// Because Bytes returns a slice with the underlying array, we don't want to overwrite any buffers out there
 			buffer = make([]byte, len(buffer))
-			createdLength, err = rand.Read(buffer)
-			if err == nil {
-				if createdLength != len(buffer) {
-					err = errors.New(fmt.Sprintf("expected %d but got %d", len(buffer), createdLength))
-				}
-			}
+			err = fill(buffer)
 		}
 	}
 	mutex.Unlock()
@@ -93,13 +179,12 @@ func Bytes(length int) ([]byte, error) {
 	// versus "crypto/rand".Read().
 	if length > 65536 {
 		data := make([]byte, length)
-		readBytes, err := rand.Read(data)
+		mutex.Lock()
+		err := fill(data)
+		mutex.Unlock()
 		if err != nil {
 			return nil, errors.Wrap(err)
 		}
-		if length != readBytes {
-			return nil, errors.New(fmt.Sprintf("expected %d but got %d", length, readBytes))
-		}
 		return data, nil
 	}
 	// It is significantly quicker to return a slice than allocate an array, but this doesn't handle boundary slices.
@@ -115,21 +200,34 @@ func Bytes(length int) ([]byte, error) {
 }
 
 // String returns a random string. All characters will be ASCII between the inclusive decimal range of 32-126, with
-// characters that are invalid in many contexts excluded, such as quotation characters.
+// characters that are invalid in many contexts excluded, such as quotation characters. If SetStringEscapes has
+// enabled escapes, then quotes, backslashes, newlines, and tabs are also included.
 func String(length int) (string, error) {
 	v, err := Bytes(length)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
 	for i := 0; i < len(v); i++ {
-		v[i] = allowedChars[v[i]%allowedCharsLen]
+		v[i] = stringChars[v[i]%stringCharsLen]
 	}
 	return string(v), nil
 }
 
 // StringCharSize returns the number of the available characters that may be used in a random string returned from String.
 func StringCharSize() int64 {
-	return int64(allowedCharsLen)
+	return int64(stringCharsLen)
+}
+
+// SetStringEscapes sets whether strings returned from String may contain characters that must be escaped, which are
+// quotes, backslashes, newlines, and tabs. This is not safe for concurrent use, and should only be called before any
+// strings are generated.
+func SetStringEscapes(enabled bool) {
+	if enabled {
+		stringChars = allowedChars + escapeChars
+	} else {
+		stringChars = allowedChars
+	}
+	stringCharsLen = byte(len(stringChars))
 }
 
 // StringExtendedAlphanumeric returns a random string. All characters will be alphanumeric, with the addition of the
@@ -151,6 +249,19 @@ func StringExtendedAlphanumericCharSize() int64 {
 	return int64(extAlphNumCharsLen)
 }
 
+// UUID returns the 16 bytes of a random version 4 UUID, as defined by RFC 4122.
+func UUID() ([]byte, error) {
+	v, err := Bytes(16)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	uuid := make([]byte, 16)
+	copy(uuid, v)
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return uuid, nil
+}
+
 // Int8 returns a random int8.
 func Int8() (int8, error) {
 	data, err := Bytes(1)
@@ -0,0 +1,74 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rand
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedFile(t *testing.T) {
+	defer func() {
+		seedFile = nil
+		repeatSeed = false
+	}()
+	path := filepath.Join(t.TempDir(), "seed")
+	require.NoError(t, os.WriteFile(path, []byte{1, 2, 3}, 0644))
+
+	require.NoError(t, SetSeedFile(path, true))
+	data, err := Bytes(8)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3, 1, 2, 3, 1, 2}, data)
+	// Large reads bypass the buffer, but must still follow the repeating pattern
+	data, err = Bytes(100000)
+	require.NoError(t, err)
+	for i := 1; i < len(data); i++ {
+		require.Equal(t, data[i-1]%3+1, data[i])
+	}
+
+	require.NoError(t, SetSeedFile(path, false))
+	data, err = Bytes(3)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, data)
+	require.Nil(t, seedFile)
+
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	require.Error(t, SetSeedFile(path, true))
+}
+
+func TestStructureSeed(t *testing.T) {
+	defer func() {
+		structureSource = nil
+	}()
+	SetStructureSeed(42)
+	first := make([]uint64, 8)
+	for i := range first {
+		v, err := StructureUint64()
+		require.NoError(t, err)
+		first[i] = v
+		// Values drawn in between must not affect the structural decisions
+		_, err = Bytes(i + 1)
+		require.NoError(t, err)
+	}
+	SetStructureSeed(42)
+	for i := range first {
+		v, err := StructureUint64()
+		require.NoError(t, err)
+		require.Equal(t, first[i], v)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rand
+
+import (
+	mathrand "math/rand"
+	"sync"
+)
+
+// Structural decisions, such as which type a column uses, which statement is run next, or whether a probability check
+// passes, normally consume the same bytes as the values that are generated. A change to how a single value is generated
+// therefore changes every structural decision that follows it, and the reverse is also true. SetStructureSeed moves the
+// structural decisions to their own deterministic source, so that the structure of a run and its values may be seeded
+// and reproduced independently of each other.
+
+var (
+	// structureSource is the source of structural decisions. This is nil when no structure seed has been set, in which
+	// case structural decisions are read from the same bytes as values.
+	structureSource *mathrand.Rand
+	structureMutex  = &sync.Mutex{}
+)
+
+// SetStructureSeed sets the seed of the source that structural decisions are read from. This is not safe for concurrent
+// use, and should only be called before any random values are generated.
+func SetStructureSeed(seed int64) {
+	structureMutex.Lock()
+	defer structureMutex.Unlock()
+	structureSource = mathrand.New(mathrand.NewSource(seed))
+}
+
+// StructureUint64 returns a random uint64 for a structural decision. When no structure seed has been set, this is
+// equivalent to Uint64.
+func StructureUint64() (uint64, error) {
+	structureMutex.Lock()
+	if structureSource != nil {
+		v := structureSource.Uint64()
+		structureMutex.Unlock()
+		return v, nil
+	}
+	structureMutex.Unlock()
+	return Uint64()
+}
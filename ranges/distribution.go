@@ -90,7 +90,7 @@ func (d *DistributionCenter) Get(weight float64) (Distributable, error) {
 		weight = 0
 	}
 	for {
-		idx, err := rand.Uint64()
+		idx, err := rand.StructureUint64()
 		if err != nil {
 			return nil, errors.Wrap(err)
 		}
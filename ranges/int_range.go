@@ -58,10 +58,20 @@ func (r *Int) Median() int64 {
 
 // RandomValue returns a random value between the inclusive bounds of the range.
 func (r *Int) RandomValue() (int64, error) {
+	return r.randomValue(rand.Uint64)
+}
+
+// StructureRandomValue is RandomValue for a structural decision, such as the number of tables or columns.
+func (r *Int) StructureRandomValue() (int64, error) {
+	return r.randomValue(rand.StructureUint64)
+}
+
+// randomValue is RandomValue, with the random number read from the given source.
+func (r *Int) randomValue(source func() (uint64, error)) (int64, error) {
 	if r.Lowerbound == r.Upperbound {
 		return r.Lowerbound, nil
 	}
-	v, err := rand.Uint64()
+	v, err := source()
 	if err != nil {
 		return 0, errors.Wrap(err)
 	}
@@ -75,6 +85,16 @@ func (r *Int) RandomValue() (int64, error) {
 // restriction is lower than the upper bound, then it is substituted. If the restriction is lower than the lower bound,
 // then it is returned.
 func (r *Int) RandomValueRestrictUpper(upperRestriction int64) (int64, error) {
+	return r.randomValueRestrictUpper(upperRestriction, rand.Uint64)
+}
+
+// StructureRandomValueRestrictUpper is RandomValueRestrictUpper for a structural decision.
+func (r *Int) StructureRandomValueRestrictUpper(upperRestriction int64) (int64, error) {
+	return r.randomValueRestrictUpper(upperRestriction, rand.StructureUint64)
+}
+
+// randomValueRestrictUpper is RandomValueRestrictUpper, with the random number read from the given source.
+func (r *Int) randomValueRestrictUpper(upperRestriction int64, source func() (uint64, error)) (int64, error) {
 	lowerbound := r.Lowerbound
 	upperbound := r.Upperbound
 	if upperRestriction <= lowerbound {
@@ -84,7 +104,7 @@ func (r *Int) RandomValueRestrictUpper(upperRestriction int64) (int64, error) {
 	} else if lowerbound == upperbound {
 		return upperbound, nil
 	}
-	v, err := rand.Uint64()
+	v, err := source()
 	if err != nil {
 		return 0, errors.Wrap(err)
 	}
@@ -98,6 +118,16 @@ func (r *Int) RandomValueRestrictUpper(upperRestriction int64) (int64, error) {
 // is lower than the current lower bound, then it is substituted. If the expansion is greater than the current lower
 // bound, then it is ignored.
 func (r *Int) RandomValueExpandLower(lowerExpansion int64) (int64, error) {
+	return r.randomValueExpandLower(lowerExpansion, rand.Uint64)
+}
+
+// StructureRandomValueExpandLower is RandomValueExpandLower for a structural decision.
+func (r *Int) StructureRandomValueExpandLower(lowerExpansion int64) (int64, error) {
+	return r.randomValueExpandLower(lowerExpansion, rand.StructureUint64)
+}
+
+// randomValueExpandLower is RandomValueExpandLower, with the random number read from the given source.
+func (r *Int) randomValueExpandLower(lowerExpansion int64, source func() (uint64, error)) (int64, error) {
 	lowerbound := r.Lowerbound
 	upperbound := r.Upperbound
 	if lowerExpansion < r.Lowerbound {
@@ -106,7 +136,7 @@ func (r *Int) RandomValueExpandLower(lowerExpansion int64) (int64, error) {
 	if r.Lowerbound == r.Upperbound {
 		return r.Lowerbound, nil
 	}
-	v, err := rand.Uint64()
+	v, err := source()
 	if err != nil {
 		return 0, errors.Wrap(err)
 	}
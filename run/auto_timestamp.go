@@ -0,0 +1,270 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+const (
+	// autoTimestampBase is the earliest Unix time that is assigned to a timestamp explicitly. It is far enough in the
+	// past that it may never be mistaken for a timestamp that was assigned automatically.
+	autoTimestampBase = 978307200 // 2001-01-01 00:00:00 UTC
+	// autoTimestampSpread is the range of seconds past autoTimestampBase that explicit timestamps are chosen from.
+	autoTimestampSpread = 30 * 24 * 60 * 60
+)
+
+// AutoTimestampTable is a table whose timestamp column is declared with DEFAULT CURRENT_TIMESTAMP and ON UPDATE
+// CURRENT_TIMESTAMP, so that Dolt assigns the current time whenever a row is inserted without a timestamp, or whenever
+// an UPDATE changes any of the row's other columns. The wall clock cannot be predicted exactly, so each row tracks the
+// window of time that its timestamp must fall within, which is measured around the statement that last assigned it.
+// Timestamps that are assigned explicitly are tracked exactly. The table is not part of the internal data, and is read
+// using UNIX_TIMESTAMP so that the session's time zone does not matter.
+type AutoTimestampTable struct {
+	Name string
+	// Type is either TIMESTAMP or DATETIME.
+	Type   string
+	rows   map[int64]*autoTimestampRow
+	nextPK int64
+}
+
+// autoTimestampRow is the expected state of a single row of an AutoTimestampTable.
+type autoTimestampRow struct {
+	value int64
+	// earliest and latest are the bounds, in Unix seconds, that the row's timestamp must fall within. Both are equal
+	// when the timestamp was assigned explicitly.
+	earliest int64
+	latest   int64
+	explicit bool
+}
+
+// NewAutoTimestampTable returns a new AutoTimestampTable with a random name and timestamp type. The table has not yet
+// been created.
+func NewAutoTimestampTable(c *Cycle) (*AutoTimestampTable, error) {
+	tableName, err := newTableName(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	c.usedNames[tableName] = struct{}{}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	typeName := "TIMESTAMP"
+	if roll%2 == 1 {
+		typeName = "DATETIME"
+	}
+	return &AutoTimestampTable{
+		Name: tableName,
+		Type: typeName,
+		rows: make(map[int64]*autoTimestampRow),
+	}, nil
+}
+
+// CreateString returns the CREATE TABLE statement for this table.
+func (at *AutoTimestampTable) CreateString() string {
+	return fmt.Sprintf("CREATE TABLE `%s` (`pk` BIGINT PRIMARY KEY, `v` BIGINT NOT NULL, "+
+		"`ts` %s DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP);", at.Name, at.Type)
+}
+
+// Create creates the table in Dolt.
+func (at *AutoTimestampTable) Create(c *Cycle) error {
+	return c.SqlServer(at.CreateString())
+}
+
+// Drop drops the table from Dolt.
+func (at *AutoTimestampTable) Drop(c *Cycle) error {
+	return c.SqlServer(fmt.Sprintf("DROP TABLE `%s`;", at.Name))
+}
+
+// RunStatement runs a random statement against the table, updating the expected state of every row that it affects.
+// Statements either insert a row with or without a timestamp, update a row's value with or without a timestamp, update
+// a row without changing it, update a range of rows, or delete a row. Statements that need an existing row insert one
+// when the table is empty.
+func (at *AutoTimestampTable) RunStatement(c *Cycle) error {
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if len(at.rows) == 0 {
+		roll = 0
+	}
+	value, err := rand.Int32()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	explicitTs, err := rand.Uint32()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	ts := autoTimestampBase + int64(explicitTs%autoTimestampSpread)
+	pk, err := at.randomPK()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	switch roll % 7 {
+	case 0: // Insert using the default timestamp
+		pk = at.nextPK
+		at.nextPK++
+		earliest, latest, err := at.timedExec(c, fmt.Sprintf("INSERT INTO `%s` (`pk`, `v`) VALUES (%d, %d);",
+			at.Name, pk, value))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		at.rows[pk] = &autoTimestampRow{value: int64(value), earliest: earliest, latest: latest}
+	case 1: // Insert using an explicit timestamp
+		pk = at.nextPK
+		at.nextPK++
+		err = c.SqlServer(fmt.Sprintf("INSERT INTO `%s` VALUES (%d, %d, FROM_UNIXTIME(%d));", at.Name, pk, value, ts))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		at.rows[pk] = &autoTimestampRow{value: int64(value), earliest: ts, latest: ts, explicit: true}
+	case 2: // Update the value, which must update the timestamp
+		row := at.rows[pk]
+		newValue := row.value + 1
+		earliest, latest, err := at.timedExec(c, fmt.Sprintf("UPDATE `%s` SET `v` = %d WHERE `pk` = %d;",
+			at.Name, newValue, pk))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		*row = autoTimestampRow{value: newValue, earliest: earliest, latest: latest}
+	case 3: // Update the value along with an explicit timestamp, which takes precedence
+		row := at.rows[pk]
+		newValue := row.value - 1
+		err = c.SqlServer(fmt.Sprintf("UPDATE `%s` SET `v` = %d, `ts` = FROM_UNIXTIME(%d) WHERE `pk` = %d;",
+			at.Name, newValue, ts, pk))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		*row = autoTimestampRow{value: newValue, earliest: ts, latest: ts, explicit: true}
+	case 4: // Update without changing the row, which must not update the timestamp
+		err = c.SqlServer(fmt.Sprintf("UPDATE `%s` SET `v` = `v` WHERE `pk` = %d;", at.Name, pk))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	case 5: // Update every row from the chosen row onward
+		earliest, latest, err := at.timedExec(c, fmt.Sprintf("UPDATE `%s` SET `v` = `v` + 1 WHERE `pk` >= %d;",
+			at.Name, pk))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		for rowPK, row := range at.rows {
+			if rowPK >= pk {
+				*row = autoTimestampRow{value: row.value + 1, earliest: earliest, latest: latest}
+			}
+		}
+	case 6: // Delete the row
+		if err = c.SqlServer(fmt.Sprintf("DELETE FROM `%s` WHERE `pk` = %d;", at.Name, pk)); err != nil {
+			return errors.Wrap(err)
+		}
+		delete(at.rows, pk)
+	}
+	return nil
+}
+
+// Validate returns an error if Dolt's rows differ from the expected rows, or if any timestamp falls outside of its
+// expected window. Automatically assigned timestamps may be off by the given tolerance in seconds, which accounts for
+// rounding and for any difference between the fuzzer's clock and Dolt's clock. Does nothing when validation is skipped.
+func (at *AutoTimestampTable) Validate(c *Cycle, tolerance int64) error {
+	if c.Planner.Base.Arguments.SkipValidation {
+		return nil
+	}
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	rows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf(
+		"SELECT `pk`, `v`, CAST(UNIX_TIMESTAMP(`ts`) AS SIGNED) FROM `%s` ORDER BY `pk`;", at.Name))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer rows.Close()
+	seen := make(map[int64]struct{})
+	for rows.Next() {
+		var pk, value, ts int64
+		if err = rows.Scan(&pk, &value, &ts); err != nil {
+			return errors.Wrap(err)
+		}
+		seen[pk] = struct{}{}
+		expected, ok := at.rows[pk]
+		if !ok {
+			return errors.New(fmt.Sprintf("On %s table `%s`, Dolt contains the unexpected row with pk %d", at.Type, at.Name, pk))
+		}
+		if value != expected.value {
+			return errors.New(fmt.Sprintf("On %s table `%s`, row %d has the value %d but expected %d",
+				at.Type, at.Name, pk, value, expected.value))
+		}
+		if expected.explicit && ts != expected.earliest {
+			return errors.New(fmt.Sprintf("On %s table `%s`, row %d has the timestamp %s but was explicitly assigned %s",
+				at.Type, at.Name, pk, formatUnixTime(ts), formatUnixTime(expected.earliest)))
+		}
+		if !expected.explicit && (ts < expected.earliest-tolerance || ts > expected.latest+tolerance) {
+			return errors.New(fmt.Sprintf("On %s table `%s`, row %d has the timestamp %s but expected a time from %s to %s",
+				at.Type, at.Name, pk, formatUnixTime(ts), formatUnixTime(expected.earliest), formatUnixTime(expected.latest)))
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return errors.Wrap(err)
+	}
+	for pk := range at.rows {
+		if _, ok := seen[pk]; !ok {
+			return errors.New(fmt.Sprintf("On %s table `%s`, Dolt is missing the row with pk %d", at.Type, at.Name, pk))
+		}
+	}
+	return nil
+}
+
+// timedExec runs the statement, returning the Unix seconds immediately before and after it ran.
+func (at *AutoTimestampTable) timedExec(c *Cycle, statement string) (int64, int64, error) {
+	earliest := time.Now().Unix()
+	if err := c.SqlServer(statement); err != nil {
+		return 0, 0, errors.Wrap(err)
+	}
+	return earliest, time.Now().Unix(), nil
+}
+
+// randomPK returns the primary key of a random existing row. Returns 0 when the table is empty.
+func (at *AutoTimestampTable) randomPK() (int64, error) {
+	if len(at.rows) == 0 {
+		return 0, nil
+	}
+	pks := make([]int64, 0, len(at.rows))
+	for pk := range at.rows {
+		pks = append(pks, pk)
+	}
+	// Map iteration order is random, so the keys are sorted for seeded runs to choose the same row
+	sort.Slice(pks, func(i, j int) bool {
+		return pks[i] < pks[j]
+	})
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	return pks[roll%uint64(len(pks))], nil
+}
+
+// formatUnixTime returns the given Unix seconds as a UTC time.
+func formatUnixTime(seconds int64) string {
+	return time.Unix(seconds, 0).UTC().Format("2006-01-02 15:04:05")
+}
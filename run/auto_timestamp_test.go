@@ -0,0 +1,44 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoTimestampTable(t *testing.T) {
+	c := &Cycle{
+		nameRegexes: &nameRegexes{Tables: regexp.MustCompile("^$")},
+		usedNames:   map[string]struct{}{},
+	}
+	table, err := NewAutoTimestampTable(c)
+	require.NoError(t, err)
+	require.Contains(t, c.usedNames, table.Name)
+	require.Contains(t, []string{"TIMESTAMP", "DATETIME"}, table.Type)
+	require.Equal(t, "CREATE TABLE `"+table.Name+"` (`pk` BIGINT PRIMARY KEY, `v` BIGINT NOT NULL, `ts` "+table.Type+
+		" DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP);", table.CreateString())
+
+	pk, err := table.randomPK()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), pk)
+	table.rows[4] = &autoTimestampRow{}
+	pk, err = table.randomPK()
+	require.NoError(t, err)
+	require.Equal(t, int64(4), pk)
+	require.Equal(t, "2001-01-01 00:00:00", formatUnixTime(autoTimestampBase))
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils"
+)
+
+// BlameTable returns the hash of the commit that last wrote each row of the given table, as of the given commit. The
+// map is keyed by the hash of each row's primary key. Since every commit holds its own copy of each table, the commit
+// that last wrote a row is found by walking backwards through the history, and finding the first commit whose diff
+// against its parent contains the row.
+func BlameTable(head *Commit, tableName string) (map[utils.Hash]string, error) {
+	headTable := head.GetTable(tableName)
+	if headTable == nil {
+		return nil, errors.New(fmt.Sprintf("table `%s` does not exist on commit %s", tableName, head.Hash))
+	}
+	unattributed := make(map[utils.Hash]struct{})
+	cursor, err := headTable.Data.GetRowCursor()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer cursor.Close()
+	for row, ok, err := cursor.NextRow(); ok || err != nil; row, ok, err = cursor.NextRow() {
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		unattributed[RowDiff{To: row}.Key()] = struct{}{}
+	}
+
+	blame := make(map[utils.Hash]string)
+	// TODO: Whenever we can generate commits with multiple parents, we'll need to update this logic
+	for commit := head; len(unattributed) > 0; commit = commit.Parents[0] {
+		var parentTable *Table
+		if len(commit.Parents) > 0 {
+			parentTable = commit.Parents[0].GetTable(tableName)
+		}
+		rowDiffs, err := DiffTables(parentTable, commit.GetTable(tableName))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		for _, rowDiff := range rowDiffs {
+			if rowDiff.Type == DiffType_Removed {
+				continue
+			}
+			key := rowDiff.Key()
+			if _, ok := unattributed[key]; ok {
+				blame[key] = commit.Hash
+				delete(unattributed, key)
+			}
+		}
+		if len(commit.Parents) == 0 && len(unattributed) > 0 {
+			return nil, errors.New(fmt.Sprintf("unable to find the commits that wrote %d rows in table `%s`",
+				len(unattributed), tableName))
+		}
+	}
+	return blame, nil
+}
+
+// GetDoltBlameCursor returns a cursor over Dolt's blame for this table, using the current branch. Each returned row
+// contains the primary key, followed by the hash of the commit that last wrote the row.
+func (t *Table) GetDoltBlameCursor(c *Cycle) (*DoltDataCursor, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	colsToSelect := ""
+	for _, col := range t.PKCols {
+		colsToSelect += fmt.Sprintf("`%s`,", col.Name)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(),
+		fmt.Sprintf("SELECT %s`commit` FROM `dolt_blame_%s`;", colsToSelect, t.Name))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	baselineTemplate := t.Data.ConstructTemplateRow()
+	fullTemplateVals := make([]types.Value, len(t.PKCols)+1)
+	copy(fullTemplateVals, baselineTemplate.Key())
+	fullTemplateVals[len(t.PKCols)] = types.VarcharValue{StringValue: types.StringValue("")}
+	return &DoltDataCursor{
+		rows: outRows,
+		template: Row{
+			Values:    fullTemplateVals,
+			PkColsLen: int32(len(t.PKCols)),
+		},
+		once: &sync.Once{},
+	}, nil
+}
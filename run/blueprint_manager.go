@@ -14,7 +14,12 @@
 
 package run
 
-import "github.com/dolthub/fuzzer/errors"
+import (
+	"math"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
 
 // BlueprintManager handles the blueprint creation and alteration during a cycle's run.
 type BlueprintManager struct{}
@@ -31,17 +36,20 @@ func (m *BlueprintManager) Register(hooks *Hooks) {
 
 // InitializeBlueprint is run when the cycle is initialized. Handles the initialization of the blueprint for this cycle.
 func (m *BlueprintManager) InitializeBlueprint(c *Cycle) error {
-	branchCount, err := c.Planner.Base.Amounts.Branches.RandomValue()
+	branchCount, err := c.DecideValue("branch-count", c.Planner.Base.Amounts.Branches.StructureRandomValue)
 	if err != nil {
 		return errors.Wrap(err)
 	}
 	c.Blueprint.BranchCount = uint64(branchCount)
-	tableCount, err := c.Planner.Base.Amounts.Tables.RandomValue()
+	tableCount, err := c.DecideValue("table-count", c.Planner.Base.Amounts.Tables.StructureRandomValue)
 	if err != nil {
 		return errors.Wrap(err)
 	}
 	c.Blueprint.TableCount = uint64(tableCount)
 	c.Blueprint.TargetRowCount = map[string]map[string]uint64{"main": make(map[string]uint64)}
+	c.Blueprint.BranchWrites = make(map[string]map[string]uint64)
+	c.Blueprint.TableRowBuckets = make(map[string]int)
+	c.Blueprint.EmptyTables = make(map[string]struct{})
 	return nil
 }
 
@@ -54,43 +62,67 @@ func (m *BlueprintManager) UpdateStatementsExecuted(c *Cycle, statement string)
 // NewBranch is run when a new branch has been created.
 func (m *BlueprintManager) NewBranch(c *Cycle, branch *Branch) error {
 	tablesOnThisBranch := make(map[string]uint64)
+	writesOnThisBranch := make(map[string]uint64)
 	currentBranchName := c.GetCurrentBranch().Name
-	var rowCount int64
-	var err error
 	for tableName := range c.Blueprint.TargetRowCount[currentBranchName] {
-		if c.Planner.Base.Options.LowerRowsMainOnly && currentBranchName != "main" {
-			rowCount, err = c.Planner.Base.Amounts.Rows.RandomValueExpandLower(0)
-			if err != nil {
-				return errors.Wrap(err)
-			}
-		} else {
-			rowCount, err = c.Planner.Base.Amounts.Rows.RandomValue()
-			if err != nil {
-				return errors.Wrap(err)
-			}
+		rowCount, err := m.targetRowCount(c, currentBranchName, tableName)
+		if err != nil {
+			return errors.Wrap(err)
 		}
-		tablesOnThisBranch[tableName] = uint64(rowCount)
+		tablesOnThisBranch[tableName] = rowCount
+		// The new branch starts with its parent's rows, which may already meet the target, so it is given writes of its own
+		writesOnThisBranch[tableName] = uint64(math.Ceil(c.Planner.Base.Options.BranchWriteFraction * float64(rowCount)))
 	}
 	c.Blueprint.TargetRowCount[branch.Name] = tablesOnThisBranch
+	c.Blueprint.BranchWrites[branch.Name] = writesOnThisBranch
 	return nil
 }
 
 // NewTable is run when a new table has been created.
 func (m *BlueprintManager) NewTable(c *Cycle, table *Table) error {
 	currentBranchName := c.GetCurrentBranch().Name
-	var rowCount int64
-	var err error
-	if c.Planner.Base.Options.LowerRowsMainOnly && currentBranchName != "main" {
-		rowCount, err = c.Planner.Base.Amounts.Rows.RandomValueExpandLower(0)
+	bucket, err := c.Planner.Base.Amounts.RandomRowBucket()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	c.Blueprint.TableRowBuckets[table.Name] = bucket
+	if c.Planner.Base.Options.EmptyTableProbability > 0 {
+		roll, err := rand.StructureUint64()
 		if err != nil {
 			return errors.Wrap(err)
 		}
-	} else {
-		rowCount, err = c.Planner.Base.Amounts.Rows.RandomValue()
-		if err != nil {
-			return errors.Wrap(err)
+		if float64(roll) < c.Planner.Base.Options.EmptyTableProbability*math.MaxUint64 {
+			c.Blueprint.EmptyTables[table.Name] = struct{}{}
 		}
 	}
-	c.Blueprint.TargetRowCount[currentBranchName][table.Name] = uint64(rowCount)
+	rowCount, err := m.targetRowCount(c, currentBranchName, table.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	c.Blueprint.TargetRowCount[currentBranchName][table.Name] = rowCount
 	return nil
 }
+
+// targetRowCount returns a random target row count for the table on the given branch, using the table's row bucket.
+// Empty tables always target zero rows.
+func (m *BlueprintManager) targetRowCount(c *Cycle, branchName string, tableName string) (uint64, error) {
+	if _, ok := c.Blueprint.EmptyTables[tableName]; ok {
+		return 0, nil
+	}
+	bucket, ok := c.Blueprint.TableRowBuckets[tableName]
+	if !ok {
+		bucket = -1
+	}
+	rows := c.Planner.Base.Amounts.RowRange(bucket)
+	var rowCount int64
+	var err error
+	if c.Planner.Base.Options.LowerRowsMainOnly && branchName != "main" {
+		rowCount, err = rows.StructureRandomValueExpandLower(0)
+	} else {
+		rowCount, err = rows.StructureRandomValue()
+	}
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	return uint64(rowCount), nil
+}
@@ -16,13 +16,18 @@ package run
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/dolthub/fuzzer/errors"
 	"github.com/dolthub/fuzzer/rand"
 	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils"
 )
 
+// maxIndexColumns is the maximum number of columns that an index may contain, which is the limit imposed by MySQL.
+const maxIndexColumns = 16
+
 // Branch represents a dolt branch.
 type Branch struct {
 	Name    string
@@ -113,36 +118,44 @@ func (b *Branch) NewCustomBranch(c *Cycle, branchName string) (*Branch, error) {
 
 // NewTable creates a new random table on the branch.
 func (b *Branch) NewTable(c *Cycle) (*Table, error) {
-	var tableName string
-	var err error
-	for i := 0; i <= 10000000; i++ {
-		tableName, err = rand.StringExtendedAlphanumeric(10)
-		if err != nil {
-			return nil, errors.Wrap(err)
-		}
-		if _, ok := c.usedNames[tableName]; !ok && !c.nameRegexes.Tables.MatchString(tableName) {
-			break
-		}
-		if i == 10000000 {
-			return nil, errors.New("10 million consecutive failed regexes on table name, aborting cycle")
-		}
+	if err := probeSchemaLimits(c); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	tableName, err := newTableName(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
 	}
 	c.usedNames[tableName] = struct{}{}
 
 	parent := b.GetWorkingSet()
-	totalCols, err := c.Planner.Base.Amounts.Columns.RandomValue()
+	totalCols, err := c.Planner.Base.Amounts.Columns.StructureRandomValue()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	pkCount, err := c.Planner.Base.Amounts.PrimaryKeys.StructureRandomValueRestrictUpper(totalCols)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	pkCount, err := c.Planner.Base.Amounts.PrimaryKeys.RandomValueRestrictUpper(totalCols)
+	widePKCount, err := c.Planner.Base.Amounts.WidePrimaryKeys.RandomCount()
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
+	if widePKCount > 0 {
+		pkCount = widePKCount
+		if totalCols < pkCount {
+			totalCols = pkCount
+		}
+	}
 	pkCols := make([]*Column, pkCount)
 	nonPkCols := make([]*Column, totalCols-pkCount)
 
-	for pkIter := 0; pkIter <= 100; pkIter++ {
-		valueCombinations := float64(1)
+	pkAttempts := c.Planner.Base.Options.PKAttempts
+	pkDivisor := c.Planner.Base.Options.PKSaturationDivisor
+	// The table's row bucket is chosen after it has been created, so the key must support the largest bucket
+	rowsUpperbound := c.Planner.Base.Amounts.RowsUpperbound()
+	for pkIter := int64(1); pkIter <= pkAttempts; pkIter++ {
+		// Wide keys may have far more combinations than a float64 can hold, so the combinations are summed as logarithms
+		logValueCombinations := float64(0)
 		for i := 0; i < len(pkCols); i++ {
 			fullType, err := c.pkTypeDist.Get(1)
 			if err != nil {
@@ -152,35 +165,36 @@ func (b *Branch) NewTable(c *Cycle) (*Table, error) {
 			if err != nil {
 				return nil, errors.Wrap(err)
 			}
-			var colName string
-			for j := 0; j <= 10000000; j++ {
-				colName, err = rand.StringExtendedAlphanumeric(6)
-				if err != nil {
-					return nil, errors.Wrap(err)
-				}
-				if _, ok := c.usedNames[colName]; !ok && !c.nameRegexes.Columns.MatchString(colName) {
-					break
-				}
-				if j == 10000000 {
-					return nil, errors.New("10 million consecutive failed regexes on column name, aborting cycle")
-				}
+			colName, err := newColumnName(c)
+			if err != nil {
+				return nil, errors.Wrap(err)
 			}
-			valueCombinations *= typeInstance.MaxValueCount()
+			logValueCombinations += math.Log(typeInstance.MaxValueCount())
 			pkCols[i] = &Column{
 				Name: colName,
 				Type: typeInstance,
 			}
 		}
 		// The divisor controls the relative saturation of the primary key's range. The higher the number, the lower
-		// the max saturation, meaning it is quicker to generate a random key that does not already exist.
-		if (valueCombinations / 3) > float64(c.Planner.Base.Amounts.Rows.Upperbound) {
+		// the max saturation, meaning it is quicker to generate a random key that does not already exist. Keyless
+		// tables allow duplicate rows, so they have no key to saturate.
+		if len(pkCols) == 0 || logValueCombinations-math.Log(pkDivisor) > math.Log(float64(rowsUpperbound)) {
 			for i := 0; i < len(pkCols); i++ {
 				c.usedNames[pkCols[i].Name] = struct{}{}
 			}
 			break
 		}
-		if pkIter == 100 {
-			return nil, errors.New("100 consecutive failed attempts at primary keys conforming to the desired row count, aborting cycle")
+		if pkIter == pkAttempts {
+			pkTypes := make([]string, len(pkCols))
+			for i := 0; i < len(pkCols); i++ {
+				pkTypes[i] = pkCols[i].Type.Name(false)
+			}
+			valueCombinations := math.Exp(logValueCombinations)
+			return nil, errors.New(fmt.Sprintf("%d consecutive failed attempts at primary keys conforming to the desired "+
+				"row count, aborting cycle. The last attempt used the types [%s], which have %g value combinations for "+
+				"an upper bound of %d rows (a ratio of %g, which must be greater than Options.PK_Saturation_Divisor = %g)",
+				pkAttempts, strings.Join(pkTypes, ", "), valueCombinations, rowsUpperbound,
+				valueCombinations/float64(rowsUpperbound), pkDivisor))
 		}
 	}
 
@@ -193,26 +207,36 @@ func (b *Branch) NewTable(c *Cycle) (*Table, error) {
 		if err != nil {
 			return nil, errors.Wrap(err)
 		}
-		var colName string
-		for j := 0; j <= 10000000; j++ {
-			colName, err = rand.StringExtendedAlphanumeric(6)
-			if err != nil {
-				return nil, errors.Wrap(err)
-			}
-			if _, ok := c.usedNames[colName]; !ok && !c.nameRegexes.Columns.MatchString(colName) {
-				break
-			}
-			if j == 10000000 {
-				return nil, errors.New("10 million consecutive failed regexes on column name, aborting cycle")
-			}
+		colName, err := newColumnName(c)
+		if err != nil {
+			return nil, errors.Wrap(err)
 		}
 		c.usedNames[colName] = struct{}{}
+		invisible, err := newColumnInvisible(c)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
 		nonPkCols[i] = &Column{
-			Name: colName,
-			Type: typeInstance,
+			Name:      colName,
+			Type:      typeInstance,
+			Invisible: invisible,
+		}
+	}
+	// A table must have at least one visible column, which keyless tables may otherwise be left without
+	if len(pkCols) == 0 && len(nonPkCols) > 0 {
+		allInvisible := true
+		for _, col := range nonPkCols {
+			allInvisible = allInvisible && col.Invisible
+		}
+		if allInvisible {
+			nonPkCols[0].Invisible = false
 		}
 	}
-	table, err := NewTable(parent, tableName, pkCols, nonPkCols, nil)
+	indexes, err := newIndexes(c, tableName, pkCols, nonPkCols)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	table, err := NewTable(parent, tableName, pkCols, nonPkCols, indexes)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -222,10 +246,326 @@ func (b *Branch) NewTable(c *Cycle) (*Table, error) {
 		Cycle:  c,
 		Param1: table,
 	}
+	for _, index := range indexes {
+		c.hookQueue <- Hook{
+			Type:   HookType_IndexCreated,
+			Cycle:  c,
+			Param1: table,
+			Param2: index,
+		}
+	}
 	return table, c.SqlServer(table.CreateString(false, false))
 }
 
-// Commit adds all of the changes from this branch to the staged set, and then commits those.
+// newTableName returns a random table name that has not yet been used. The name is not marked as used, therefore that
+// is left to the caller.
+func newTableName(c *Cycle) (string, error) {
+	for i := 0; i < 10000000; i++ {
+		tableName, err := rand.StringExtendedAlphanumeric(10)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		if _, ok := c.usedNames[tableName]; !ok && !c.nameRegexes.Tables.MatchString(tableName) {
+			return tableName, nil
+		}
+	}
+	return "", errors.New("10 million consecutive failed regexes on table name, aborting cycle")
+}
+
+// newColumnName returns a random column name that has not yet been used. The name is not marked as used, therefore
+// that is left to the caller.
+func newColumnName(c *Cycle) (string, error) {
+	for j := 0; j < 10000000; j++ {
+		colName, err := rand.StringExtendedAlphanumeric(6)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		if _, ok := c.usedNames[colName]; !ok && !c.nameRegexes.Columns.MatchString(colName) {
+			return colName, nil
+		}
+	}
+	return "", errors.New("10 million consecutive failed regexes on column name, aborting cycle")
+}
+
+// newIndexes returns a random set of indexes over the given columns. String and blob columns may be indexed using a
+// prefix, which is always the case for the TEXT and BLOB families as they require one. Indexes that share the exact
+// same definition are skipped, therefore the returned amount may be less than the amount chosen. Spatial columns are
+// never indexed. Both the number of indexes and their width are capped by the number of indexable non-primary key
+// columns, as a table with few such columns cannot support many distinct indexes.
+func newIndexes(c *Cycle, tableName string, pkCols []*Column, nonPkCols []*Column) ([]*Index, error) {
+	indexCount, err := c.Planner.Base.Amounts.Indexes.StructureRandomValue()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if indexCount == 0 {
+		return nil, nil
+	}
+	var columns []*Column
+	nonPkCount := int64(0)
+	for _, col := range append(append([]*Column{}, pkCols...), nonPkCols...) {
+		if !types.IsSpatial(col.Type) {
+			columns = append(columns, col)
+		}
+	}
+	for _, col := range nonPkCols {
+		if !types.IsSpatial(col.Type) {
+			nonPkCount++
+		}
+	}
+	maxCount, maxWidth := maxIndexesForColumns(c.Planner.Base.Options.MaxIndexesPerColumn, nonPkCount)
+	if indexCount > maxCount {
+		err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Clamping the indexes of table `%s` from %d to %d, as it "+
+			"has %d indexable non-primary key columns", tableName, indexCount, maxCount, nonPkCount))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		indexCount = maxCount
+	}
+	if indexCount == 0 {
+		return nil, nil
+	}
+	usedDefinitions := make(map[string]struct{})
+	var indexes []*Index
+	for i := int64(0); i < indexCount; i++ {
+		// Redundant indexes intentionally bypass the check against duplicate definitions
+		if len(indexes) > 0 && c.Planner.Base.Options.RedundantIndexProbability > 0 {
+			roll, err := rand.StructureUint64()
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			if float64(roll) < c.Planner.Base.Options.RedundantIndexProbability*math.MaxUint64 {
+				colNames, prefixLengths, descending, err := newRedundantIndexColumns(c, indexes[roll%uint64(len(indexes))], columns, int(maxWidth))
+				if err != nil {
+					return nil, errors.Wrap(err)
+				}
+				indexName, err := newIndexName(c)
+				if err != nil {
+					return nil, errors.Wrap(err)
+				}
+				index := NewIndex(indexName, colNames, prefixLengths, false)
+				index.Descending = descending
+				indexes = append(indexes, index)
+				continue
+			}
+		}
+		width, err := rand.StructureUint64()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		width = (width % maxWidth) + 1
+		colRandArray, err := utils.NewRandomArray(int64(len(columns)))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		colNames := make([]string, 0, width)
+		prefixLengths := make([]int64, 0, width)
+		descending := make([]bool, 0, width)
+		keyBytes := int64(0)
+		for j, ok := colRandArray.NextIndex(); ok && uint64(len(colNames)) < width; j, ok = colRandArray.NextIndex() {
+			prefixLength, fits, err := newIndexPrefixLength(c, columns[j], maxIndexKeyBytes-keyBytes, true)
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			if !fits {
+				continue
+			}
+			keyBytes += indexKeyBytes(columns[j], prefixLength)
+			isDescending, err := newIndexDescending(c)
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			colNames = append(colNames, columns[j].Name)
+			prefixLengths = append(prefixLengths, prefixLength)
+			descending = append(descending, isDescending)
+		}
+		definition := fmt.Sprintf("%v%v%v", colNames, prefixLengths, descending)
+		if _, ok := usedDefinitions[definition]; ok {
+			continue
+		}
+		usedDefinitions[definition] = struct{}{}
+
+		indexName, err := newIndexName(c)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		index := NewIndex(indexName, colNames, prefixLengths, false)
+		index.Descending = descending
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// maxIndexesForColumns returns the maximum number of indexes, along with the maximum width of each index, for a table
+// with the given number of indexable non-primary key columns. The count is the column count multiplied by the given
+// ratio (rounded down, but always allowing one index when there is a column), while a ratio of 0 does not limit the
+// count. An index is never wider than the number of such columns, nor wider than MySQL allows.
+func maxIndexesForColumns(ratio float64, nonPkCount int64) (maxCount int64, maxWidth uint64) {
+	if nonPkCount <= 0 {
+		return 0, 0
+	}
+	maxCount = math.MaxInt64
+	if ratio > 0 {
+		maxCount = int64(ratio * float64(nonPkCount))
+		if maxCount < 1 {
+			maxCount = 1
+		}
+	}
+	maxWidth = uint64(nonPkCount)
+	if maxWidth > maxIndexColumns {
+		maxWidth = maxIndexColumns
+	}
+	return maxCount, maxWidth
+}
+
+// newColumnInvisible returns whether a new non-primary key column should be INVISIBLE.
+func newColumnInvisible(c *Cycle) (bool, error) {
+	if c.Planner.Base.Options.InvisibleColumnProbability <= 0 {
+		return false, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return float64(roll) < c.Planner.Base.Options.InvisibleColumnProbability*math.MaxUint64, nil
+}
+
+// newIndexDescending returns whether a new index column should be sorted in descending order.
+func newIndexDescending(c *Cycle) (bool, error) {
+	if c.Planner.Base.Options.DescendingIndexProbability <= 0 {
+		return false, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return float64(roll) < c.Planner.Base.Options.DescendingIndexProbability*math.MaxUint64, nil
+}
+
+// newRedundantIndexColumns returns the columns, prefix lengths, and sort orders of an index that overlaps the given
+// index. The index is either an exact duplicate, a leading prefix of the given index's columns, or the given index's
+// columns followed by an additional column.
+func newRedundantIndexColumns(c *Cycle, base *Index, columns []*Column, maxWidth int) ([]string, []int64, []bool, error) {
+	colNames := append([]string{}, base.Columns...)
+	prefixLengths := append([]int64{}, base.PrefixLengths...)
+	descending := append([]bool{}, base.Descending...)
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err)
+	}
+	switch roll % 3 {
+	case 1: // Leading prefix
+		if len(colNames) > 1 {
+			width := int(roll/3%uint64(len(colNames)-1)) + 1
+			colNames = colNames[:width]
+			prefixLengths = prefixLengths[:width]
+			descending = descending[:width]
+		}
+	case 2: // Extension
+		if len(colNames) >= maxWidth {
+			break
+		}
+		used := make(map[string]struct{})
+		for _, colName := range colNames {
+			used[colName] = struct{}{}
+		}
+		var candidates []*Column
+		for _, col := range columns {
+			if _, ok := used[col.Name]; !ok {
+				candidates = append(candidates, col)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		col := candidates[roll/3%uint64(len(candidates))]
+		keyBytes := int64(0)
+		for i, colName := range colNames {
+			for _, baseCol := range columns {
+				if baseCol.Name == colName {
+					keyBytes += indexKeyBytes(baseCol, prefixLengths[i])
+				}
+			}
+		}
+		prefixLength, fits, err := newIndexPrefixLength(c, col, maxIndexKeyBytes-keyBytes, false)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err)
+		}
+		if !fits {
+			break
+		}
+		isDescending, err := newIndexDescending(c)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err)
+		}
+		colNames = append(colNames, col.Name)
+		prefixLengths = append(prefixLengths, prefixLength)
+		descending = append(descending, isDescending)
+	}
+	return colNames, prefixLengths, descending, nil
+}
+
+// newIndexPrefixLength returns the prefix length of a new index column, where 0 indexes the entire value. Columns of the
+// TEXT and BLOB families always use a prefix, while other string and blob columns randomly use one when optional is
+// true. A column whose entire value would not fit within the remaining bytes of the index's key uses a prefix that does,
+// and returns false when not even a single character fits.
+func newIndexPrefixLength(c *Cycle, col *Column, remainingBytes int64, optional bool) (int64, bool, error) {
+	prefixType, ok := col.Type.(types.PrefixableTypeInstance)
+	if !ok {
+		return 0, indexKeyBytes(col, 0) <= remainingBytes, nil
+	}
+	maxPrefixLength := remainingBytes / indexKeyCharBytes(col.Type)
+	if maxPrefixLength < 1 {
+		return 0, false, nil
+	}
+	usePrefix := prefixType.RequiresPrefix() || indexKeyBytes(col, 0) > remainingBytes
+	if !usePrefix && optional {
+		var err error
+		usePrefix, err = rand.Bool()
+		if err != nil {
+			return 0, false, errors.Wrap(err)
+		}
+	}
+	if !usePrefix {
+		return 0, true, nil
+	}
+	// A prefix of 0 would index the entire value, which the TEXT and BLOB families do not allow, so a type whose values
+	// are always empty still uses a prefix of 1
+	if prefixType.MaxPrefixLength() < maxPrefixLength {
+		maxPrefixLength = prefixType.MaxPrefixLength()
+		if maxPrefixLength < 1 {
+			maxPrefixLength = 1
+		}
+	}
+	prefixLength, err := c.Planner.Base.Amounts.IndexPrefixLength.StructureRandomValueRestrictUpper(maxPrefixLength)
+	if err != nil {
+		return 0, false, errors.Wrap(err)
+	}
+	return prefixLength, true, nil
+}
+
+// newIndexName returns a new unique index name.
+func newIndexName(c *Cycle) (string, error) {
+	var indexName string
+	var err error
+	for j := 0; j <= 10000000; j++ {
+		indexName, err = rand.StringExtendedAlphanumeric(8)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		if _, ok := c.usedNames[indexName]; !ok && !c.nameRegexes.Indexes.MatchString(indexName) {
+			break
+		}
+		if j == 10000000 {
+			return "", errors.New("10 million consecutive failed regexes on index name, aborting cycle")
+		}
+	}
+	c.usedNames[indexName] = struct{}{}
+	return indexName, nil
+}
+
+// Commit adds all of the changes from this branch to the staged set, and then commits those. The current working set
+// becomes the commit, keeping its table data as a snapshot, while the returned working set is a deep copy of it. If
+// there is nothing to commit, then the current working set is returned.
 func (b *Branch) Commit(c *Cycle, verifyCurrentBranch bool) (*Commit, error) {
 	if verifyCurrentBranch {
 		currentBranchName, err := c.CliQuery("branch", "--show-current")
@@ -244,11 +584,27 @@ func (b *Branch) Commit(c *Cycle, verifyCurrentBranch bool) (*Commit, error) {
 	if strings.Contains(repoStatus, "nothing to commit") {
 		return workingSet, nil
 	}
+	if err = c.Planner.Hooks.RunHook(Hook{
+		Type:   HookType_CommitPreCreation,
+		Cycle:  c,
+		Param1: b,
+		Param2: workingSet,
+	}); err != nil {
+		return nil, errors.Wrap(err)
+	}
 	_, err = c.CliQuery("add", "-A")
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	result, err := c.CliQuery("commit", "-m", "COMMITTED")
+	message, err := newCommitMessage(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	author, err := newCommitAuthor(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	result, err := c.CliQuery("commit", "-m", message, "--author", author)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -260,9 +616,21 @@ func (b *Branch) Commit(c *Cycle, verifyCurrentBranch bool) (*Commit, error) {
 		return nil, errors.Wrap(err)
 	}
 	newWorkingSet.Hash = ""
+	newWorkingSet.Message = ""
+	newWorkingSet.Author = ""
 	workingSet.Hash = hash
+	workingSet.Message = message
+	workingSet.Author = author
 	newWorkingSet.Parents = []*Commit{workingSet}
 	b.Commits = append(b.Commits, newWorkingSet)
+	if err = c.Planner.Hooks.RunHook(Hook{
+		Type:   HookType_CommitPostCreation,
+		Cycle:  c,
+		Param1: b,
+		Param2: workingSet,
+	}); err != nil {
+		return nil, errors.Wrap(err)
+	}
 	c.hookQueue <- Hook{
 		Type:   HookType_CommitCreated,
 		Cycle:  c,
@@ -275,3 +643,33 @@ func (b *Branch) Commit(c *Cycle, verifyCurrentBranch bool) (*Commit, error) {
 func (b *Branch) GetWorkingSet() *Commit {
 	return b.Commits[len(b.Commits)-1]
 }
+
+// RestoreTable discards the working set's changes to the given table, replacing it with a copy of the table from the
+// last commit, just as `dolt checkout <table>` does. Returns an error if the table does not exist in both the working
+// set and the last commit.
+func (b *Branch) RestoreTable(tableName string) error {
+	workingSet := b.GetWorkingSet()
+	if len(workingSet.Parents) == 0 {
+		return errors.New(fmt.Sprintf("cannot restore table `%s` on branch `%s` without a commit", tableName, b.Name))
+	}
+	headTable := workingSet.Parents[0].GetTable(tableName)
+	if headTable == nil {
+		return errors.New(fmt.Sprintf("cannot restore table `%s` on branch `%s` as it is not in the last commit",
+			tableName, b.Name))
+	}
+	for i, table := range workingSet.Tables {
+		if table.Name != headTable.Name {
+			continue
+		}
+		restoredTable, err := headTable.Copy()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		restoredTable.Parent = workingSet
+		workingSet.Tables[i] = restoredTable
+		table.Data.Close()
+		return nil
+	}
+	return errors.New(fmt.Sprintf("cannot restore table `%s` on branch `%s` as it is not in the working set",
+		tableName, b.Name))
+}
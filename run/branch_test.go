@@ -0,0 +1,180 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/ranges"
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestNewRedundantIndexColumns(t *testing.T) {
+	c := &Cycle{Planner: &Planner{Base: &parameters.Base{
+		Amounts: parameters.Amounts{IndexPrefixLength: ranges.NewInt([]int64{1, 10})},
+	}}}
+	columns := []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+		{Name: "v1", Type: &types.IntInstance{}},
+		{Name: "v2", Type: &types.IntInstance{}},
+		{Name: "v3", Type: &types.IntInstance{}},
+	}
+	base := NewIndex("base", []string{"v2", "v1"}, []int64{0, 0}, false)
+	base.Descending = []bool{true, false}
+	sawDuplicate, sawPrefix, sawExtension := false, false, false
+	for i := 0; i < 200; i++ {
+		colNames, prefixLengths, descending, err := newRedundantIndexColumns(c, base, columns, maxIndexColumns)
+		require.NoError(t, err)
+		require.Len(t, prefixLengths, len(colNames))
+		require.Len(t, descending, len(colNames))
+		// Columns taken from the base index keep their sort order
+		shared := len(colNames)
+		if shared > len(base.Descending) {
+			shared = len(base.Descending)
+		}
+		require.Equal(t, base.Descending[:shared], descending[:shared])
+		switch len(colNames) {
+		case 1:
+			require.Equal(t, []string{"v2"}, colNames)
+			sawPrefix = true
+		case 2:
+			require.Equal(t, []string{"v2", "v1"}, colNames)
+			sawDuplicate = true
+		case 3:
+			require.Equal(t, []string{"v2", "v1"}, colNames[:2])
+			require.NotContains(t, base.Columns, colNames[2])
+			sawExtension = true
+		default:
+			require.Fail(t, "unexpected index width", "%v", colNames)
+		}
+	}
+	require.True(t, sawDuplicate && sawPrefix && sawExtension)
+	// The base index must not be modified
+	require.Equal(t, []string{"v2", "v1"}, base.Columns)
+
+	// Extensions are not made past the maximum width
+	for i := 0; i < 50; i++ {
+		colNames, _, _, err := newRedundantIndexColumns(c, base, columns, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(colNames), 2)
+	}
+}
+
+func TestNewIndexesCapped(t *testing.T) {
+	c := &Cycle{
+		Planner: &Planner{Base: &parameters.Base{
+			Amounts: parameters.Amounts{
+				Indexes:           ranges.NewInt([]int64{10, 10}),
+				IndexPrefixLength: ranges.NewInt([]int64{1, 10}),
+			},
+			Options: parameters.Options{MaxIndexesPerColumn: 1},
+		}},
+		Logger:      &fakeLogger{},
+		usedNames:   make(map[string]struct{}),
+		nameRegexes: &nameRegexes{Indexes: regexp.MustCompile("^dolt")},
+	}
+	pkCols := []*Column{{Name: "pk", Type: &types.IntInstance{}}}
+	nonPkCols := []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+		{Name: "v2", Type: &types.IntInstance{}},
+	}
+	for i := 0; i < 50; i++ {
+		indexes, err := newIndexes(c, "t", pkCols, nonPkCols)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(indexes), 2)
+		for _, index := range indexes {
+			require.LessOrEqual(t, len(index.Columns), 2)
+		}
+	}
+
+	// Tables without indexable non-primary key columns are never indexed
+	indexes, err := newIndexes(c, "t", pkCols, nil)
+	require.NoError(t, err)
+	require.Empty(t, indexes)
+
+	// A ratio of 0 does not limit the count, while the width is still limited
+	maxCount, maxWidth := maxIndexesForColumns(0, 2)
+	require.Equal(t, int64(math.MaxInt64), maxCount)
+	require.Equal(t, uint64(2), maxWidth)
+	maxCount, maxWidth = maxIndexesForColumns(0.25, 2)
+	require.Equal(t, int64(1), maxCount)
+	require.Equal(t, uint64(2), maxWidth)
+	_, maxWidth = maxIndexesForColumns(2, 100)
+	require.Equal(t, uint64(maxIndexColumns), maxWidth)
+}
+
+func TestNewIndexPrefixLength(t *testing.T) {
+	c := &Cycle{Planner: &Planner{Base: &parameters.Base{
+		Amounts: parameters.Amounts{IndexPrefixLength: ranges.NewInt([]int64{1, 1000})},
+	}}}
+	wide, err := (&types.Varchar{Collations: []string{"utf8mb4_0900_bin"}, Length: ranges.NewInt([]int64{1, 1000})}).Instance()
+	require.NoError(t, err)
+	wideCol := &Column{Name: "v1", Type: wide}
+
+	// A TEXT column whose values are always empty still requires a prefix, which must be at least 1
+	for i := 0; i < 20; i++ {
+		prefixLength, fits, err := newIndexPrefixLength(c, &Column{Name: "t", Type: &types.TextInstance{}}, maxIndexKeyBytes, true)
+		require.NoError(t, err)
+		require.True(t, fits)
+		require.Equal(t, int64(1), prefixLength)
+	}
+	// A column that would exceed the remaining bytes in its entirety is given a prefix that fits
+	for i := 0; i < 20; i++ {
+		prefixLength, fits, err := newIndexPrefixLength(c, wideCol, 400, true)
+		require.NoError(t, err)
+		require.True(t, fits)
+		require.GreaterOrEqual(t, prefixLength, int64(1))
+		require.LessOrEqual(t, indexKeyBytes(wideCol, prefixLength), int64(400))
+	}
+	// Columns that cannot fit at all are rejected
+	_, fits, err := newIndexPrefixLength(c, wideCol, 3, true)
+	require.NoError(t, err)
+	require.False(t, fits)
+	_, fits, err = newIndexPrefixLength(c, &Column{Name: "i", Type: &types.IntInstance{}}, maxFixedKeyBytes-1, true)
+	require.NoError(t, err)
+	require.False(t, fits)
+
+	// Every generated index stays within the key limit, even when many wide columns are indexed together
+	c.Planner.Base.Amounts.Indexes = ranges.NewInt([]int64{10, 10})
+	c.Logger = &fakeLogger{}
+	c.usedNames = make(map[string]struct{})
+	c.nameRegexes = &nameRegexes{Indexes: regexp.MustCompile("^dolt")}
+	var nonPkCols []*Column
+	for i := 0; i < 8; i++ {
+		nonPkCols = append(nonPkCols, &Column{Name: fmt.Sprintf("v%d", i), Type: wide})
+	}
+	columns := append([]*Column{{Name: "pk", Type: &types.IntInstance{}}}, nonPkCols...)
+	for i := 0; i < 20; i++ {
+		indexes, err := newIndexes(c, "t", columns[:1], nonPkCols)
+		require.NoError(t, err)
+		for _, index := range indexes {
+			keyBytes := int64(0)
+			for j, colName := range index.Columns {
+				for _, col := range columns {
+					if col.Name == colName {
+						keyBytes += indexKeyBytes(col, index.PrefixLengths[j])
+					}
+				}
+			}
+			require.LessOrEqual(t, keyBytes, int64(maxIndexKeyBytes), index.String())
+		}
+	}
+}
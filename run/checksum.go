@@ -0,0 +1,62 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+// checksumQuery returns a query that aggregates every row of the table into a single checksum, which does not depend on
+// the order of the rows, so that it may be compared between sources that return their rows in different orders, such
+// as keyless tables. Each row is hashed from the bytes of its values, and the hashes are summed and XORed along with
+// the number of rows, so that duplicate rows each contribute to the checksum. The query is evaluated by the database
+// that holds the rows, so neither side needs to be read by the fuzzer.
+func checksumQuery(table *Table) string {
+	values := make([]string, 0, len(table.PKCols)+len(table.NonPKCols))
+	for _, name := range table.ColumnNames() {
+		values = append(values, fmt.Sprintf("COALESCE(HEX(CAST(`%s` AS BINARY)), 'NULL')", name))
+	}
+	rowHash := fmt.Sprintf("MD5(CONCAT_WS(',', %s))", strings.Join(values, ", "))
+	return fmt.Sprintf("SELECT CONCAT(COUNT(*), ':', COALESCE(CAST(SUM(CRC32(%[1]s)) AS UNSIGNED), 0), ':', "+
+		"BIT_XOR(CAST(CONV(LEFT(%[1]s, 16), 16, 10) AS UNSIGNED)), ':', "+
+		"BIT_XOR(CAST(CONV(RIGHT(%[1]s, 16), 16, 10) AS UNSIGNED))) FROM `%[2]s`;", rowHash, table.Name)
+}
+
+// checksumsMatch returns whether the checksum of the table's rows in the internal data matches the checksum of the
+// table's rows in Dolt, using the current branch. The Oracle evaluates the same query as Dolt, so neither side is read
+// by the fuzzer, which is why this requires a MySQL Oracle.
+func checksumsMatch(c *Cycle, table *Table) (bool, error) {
+	query := checksumQuery(table)
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	var doltChecksum sql.NullString
+	err = dc.Conn.QueryRowContext(context.Background(), query).Scan(&doltChecksum)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	internalChecksum, err := table.Data.QueryScalar(query)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return internalChecksum == doltChecksum, nil
+}
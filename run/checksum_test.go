@@ -0,0 +1,38 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestChecksumQuery(t *testing.T) {
+	table := &Table{
+		Name:      "t",
+		PKCols:    []*Column{{Name: "pk", Type: &types.IntInstance{}}},
+		NonPKCols: []*Column{{Name: "v1", Type: &types.IntInstance{}}, {Name: "v2", Type: &types.IntInstance{}}},
+	}
+	query := checksumQuery(table)
+	// Every column is hashed in order, with NULL kept distinct from every value
+	require.Contains(t, query, "MD5(CONCAT_WS(',', COALESCE(HEX(CAST(`pk` AS BINARY)), 'NULL'), "+
+		"COALESCE(HEX(CAST(`v1` AS BINARY)), 'NULL'), COALESCE(HEX(CAST(`v2` AS BINARY)), 'NULL')))")
+	require.True(t, strings.HasPrefix(query, "SELECT CONCAT(COUNT(*), ':', "))
+	require.True(t, strings.HasSuffix(query, " FROM `t`;"))
+}
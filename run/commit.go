@@ -15,14 +15,22 @@
 package run
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/dolthub/fuzzer/errors"
 )
 
-// Commit represents either a commit or the working set in dolt.
+// Commit represents either a commit or the working set in dolt. Only the working set is ever modified. When a branch
+// is committed, the working set becomes the commit, and a deep copy of it (including each table's data) becomes the new
+// working set. Therefore, every commit holds an immutable snapshot of its tables that may be read at any time, such as
+// to compare against Dolt's history.
 type Commit struct {
-	Hash        string
+	Hash string
+	// Message and Author are the metadata that the commit was created with. The author is written as "Name <email>".
+	// Both are empty for working sets, and for commits that the fuzzer did not create, such as the initial commit.
+	Message     string
+	Author      string
 	Parents     []*Commit
 	Tables      []*Table
 	ForeignKeys []*ForeignKey
@@ -55,10 +63,41 @@ func (c *Commit) Copy() (*Commit, error) {
 	for i := 0; i < len(c.ForeignKeys); i++ {
 		foreignKeys[i] = c.ForeignKeys[i].Copy()
 	}
-	return &Commit{
+	newCommit := &Commit{
 		Hash:        c.Hash,
+		Message:     c.Message,
+		Author:      c.Author,
 		Parents:     parents,
 		Tables:      tables,
 		ForeignKeys: foreignKeys,
-	}, nil
+	}
+	// The copied tables should reference the copied foreign keys, rather than those of the original commit
+	for _, table := range tables {
+		table.Parent = newCommit
+	}
+	return newCommit, nil
+}
+
+// MergeBase returns the latest common ancestor of the two given commits. Returns an error if the commits do not share
+// an ancestor.
+func MergeBase(ours *Commit, theirs *Commit) (*Commit, error) {
+	parent1 := ours
+	parent2 := theirs
+	for {
+		if parent1.Hash == parent2.Hash {
+			return parent1, nil
+		}
+		// TODO: Whenever we can generate commits with multiple parents, we'll need to update this logic
+		if len(parent2.Parents) == 0 {
+			if len(parent1.Parents) == 0 {
+				return nil, errors.New(fmt.Sprintf("the following commits do not have a common ancestor: %s, %s",
+					ours.Hash, theirs.Hash))
+			} else {
+				parent1 = parent1.Parents[0]
+				parent2 = theirs
+			}
+		} else {
+			parent2 = parent2.Parents[0]
+		}
+	}
 }
@@ -0,0 +1,121 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+// commitGraphMessageLength is the number of characters of each commit message that are shown in the commit graph.
+const commitGraphMessageLength = 40
+
+// WriteCommitGraph writes the commit graph of every branch to "commits.dot" in the cycle's directory, using the
+// Graphviz DOT language. The graph is built entirely from the internal data, so it shows the history that the fuzzer
+// expects Dolt to have, which may be rendered using `dot -Tsvg commits.dot -o commits.svg`.
+func WriteCommitGraph(c *Cycle) error {
+	if len(c.branches) == 0 {
+		return nil
+	}
+	err := os.WriteFile(c.Planner.Base.Arguments.RepoWorkingPath+c.Name+"/commits.dot", []byte(CommitGraphDot(c.branches)), 0777)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// CommitGraphDot returns the commit graph of the given branches in the Graphviz DOT language. Each commit points to its
+// parents, with the first parent drawn as a solid edge and any other parents as dashed edges, and each branch points to
+// its head. A branch with a merge in progress also points to a node for its working set, which has every merge parent. Commits are
+// written in the order that they are first reached from the branches, so that the same history always produces the same
+// output.
+func CommitGraphDot(branches []*Branch) string {
+	sb := strings.Builder{}
+	sb.WriteString("digraph commits {\n")
+	sb.WriteString("\trankdir=\"BT\";\n")
+	sb.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	written := make(map[string]struct{})
+	var writeCommit func(commit *Commit)
+	writeCommit = func(commit *Commit) {
+		if _, ok := written[commit.Hash]; ok {
+			return
+		}
+		written[commit.Hash] = struct{}{}
+		label := commit.Hash
+		if len(label) > 8 {
+			label = label[:8]
+		}
+		if message := commitGraphMessage(commit.Message); message != "" {
+			label += "\n" + message
+		}
+		sb.WriteString(fmt.Sprintf("\t%s [label=%s];\n", strconv.Quote(commit.Hash), strconv.Quote(label)))
+		for i, parent := range commit.Parents {
+			style := ""
+			if i > 0 {
+				style = " [style=dashed]"
+			}
+			sb.WriteString(fmt.Sprintf("\t%s -> %s%s;\n", strconv.Quote(commit.Hash), strconv.Quote(parent.Hash), style))
+		}
+		for _, parent := range commit.Parents {
+			writeCommit(parent)
+		}
+	}
+	for _, branch := range branches {
+		workingSet := branch.GetWorkingSet()
+		branchNode := strconv.Quote("branch:" + branch.Name)
+		sb.WriteString(fmt.Sprintf("\t%s [label=%s, shape=ellipse, style=filled, fillcolor=\"lightblue\"];\n",
+			branchNode, strconv.Quote(branch.Name)))
+		if workingSet.Hash != "" {
+			// The branch has no separate working set, so its last commit is its head
+			sb.WriteString(fmt.Sprintf("\t%s -> %s;\n", branchNode, strconv.Quote(workingSet.Hash)))
+			writeCommit(workingSet)
+			continue
+		}
+		if len(workingSet.Parents) == 0 {
+			continue
+		}
+		head := workingSet.Parents[0]
+		sb.WriteString(fmt.Sprintf("\t%s -> %s;\n", branchNode, strconv.Quote(head.Hash)))
+		if len(workingSet.Parents) > 1 {
+			workingSetNode := strconv.Quote("working:" + branch.Name)
+			sb.WriteString(fmt.Sprintf("\t%s [label=%s, style=dashed];\n", workingSetNode,
+				strconv.Quote(branch.Name+" working set (merging)")))
+			sb.WriteString(fmt.Sprintf("\t%s -> %s [style=dotted];\n", branchNode, workingSetNode))
+			for _, parent := range workingSet.Parents {
+				sb.WriteString(fmt.Sprintf("\t%s -> %s;\n", workingSetNode, strconv.Quote(parent.Hash)))
+			}
+		}
+		for _, parent := range workingSet.Parents {
+			writeCommit(parent)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// commitGraphMessage returns the first line of the commit message, shortened to commitGraphMessageLength characters.
+func commitGraphMessage(message string) string {
+	if idx := strings.IndexAny(message, "\r\n"); idx != -1 {
+		message = message[:idx]
+	}
+	if runes := []rune(message); len(runes) > commitGraphMessageLength {
+		message = string(runes[:commitGraphMessageLength-3]) + "..."
+	}
+	return message
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitGraphDot(t *testing.T) {
+	initial := &Commit{Hash: "aaaaaaaaaaaa", Message: "Initialize data repository"}
+	first := &Commit{Hash: "bbbbbbbbbbbb", Message: "first\nsecond line", Parents: []*Commit{initial}}
+	other := &Commit{Hash: "cccccccccccc", Message: "other", Parents: []*Commit{initial}}
+	merge := &Commit{Hash: "dddddddddddd", Message: "merge", Parents: []*Commit{first, other}}
+	branches := []*Branch{
+		{Name: "main", Commits: []*Commit{initial, first, merge, {Parents: []*Commit{merge}}}},
+		{Name: "other", Commits: []*Commit{initial, other, {Parents: []*Commit{other, first}}}},
+	}
+	dot := CommitGraphDot(branches)
+	require.True(t, strings.HasPrefix(dot, "digraph commits {\n"))
+	require.Contains(t, dot, "\"branch:main\" -> \"dddddddddddd\";")
+	require.Contains(t, dot, "\"dddddddddddd\" -> \"bbbbbbbbbbbb\";")
+	require.Contains(t, dot, "\"dddddddddddd\" -> \"cccccccccccc\" [style=dashed];")
+	require.Contains(t, dot, "\"bbbbbbbbbbbb\" [label=\"bbbbbbbb\\nfirst\"];")
+	// Only the branch with a merge in progress has a working set node
+	require.Contains(t, dot, "\"working:other\" -> \"bbbbbbbbbbbb\";")
+	require.NotContains(t, dot, "working:main")
+	// Every commit is written once, even when it is reachable from several branches
+	require.Equal(t, 1, strings.Count(dot, "\"aaaaaaaaaaaa\" [label="))
+	require.Equal(t, dot, CommitGraphDot(branches))
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// commitMessageChars are the characters that random commit messages are built from. Beyond alphanumerics, these
+// contain quotes, backslashes, newlines, and non-ASCII characters, to ensure that messages are passed to Dolt and stored
+// verbatim.
+var commitMessageChars = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 .,:;!?-_()[]{}'\"`\\$%&*#@=+\n\téüñßøΩλЖ中文日本語한국😀🚀")
+
+// commitAuthorChars are the characters that the names of random commit authors are built from.
+var commitAuthorChars = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 .'-éüñøΩЖ中文")
+
+// newCommitMessage returns a message for the next commit, which is chosen from the configured messages if any exist.
+func newCommitMessage(c *Cycle) (string, error) {
+	if len(c.Planner.Base.Commits.Messages) > 0 {
+		idx, err := rand.Uint64()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		return c.Planner.Base.Commits.Messages[idx%uint64(len(c.Planner.Base.Commits.Messages))], nil
+	}
+	length, err := c.Planner.Base.Commits.MessageLength.RandomValue()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return randomCommitString(commitMessageChars, int(length))
+}
+
+// newCommitAuthor returns an author for the next commit, written as "Name <email>". The author is chosen from the
+// configured authors if any exist.
+func newCommitAuthor(c *Cycle) (string, error) {
+	if len(c.Planner.Base.Commits.Authors) > 0 {
+		idx, err := rand.Uint64()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		return c.Planner.Base.Commits.Authors[idx%uint64(len(c.Planner.Base.Commits.Authors))], nil
+	}
+	nameLength, err := rand.Uint8()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	name, err := randomCommitString(commitAuthorChars, int(nameLength%32)+1)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	user, err := rand.StringExtendedAlphanumeric(int(nameLength%16) + 1)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("%s <%s@example.com>", name, user), nil
+}
+
+// randomCommitString returns a random string of the given length using the given characters. The string never begins
+// or ends with whitespace, as such whitespace may be trimmed when stored.
+func randomCommitString(chars []rune, length int) (string, error) {
+	sb := strings.Builder{}
+	for i := 0; i < length; i++ {
+		idx, err := rand.Uint64()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		char := chars[idx%uint64(len(chars))]
+		if (i == 0 || i == length-1) && unicode.IsSpace(char) {
+			char = 'x'
+		}
+		sb.WriteRune(char)
+	}
+	return sb.String(), nil
+}
+
+// SplitCommitAuthor splits an author written as "Name <email>" into its name and email. Returns the author as the name
+// if it is not written in that form.
+func SplitCommitAuthor(author string) (name string, email string) {
+	idx := strings.LastIndex(author, " <")
+	if idx == -1 || !strings.HasSuffix(author, ">") {
+		return author, ""
+	}
+	return author[:idx], author[idx+2 : len(author)-1]
+}
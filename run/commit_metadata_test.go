@@ -0,0 +1,40 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomCommitString(t *testing.T) {
+	for i := 1; i < 200; i++ {
+		message, err := randomCommitString(commitMessageChars, i)
+		require.NoError(t, err)
+		require.Len(t, []rune(message), i)
+		require.Equal(t, strings.TrimSpace(message), message)
+	}
+}
+
+func TestSplitCommitAuthor(t *testing.T) {
+	name, email := SplitCommitAuthor("Jane <Doe> <jane@example.com>")
+	require.Equal(t, "Jane <Doe>", name)
+	require.Equal(t, "jane@example.com", email)
+	name, email = SplitCommitAuthor("no email")
+	require.Equal(t, "no email", name)
+	require.Equal(t, "", email)
+}
@@ -16,6 +16,8 @@ package connection
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"os/exec"
@@ -32,8 +34,13 @@ import (
 // calls to the server to reuse the process and connection (as creating new processes is extremely slow). This
 // connection and its associated process is automatically closed whenever any non-server calls are made (CLI calls being
 // one such example).
+//
+// Session is a dedicated connection within Conn that every generated statement runs on. Session variables and explicit
+// transactions belong to a single session, so they would be lost if the statements that depend on them were spread
+// across the pooled connections.
 type DoltConnection struct {
 	Conn         *dbr.Connection
+	Session      *sql.Conn
 	Process      *os.Process
 	StdErrBuffer *bytes.Buffer
 	dbName       string
@@ -84,12 +91,13 @@ func GetDoltConnection(port int64, dbName string) (*DoltConnection, error) {
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
+	RegisterProcess(doltSqlServer.Process)
 
 	// Wait for the process to start before continuing
 	for exitLoop, timeout := false, time.After(5*time.Second); !exitLoop; {
 		select {
 		case <-timeout:
-			return nil, errors.New("unable to connect to dolt sql-server").Ignorable()
+			return nil, errors.New("unable to connect to dolt sql-server").Ignorable("server-startup-timeout")
 		default:
 			if doltSqlServer.Process != nil {
 				exitLoop = true
@@ -109,7 +117,7 @@ func GetDoltConnection(port int64, dbName string) (*DoltConnection, error) {
 		case <-timeout:
 			_ = conn.Close()
 			_ = doltSqlServer.Process.Kill()
-			return nil, errors.New("unable to connect to dolt sql-server").Ignorable()
+			return nil, errors.New("unable to connect to dolt sql-server").Ignorable("server-startup-timeout")
 		default:
 		}
 	}
@@ -120,8 +128,18 @@ func GetDoltConnection(port int64, dbName string) (*DoltConnection, error) {
 		_ = doltSqlServer.Process.Kill()
 		return nil, errors.Wrap(err)
 	}
+	session, err := conn.Conn(context.Background())
+	if err == nil {
+		_, err = session.ExecContext(context.Background(), fmt.Sprintf("USE `%s`;", dbName))
+	}
+	if err != nil {
+		_ = conn.Close()
+		_ = doltSqlServer.Process.Kill()
+		return nil, errors.Wrap(err)
+	}
 	globalDoltConnection = &DoltConnection{
 		Conn:         conn,
+		Session:      session,
 		Process:      doltSqlServer.Process,
 		StdErrBuffer: stdErrBuffer,
 		dbName:       dbName,
@@ -130,11 +148,40 @@ func GetDoltConnection(port int64, dbName string) (*DoltConnection, error) {
 	return globalDoltConnection, nil
 }
 
+// NewSession returns a dedicated connection to the server, which has its own session and transaction state, unlike
+// the pooled connection. The session should be closed once it is no longer needed.
+func (conn *DoltConnection) NewSession(ctx context.Context) (*sql.Conn, error) {
+	session, err := conn.Conn.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	_, err = session.ExecContext(ctx, fmt.Sprintf("USE `%s`;", conn.dbName))
+	if err != nil {
+		_ = session.Close()
+		return nil, errors.Wrap(err)
+	}
+	return session, nil
+}
+
 // CloseDoltConnections closes all open Dolt connections. If there are no connection, then this is a no-op.
 func CloseDoltConnections() error {
 	return globalDoltConnection.Close()
 }
 
+// StopDoltServers closes all open Dolt connections, and stops every `dolt sql-server` process that is either tracked or
+// listening on the given port. The next call to GetDoltConnection will then start a fresh server, which cannot rely on
+// any state held in the memory of an earlier process.
+func StopDoltServers(port int64) error {
+	if err := CloseDoltConnections(); err != nil {
+		return errors.Wrap(err)
+	}
+	ReapProcesses()
+	if err := KillStaleServers(port); err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
 // Close closes the DoltConnection, which is assumed to be the global. Therefore, this also sets the global connection
 // to nil.
 func (conn *DoltConnection) Close() error {
@@ -144,8 +191,10 @@ func (conn *DoltConnection) Close() error {
 	if conn == nil {
 		return nil
 	}
+	// The session must be returned to the pool before the pool may be closed
+	_ = conn.Session.Close()
 	cErr := conn.Conn.Close()
-	pErr := fuzzer_os.CloseProcess(conn.Process)
+	pErr := stopProcess(conn.Process)
 	// Check errors in reverse order
 	if pErr != nil {
 		return errors.Wrap(pErr)
@@ -0,0 +1,124 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	fuzzer_os "github.com/dolthub/fuzzer/utils/os"
+)
+
+// processShutdownTimeout is how long a process is given to exit after being asked to close, before it is killed.
+const processShutdownTimeout = 10 * time.Second
+
+var (
+	processLock sync.Mutex
+	processes   = make(map[int]*os.Process)
+)
+
+// RegisterProcess tracks the given child process, so that it may be reaped by ReapProcesses if it is never closed
+// through its normal path.
+func RegisterProcess(process *os.Process) {
+	processLock.Lock()
+	defer processLock.Unlock()
+	processes[process.Pid] = process
+}
+
+// UnregisterProcess stops tracking the given child process, which should only be called once it has exited.
+func UnregisterProcess(process *os.Process) {
+	processLock.Lock()
+	defer processLock.Unlock()
+	delete(processes, process.Pid)
+}
+
+// ReapProcesses kills every tracked child process that is still running, and waits for each to exit. This is intended
+// to be called once all connections have been closed, so that a panic or unexpected error cannot leave a process
+// holding onto the port.
+func ReapProcesses() {
+	processLock.Lock()
+	remaining := make([]*os.Process, 0, len(processes))
+	for _, process := range processes {
+		remaining = append(remaining, process)
+	}
+	processes = make(map[int]*os.Process)
+	processLock.Unlock()
+
+	for _, process := range remaining {
+		_ = process.Kill()
+		_, _ = process.Wait()
+	}
+}
+
+// stopProcess asks the process to close, killing it if it has not exited within the shutdown timeout. The process is
+// no longer tracked once it has exited.
+func stopProcess(process *os.Process) error {
+	closeErr := fuzzer_os.CloseProcess(process)
+	exited := make(chan struct{})
+	go func() {
+		_, _ = process.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(processShutdownTimeout):
+		_ = process.Kill()
+		<-exited
+	}
+	UnregisterProcess(process)
+	if closeErr != nil {
+		return errors.Wrap(closeErr)
+	}
+	return nil
+}
+
+// KillStaleServers kills every `dolt sql-server` process that is listening on the given port, which were most likely
+// left behind by a previous run of the fuzzer. Processes that are tracked by this process are not considered stale.
+func KillStaleServers(port int64) error {
+	portArg := fmt.Sprintf("-P=%d", port)
+	pids, err := fuzzer_os.FindProcesses(func(args []string) bool {
+		if len(args) < 2 || strings.TrimSuffix(filepath.Base(args[0]), ".exe") != "dolt" || args[1] != "sql-server" {
+			return false
+		}
+		for _, arg := range args[2:] {
+			if arg == portArg {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	processLock.Lock()
+	defer processLock.Unlock()
+	for _, pid := range pids {
+		if _, ok := processes[pid]; ok {
+			continue
+		}
+		process, err := os.FindProcess(pid)
+		if err != nil {
+			continue
+		}
+		// The process may have exited since it was found, so errors are ignored
+		_ = process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+// csvField returns the value as a single CSV field. Values that quote themselves are returned as-is, while any other
+// value containing a comma, quote, or line break is quoted following RFC 4180, so that no value may split its row.
+func csvField(val types.Value) string {
+	field := val.CSVString()
+	if len(field) >= 2 && field[0] == '"' && field[len(field)-1] == '"' {
+		return field
+	}
+	if !strings.ContainsAny(field, ",\"\r\n") {
+		return field
+	}
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
+
+// ScanCSVRecords is a split function for a bufio.Scanner that returns each record of a CSV file, without its trailing
+// line break. Line breaks within a quoted field belong to the field, so a record may span several lines.
+func ScanCSVRecords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	inQuotes := false
+	for i, b := range data {
+		switch b {
+		case '"':
+			// A doubled quote within a quoted field toggles twice, which leaves the field quoted
+			inQuotes = !inQuotes
+		case '\n':
+			if !inQuotes {
+				return i + 1, bytes.TrimSuffix(data[:i], []byte{'\r'}), nil
+			}
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bufio"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	rows := []Row{
+		{Values: []types.Value{types.VarcharValue{StringValue: "a,b"}, types.NilValue{}, types.CharValue{StringValue: ""}}},
+		{Values: []types.Value{types.VarcharValue{StringValue: "line\nbreak"}, types.VarcharValue{StringValue: `say "hi"`},
+			types.CharValue{StringValue: "back\\slash\r\n"}}},
+	}
+	sb := strings.Builder{}
+	sb.WriteString("c1,c2,c3\n")
+	for _, row := range rows {
+		sb.WriteString(row.CSVString())
+		sb.WriteString("\n")
+	}
+
+	// Every record is read back exactly as it was written, even when it spans several lines
+	scanner := bufio.NewScanner(strings.NewReader(sb.String()))
+	scanner.Split(ScanCSVRecords)
+	require.True(t, scanner.Scan())
+	require.Equal(t, "c1,c2,c3", scanner.Text())
+	for _, row := range rows {
+		require.True(t, scanner.Scan())
+		require.Equal(t, row.CSVString(), scanner.Text())
+	}
+	require.False(t, scanner.Scan())
+
+	// The file is also valid according to a standard CSV reader
+	records, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"c1", "c2", "c3"},
+		{"a,b", "", ""},
+		{"line\nbreak", `say "hi"`, "back\\slash\n"},
+	}, records)
+}
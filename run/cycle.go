@@ -19,15 +19,20 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	_ "github.com/go-sql-driver/mysql"
 
 	"github.com/dolthub/fuzzer/blueprint"
 	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
 	"github.com/dolthub/fuzzer/ranges"
 	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
 	"github.com/dolthub/fuzzer/utils/file"
 	fuzzer_os "github.com/dolthub/fuzzer/utils/os"
 )
@@ -35,15 +40,23 @@ import (
 // Cycle is the orchestrator of a run cycle, which includes the creation of a repository, as well as the execution of
 // any commands obtained from the planner.
 type Cycle struct {
-	Name          string
-	Planner       *Planner
-	Blueprint     *blueprint.Blueprint
-	Logger        Logger
+	Name      string
+	Planner   *Planner
+	Blueprint *blueprint.Blueprint
+	Logger    Logger
+	// Storage holds the storage metrics of the cycle, which are only measured when enabled.
+	Storage       *StorageMetrics
+	decisions     *decisionLog
 	statementDist *ranges.DistributionCenter
+	transactions  transactionSettings
+	transaction   bool
+	schemaChanges []SchemaChange
 	pkTypeDist    *ranges.DistributionCenter
 	nonPkTypeDist *ranges.DistributionCenter
 	nameRegexes   *nameRegexes
 	usedNames     map[string]struct{}
+	// seedTables are the tables loaded from the seed repository that have yet to be announced through hooks.
+	seedTables    []*Table
 	branches      []*Branch
 	currentBranch int
 	curBranch     *Branch
@@ -51,86 +64,117 @@ type Cycle struct {
 	hookQueue     chan Hook
 }
 
+// PrimaryKeyTypes returns every type that may be chosen for a primary key column.
+func PrimaryKeyTypes(t *parameters.Types) []types.Type {
+	return []types.Type{
+		&t.Bigint,
+		&t.BigintUnsigned,
+		&t.Binary,
+		&t.Bit,
+		&t.Char,
+		&t.Date,
+		&t.Datetime,
+		&t.Decimal,
+		&t.Double,
+		&t.Enum,
+		&t.Float,
+		&t.Int,
+		&t.IntUnsigned,
+		&t.Mediumint,
+		&t.MediumintUnsigned,
+		&t.Set,
+		&t.Smallint,
+		&t.SmallintUnsigned,
+		&t.Time,
+		&t.Timestamp,
+		&t.Tinyint,
+		&t.TinyintUnsigned,
+		&t.Varbinary,
+		&t.Varchar,
+		&t.Year,
+	}
+}
+
+// NonPrimaryKeyTypes returns every type that may be chosen for a non-primary key column.
+func NonPrimaryKeyTypes(t *parameters.Types) []types.Type {
+	return []types.Type{
+		&t.Bigint,
+		&t.BigintUnsigned,
+		&t.Binary,
+		&t.Bit,
+		&t.Blob,
+		&t.Char,
+		&t.Date,
+		&t.Datetime,
+		&t.Decimal,
+		&t.Double,
+		&t.Enum,
+		&t.Float,
+		&t.Int,
+		&t.IntUnsigned,
+		&t.Linestring,
+		&t.Longblob,
+		&t.Longtext,
+		&t.Mediumblob,
+		&t.Mediumint,
+		&t.MediumintUnsigned,
+		&t.Mediumtext,
+		&t.Polygon,
+		&t.Set,
+		&t.Smallint,
+		&t.SmallintUnsigned,
+		&t.Text,
+		&t.Time,
+		&t.Timestamp,
+		&t.Tinyblob,
+		&t.Tinyint,
+		&t.TinyintUnsigned,
+		&t.Tinytext,
+		&t.Varbinary,
+		&t.Varchar,
+		&t.Year,
+	}
+}
+
+// newTypeDistribution returns a *ranges.DistributionCenter that chooses between the given types.
+func newTypeDistribution(typeList []types.Type) (*ranges.DistributionCenter, error) {
+	distributables := make([]ranges.Distributable, len(typeList))
+	for i, t := range typeList {
+		distributables[i] = t
+	}
+	return ranges.NewDistributionCenter(distributables...)
+}
+
 // newCycle returns a *Cycle.
 func newCycle(planner *Planner) (*Cycle, error) {
 	nameRegexes, err := newNameRegexes(planner.Base)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
+	hotKeys := hotKeySettings{
+		count:       planner.Base.Options.HotKeys,
+		probability: planner.Base.Options.HotKeyProbability,
+	}
 	statementDist, err := ranges.NewDistributionCenter(
-		&InsertStatement{planner.Base.StatementDistribution.Insert},
-		&ReplaceStatement{planner.Base.StatementDistribution.Replace},
-		&UpdateStatement{planner.Base.StatementDistribution.Update},
+		&InsertStatement{
+			r:                       planner.Base.StatementDistribution.Insert,
+			columnListProbability:   planner.Base.Options.InsertColumnListProbability,
+			enumNameProbability:     planner.Base.Options.EnumNameProbability,
+			keyCollisionProbability: planner.Base.Options.KeyCollisionProbability,
+		},
+		&ReplaceStatement{planner.Base.StatementDistribution.Replace, hotKeys, planner.Base.Options.KeyCollisionProbability},
+		&UpdateStatement{planner.Base.StatementDistribution.Update, hotKeys},
 		&DeleteStatement{planner.Base.StatementDistribution.Delete},
+		&PreparedInsertStatement{planner.Base.StatementDistribution.PreparedInsert},
 	)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	pkTypeDist, err := ranges.NewDistributionCenter(
-		&planner.Base.Types.Bigint,
-		&planner.Base.Types.BigintUnsigned,
-		&planner.Base.Types.Binary,
-		&planner.Base.Types.Bit,
-		&planner.Base.Types.Char,
-		&planner.Base.Types.Date,
-		&planner.Base.Types.Datetime,
-		&planner.Base.Types.Decimal,
-		&planner.Base.Types.Double,
-		&planner.Base.Types.Enum,
-		&planner.Base.Types.Float,
-		&planner.Base.Types.Int,
-		&planner.Base.Types.IntUnsigned,
-		&planner.Base.Types.Mediumint,
-		&planner.Base.Types.MediumintUnsigned,
-		&planner.Base.Types.Set,
-		&planner.Base.Types.Smallint,
-		&planner.Base.Types.SmallintUnsigned,
-		&planner.Base.Types.Time,
-		&planner.Base.Types.Timestamp,
-		&planner.Base.Types.Tinyint,
-		&planner.Base.Types.TinyintUnsigned,
-		&planner.Base.Types.Varbinary,
-		&planner.Base.Types.Varchar,
-		&planner.Base.Types.Year,
-	)
+	pkTypeDist, err := newTypeDistribution(PrimaryKeyTypes(&planner.Base.Types))
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	nonPkTypeDist, err := ranges.NewDistributionCenter(
-		&planner.Base.Types.Bigint,
-		&planner.Base.Types.BigintUnsigned,
-		&planner.Base.Types.Binary,
-		&planner.Base.Types.Bit,
-		&planner.Base.Types.Blob,
-		&planner.Base.Types.Char,
-		&planner.Base.Types.Date,
-		&planner.Base.Types.Datetime,
-		&planner.Base.Types.Decimal,
-		&planner.Base.Types.Double,
-		&planner.Base.Types.Enum,
-		&planner.Base.Types.Float,
-		&planner.Base.Types.Int,
-		&planner.Base.Types.IntUnsigned,
-		&planner.Base.Types.Longblob,
-		&planner.Base.Types.Longtext,
-		&planner.Base.Types.Mediumblob,
-		&planner.Base.Types.Mediumint,
-		&planner.Base.Types.MediumintUnsigned,
-		&planner.Base.Types.Mediumtext,
-		&planner.Base.Types.Set,
-		&planner.Base.Types.Smallint,
-		&planner.Base.Types.SmallintUnsigned,
-		&planner.Base.Types.Text,
-		&planner.Base.Types.Time,
-		&planner.Base.Types.Timestamp,
-		&planner.Base.Types.Tinyblob,
-		&planner.Base.Types.Tinyint,
-		&planner.Base.Types.TinyintUnsigned,
-		&planner.Base.Types.Tinytext,
-		&planner.Base.Types.Varbinary,
-		&planner.Base.Types.Varchar,
-		&planner.Base.Types.Year,
-	)
+	nonPkTypeDist, err := newTypeDistribution(NonPrimaryKeyTypes(&planner.Base.Types))
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -140,6 +184,19 @@ func newCycle(planner *Planner) (*Cycle, error) {
 		Logger:        &fakeLogger{},
 		usedNames:     map[string]struct{}{"main": {}},
 		statementDist: statementDist,
+		transactions: transactionSettings{
+			probability:         planner.Base.Options.TransactionProbability,
+			rollbackProbability: planner.Base.Options.TransactionRollbackProbability,
+			maxStatements:       planner.Base.Options.TransactionStatements,
+		},
+		schemaChanges: []SchemaChange{
+			&RenameColumnSchemaChange{planner.Base.SchemaChanges.RenameColumn},
+			&AddColumnSchemaChange{planner.Base.SchemaChanges.AddColumn},
+			&DropColumnSchemaChange{planner.Base.SchemaChanges.DropColumn},
+			&ModifyColumnSchemaChange{planner.Base.SchemaChanges.ModifyColumn, planner.Base.SchemaChanges.ModifyColumnConversions},
+			&AddPrimaryKeySchemaChange{planner.Base.SchemaChanges.AddPrimaryKey},
+			&DropPrimaryKeySchemaChange{planner.Base.SchemaChanges.DropPrimaryKey},
+		},
 		pkTypeDist:    pkTypeDist,
 		nonPkTypeDist: nonPkTypeDist,
 		nameRegexes:   nameRegexes,
@@ -160,7 +217,22 @@ func (c *Cycle) Run() (err error) {
 				err = errors.New(fmt.Sprintf("%+v", r))
 			}
 		}
+		// Closed before the repository may be deleted or moved, as the decision log is within it
+		if dErr := c.decisions.Close(); err == nil && dErr != nil {
+			err = errors.Wrap(dErr)
+		}
+		if policy := c.Planner.Base.Arguments.DumpCommitGraph; policy == parameters.CommitGraphPolicy_Always ||
+			(policy == parameters.CommitGraphPolicy_Failures && err != nil && !errors.ShouldIgnore(err)) {
+			if dErr := WriteCommitGraph(c); dErr != nil {
+				_ = c.Logger.WriteLine(LogType_WARN, fmt.Sprintf("Unable to write the commit graph: %s", dErr.Error()))
+			}
+		}
 		if err != nil {
+			if c.Planner.Base.Arguments.DumpSchemaOnFailure && !errors.ShouldIgnore(err) {
+				if dErr := WriteSchemaDump(c); dErr != nil {
+					_ = c.Logger.WriteLine(LogType_WARN, fmt.Sprintf("Unable to write the schema dump: %s", dErr.Error()))
+				}
+			}
 			now := time.Now()
 			since := now.Sub(c.Blueprint.CycleStart)
 			_ = c.Logger.WriteLine(LogType_INFO,
@@ -178,7 +250,7 @@ func (c *Cycle) Run() (err error) {
 				}()
 				_, _ = errFile.WriteString(fmt.Sprintf("%+v", err))
 			}()
-			if errors.ShouldIgnore(err) {
+			if errors.ShouldIgnore(err) || c.Planner.Base.Arguments.Retention == parameters.RetentionPolicy_None {
 				moveRepo = false
 				_ = os.Chdir(c.Planner.Base.Arguments.RepoWorkingPath)
 				_ = file.RemoveAll(c.Planner.Base.Arguments.RepoWorkingPath + c.Name)
@@ -195,7 +267,7 @@ func (c *Cycle) Run() (err error) {
 			if err == nil && cErr != nil {
 				err = errors.Wrap(cErr)
 			}
-			if c.Planner.Base.Options.DeleteSuccesses {
+			if c.Planner.Base.Arguments.Retention != parameters.RetentionPolicy_All {
 				moveRepo = false
 				cErr := os.Chdir(c.Planner.Base.Arguments.RepoWorkingPath)
 				if err == nil && cErr != nil {
@@ -228,12 +300,26 @@ func (c *Cycle) Run() (err error) {
 				}
 			}
 		}
+		if moveRepo && c.Planner.Base.Arguments.MaxRetained > 0 {
+			pErr := pruneRetainedCycles(c.Planner.Base.Arguments.RepoFinishedPath, c.Planner.Base.Arguments.MaxRetained)
+			if err == nil && pErr != nil {
+				err = errors.Wrap(pErr)
+			}
+		}
 		cErr := connection.CloseDoltConnections()
 		if err == nil && cErr != nil {
 			err = errors.Wrap(cErr)
 		}
+		// Anything that was not closed above was left behind by an error or panic
+		connection.ReapProcesses()
 	}()
 
+	if _, ok := c.Planner.Interface.(*DoltInterface); ok {
+		err = connection.KillStaleServers(c.Planner.Base.Options.Port)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
 	err = c.init()
 	if err != nil {
 		return errors.Wrap(err)
@@ -251,9 +337,13 @@ func (c *Cycle) Run() (err error) {
 		Type:  HookType_CycleInitialized,
 		Cycle: c,
 	}
-	c.hookQueue <- Hook{
-		Type:  HookType_CycleStarted,
-		Cycle: c,
+	if len(c.seedTables) > 0 {
+		c.QueueAction(c.announceSeedTable)
+	} else {
+		c.hookQueue <- Hook{
+			Type:  HookType_CycleStarted,
+			Cycle: c,
+		}
 	}
 
 	for breakOuter := false; !breakOuter; {
@@ -282,6 +372,44 @@ func (c *Cycle) Run() (err error) {
 	return nil
 }
 
+// cycleNameFormat is the time format of a cycle's name, which is the time that the cycle started.
+const cycleNameFormat = "20060102150405"
+
+// pruneRetainedCycles deletes the oldest cycle directories in the given directory until at most maxRetained remain.
+// Cycle directories are identified by their names, which also sort them from oldest to newest.
+func pruneRetainedCycles(dir string, maxRetained int64) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	var cycleNames []string
+	for _, entry := range entries {
+		if entry.IsDir() && isCycleName(entry.Name()) {
+			cycleNames = append(cycleNames, entry.Name())
+		}
+	}
+	sort.Strings(cycleNames)
+	for i := 0; int64(len(cycleNames)-i) > maxRetained; i++ {
+		if err = file.RemoveAll(dir + cycleNames[i]); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// isCycleName returns whether the name could belong to a cycle.
+func isCycleName(name string) bool {
+	if len(name) != len(cycleNameFormat) {
+		return false
+	}
+	for _, r := range name {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // GetBranchNames returns all of the branch names.
 func (c *Cycle) GetBranchNames() []string {
 	branchNames := make([]string, len(c.branches))
@@ -317,6 +445,12 @@ func (c *Cycle) SwitchCurrentBranch(targetBranch string) error {
 			if err != nil {
 				return errors.Wrap(err)
 			}
+			if c.Planner.Base.Options.ValidateOnSwitch && !c.Planner.Base.Arguments.SkipValidation {
+				err = c.ValidateCurrentBranch()
+				if err != nil {
+					return errors.Wrap(err)
+				}
+			}
 			c.hookQueue <- Hook{
 				Type:   HookType_BranchSwitched,
 				Cycle:  c,
@@ -329,6 +463,21 @@ func (c *Cycle) SwitchCurrentBranch(targetBranch string) error {
 	return errors.New(fmt.Sprintf("could not find a branch with the name '%s' to switch to", targetBranch))
 }
 
+// ValidateCurrentBranch validates the schema and rows of every table on the current branch against the internal data.
+// This is run after switching branches, so that data leaking between branches is caught at the switch that caused it.
+func (c *Cycle) ValidateCurrentBranch() error {
+	branchName := c.GetCurrentBranch().Name
+	for _, table := range c.GetCurrentBranch().GetWorkingSet().Tables {
+		if err := validateSchema(c, table); err != nil {
+			return errors.WrapWithMessage(err, fmt.Sprintf("After switching to branch `%s`:", branchName))
+		}
+		if err := ValidateTableRows(c, branchName, table); err != nil {
+			return errors.WrapWithMessage(err, fmt.Sprintf("After switching to branch `%s`:", branchName))
+		}
+	}
+	return nil
+}
+
 // GetBranch returns the Branch with the given name. If the branch does not exist, returns nil.
 func (c *Cycle) GetBranch(branchName string) *Branch {
 	for _, branch := range c.branches {
@@ -344,31 +493,121 @@ func (c *Cycle) QueueAction(f func(*Cycle) error) {
 	c.actionQueue <- f
 }
 
-// CliQuery is used to run dolt commands on the CLI. Automatically closes any running servers before usage.
+// CliQuery is used to run dolt commands on the CLI, through the planner's Interface.
 func (c *Cycle) CliQuery(args ...string) (string, error) {
+	err := c.Logger.WriteLine(LogType_CLI, "dolt "+FormatCliArgs(args))
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return c.Planner.Interface.Cli(c, args)
+}
+
+// transientCliErrors are substrings of errors that are caused by filesystem races, such as lock contention right after
+// the server has been shut down, rather than an actual issue with the command.
+var transientCliErrors = []string{
+	"database is locked",
+	"lock file",
+	"resource temporarily unavailable",
+	"text file busy",
+	"cannot update manifest",
+}
+
+// FormatCliArgs returns the given CLI arguments as a single line. Arguments that contain whitespace, quotes, backslashes,
+// or non-printable characters are quoted using Go's escaping rules, so that the line may be parsed by ParseCliArgs.
+func FormatCliArgs(args []string) string {
 	formattedArgs := make([]string, len(args))
-	copy(formattedArgs, args)
-	for i, arg := range formattedArgs {
-		if strings.Contains(arg, " ") {
-			formattedArgs[i] = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+	for i, arg := range args {
+		if arg == "" || strings.IndexFunc(arg, func(r rune) bool {
+			return unicode.IsSpace(r) || !unicode.IsPrint(r) || r == '"' || r == '\\'
+		}) != -1 {
+			formattedArgs[i] = strconv.Quote(arg)
+		} else {
+			formattedArgs[i] = arg
 		}
 	}
+	return strings.Join(formattedArgs, " ")
+}
 
-	err := c.Logger.WriteLine(LogType_CLI, strings.Join(append([]string{"dolt"}, formattedArgs...), " "))
-	if err != nil {
-		return "", errors.Wrap(err)
+// ParseCliArgs parses a line written by FormatCliArgs back into its arguments.
+func ParseCliArgs(line string) ([]string, error) {
+	var args []string
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		if line[i] != '"' {
+			end := strings.IndexByte(line[i:], ' ')
+			if end == -1 {
+				end = len(line) - i
+			}
+			args = append(args, line[i:i+end])
+			i += end
+			continue
+		}
+		end := i + 1
+		for ; end < len(line) && line[end] != '"'; end++ {
+			if line[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(line) {
+			return nil, errors.New(fmt.Sprintf("unterminated quoted argument: %s", line[i:]))
+		}
+		arg, err := strconv.Unquote(line[i : end+1])
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		args = append(args, arg)
+		i = end + 1
 	}
-	err = connection.CloseDoltConnections()
-	if err != nil {
-		return "", errors.Wrap(err)
+	return args, nil
+}
+
+// isRetryableCliQuery returns whether the given CLI arguments represent a read-only command. Commands that modify the
+// repository must never be retried, since a failed attempt may have partially applied its changes.
+func isRetryableCliQuery(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "status":
+		return len(args) == 1
+	case "log":
+		return true
+	case "branch":
+		return len(args) == 2 && args[1] == "--show-current"
+	default:
+		return false
 	}
+}
+
+// isTransientCliError returns whether the given error matches a known transient error.
+func isTransientCliError(err error) bool {
+	errStr := strings.ToLower(err.Error())
+	for _, transientErr := range transientCliErrors {
+		if strings.Contains(errStr, transientErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// runCliQuery runs the given dolt command on the CLI, returning its output.
+func runCliQuery(args []string) (string, error) {
 	stdOutBuffer := &bytes.Buffer{}
 	stdErrBuffer := &bytes.Buffer{}
 	doltQuery := exec.Command("dolt", args...)
 	doltQuery.Env = fuzzer_os.Environ()
 	doltQuery.Stdout = stdOutBuffer
 	doltQuery.Stderr = stdErrBuffer
-	err = doltQuery.Run()
+	err := doltQuery.Start()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	connection.RegisterProcess(doltQuery.Process)
+	err = doltQuery.Wait()
+	connection.UnregisterProcess(doltQuery.Process)
 	if stdErrBuffer.Len() > 0 {
 		return "", errors.New(stdErrBuffer.String())
 	}
@@ -378,9 +617,47 @@ func (c *Cycle) CliQuery(args ...string) (string, error) {
 	return strings.TrimSpace(stdOutBuffer.String()), nil
 }
 
-// SqlServer is used to run SQL statements on the server. If output of a statement is desired, then the connection
-// should be manually acquired using GetDoltConnection. This will reuse an existing server connection if one exists.
-// Additionally, this will call the pre- and post-SQL execution hooks.
+// NewStatement returns a random statement for the given table, chosen from the cycle's statement distribution. The
+// statement has already been applied to the table's internal data, so it must be run against Dolt using SqlServer.
+func (c *Cycle) NewStatement(table *Table) (string, error) {
+	statement, err := c.statementDist.Get(1)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	statementStr, err := statement.(Statement).GenerateStatement(table)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return statementStr, nil
+}
+
+// RunStatement generates a random statement for the given table, in the same way as NewStatement, and runs it against
+// Dolt after the statement delay. Prepared statements are run using SqlServerPrepared, so that their values are bound
+// as arguments.
+func (c *Cycle) RunStatement(table *Table) error {
+	statement, err := c.statementDist.Get(1)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if preparedStatement, ok := statement.(PreparedStatement); ok {
+		prepared, err := preparedStatement.GeneratePrepared(table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		c.delayStatement()
+		return c.SqlServerPrepared(prepared)
+	}
+	statementStr, err := statement.(Statement).GenerateStatement(table)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	c.delayStatement()
+	return c.SqlServer(statementStr)
+}
+
+// SqlServer is used to run SQL statements on the server, through the planner's Interface. If output of a statement is
+// desired, then the connection should be manually acquired using GetDoltConnection. This will reuse an existing server
+// connection if one exists. Additionally, this will call the pre- and post-SQL execution hooks.
 func (c *Cycle) SqlServer(statement string) error {
 	if err := c.Planner.Hooks.RunHook(Hook{
 		Type:   HookType_SqlStatementPreExecution,
@@ -394,11 +671,38 @@ func (c *Cycle) SqlServer(statement string) error {
 	if err != nil {
 		return errors.Wrap(err)
 	}
-	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	err = c.Planner.Interface.Exec(c, statement)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if err = c.Planner.Hooks.RunHook(Hook{
+		Type:   HookType_SqlStatementPostExecution,
+		Cycle:  c,
+		Param1: statement,
+	}); err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// SqlServerPrepared is used to run prepared statements on the server, through the planner's Interface. This is the
+// same as SqlServer, except that the arguments are bound to the statement rather than written within it. The hooks and
+// the log receive the literal form of the statement, with the log noting that it was prepared.
+func (c *Cycle) SqlServerPrepared(prepared Prepared) error {
+	if err := c.Planner.Hooks.RunHook(Hook{
+		Type:   HookType_SqlStatementPreExecution,
+		Cycle:  c,
+		Param1: prepared.Literal,
+	}); err != nil {
+		return errors.Wrap(err)
+	}
+
+	err := c.Logger.WriteLine(LogType_SQLS, fmt.Sprintf("%s -- prepared", prepared.Literal))
 	if err != nil {
 		return errors.Wrap(err)
 	}
-	_, err = dc.Conn.Exec(statement)
+	err = c.Planner.Interface.ExecPrepared(c, prepared)
 	if err != nil {
 		return errors.Wrap(err)
 	}
@@ -406,13 +710,21 @@ func (c *Cycle) SqlServer(statement string) error {
 	if err = c.Planner.Hooks.RunHook(Hook{
 		Type:   HookType_SqlStatementPostExecution,
 		Cycle:  c,
-		Param1: statement,
+		Param1: prepared.Literal,
 	}); err != nil {
 		return errors.Wrap(err)
 	}
 	return nil
 }
 
+// delayStatement sleeps for the statement delay given in the arguments, which is used to slow down the generated
+// statements when reproducing timing-sensitive bugs.
+func (c *Cycle) delayStatement() {
+	if delay := c.Planner.Base.Arguments.StatementDelay; delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
 // init creates the initial repository.
 func (c *Cycle) init() error {
 	var err error
@@ -422,7 +734,7 @@ func (c *Cycle) init() error {
 		c.Blueprint.CycleStart = time.Now()
 	}
 	c.Planner.lastRunStartTime = c.Blueprint.CycleStart
-	dbName := c.Blueprint.CycleStart.Format("20060102150405")
+	dbName := c.Blueprint.CycleStart.Format(cycleNameFormat)
 	c.Name = dbName
 
 	cycleDir := c.Planner.Base.Arguments.RepoWorkingPath + dbName
@@ -442,19 +754,77 @@ func (c *Cycle) init() error {
 		}
 		c.Logger = &fileLogger{logFile}
 	}
-
-	err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Cycle started: %s", c.Blueprint.CycleStart.Format("2006-01-02 15:04:05")))
+	decisionLogPath := ""
+	if c.Planner.Base.Options.Logging {
+		decisionLogPath = "./" + decisionLogName
+	}
+	c.decisions, err = newDecisionLog(decisionLogPath, c.Planner.Base.Arguments.ReplayDecisions)
 	if err != nil {
 		return errors.Wrap(err)
 	}
-	_, err = c.CliQuery("init")
+
+	err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Cycle started: %s", c.Blueprint.CycleStart.Format("2006-01-02 15:04:05")))
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	seedRepo := c.Planner.Base.Arguments.SeedRepo
+	if len(seedRepo) > 0 {
+		err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Seeding repository from: %s", seedRepo))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = copySeedRepo(seedRepo, cycleDir); err != nil {
+			return errors.Wrap(err)
+		}
+		if err = prepareSeedRepo(c); err != nil {
+			return errors.Wrap(err)
+		}
+	} else {
+		_, err = c.CliQuery("init")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
 	mainBranch, err := NewMainBranch(c)
 	if err != nil {
 		return errors.Wrap(err)
 	}
 	c.branches = []*Branch{mainBranch}
+	if len(seedRepo) > 0 {
+		c.seedTables, err = loadSeedTables(c, mainBranch)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// announceSeedTable runs the hooks for the next table loaded from the seed repository, just as though the table had
+// been created by the cycle. Tables are announced one at a time, as the hook queue is bounded. Once every table has been
+// announced, the cycle is started.
+func (c *Cycle) announceSeedTable(*Cycle) error {
+	if len(c.seedTables) == 0 {
+		c.hookQueue <- Hook{
+			Type:  HookType_CycleStarted,
+			Cycle: c,
+		}
+		return nil
+	}
+	table := c.seedTables[0]
+	c.seedTables = c.seedTables[1:]
+	c.hookQueue <- Hook{
+		Type:   HookType_TableCreated,
+		Cycle:  c,
+		Param1: table,
+	}
+	for _, index := range table.Indexes {
+		c.hookQueue <- Hook{
+			Type:   HookType_IndexCreated,
+			Cycle:  c,
+			Param1: table,
+			Param2: index,
+		}
+	}
+	c.QueueAction(c.announceSeedTable)
 	return nil
 }
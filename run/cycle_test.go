@@ -0,0 +1,69 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+func TestCliQueryRetries(t *testing.T) {
+	require.True(t, isRetryableCliQuery([]string{"status"}))
+	require.True(t, isRetryableCliQuery([]string{"log", "-n", "1"}))
+	require.True(t, isRetryableCliQuery([]string{"branch", "--show-current"}))
+	require.False(t, isRetryableCliQuery([]string{"branch", "newbranch"}))
+	require.False(t, isRetryableCliQuery([]string{"commit", "-m", "COMMITTED"}))
+	require.False(t, isRetryableCliQuery([]string{"add", "-A"}))
+	require.False(t, isRetryableCliQuery(nil))
+
+	require.True(t, isTransientCliError(errors.New("error: Database is locked by another process")))
+	require.False(t, isTransientCliError(errors.New("error: table not found")))
+}
+
+func TestFormatCliArgs(t *testing.T) {
+	args := []string{"commit", "-m", "a \"quoted\"\nmessage with \\ and 日本語 😀", "--author", "Jane Doe <jane@example.com>", ""}
+	line := FormatCliArgs(args)
+	require.NotContains(t, line, "\n")
+	parsed, err := ParseCliArgs(line)
+	require.NoError(t, err)
+	require.Equal(t, args, parsed)
+
+	require.Equal(t, "checkout main", FormatCliArgs([]string{"checkout", "main"}))
+	_, err = ParseCliArgs(`commit -m "unterminated`)
+	require.Error(t, err)
+}
+
+func TestPruneRetainedCycles(t *testing.T) {
+	dir := t.TempDir() + "/"
+	names := []string{"20240102030405", "20240102030406", "20230102030405", "20240102030407"}
+	for _, name := range names {
+		require.NoError(t, os.Mkdir(dir+name, 0777))
+	}
+	require.NoError(t, os.Mkdir(dir+"metrics", 0777))
+	require.NoError(t, os.WriteFile(dir+"20200102030405", nil, 0777))
+
+	require.NoError(t, pruneRetainedCycles(dir, 2))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var remaining []string
+	for _, entry := range entries {
+		remaining = append(remaining, entry.Name())
+	}
+	require.Equal(t, []string{"20200102030405", "20240102030406", "20240102030407", "metrics"}, remaining)
+}
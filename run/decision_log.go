@@ -0,0 +1,207 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// decisionLogName is the name of the file, within each cycle's directory, that the cycle's decisions are written to.
+const decisionLogName = "decisions.txt"
+
+// Decision is a single high-level choice made during a cycle, such as the table that the next statement targets, the
+// branch that is switched to, or the merge that is tested next. Decisions are written to the cycle's decision log, so
+// that the cycle may later be driven by the exact same choices.
+type Decision struct {
+	// Kind names the choice that was made, which must match when the decision is replayed.
+	Kind string
+	// Value is the chosen value. For choices between a number of options, this is the index of the chosen option.
+	Value int64
+	// Count is the number of options that the value was chosen from, which must match when the decision is replayed.
+	// A count of 0 means that the value was not chosen from a set of options, such as the number of tables in a cycle.
+	Count int64
+}
+
+// String returns the decision as a single line of a decision log, such as "table 2/5" or "table-count 4".
+func (d Decision) String() string {
+	if d.Count == 0 {
+		return fmt.Sprintf("%s %d", d.Kind, d.Value)
+	}
+	return fmt.Sprintf("%s %d/%d", d.Kind, d.Value, d.Count)
+}
+
+// ParseDecision parses a single line of a decision log, as written by Decision.String.
+func ParseDecision(line string) (Decision, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return Decision{}, errors.New(fmt.Sprintf("invalid decision '%s'", line))
+	}
+	decision := Decision{Kind: fields[0]}
+	valueStr, countStr, hasCount := strings.Cut(fields[1], "/")
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return Decision{}, errors.New(fmt.Sprintf("invalid decision '%s': %s", line, err.Error()))
+	}
+	decision.Value = value
+	if hasCount {
+		count, err := strconv.ParseInt(countStr, 10, 64)
+		if err != nil || count <= 0 || value < 0 || value >= count {
+			return Decision{}, errors.New(fmt.Sprintf("invalid decision '%s'", line))
+		}
+		decision.Count = count
+	}
+	return decision, nil
+}
+
+// LoadDecisions reads every decision from the decision log at the given path.
+func LoadDecisions(path string) ([]Decision, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer file.Close()
+	var decisions []Decision
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		decision, err := ParseDecision(line)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		decisions = append(decisions, decision)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return decisions, nil
+}
+
+// decisionLog records the decisions made by a cycle, and supplies them again when replaying a decision log. When
+// replaying, the recorded decisions are used rather than random values.
+type decisionLog struct {
+	// file is where decisions are written, which is nil when decisions are not being recorded.
+	file *os.File
+	// replay holds the decisions that are being replayed, which is nil when decisions are made randomly.
+	replay []Decision
+	next   int
+}
+
+// newDecisionLog returns a *decisionLog that writes to the given path, unless the path is empty. When replayPath is not
+// empty, decisions are read from the decision log at that path rather than being made randomly.
+func newDecisionLog(path string, replayPath string) (*decisionLog, error) {
+	log := &decisionLog{}
+	if len(replayPath) > 0 {
+		replay, err := LoadDecisions(replayPath)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		// A nil slice would disable replaying, so an empty log must still replay (and immediately be exhausted)
+		log.replay = append(make([]Decision, 0, len(replay)), replay...)
+	}
+	if len(path) > 0 {
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0777)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		log.file = file
+	}
+	return log, nil
+}
+
+// decide returns the value of the next decision of the given kind. When replaying, this is the next recorded decision,
+// which must have the same kind and count. Otherwise, the value comes from generate. The decision is then recorded.
+func (l *decisionLog) decide(kind string, count int64, generate func() (int64, error)) (int64, error) {
+	var decision Decision
+	if l != nil && l.replay != nil {
+		if l.next >= len(l.replay) {
+			return 0, errors.New(fmt.Sprintf("the decision log was exhausted after %d decisions, while deciding %s",
+				len(l.replay), kind))
+		}
+		decision = l.replay[l.next]
+		if decision.Kind != kind || decision.Count != count {
+			expected := kind
+			if count > 0 {
+				expected = fmt.Sprintf("%s between %d options", kind, count)
+			}
+			return 0, errors.New(fmt.Sprintf("the cycle diverged from the decision log at decision %d, which recorded "+
+				"'%s' while the cycle was deciding %s", l.next+1, decision.String(), expected))
+		}
+		l.next++
+	} else {
+		value, err := generate()
+		if err != nil {
+			return 0, errors.Wrap(err)
+		}
+		decision = Decision{Kind: kind, Value: value, Count: count}
+	}
+	if l != nil && l.file != nil {
+		if _, err := l.file.WriteString(decision.String() + "\n"); err != nil {
+			return 0, errors.Wrap(err)
+		}
+	}
+	return decision.Value, nil
+}
+
+// Close closes the decision log's file, if there is one.
+func (l *decisionLog) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// DecideIndex returns the index of the chosen option out of the given number of options, which must be positive. The
+// choice is random unless a decision log is being replayed.
+func (c *Cycle) DecideIndex(kind string, count int) (int, error) {
+	if count <= 0 {
+		return 0, errors.New(fmt.Sprintf("cannot decide %s between %d options", kind, count))
+	}
+	value, err := c.decisions.decide(kind, int64(count), func() (int64, error) {
+		roll, err := rand.StructureUint64()
+		return int64(roll % uint64(count)), err
+	})
+	return int(value), err
+}
+
+// DecideChance returns whether a random uint64 falls below the given threshold, which is recorded as a choice between
+// two options. The choice is random unless a decision log is being replayed.
+func (c *Cycle) DecideChance(kind string, threshold uint64) (bool, error) {
+	value, err := c.decisions.decide(kind, 2, func() (int64, error) {
+		roll, err := rand.StructureUint64()
+		if roll < threshold {
+			return 1, err
+		}
+		return 0, err
+	})
+	return value == 1, err
+}
+
+// DecideValue returns a value from the given function, unless a decision log is being replayed, in which case the
+// recorded value is returned without calling the function.
+func (c *Cycle) DecideValue(kind string, generate func() (int64, error)) (int64, error) {
+	return c.decisions.decide(kind, 0, generate)
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecision(t *testing.T) {
+	for _, decision := range []Decision{
+		{Kind: "table", Value: 2, Count: 5},
+		{Kind: "new-branch", Value: 0, Count: 2},
+		{Kind: "table-count", Value: 12},
+	} {
+		parsed, err := ParseDecision(decision.String())
+		require.NoError(t, err)
+		require.Equal(t, decision, parsed)
+	}
+	for _, line := range []string{"", "table", "table x", "table 5/5", "table -1/5", "table 1/0", "table 1 2"} {
+		_, err := ParseDecision(line)
+		require.Error(t, err, line)
+	}
+}
+
+func TestDecisionLogReplay(t *testing.T) {
+	dir := t.TempDir()
+	recordedPath := filepath.Join(dir, "recorded.txt")
+	recorder, err := newDecisionLog(recordedPath, "")
+	require.NoError(t, err)
+	c := &Cycle{decisions: recorder}
+	var indexes []int
+	var chances []bool
+	for i := 0; i < 20; i++ {
+		idx, err := c.DecideIndex("table", 7)
+		require.NoError(t, err)
+		require.True(t, idx >= 0 && idx < 7)
+		indexes = append(indexes, idx)
+		chance, err := c.DecideChance("new-table", 1<<63)
+		require.NoError(t, err)
+		chances = append(chances, chance)
+	}
+	value, err := c.DecideValue("table-count", func() (int64, error) { return 42, nil })
+	require.NoError(t, err)
+	require.Equal(t, int64(42), value)
+	require.NoError(t, recorder.Close())
+
+	// Replaying never calls the generating function, and records the same decisions again
+	replayedPath := filepath.Join(dir, "replayed.txt")
+	replayer, err := newDecisionLog(replayedPath, recordedPath)
+	require.NoError(t, err)
+	c = &Cycle{decisions: replayer}
+	for i := 0; i < 20; i++ {
+		idx, err := c.DecideIndex("table", 7)
+		require.NoError(t, err)
+		require.Equal(t, indexes[i], idx)
+		chance, err := c.DecideChance("new-table", 1<<63)
+		require.NoError(t, err)
+		require.Equal(t, chances[i], chance)
+	}
+	value, err = c.DecideValue("table-count", func() (int64, error) {
+		require.Fail(t, "replayed decisions must not be generated")
+		return 0, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(42), value)
+	// The log has been exhausted
+	_, err = c.DecideIndex("table", 7)
+	require.Error(t, err)
+	require.NoError(t, replayer.Close())
+	recorded, err := os.ReadFile(recordedPath)
+	require.NoError(t, err)
+	replayed, err := os.ReadFile(replayedPath)
+	require.NoError(t, err)
+	require.Equal(t, string(recorded), string(replayed))
+
+	// Decisions that differ in kind or count have diverged
+	replayer, err = newDecisionLog("", recordedPath)
+	require.NoError(t, err)
+	c = &Cycle{decisions: replayer}
+	_, err = c.DecideIndex("table", 6)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "diverged"))
+	replayer, err = newDecisionLog("", recordedPath)
+	require.NoError(t, err)
+	c = &Cycle{decisions: replayer}
+	_, err = c.DecideIndex("branch", 7)
+	require.Error(t, err)
+}
@@ -0,0 +1,357 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils"
+)
+
+// DiffType is the type of change that a row went through between two commits.
+type DiffType string
+
+const (
+	DiffType_Added    DiffType = "added"
+	DiffType_Modified DiffType = "modified"
+	DiffType_Removed  DiffType = "removed"
+)
+
+// RowDiff is the difference of a single row between two commits. From is empty for added rows, while To is empty for
+// removed rows.
+type RowDiff struct {
+	Type DiffType
+	From Row
+	To   Row
+}
+
+// TableDiff contains all of the row differences for a table between two commits.
+type TableDiff struct {
+	TableName string
+	Rows      []RowDiff
+}
+
+// DiffCommits returns the differences of every table going from the `from` commit to the `to` commit. Tables that
+// have no differences are still included, so that they may be checked for the absence of changes.
+func DiffCommits(from *Commit, to *Commit) ([]TableDiff, error) {
+	tableNames := make(map[string]struct{})
+	for _, table := range from.Tables {
+		tableNames[table.Name] = struct{}{}
+	}
+	for _, table := range to.Tables {
+		tableNames[table.Name] = struct{}{}
+	}
+	sortedNames := make([]string, 0, len(tableNames))
+	for tableName := range tableNames {
+		sortedNames = append(sortedNames, tableName)
+	}
+	sort.Strings(sortedNames)
+
+	diffs := make([]TableDiff, len(sortedNames))
+	for i, tableName := range sortedNames {
+		rowDiffs, err := DiffTables(from.GetTable(tableName), to.GetTable(tableName))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		diffs[i] = TableDiff{
+			TableName: tableName,
+			Rows:      rowDiffs,
+		}
+	}
+	return diffs, nil
+}
+
+// DiffTables returns the row differences going from the `from` table to the `to` table, ordered by primary key. Either
+// table may be nil, which represents a table that does not exist on that side. Both tables are expected to have the
+// same schema when they both exist.
+func DiffTables(from *Table, to *Table) ([]RowDiff, error) {
+	var diffs []RowDiff
+	if from == nil && to == nil {
+		return nil, nil
+	}
+	if (from != nil && from.IsKeyless()) || (to != nil && to.IsKeyless()) {
+		return diffKeylessTables(from, to)
+	}
+	fromCursor, err := diffTableCursor(from)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer fromCursor.Close()
+	toCursor, err := diffTableCursor(to)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer toCursor.Close()
+
+	fromRow, fromOk, err := fromCursor.NextRow()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	toRow, toOk, err := toCursor.NextRow()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	for fromOk || toOk {
+		// Empty rows always compare greater than non-empty rows, so an exhausted cursor sorts last
+		switch fromRow.PKCompare(toRow) {
+		case -1:
+			diffs = append(diffs, RowDiff{Type: DiffType_Removed, From: fromRow})
+			fromRow, fromOk, err = fromCursor.NextRow()
+		case 1:
+			diffs = append(diffs, RowDiff{Type: DiffType_Added, To: toRow})
+			toRow, toOk, err = toCursor.NextRow()
+		default:
+			if !fromRow.Equals(toRow) {
+				diffs = append(diffs, RowDiff{Type: DiffType_Modified, From: fromRow, To: toRow})
+			}
+			fromRow, fromOk, err = fromCursor.NextRow()
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			toRow, toOk, err = toCursor.NextRow()
+		}
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+	}
+	return diffs, nil
+}
+
+// diffKeylessTables is DiffTables for tables without a primary key. Just as in Dolt, a keyless row is never modified,
+// as changing any value changes its identity. Instead, each duplicate that was removed or added is its own difference,
+// and the differences are ordered by the row.
+func diffKeylessTables(from *Table, to *Table) ([]RowDiff, error) {
+	fromCursor, err := diffTableCursor(from)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer fromCursor.Close()
+	toCursor, err := diffTableCursor(to)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer toCursor.Close()
+	schema := to
+	if schema == nil {
+		schema = from
+	}
+	counts, err := NewRowCountCursor(schema.Name, schema.PKCols, schema.NonPKCols, fromCursor, toCursor)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer counts.Close()
+
+	var diffs []RowDiff
+	row, rowCounts, ok, err := counts.NextRow()
+	for ; err == nil && ok; row, rowCounts, ok, err = counts.NextRow() {
+		for i := rowCounts[1]; i < rowCounts[0]; i++ {
+			diffs = append(diffs, RowDiff{Type: DiffType_Removed, From: row})
+		}
+		for i := rowCounts[0]; i < rowCounts[1]; i++ {
+			diffs = append(diffs, RowDiff{Type: DiffType_Added, To: row})
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return diffs, nil
+}
+
+// Key returns the identity of the changed row, which is the primary key of whichever side exists. For tables without a
+// primary key, the entire row is the identity.
+func (rd RowDiff) Key() utils.Hash {
+	row := rd.To
+	if row.IsEmpty() {
+		row = rd.From
+	}
+	if row.PkColsLen == 0 {
+		return row.Hash()
+	}
+	return Row{Values: row.Key(), PkColsLen: row.PkColsLen}.Hash()
+}
+
+// GetDoltDiffCursor returns a cursor over Dolt's diff of this table for the given revisions, which may be any form
+// accepted by the DOLT_DIFF table function, such as "a..b" or "a...b". Each returned row has the diff type as its first
+// value, followed by the "to" values and then the "from" values, with each side using this table's column order. Use
+// DiffRowFromDolt to convert the rows.
+func (t *Table) GetDoltDiffCursor(c *Cycle, revisions string) (*DoltDataCursor, error) {
+	return t.getDoltDiffCursor(c, fmt.Sprintf("DOLT_DIFF('%s', '%s')", revisions, t.Name))
+}
+
+// GetDoltDiffSystemTableCursor returns a cursor over the `dolt_diff_<table>` system table of the current branch,
+// restricted to the rows that were changed going from the `from` commit to the `to` commit. The `to` commit must be on
+// the current branch, with `from` as its parent. The returned rows have the same layout as GetDoltDiffCursor.
+func (t *Table) GetDoltDiffSystemTableCursor(c *Cycle, from *Commit, to *Commit) (*DoltDataCursor, error) {
+	return t.getDoltDiffCursor(c, fmt.Sprintf("`dolt_diff_%s` WHERE `from_commit` = '%s' AND `to_commit` = '%s'",
+		t.Name, from.Hash, to.Hash))
+}
+
+// GetDoltCommitDiffCursor returns a cursor over the `dolt_commit_diff_<table>` system table of the current branch,
+// restricted to the rows that were changed going from the `from` commit to the `to` commit. Both commits are given as
+// SQL expressions, so that Dolt may resolve them from branch names, such as `HASHOF('main')`. The returned rows have the
+// same layout as GetDoltDiffCursor.
+func (t *Table) GetDoltCommitDiffCursor(c *Cycle, from string, to string) (*DoltDataCursor, error) {
+	return t.getDoltDiffCursor(c, fmt.Sprintf("`dolt_commit_diff_%s` WHERE `from_commit` = %s AND `to_commit` = %s",
+		t.Name, from, to))
+}
+
+// getDoltDiffCursor returns a cursor over the diff rows read from the given source, which is everything that follows
+// FROM in the query.
+func (t *Table) getDoltDiffCursor(c *Cycle, source string) (*DoltDataCursor, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	colsToSelect := "`diff_type`"
+	for _, prefix := range []string{"to_", "from_"} {
+		for _, colName := range t.ColumnNames() {
+			colsToSelect += fmt.Sprintf(",`%s%s`", prefix, colName)
+		}
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf("SELECT %s FROM %s;", colsToSelect, source))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	baselineTemplate := t.Data.ConstructTemplateRow()
+	allColsLen := len(baselineTemplate.Values)
+	fullTemplateVals := make([]types.Value, 1+2*allColsLen)
+	fullTemplateVals[0] = types.VarcharValue{StringValue: types.StringValue("")}
+	copy(fullTemplateVals[1:1+allColsLen], baselineTemplate.Values)
+	copy(fullTemplateVals[1+allColsLen:], baselineTemplate.Values)
+	return &DoltDataCursor{
+		rows: outRows,
+		template: Row{
+			Values:    fullTemplateVals,
+			PkColsLen: 0,
+		},
+		once: &sync.Once{},
+	}, nil
+}
+
+// DiffRowFromDolt converts a row returned from a cursor created by GetDoltDiffCursor into a RowDiff.
+func (t *Table) DiffRowFromDolt(row Row) (RowDiff, error) {
+	allColsLen := len(t.PKCols) + len(t.NonPKCols)
+	if len(row.Values) != 1+2*allColsLen {
+		return RowDiff{}, errors.New(fmt.Sprintf("diff row on table `%s` has %d values but expected %d",
+			t.Name, len(row.Values), 1+2*allColsLen))
+	}
+	diffType, ok := row.Values[0].(types.VarcharValue)
+	if !ok {
+		return RowDiff{}, errors.New(fmt.Sprintf("diff row on table `%s` has an invalid diff type: %s",
+			t.Name, row.Values[0].MySQLString()))
+	}
+	rd := RowDiff{Type: DiffType(diffType.StringValue)}
+	if rd.Type != DiffType_Removed {
+		rd.To = Row{Values: row.Values[1 : 1+allColsLen], PkColsLen: int32(len(t.PKCols))}.Copy()
+	}
+	if rd.Type != DiffType_Added {
+		rd.From = Row{Values: row.Values[1+allColsLen:], PkColsLen: int32(len(t.PKCols))}.Copy()
+	}
+	return rd, nil
+}
+
+// diffTableCursor returns a cursor over the table's data. A nil table returns a cursor with no rows.
+func diffTableCursor(table *Table) (*diffCursor, error) {
+	if table == nil {
+		return &diffCursor{}, nil
+	}
+	cursor, err := table.Data.GetRowCursor()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &diffCursor{cursor}, nil
+}
+
+// diffCursor wraps a TableDataCursor so that a missing table may be treated as an empty table.
+type diffCursor struct {
+	cursor *TableDataCursor
+}
+
+// NextRow returns the next row from the cursor. If there are no more rows to return, returns false.
+func (dc *diffCursor) NextRow() (Row, bool, error) {
+	if dc.cursor == nil {
+		return Row{}, false, nil
+	}
+	return dc.cursor.NextRow()
+}
+
+// Close closes the underlying cursor, if there is one.
+func (dc *diffCursor) Close() {
+	if dc.cursor != nil {
+		dc.cursor.Close()
+	}
+}
+
+// DiffStat is the number of rows that were added, deleted, and modified in a table between two commits, as reported by
+// `dolt diff --stat`.
+type DiffStat struct {
+	Added    uint64
+	Deleted  uint64
+	Modified uint64
+}
+
+// CountDiffStat returns the DiffStat of the given row differences.
+func CountDiffStat(rowDiffs []RowDiff) DiffStat {
+	stat := DiffStat{}
+	for _, rowDiff := range rowDiffs {
+		switch rowDiff.Type {
+		case DiffType_Added:
+			stat.Added++
+		case DiffType_Removed:
+			stat.Deleted++
+		case DiffType_Modified:
+			stat.Modified++
+		}
+	}
+	return stat
+}
+
+// String returns the DiffStat in the same form as `dolt diff --stat`.
+func (ds DiffStat) String() string {
+	return fmt.Sprintf("%d rows added, %d rows deleted, %d rows modified", ds.Added, ds.Deleted, ds.Modified)
+}
+
+// GetDoltDiffStat returns Dolt's diff statistics for this table between the given revisions, which may be any form
+// accepted by the DOLT_DIFF_STAT table function, such as "a..b" or "a...b". The table function computes the same
+// statistics as `dolt diff --stat`. A table without any changes has no statistics, which is returned as an empty
+// DiffStat.
+func (t *Table) GetDoltDiffStat(c *Cycle, revisions string) (DiffStat, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return DiffStat{}, errors.Wrap(err)
+	}
+	rows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf(
+		"SELECT `rows_added`, `rows_deleted`, `rows_modified` FROM DOLT_DIFF_STAT('%s', '%s');", revisions, t.Name))
+	if err != nil {
+		return DiffStat{}, errors.Wrap(err)
+	}
+	defer rows.Close()
+	stat := DiffStat{}
+	if rows.Next() {
+		if err = rows.Scan(&stat.Added, &stat.Deleted, &stat.Modified); err != nil {
+			return DiffStat{}, errors.Wrap(err)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return DiffStat{}, errors.Wrap(err)
+	}
+	return stat, nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountDiffStat(t *testing.T) {
+	stat := CountDiffStat([]RowDiff{
+		{Type: DiffType_Added},
+		{Type: DiffType_Added},
+		{Type: DiffType_Modified},
+		{Type: DiffType_Removed},
+		{Type: DiffType_Added},
+	})
+	require.Equal(t, DiffStat{Added: 3, Deleted: 1, Modified: 1}, stat)
+	require.Equal(t, "3 rows added, 1 rows deleted, 1 rows modified", stat.String())
+	require.Equal(t, DiffStat{}, CountDiffStat(nil))
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+// divergenceContextRows is the number of rows before and after the divergent row that are included in a Divergence.
+const divergenceContextRows = 5
+
+// DivergenceKind is the kind of difference found between the internal data and Dolt's data.
+type DivergenceKind string
+
+const (
+	DivergenceKind_RowMismatch         DivergenceKind = "RowMismatch"
+	DivergenceKind_MissingFromDolt     DivergenceKind = "MissingFromDolt"
+	DivergenceKind_MissingFromInternal DivergenceKind = "MissingFromInternal"
+)
+
+// Divergence is a machine-readable record of the first difference found between the internal data and Dolt's data.
+// Rows are represented by the MySQL string of each value.
+type Divergence struct {
+	Table                 string         `json:"table"`
+	Branch                string         `json:"branch"`
+	Kind                  DivergenceKind `json:"kind"`
+	RowIndex              int64          `json:"row_index"`
+	Columns               []string       `json:"columns"`
+	InternalRow           []string       `json:"internal_row"`
+	DoltRow               []string       `json:"dolt_row"`
+	PrecedingRows         [][]string     `json:"preceding_rows"`
+	FollowingInternalRows [][]string     `json:"following_internal_rows"`
+	FollowingDoltRows     [][]string     `json:"following_dolt_rows"`
+}
+
+// DivergenceRecorder tracks the rows that have matched during a comparison, so that a Divergence may be constructed
+// with the surrounding rows whenever a difference is found.
+type DivergenceRecorder struct {
+	table     string
+	branch    string
+	columns   []string
+	preceding [][]string
+	rowIndex  int64
+}
+
+// RowIterator is a cursor that returns one row at a time.
+type RowIterator interface {
+	// NextRow returns the next row from the cursor. If there are no more rows to return, returns false.
+	NextRow() (Row, bool, error)
+}
+
+var _ RowIterator = (*TableDataCursor)(nil)
+var _ RowIterator = (*DoltDataCursor)(nil)
+
+// NewDivergenceRecorder returns a new *DivergenceRecorder.
+func NewDivergenceRecorder(tableName string, branchName string, columns []string) *DivergenceRecorder {
+	return &DivergenceRecorder{
+		table:   tableName,
+		branch:  branchName,
+		columns: columns,
+	}
+}
+
+// Matched records that the given row was identical in both the internal data and Dolt.
+func (d *DivergenceRecorder) Matched(row Row) {
+	if len(d.preceding) == divergenceContextRows {
+		d.preceding = d.preceding[1:]
+	}
+	d.preceding = append(d.preceding, divergenceRowStrings(row))
+	d.rowIndex++
+}
+
+// Fail constructs a Divergence from the given rows and cursors, writes it to the cycle's directory (if enabled), and
+// returns the given error. The cursors are advanced to gather the following rows, therefore they should not be used
+// after calling this. Either cursor may be nil.
+func (d *DivergenceRecorder) Fail(c *Cycle, kind DivergenceKind, internalRow Row, doltRow Row,
+	internalCursor RowIterator, doltCursor RowIterator, err error) error {
+	if !c.Planner.Base.Options.DivergenceReport {
+		return errors.Wrap(err)
+	}
+	divergence := &Divergence{
+		Table:                 d.table,
+		Branch:                d.branch,
+		Kind:                  kind,
+		RowIndex:              d.rowIndex,
+		Columns:               d.columns,
+		InternalRow:           divergenceRowStrings(internalRow),
+		DoltRow:               divergenceRowStrings(doltRow),
+		PrecedingRows:         d.preceding,
+		FollowingInternalRows: divergenceFollowingRows(internalCursor),
+		FollowingDoltRows:     divergenceFollowingRows(doltCursor),
+	}
+	if wErr := divergence.WriteToFile(c); wErr != nil {
+		return errors.New(fmt.Sprintf("Error 1: %s\n\nError 2: %s", err.Error(), wErr.Error()))
+	}
+	return errors.Wrap(err)
+}
+
+// WriteToFile writes the divergence as JSON to "divergence.json" in the cycle's directory.
+func (d *Divergence) WriteToFile(c *Cycle) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	err = os.WriteFile(c.Planner.Base.Arguments.RepoWorkingPath+c.Name+"/divergence.json", data, 0777)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// divergenceRowStrings returns the row as a slice of strings. Returns nil for an empty row.
+func divergenceRowStrings(row Row) []string {
+	if row.IsEmpty() {
+		return nil
+	}
+	vals := make([]string, len(row.Values))
+	for i := range row.Values {
+		vals[i] = row.Values[i].MySQLString()
+	}
+	return vals
+}
+
+// divergenceFollowingRows reads the next few rows from the cursor. Any errors are ignored, as this is only used to
+// provide additional context for an error that has already occurred.
+func divergenceFollowingRows(cursor RowIterator) [][]string {
+	if cursor == nil {
+		return nil
+	}
+	var rows [][]string
+	for i := 0; i < divergenceContextRows; i++ {
+		row, ok, err := cursor.NextRow()
+		if !ok || err != nil {
+			break
+		}
+		rows = append(rows, divergenceRowStrings(row))
+	}
+	return rows
+}
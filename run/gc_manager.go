@@ -42,6 +42,10 @@ func (m *GCManager) Initialize(c *Cycle) error {
 func (m *GCManager) Counter(c *Cycle, statement string) error {
 	m.statementsSinceLastGC += 1
 	m.dataSizeSinceLastGC += uint64(len(statement))
+	// Running GC closes the server, which would discard an open transaction
+	if c.InTransaction() {
+		return nil
+	}
 	if m.statementsSinceLastGC > 256*1024 || m.dataSizeSinceLastGC > 256*1024*1024 { // 256MB
 		m.statementsSinceLastGC = 0
 		m.dataSizeSinceLastGC = 0
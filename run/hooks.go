@@ -45,6 +45,8 @@ const (
 	HookType_BranchCreated             HookType = "BranchCreated"
 	HookType_BranchSwitched            HookType = "BranchSwitched"
 	HookType_CommitCreated             HookType = "CommitCreated"
+	HookType_CommitPreCreation         HookType = "CommitPreCreation"
+	HookType_CommitPostCreation        HookType = "CommitPostCreation"
 	HookType_TableCreated              HookType = "TableCreated"
 	HookType_IndexCreated              HookType = "IndexCreated"
 	HookType_ForeignKeyCreated         HookType = "ForeignKeyCreated"
@@ -61,6 +63,8 @@ type Hooks struct {
 	branchCreated             []func(c *Cycle, branch *Branch) error
 	branchSwitched            []func(c *Cycle, prevBranch *Branch, branch *Branch) error
 	commitCreated             []func(c *Cycle, commit *Commit) error
+	commitPreCreation         []func(c *Cycle, branch *Branch, workingSet *Commit) error
+	commitPostCreation        []func(c *Cycle, branch *Branch, commit *Commit) error
 	tableCreated              []func(c *Cycle, table *Table) error
 	indexCreated              []func(c *Cycle, table *Table, index *Index) error
 	foreignKeyCreated         []func(c *Cycle, commit *Commit, foreignKey *ForeignKey) error
@@ -120,6 +124,22 @@ func (h *Hooks) RunHook(hook Hook) error {
 				return errors.Wrap(err)
 			}
 		}
+	case HookType_CommitPreCreation:
+		branch := hook.Param1.(*Branch)
+		workingSet := hook.Param2.(*Commit)
+		for _, hookFunc := range h.commitPreCreation {
+			if err := hookFunc(hook.Cycle, branch, workingSet); err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	case HookType_CommitPostCreation:
+		branch := hook.Param1.(*Branch)
+		commit := hook.Param2.(*Commit)
+		for _, hookFunc := range h.commitPostCreation {
+			if err := hookFunc(hook.Cycle, branch, commit); err != nil {
+				return errors.Wrap(err)
+			}
+		}
 	case HookType_TableCreated:
 		table := hook.Param1.(*Table)
 		for _, hookFunc := range h.tableCreated {
@@ -201,6 +221,19 @@ func (h *Hooks) CommitCreated(f func(c *Cycle, commit *Commit) error) {
 	h.commitCreated = append(h.commitCreated, f)
 }
 
+// CommitPreCreation is called immediately before the working set of a branch is committed, and only when there are
+// changes to commit. Unlike CommitCreated, this is called synchronously, so the working set may be inspected while it
+// still matches the repository. Returning an error prevents the commit from being created.
+func (h *Hooks) CommitPreCreation(f func(c *Cycle, branch *Branch, workingSet *Commit) error) {
+	h.commitPreCreation = append(h.commitPreCreation, f)
+}
+
+// CommitPostCreation is called synchronously immediately after a commit has been created, before any other changes are
+// made to the repository. The branch's new working set has already been created.
+func (h *Hooks) CommitPostCreation(f func(c *Cycle, branch *Branch, commit *Commit) error) {
+	h.commitPostCreation = append(h.commitPostCreation, f)
+}
+
 // TableCreated is called when a table has been created.
 func (h *Hooks) TableCreated(f func(c *Cycle, table *Table) error) {
 	h.tableCreated = append(h.tableCreated, f)
@@ -0,0 +1,53 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+func TestCommitCreationHooks(t *testing.T) {
+	hooks := &Hooks{}
+	branch := &Branch{Name: "main"}
+	workingSet := &Commit{}
+	var called []string
+	hooks.CommitPreCreation(func(c *Cycle, b *Branch, ws *Commit) error {
+		require.Same(t, branch, b)
+		require.Same(t, workingSet, ws)
+		called = append(called, "pre")
+		return nil
+	})
+	hooks.CommitPostCreation(func(c *Cycle, b *Branch, commit *Commit) error {
+		require.Same(t, branch, b)
+		require.Same(t, workingSet, commit)
+		called = append(called, "post")
+		return nil
+	})
+	require.NoError(t, hooks.RunHook(Hook{Type: HookType_CommitPreCreation, Param1: branch, Param2: workingSet}))
+	require.NoError(t, hooks.RunHook(Hook{Type: HookType_CommitPostCreation, Param1: branch, Param2: workingSet}))
+	require.Equal(t, []string{"pre", "post"}, called)
+
+	// An error from a pre-creation hook is returned so that the commit is not created
+	hooks.CommitPreCreation(func(c *Cycle, b *Branch, ws *Commit) error {
+		return errors.New("vetoed")
+	})
+	err := hooks.RunHook(Hook{Type: HookType_CommitPreCreation, Param1: branch, Param2: workingSet})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "vetoed")
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"math"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// hotKeySettings controls how often UPDATE and REPLACE statements write to a table's hot keys, which concentrates
+// writes on a small set of rows rather than spreading them across the whole table.
+type hotKeySettings struct {
+	count       int64
+	probability float64
+}
+
+// next returns one of the table's hot keys when the next write should target a hot key. Returns false when the write
+// should target any row, which is always the case for keyless tables. Hot keys are recorded as they are first needed,
+// using the key of an existing row (or a new key when the table is empty), until the table has the configured count.
+func (h hotKeySettings) next(table *Table) ([]types.Value, bool, error) {
+	if h.count <= 0 || h.probability <= 0 || table.IsKeyless() {
+		return nil, false, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	if float64(roll) >= h.probability*math.MaxUint64 {
+		return nil, false, nil
+	}
+	if int64(len(table.hotKeys)) < h.count {
+		row, ok, err := table.Data.GetRandomRow()
+		if err != nil {
+			return nil, false, errors.Wrap(err)
+		}
+		if !ok {
+			if row, err = NewRow(table); err != nil {
+				return nil, false, errors.Wrap(err)
+			}
+		}
+		table.hotKeys = append(table.hotKeys, append([]types.Value{}, row.Key()...))
+	}
+	idx, err := rand.Uint64()
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	return table.hotKeys[idx%uint64(len(table.hotKeys))], true, nil
+}
@@ -17,6 +17,16 @@ package run
 import (
 	"fmt"
 	"strings"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+const (
+	// maxIndexKeyBytes is the maximum number of bytes that an index's key may hold, which is the limit imposed by MySQL.
+	maxIndexKeyBytes = 3072
+	// maxFixedKeyBytes is the most bytes that a column of any type other than the string and blob types adds to an
+	// index's key, which is the size of the largest DECIMAL.
+	maxFixedKeyBytes = 32
 )
 
 // Index represents an index in dolt.
@@ -24,15 +34,26 @@ type Index struct {
 	Name     string
 	IsUnique bool
 	Columns  []string
+	// PrefixLengths contains the prefix length for each column, in the same order as Columns. A length of zero means
+	// that the entire column value is indexed.
+	PrefixLengths []int64
+	// Descending contains whether each column is sorted in descending order, in the same order as Columns.
+	Descending []bool
 	//TODO: track data for foreign keys
 }
 
-// NewIndex returns an *Index.
-func NewIndex(name string, columns []string, isUnique bool) *Index {
+// NewIndex returns an *Index. If prefixLengths is nil, then no column uses a prefix. All columns are ascending, which
+// may be changed by setting Descending afterward.
+func NewIndex(name string, columns []string, prefixLengths []int64, isUnique bool) *Index {
+	if prefixLengths == nil {
+		prefixLengths = make([]int64, len(columns))
+	}
 	return &Index{
-		Name:     name,
-		IsUnique: isUnique,
-		Columns:  columns,
+		Name:          name,
+		IsUnique:      isUnique,
+		Columns:       columns,
+		PrefixLengths: prefixLengths,
+		Descending:    make([]bool, len(columns)),
 	}
 }
 
@@ -42,7 +63,7 @@ func (i Index) String() string {
 	if i.IsUnique {
 		unique = "UNIQUE "
 	}
-	return fmt.Sprintf("%sINDEX `%s` (`%s`)", unique, i.Name, strings.Join(i.Columns, "`,`"))
+	return fmt.Sprintf("%sINDEX `%s` (%s)", unique, i.Name, i.columnsString())
 }
 
 // CreateString returns the index as a `CREATE INDEX` statement.
@@ -51,16 +72,85 @@ func (i *Index) CreateString(tableName string) string {
 	if i.IsUnique {
 		unique = "UNIQUE "
 	}
-	return fmt.Sprintf("CREATE %sINDEX `%s` ON `%s`(`%s`)", unique, i.Name, tableName, strings.Join(i.Columns, "`,`"))
+	return fmt.Sprintf("CREATE %sINDEX `%s` ON `%s`(%s)", unique, i.Name, tableName, i.columnsString())
+}
+
+// HasPrefix returns whether any column in the index uses a prefix length.
+func (i *Index) HasPrefix() bool {
+	for _, prefixLength := range i.PrefixLengths {
+		if prefixLength > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDescending returns whether any column in the index is sorted in descending order.
+func (i *Index) HasDescending() bool {
+	for _, descending := range i.Descending {
+		if descending {
+			return true
+		}
+	}
+	return false
 }
 
 // Copy returns a deep copy of the index.
 func (i *Index) Copy() *Index {
 	columns := make([]string, len(i.Columns))
 	copy(columns, i.Columns)
+	prefixLengths := make([]int64, len(i.PrefixLengths))
+	copy(prefixLengths, i.PrefixLengths)
+	descending := make([]bool, len(i.Descending))
+	copy(descending, i.Descending)
 	return &Index{
-		Name:     i.Name,
-		IsUnique: i.IsUnique,
-		Columns:  columns,
+		Name:          i.Name,
+		IsUnique:      i.IsUnique,
+		Columns:       columns,
+		PrefixLengths: prefixLengths,
+		Descending:    descending,
+	}
+}
+
+// columnsString returns the index's columns in the form used by index definitions, such as
+// "`col1`(10),`col2` DESC".
+func (i *Index) columnsString() string {
+	cols := make([]string, len(i.Columns))
+	for idx, col := range i.Columns {
+		if idx < len(i.PrefixLengths) && i.PrefixLengths[idx] > 0 {
+			cols[idx] = fmt.Sprintf("`%s`(%d)", col, i.PrefixLengths[idx])
+		} else {
+			cols[idx] = fmt.Sprintf("`%s`", col)
+		}
+		if idx < len(i.Descending) && i.Descending[idx] {
+			cols[idx] += " DESC"
+		}
+	}
+	return strings.Join(cols, ",")
+}
+
+// indexKeyBytes returns the most bytes that the column adds to an index's key, when indexed using the given prefix
+// length. A prefix length of 0 indexes the entire value.
+func indexKeyBytes(col *Column, prefixLength int64) int64 {
+	prefixType, ok := col.Type.(types.PrefixableTypeInstance)
+	if !ok {
+		return maxFixedKeyBytes
+	}
+	if prefixLength == 0 {
+		prefixLength = prefixType.MaxPrefixLength()
+	}
+	return prefixLength * indexKeyCharBytes(col.Type)
+}
+
+// indexKeyCharBytes returns the most bytes that each unit of a prefix length may hold for the given type. Binary types
+// measure their prefixes in bytes, while every other type measures them in characters, which may each use up to 4
+// bytes.
+func indexKeyCharBytes(typeInstance types.TypeInstance) int64 {
+	switch typeInstance.(type) {
+	case *types.BinaryInstance, *types.VarbinaryInstance, *types.TinyblobInstance, *types.BlobInstance,
+		*types.MediumblobInstance, *types.LongblobInstance:
+		return 1
+	default:
+		return 4
 	}
 }
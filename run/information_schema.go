@@ -0,0 +1,275 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// informationSchemaColumn is a column of a table, as described by "information_schema.columns".
+type informationSchemaColumn struct {
+	DataType   string
+	ColumnType string
+	IsNullable string
+	ColumnKey  string
+	MaxLength  sql.NullInt64
+}
+
+// informationSchemaIndexColumn is a single column of an index, as described by "information_schema.statistics".
+type informationSchemaIndexColumn struct {
+	ColumnName string
+	NonUnique  int64
+	SubPart    sql.NullInt64
+	Collation  string
+}
+
+// ValidateInformationSchema returns an error if the columns and indexes of the table, as described by
+// "information_schema.columns" and "information_schema.statistics", do not match the table. Names are compared without
+// regard to case, as they are case-insensitive identifiers. Dolt may create indexes to support foreign keys, so tables
+// with foreign keys may have additional indexes.
+func ValidateInformationSchema(c *Cycle, table *Table) error {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = validateInformationSchemaColumns(dc, table); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = validateInformationSchemaStatistics(dc, table); err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// validateInformationSchemaColumns validates the table's columns against "information_schema.columns".
+func validateInformationSchemaColumns(dc *connection.DoltConnection, table *Table) error {
+	rows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf("SELECT `COLUMN_NAME`, `DATA_TYPE`, "+
+		"`COLUMN_TYPE`, `IS_NULLABLE`, `COLUMN_KEY`, `CHARACTER_MAXIMUM_LENGTH` FROM `information_schema`.`columns` "+
+		"WHERE `TABLE_SCHEMA` = DATABASE() AND `TABLE_NAME` = '%s';", table.Name))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer rows.Close()
+	actual := make(map[string]informationSchemaColumn)
+	for rows.Next() {
+		var name string
+		var col informationSchemaColumn
+		if err = rows.Scan(&name, &col.DataType, &col.ColumnType, &col.IsNullable, &col.ColumnKey, &col.MaxLength); err != nil {
+			return errors.Wrap(err)
+		}
+		actual[strings.ToLower(name)] = col
+	}
+	if err = rows.Err(); err != nil {
+		return errors.Wrap(err)
+	}
+
+	columns := append(append([]*Column{}, table.PKCols...), table.NonPKCols...)
+	if len(actual) != len(columns) {
+		return errors.New(fmt.Sprintf("information_schema.columns lists %d columns for table `%s`, but expected %d",
+			len(actual), table.Name, len(columns)))
+	}
+	for i, col := range columns {
+		actualCol, ok := actual[strings.ToLower(col.Name)]
+		if !ok {
+			return errors.New(fmt.Sprintf("information_schema.columns is missing column `%s` of table `%s`",
+				col.Name, table.Name))
+		}
+		expectedCol := informationSchemaColumn{
+			DataType:   informationSchemaDataType(col.Type),
+			IsNullable: "YES",
+			ColumnKey:  table.informationSchemaColumnKey(col.Name),
+		}
+		if i < len(table.PKCols) {
+			expectedCol.IsNullable = "NO"
+		}
+		if !strings.EqualFold(actualCol.DataType, expectedCol.DataType) {
+			return errors.New(fmt.Sprintf("information_schema.columns has DATA_TYPE '%s' for column `%s` of table `%s`, but expected '%s'",
+				actualCol.DataType, col.Name, table.Name, expectedCol.DataType))
+		}
+		unsigned := strings.Contains(col.Type.Name(false), "UNSIGNED")
+		if unsigned != strings.Contains(strings.ToLower(actualCol.ColumnType), "unsigned") {
+			return errors.New(fmt.Sprintf("information_schema.columns has COLUMN_TYPE '%s' for column `%s` of table `%s`, but the type is %s",
+				actualCol.ColumnType, col.Name, table.Name, col.Type.Name(false)))
+		}
+		if actualCol.IsNullable != expectedCol.IsNullable {
+			return errors.New(fmt.Sprintf("information_schema.columns has IS_NULLABLE '%s' for column `%s` of table `%s`, but expected '%s'",
+				actualCol.IsNullable, col.Name, table.Name, expectedCol.IsNullable))
+		}
+		// Indexes that Dolt creates for foreign keys are not tracked, so their columns may be marked as MUL
+		if actualCol.ColumnKey != expectedCol.ColumnKey &&
+			!(expectedCol.ColumnKey == "" && actualCol.ColumnKey == "MUL" && table.HasForeignKeys()) {
+			return errors.New(fmt.Sprintf("information_schema.columns has COLUMN_KEY '%s' for column `%s` of table `%s`, but expected '%s'",
+				actualCol.ColumnKey, col.Name, table.Name, expectedCol.ColumnKey))
+		}
+		switch col.Type.(type) {
+		case *types.CharInstance, *types.VarcharInstance, *types.BinaryInstance, *types.VarbinaryInstance:
+			maxLength := col.Type.(types.PrefixableTypeInstance).MaxPrefixLength()
+			if !actualCol.MaxLength.Valid || actualCol.MaxLength.Int64 != maxLength {
+				return errors.New(fmt.Sprintf("information_schema.columns has CHARACTER_MAXIMUM_LENGTH %s for column `%s` of table `%s`, but expected %d",
+					nullInt64String(actualCol.MaxLength), col.Name, table.Name, maxLength))
+			}
+		}
+	}
+	return nil
+}
+
+// validateInformationSchemaStatistics validates the table's primary key and indexes against
+// "information_schema.statistics".
+func validateInformationSchemaStatistics(dc *connection.DoltConnection, table *Table) error {
+	actual, err := readInformationSchemaStatistics(dc, table.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	expected := make(map[string][]informationSchemaIndexColumn)
+	if len(table.PKCols) > 0 {
+		pkCols := make([]informationSchemaIndexColumn, len(table.PKCols))
+		for i, col := range table.PKCols {
+			pkCols[i] = informationSchemaIndexColumn{ColumnName: col.Name, Collation: "A"}
+		}
+		expected["primary"] = pkCols
+	}
+	for _, index := range table.Indexes {
+		indexCols := make([]informationSchemaIndexColumn, len(index.Columns))
+		for i, colName := range index.Columns {
+			indexCols[i] = informationSchemaIndexColumn{ColumnName: colName, NonUnique: 1, Collation: "A"}
+			if index.IsUnique {
+				indexCols[i].NonUnique = 0
+			}
+			if index.PrefixLengths[i] > 0 {
+				indexCols[i].SubPart = sql.NullInt64{Int64: index.PrefixLengths[i], Valid: true}
+			}
+			if index.Descending[i] {
+				indexCols[i].Collation = "D"
+			}
+		}
+		expected[strings.ToLower(index.Name)] = indexCols
+	}
+
+	for indexName, expectedCols := range expected {
+		actualCols, ok := actual[indexName]
+		if !ok {
+			return errors.New(fmt.Sprintf("information_schema.statistics is missing index `%s` of table `%s`",
+				indexName, table.Name))
+		}
+		if len(actualCols) != len(expectedCols) {
+			return errors.New(fmt.Sprintf("information_schema.statistics lists %d columns for index `%s` of table `%s`, but expected %d",
+				len(actualCols), indexName, table.Name, len(expectedCols)))
+		}
+		for i := range expectedCols {
+			expectedCol := expectedCols[i]
+			actualCol := actualCols[i]
+			if !strings.EqualFold(actualCol.ColumnName, expectedCol.ColumnName) || actualCol.NonUnique != expectedCol.NonUnique ||
+				actualCol.SubPart != expectedCol.SubPart || actualCol.Collation != expectedCol.Collation {
+				return errors.New(fmt.Sprintf("information_schema.statistics differs at column %d of index `%s` of table `%s`\n"+
+					"Expected: COLUMN_NAME `%s`, NON_UNIQUE %d, SUB_PART %s, COLLATION '%s'\n"+
+					"Actual:   COLUMN_NAME `%s`, NON_UNIQUE %d, SUB_PART %s, COLLATION '%s'",
+					i+1, indexName, table.Name,
+					expectedCol.ColumnName, expectedCol.NonUnique, nullInt64String(expectedCol.SubPart), expectedCol.Collation,
+					actualCol.ColumnName, actualCol.NonUnique, nullInt64String(actualCol.SubPart), actualCol.Collation))
+			}
+		}
+	}
+	if !table.HasForeignKeys() {
+		for indexName := range actual {
+			if _, ok := expected[indexName]; !ok {
+				return errors.New(fmt.Sprintf("information_schema.statistics lists an unexpected index `%s` on table `%s`",
+					indexName, table.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// informationSchemaColumnKey returns the COLUMN_KEY of the given column, which is the highest priority of PRI for a
+// column of the primary key, UNI for the only column of a unique index, and MUL for the first column of any other index.
+func (t *Table) informationSchemaColumnKey(colName string) string {
+	for _, col := range t.PKCols {
+		if col.Name == colName {
+			return "PRI"
+		}
+	}
+	key := ""
+	for _, index := range t.Indexes {
+		if len(index.Columns) == 0 || index.Columns[0] != colName {
+			continue
+		}
+		if index.IsUnique && len(index.Columns) == 1 {
+			return "UNI"
+		}
+		key = "MUL"
+	}
+	return key
+}
+
+// informationSchemaDataType returns the DATA_TYPE of the given type, which is its name without any parameters or
+// attributes.
+func informationSchemaDataType(typeInstance types.TypeInstance) string {
+	name := typeInstance.Name(false)
+	if idx := strings.IndexAny(name, "( "); idx >= 0 {
+		name = name[:idx]
+	}
+	return strings.ToLower(name)
+}
+
+// nullInt64String returns the integer as a string, or NULL when it is not valid.
+func nullInt64String(n sql.NullInt64) string {
+	if !n.Valid {
+		return "NULL"
+	}
+	return fmt.Sprint(n.Int64)
+}
+
+// readInformationSchemaStatistics returns the columns of every index on the given table from
+// "information_schema.statistics", keyed by the lowercase name of each index. Each index's columns are in order.
+func readInformationSchemaStatistics(dc *connection.DoltConnection,
+	tableName string) (map[string][]informationSchemaIndexColumn, error) {
+	rows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf("SELECT `INDEX_NAME`, `SEQ_IN_INDEX`, "+
+		"`COLUMN_NAME`, `NON_UNIQUE`, `SUB_PART`, `COLLATION` FROM `information_schema`.`statistics` "+
+		"WHERE `TABLE_SCHEMA` = DATABASE() AND `TABLE_NAME` = '%s';", tableName))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer rows.Close()
+	actual := make(map[string][]informationSchemaIndexColumn)
+	for rows.Next() {
+		var indexName string
+		var seq int64
+		var collation sql.NullString
+		var indexCol informationSchemaIndexColumn
+		if err = rows.Scan(&indexName, &seq, &indexCol.ColumnName, &indexCol.NonUnique, &indexCol.SubPart, &collation); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		indexCol.Collation = collation.String
+		indexName = strings.ToLower(indexName)
+		indexCols := actual[indexName]
+		for int64(len(indexCols)) < seq {
+			indexCols = append(indexCols, informationSchemaIndexColumn{})
+		}
+		indexCols[seq-1] = indexCol
+		actual[indexName] = indexCols
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return actual, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestInformationSchemaExpectations(t *testing.T) {
+	require.Equal(t, "bigint", informationSchemaDataType(&types.BigintUnsignedInstance{}))
+	require.Equal(t, "varchar", informationSchemaDataType(&types.VarcharInstance{}))
+	require.Equal(t, "longtext", informationSchemaDataType(&types.LongtextInstance{}))
+
+	table := &Table{
+		Name:      "t",
+		PKCols:    []*Column{{Name: "pk1"}, {Name: "pk2"}},
+		NonPKCols: []*Column{{Name: "v1"}, {Name: "v2"}, {Name: "v3"}, {Name: "v4"}},
+		Indexes: []*Index{
+			NewIndex("idx1", []string{"v1", "v2"}, nil, true),
+			NewIndex("idx2", []string{"v2"}, []int64{10}, true),
+			NewIndex("idx3", []string{"v3"}, nil, false),
+			NewIndex("idx4", []string{"pk2", "v4"}, nil, false),
+		},
+	}
+	expected := map[string]string{
+		"pk1": "PRI",
+		"pk2": "PRI",
+		"v1":  "MUL",
+		"v2":  "UNI",
+		"v3":  "MUL",
+		"v4":  "",
+	}
+	for colName, key := range expected {
+		require.Equal(t, key, table.informationSchemaColumnKey(colName), colName)
+	}
+}
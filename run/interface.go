@@ -0,0 +1,229 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// Interface is how a cycle's SQL statements and dolt commands reach the repository.
+type Interface interface {
+	// Exec executes the given SQL statement.
+	Exec(c *Cycle, statement string) error
+	// ExecPrepared executes the given prepared statement, binding the arguments to its placeholders.
+	ExecPrepared(c *Cycle, prepared Prepared) error
+	// Cli runs the given dolt command, returning its output.
+	Cli(c *Cycle, args []string) (string, error)
+	// Session returns a value identifying the SQL session that the next statement will run on. A different value means
+	// that a new session has begun.
+	Session(c *Cycle) (interface{}, error)
+}
+
+// DoltInterface runs statements on a Dolt SQL server, and commands on the Dolt CLI.
+type DoltInterface struct{}
+
+var _ Interface = (*DoltInterface)(nil)
+
+// Exec implements the interface Interface. Statements run on the connection's dedicated session.
+func (i *DoltInterface) Exec(c *Cycle, statement string) error {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	_, err = dc.Session.ExecContext(context.Background(), statement)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// ExecPrepared implements the interface Interface. The driver prepares the statement on the server and sends the
+// arguments using the binary protocol, as it does not interpolate arguments into the query.
+func (i *DoltInterface) ExecPrepared(c *Cycle, prepared Prepared) error {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	args := make([]interface{}, len(prepared.Args))
+	for idx, arg := range prepared.Args {
+		valuer, ok := arg.(driver.Valuer)
+		if !ok {
+			return errors.New(fmt.Sprintf("cannot bind a %s value as an argument", arg.Name()))
+		}
+		args[idx], err = valuer.Value()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	_, err = dc.Session.ExecContext(context.Background(), prepared.Query, args...)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// Cli implements the interface Interface. Automatically closes any running servers before usage. Read-only commands
+// are retried when they fail with a known transient error.
+func (i *DoltInterface) Cli(c *Cycle, args []string) (string, error) {
+	err := connection.CloseDoltConnections()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	retries := int64(0)
+	if isRetryableCliQuery(args) {
+		retries = c.Planner.Base.Options.CLIReadRetries
+	}
+	for attempt := int64(0); ; attempt++ {
+		out, err := runCliQuery(args)
+		if err == nil || attempt >= retries || !isTransientCliError(err) {
+			return out, err
+		}
+		err = c.Logger.WriteLine(LogType_WARN, fmt.Sprintf("Retrying CLI command after transient error: %s", err.Error()))
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		time.Sleep(time.Duration(attempt+1) * 250 * time.Millisecond)
+	}
+}
+
+// Session implements the interface Interface. A new session begins whenever the server connection is reopened.
+func (i *DoltInterface) Session(c *Cycle) (interface{}, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return dc.Session, nil
+}
+
+// WriterInterface writes statements to a writer, one per line, rather than running them. Commands that modify the
+// repository are written as their equivalent stored procedures, so that the output may be run as a SQL script. As
+// nothing is run, the output of read-only commands is simulated using the cycle's internal state. Each cycle creates
+// and uses its own database, so the output of several cycles may be run as a single script.
+type WriterInterface struct {
+	Writer  io.Writer
+	commits uint64
+	dirty   map[string]bool
+}
+
+var _ Interface = (*WriterInterface)(nil)
+
+// NewWriterInterface returns a new *WriterInterface that writes to the given writer.
+func NewWriterInterface(writer io.Writer) *WriterInterface {
+	return &WriterInterface{
+		Writer: writer,
+		dirty:  make(map[string]bool),
+	}
+}
+
+// Exec implements the interface Interface.
+func (i *WriterInterface) Exec(c *Cycle, statement string) error {
+	i.dirty[c.GetCurrentBranch().Name] = true
+	return i.write(statement)
+}
+
+// ExecPrepared implements the interface Interface. The statement is written using PREPARE and EXECUTE, with each
+// argument held in a user variable, so that the script still runs it as a prepared statement.
+func (i *WriterInterface) ExecPrepared(c *Cycle, prepared Prepared) error {
+	i.dirty[c.GetCurrentBranch().Name] = true
+	lines := []string{fmt.Sprintf("PREPARE fuzzer_stmt FROM %s;", types.StringValue(prepared.Query).MySQLQuoted())}
+	variables := make([]string, len(prepared.Args))
+	for idx, arg := range prepared.Args {
+		variables[idx] = fmt.Sprintf("@fuzzer_arg%d", idx+1)
+		lines = append(lines, fmt.Sprintf("SET %s = %s;", variables[idx], arg.MySQLString()))
+	}
+	if len(variables) > 0 {
+		lines = append(lines, fmt.Sprintf("EXECUTE fuzzer_stmt USING %s;", strings.Join(variables, ", ")))
+	} else {
+		lines = append(lines, "EXECUTE fuzzer_stmt;")
+	}
+	lines = append(lines, "DEALLOCATE PREPARE fuzzer_stmt;")
+	return i.write(strings.Join(lines, "\n"))
+}
+
+// Cli implements the interface Interface.
+func (i *WriterInterface) Cli(c *Cycle, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("cannot write an empty dolt command")
+	}
+	switch args[0] {
+	case "init":
+		i.dirty = make(map[string]bool)
+		return "", i.write(fmt.Sprintf("CREATE DATABASE `%s`;\nUSE `%s`;", c.Name, c.Name))
+	case "log":
+		return "commit " + i.nextHash(), nil
+	case "status":
+		if !i.dirty[c.GetCurrentBranch().Name] {
+			return "nothing to commit, working tree clean", nil
+		}
+		return "", nil
+	case "gc":
+		// Garbage collection does not change the contents of the repository, so it is left out of the script
+		return "", nil
+	case "branch":
+		if len(args) == 2 && args[1] == "--show-current" {
+			return c.GetCurrentBranch().Name, nil
+		}
+	case "commit":
+		delete(i.dirty, c.GetCurrentBranch().Name)
+		if err := i.writeProcedure(args); err != nil {
+			return "", errors.Wrap(err)
+		}
+		return "commit " + i.nextHash(), nil
+	}
+	switch args[0] {
+	case "add", "branch", "checkout":
+		return "", i.writeProcedure(args)
+	default:
+		return "", errors.New(fmt.Sprintf("`dolt %s` cannot be written as SQL", FormatCliArgs(args)))
+	}
+}
+
+// Session implements the interface Interface. The written statements form a single session for each cycle.
+func (i *WriterInterface) Session(c *Cycle) (interface{}, error) {
+	return c, nil
+}
+
+// writeProcedure writes the given dolt command as a call to its stored procedure.
+func (i *WriterInterface) writeProcedure(args []string) error {
+	quotedArgs := make([]string, len(args)-1)
+	for idx, arg := range args[1:] {
+		quotedArgs[idx] = types.StringValue(arg).MySQLQuoted()
+	}
+	return i.write(fmt.Sprintf("CALL DOLT_%s(%s);", strings.ToUpper(args[0]), strings.Join(quotedArgs, ", ")))
+}
+
+// write writes the given statement on its own line.
+func (i *WriterInterface) write(statement string) error {
+	_, err := io.WriteString(i.Writer, statement+"\n")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// nextHash returns a placeholder commit hash, as the real hash is only known once the script has been run.
+func (i *WriterInterface) nextHash() string {
+	i.commits++
+	return fmt.Sprintf("%032d", i.commits)
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+// ValidateInterfaceParity reads the table through both `dolt sql -q` using the JSON format and the server connection,
+// and verifies that both interfaces return the same rows. Values must be identical once numbers are normalized, as JSON
+// may write a number differently than the server does. Columns that OutputFormats does not compare are skipped, as JSON
+// encodes their bytes rather than returning them as-is.
+func ValidateInterfaceParity(c *Cycle, table *Table) error {
+	columnNames := table.ColumnNames()
+	var comparable []bool
+	for _, col := range append(append([]*Column{}, table.PKCols...), table.NonPKCols...) {
+		comparable = append(comparable, isOutputFormatComparable(col))
+	}
+	cliRows, err := table.GetDoltOutputFormatRows(c, OutputFormat_JSON)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	serverRows, err := table.GetDoltServerStringRows(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = compareInterfaceRows(serverRows, cliRows, columnNames, comparable); err != nil {
+		return errors.New(fmt.Sprintf("On table `%s`: %s", table.Name, err.Error()))
+	}
+	return nil
+}
+
+// compareInterfaceRows returns an error if the rows read from the CLI differ from the rows read over the server
+// connection. Only the columns marked as comparable are checked.
+func compareInterfaceRows(serverRows [][]*string, cliRows [][]*string, columnNames []string, comparable []bool) error {
+	if len(serverRows) != len(cliRows) {
+		return errors.New(fmt.Sprintf("the server returned %d rows but dolt sql returned %d", len(serverRows), len(cliRows)))
+	}
+	for i := range serverRows {
+		for j := range columnNames {
+			if !comparable[j] || outputFormatValuesEqual(serverRows[i][j], cliRows[i][j]) {
+				continue
+			}
+			return errors.New(fmt.Sprintf("row %d of column `%s` is %s from the server but %s from dolt sql",
+				i, columnNames[j], outputFormatValueString(serverRows[i][j]), outputFormatValueString(cliRows[i][j])))
+		}
+	}
+	return nil
+}
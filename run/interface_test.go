@@ -0,0 +1,93 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestWriterInterface(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	i := NewWriterInterface(buffer)
+	c := &Cycle{Name: "db", branches: []*Branch{{Name: "main"}, {Name: "other"}}}
+
+	_, err := i.Cli(c, []string{"init"})
+	require.NoError(t, err)
+	out, err := i.Cli(c, []string{"log", "-n", "1"})
+	require.NoError(t, err)
+	require.Equal(t, "commit 00000000000000000000000000000001", out)
+	out, err = i.Cli(c, []string{"status"})
+	require.NoError(t, err)
+	require.Contains(t, out, "nothing to commit")
+
+	require.NoError(t, i.Exec(c, "INSERT INTO t VALUES (1);"))
+	out, err = i.Cli(c, []string{"status"})
+	require.NoError(t, err)
+	require.NotContains(t, out, "nothing to commit")
+	_, err = i.Cli(c, []string{"add", "-A"})
+	require.NoError(t, err)
+	out, err = i.Cli(c, []string{"commit", "-m", "it's\na message", "--author", "A <a@example.com>"})
+	require.NoError(t, err)
+	require.Equal(t, "commit 00000000000000000000000000000002", out)
+	out, err = i.Cli(c, []string{"status"})
+	require.NoError(t, err)
+	require.Contains(t, out, "nothing to commit")
+
+	_, err = i.Cli(c, []string{"branch", "other"})
+	require.NoError(t, err)
+	out, err = i.Cli(c, []string{"branch", "--show-current"})
+	require.NoError(t, err)
+	require.Equal(t, "main", out)
+	c.currentBranch = 1
+	_, err = i.Cli(c, []string{"checkout", "other"})
+	require.NoError(t, err)
+	_, err = i.Cli(c, []string{"gc"})
+	require.NoError(t, err)
+	_, err = i.Cli(c, []string{"merge", "main"})
+	require.Error(t, err)
+
+	require.Equal(t, "CREATE DATABASE `db`;\n"+
+		"USE `db`;\n"+
+		"INSERT INTO t VALUES (1);\n"+
+		"CALL DOLT_ADD('-A');\n"+
+		`CALL DOLT_COMMIT('-m', 'it\'s\na message', '--author', 'A <a@example.com>');`+"\n"+
+		"CALL DOLT_BRANCH('other');\n"+
+		"CALL DOLT_CHECKOUT('other');\n", buffer.String())
+}
+
+func TestWriterInterfacePrepared(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	i := NewWriterInterface(buffer)
+	c := &Cycle{Name: "db", branches: []*Branch{{Name: "main"}}}
+
+	require.NoError(t, i.ExecPrepared(c, Prepared{
+		Query:   "INSERT INTO `t` VALUES (?,?);",
+		Args:    []types.Value{types.IntValue{Int32Value: 1}, types.VarcharValue{StringValue: "it's"}},
+		Literal: "INSERT INTO `t` VALUES (1,'it\\'s');",
+	}))
+	out, err := i.Cli(c, []string{"status"})
+	require.NoError(t, err)
+	require.NotContains(t, out, "nothing to commit")
+	require.Equal(t, "PREPARE fuzzer_stmt FROM 'INSERT INTO `t` VALUES (?,?);';\n"+
+		"SET @fuzzer_arg1 = 1;\n"+
+		`SET @fuzzer_arg2 = 'it\'s';`+"\n"+
+		"EXECUTE fuzzer_stmt USING @fuzzer_arg1, @fuzzer_arg2;\n"+
+		"DEALLOCATE PREPARE fuzzer_stmt;\n", buffer.String())
+}
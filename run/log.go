@@ -0,0 +1,129 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+// LogEntry is a single commit as reported by Dolt's log.
+type LogEntry struct {
+	Hash      string
+	Committer string
+	Email     string
+	Message   string
+	// Parents are the hashes of the commit's parents, in the order that Dolt reports them.
+	Parents []string
+}
+
+// GetDoltLog returns Dolt's log of the current branch, beginning with the most recent commit. The log is read only from
+// the "dolt_log" and "dolt_commit_ancestors" system tables, and not from the output of "dolt log".
+func GetDoltLog(c *Cycle) ([]LogEntry, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	logRows, err := dc.Conn.QueryContext(context.Background(),
+		"SELECT `commit_hash`, `committer`, `email`, `message` FROM `dolt_log`;")
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer logRows.Close()
+	var entries []LogEntry
+	for logRows.Next() {
+		entry := LogEntry{}
+		if err = logRows.Scan(&entry.Hash, &entry.Committer, &entry.Email, &entry.Message); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = logRows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	parents, err := GetDoltCommitAncestors(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	for i := range entries {
+		entries[i].Parents = parents[entries[i].Hash]
+	}
+	return entries, nil
+}
+
+// GetDoltCommitAncestors returns the parents of every commit in the repository, as read from the
+// "dolt_commit_ancestors" system table. Each commit's parents are in the order of their parent index. Every commit is
+// present in the map, including commits without any parents, which have an empty slice.
+func GetDoltCommitAncestors(c *Cycle) (map[string][]string, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	ancestorRows, err := dc.Conn.QueryContext(context.Background(),
+		"SELECT `commit_hash`, `parent_hash` FROM `dolt_commit_ancestors` ORDER BY `commit_hash`, `parent_index`;")
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer ancestorRows.Close()
+	parents := make(map[string][]string)
+	for ancestorRows.Next() {
+		var commitHash string
+		var parentHash sql.NullString
+		if err = ancestorRows.Scan(&commitHash, &parentHash); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if _, ok := parents[commitHash]; !ok {
+			parents[commitHash] = []string{}
+		}
+		if parentHash.Valid {
+			parents[commitHash] = append(parents[commitHash], parentHash.String)
+		}
+	}
+	if err = ancestorRows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return parents, nil
+}
+
+// CommitAncestors returns the parents of every commit reachable from the heads of all branches, keyed by hash, using
+// the fuzzer's internal history. Each commit's parents are in the order that they were given to the commit. Working
+// sets are not commits, so they are skipped, although the commits they are built upon are included.
+func CommitAncestors(c *Cycle) map[string][]string {
+	ancestors := make(map[string][]string)
+	var visit func(commit *Commit)
+	visit = func(commit *Commit) {
+		if commit.Hash != "" {
+			if _, ok := ancestors[commit.Hash]; ok {
+				return
+			}
+			parents := make([]string, len(commit.Parents))
+			for i, parent := range commit.Parents {
+				parents[i] = parent.Hash
+			}
+			ancestors[commit.Hash] = parents
+		}
+		for _, parent := range commit.Parents {
+			visit(parent)
+		}
+	}
+	for _, branchName := range c.GetBranchNames() {
+		visit(c.GetBranch(branchName).GetWorkingSet())
+	}
+	return ancestors
+}
@@ -0,0 +1,264 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// NullUniqueIndex is a nullable BIGINT column that is added to a table along with a UNIQUE index over it, which is used
+// to test that a unique index permits any number of NULLs, while still rejecting duplicate non-NULL values. The column
+// and index are added to both Dolt and the internal data, so that the internal data enforces the same constraint.
+type NullUniqueIndex struct {
+	Table  *Table
+	Column *Column
+	Index  *Index
+	// nextValue is the next non-NULL value written to the column, which is incremented so that values never collide.
+	nextValue int64
+}
+
+// NewNullUniqueIndex adds a new nullable column to the table, which every existing row holds as NULL, and then adds a
+// UNIQUE index over it. The table must have a primary key.
+func NewNullUniqueIndex(c *Cycle, table *Table) (*NullUniqueIndex, error) {
+	if table.IsKeyless() {
+		return nil, errors.New(fmt.Sprintf("cannot test NULLs in a unique index on keyless table `%s`", table.Name))
+	}
+	colName, err := newColumnName(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	c.usedNames[colName] = struct{}{}
+	indexName, err := newIndexName(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	col := &Column{Name: colName, Type: &types.BigintInstance{}}
+	if err = table.AddColumn(col, types.NilValue{}); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	err = c.SqlServer(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s;", table.Name, colName, col.Type.Name(false)))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	// Every existing row holds a NULL, so creating the index is the first test of the exception
+	index := NewIndex(indexName, []string{colName}, nil, true)
+	if err = table.Data.Exec(index.CreateString(table.Name) + ";"); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	table.Indexes = append(table.Indexes, index)
+	if err = c.SqlServer(index.CreateString(table.Name) + ";"); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &NullUniqueIndex{
+		Table:  table,
+		Column: col,
+		Index:  index,
+	}, nil
+}
+
+// InsertNull inserts a new random row that holds NULL in the unique column.
+func (n *NullUniqueIndex) InsertNull(c *Cycle) error {
+	colIdx, _ := n.Table.getColumn(n.Column.Name)
+	for i := 0; i < 10000000; i++ {
+		row, err := NewRow(n.Table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		row.Values[colIdx] = types.NilValue{}
+		err = n.Table.Data.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", n.Table.Name, n.Table.Data.RowString(row)))
+		if err != nil {
+			if n.Table.Data.IsConstraintError(err) {
+				continue
+			}
+			return errors.Wrap(err)
+		}
+		return c.SqlServer(fmt.Sprintf("INSERT INTO `%s`%s VALUES (%s);", n.Table.Name, n.Table.valuesColumnList(),
+			row.MySQLString()))
+	}
+	return errors.New("10 million consecutive collisions on attempted INSERT, aborting cycle")
+}
+
+// SetDistinct sets the unique column of a random row to a value that no other row holds.
+func (n *NullUniqueIndex) SetDistinct(c *Cycle) error {
+	value := types.BigintValue{Int64Value: types.Int64Value(n.nextValue)}
+	n.nextValue++
+	return n.setRandomRow(c, value)
+}
+
+// SetNull sets the unique column of a random row to NULL.
+func (n *NullUniqueIndex) SetNull(c *Cycle) error {
+	return n.setRandomRow(c, types.NilValue{})
+}
+
+// setRandomRow sets the unique column of a random row to the given value. Does nothing if the table is empty.
+func (n *NullUniqueIndex) setRandomRow(c *Cycle, value types.Value) error {
+	row, ok, err := n.Table.Data.GetRandomRow()
+	if err != nil || !ok {
+		return err
+	}
+	update, internalUpdate, err := n.updateStrings(row, value)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = n.Table.Data.Exec(internalUpdate); err != nil {
+		return errors.Wrap(err)
+	}
+	return c.SqlServer(update)
+}
+
+// Collide attempts to set the unique column of a random row to a non-NULL value that is already held by another row,
+// which both Dolt and the internal data must reject. Does nothing if no row holds a non-NULL value, or if the chosen row
+// already holds the value. The statement is run directly on Dolt rather than through the Interface, as it is expected to
+// fail, so this also does nothing when validation is skipped.
+func (n *NullUniqueIndex) Collide(c *Cycle) error {
+	if c.Planner.Base.Arguments.SkipValidation {
+		return nil
+	}
+	existing, ok, err := n.randomNonNullValue()
+	if err != nil || !ok {
+		return err
+	}
+	row, ok, err := n.Table.Data.GetRandomRow()
+	if err != nil || !ok {
+		return err
+	}
+	colIdx, _ := n.Table.getColumn(n.Column.Name)
+	if row.Values[colIdx].Compare(existing) == 0 {
+		return nil
+	}
+	update, internalUpdate, err := n.updateStrings(row, existing)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = n.Table.Data.Exec(internalUpdate); err == nil {
+		return errors.New(fmt.Sprintf("the internal data accepted a duplicate value in unique index `%s`: %s",
+			n.Index.Name, update))
+	} else if !n.Table.Data.IsConstraintError(err) {
+		return errors.Wrap(err)
+	}
+	// The statement is expected to fail, so it is not written as a statement, which would prevent replaying the log
+	err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Expecting a duplicate entry rejection: %s", update))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	_, err = dc.Conn.ExecContext(context.Background(), update)
+	if err == nil {
+		return errors.New(fmt.Sprintf("Dolt accepted a duplicate value in unique index `%s` on table `%s`: %s",
+			n.Index.Name, n.Table.Name, update))
+	}
+	if !strings.Contains(strings.ToLower(err.Error()), "duplicate") {
+		return errors.New(fmt.Sprintf("Dolt rejected a duplicate value in unique index `%s` on table `%s` with an "+
+			"unexpected error: %s\n%s", n.Index.Name, n.Table.Name, update, err.Error()))
+	}
+	return nil
+}
+
+// Drop drops the unique index and its column from both Dolt and the internal data, restoring the table's schema.
+func (n *NullUniqueIndex) Drop(c *Cycle) error {
+	for i, index := range n.Table.Indexes {
+		if index == n.Index {
+			n.Table.Indexes = append(n.Table.Indexes[:i:i], n.Table.Indexes[i+1:]...)
+			break
+		}
+	}
+	err := n.Table.Data.Exec(n.Table.Data.Dialect(
+		fmt.Sprintf("DROP INDEX `%s` ON `%s`;", n.Index.Name, n.Table.Name),
+		fmt.Sprintf("DROP INDEX `%s`;", n.Index.Name)))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = c.SqlServer(fmt.Sprintf("DROP INDEX `%s` ON `%s`;", n.Index.Name, n.Table.Name)); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = n.Table.DropColumn(n.Column.Name); err != nil {
+		return errors.Wrap(err)
+	}
+	return c.SqlServer(fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`;", n.Table.Name, n.Column.Name))
+}
+
+// NullCount returns the number of rows in the internal data that hold NULL in the unique column.
+func (n *NullUniqueIndex) NullCount() (int64, error) {
+	var count int64
+	err := n.Table.Data.namespace.QueryRow(context.Background(), fmt.Sprintf(
+		"SELECT COUNT(*) FROM `%s` WHERE `%s` IS NULL;", n.Table.Name, n.Column.Name), &count)
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	return count, nil
+}
+
+// randomNonNullValue returns a random non-NULL value held by the unique column. Returns false if every row holds NULL.
+func (n *NullUniqueIndex) randomNonNullValue() (types.Value, bool, error) {
+	var count int64
+	err := n.Table.Data.namespace.QueryRow(context.Background(), fmt.Sprintf(
+		"SELECT COUNT(*) FROM `%s` WHERE `%s` IS NOT NULL;", n.Table.Name, n.Column.Name), &count)
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	if count == 0 {
+		return nil, false, nil
+	}
+	offset, err := rand.Uint64()
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	var value int64
+	err = n.Table.Data.namespace.QueryRow(context.Background(), fmt.Sprintf(
+		"SELECT `%s` FROM `%s` WHERE `%s` IS NOT NULL LIMIT 1 OFFSET %d;",
+		n.Column.Name, n.Table.Name, n.Column.Name, offset%uint64(count)), &value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	return types.BigintValue{Int64Value: types.Int64Value(value)}, true, nil
+}
+
+// updateStrings returns the UPDATE statements, for Dolt and the internal data respectively, that set the unique column
+// of the given row to the given value.
+func (n *NullUniqueIndex) updateStrings(row Row, value types.Value) (string, string, error) {
+	sets, err := GenerateColumnEquals([]*Column{n.Column}, []types.Value{value})
+	if err != nil {
+		return "", "", errors.Wrap(err)
+	}
+	setsInternal, err := n.Table.Data.ColumnEquals([]*Column{n.Column}, []types.Value{value})
+	if err != nil {
+		return "", "", errors.Wrap(err)
+	}
+	whereCols, whereVals := n.Table.identifyingColumns(row)
+	wheres, err := GenerateColumnEquals(whereCols, whereVals)
+	if err != nil {
+		return "", "", errors.Wrap(err)
+	}
+	wheresInternal, err := n.Table.Data.ColumnEquals(whereCols, whereVals)
+	if err != nil {
+		return "", "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("UPDATE `%s` SET %s WHERE %s;", n.Table.Name, sets[0], strings.Join(wheres, " AND ")),
+		fmt.Sprintf("UPDATE `%s` SET %s WHERE %s;", n.Table.Name, setsInternal[0], strings.Join(wheresInternal, " AND ")),
+		nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestNullUniqueIndex(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	c := &Cycle{
+		Planner: &Planner{
+			Base:      &parameters.Base{},
+			Hooks:     &Hooks{},
+			Interface: NewWriterInterface(buffer),
+		},
+		Name:        "db",
+		Logger:      &fakeLogger{},
+		branches:    []*Branch{{Name: "main"}},
+		nameRegexes: &nameRegexes{Columns: regexp.MustCompile("^$"), Indexes: regexp.MustCompile("^$")},
+		usedNames:   map[string]struct{}{"nulls": {}},
+	}
+	table, err := NewTable(&Commit{}, "nulls", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	for i := 0; i < 5; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+
+	// Every existing row holds a NULL once the column has been added, which the unique index must permit
+	nullUnique, err := NewNullUniqueIndex(c, table)
+	require.NoError(t, err)
+	require.Contains(t, table.Indexes, nullUnique.Index)
+	require.True(t, nullUnique.Index.IsUnique)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, nullUnique.InsertNull(c))
+	}
+	nullCount, err := nullUnique.NullCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(10), nullCount)
+
+	for i := 0; i < 20; i++ {
+		require.NoError(t, nullUnique.SetDistinct(c))
+	}
+	require.NoError(t, nullUnique.SetNull(c))
+
+	// The internal data must reject a duplicate non-NULL value
+	existing, ok, err := nullUnique.randomNonNullValue()
+	require.NoError(t, err)
+	require.True(t, ok)
+	colIdx, _ := table.getColumn(nullUnique.Column.Name)
+	rows, err := table.Data.GetAllRows()
+	require.NoError(t, err)
+	for _, row := range rows {
+		if row.Values[colIdx].Compare(existing) != 0 {
+			_, internalUpdate, err := nullUnique.updateStrings(row, existing)
+			require.NoError(t, err)
+			err = table.Data.Exec(internalUpdate)
+			require.Error(t, err)
+			require.True(t, table.Data.IsConstraintError(err))
+			break
+		}
+	}
+
+	// Dropping restores the original schema
+	require.NoError(t, nullUnique.Drop(c))
+	require.Empty(t, table.Indexes)
+	require.Equal(t, []string{"pk", "v1"}, table.ColumnNames())
+	rowCount, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(10), rowCount)
+	require.Contains(t, buffer.String(), "CREATE UNIQUE INDEX")
+}
@@ -0,0 +1,291 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dolthub/go-sqlite3"
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+// Oracle is the database that holds the internal data of every table, which is the source of truth that Dolt is
+// validated against. Each table is held in its own namespace that no other table can see, so that every commit may
+// hold its own copy of a table with the same name.
+type Oracle interface {
+	// Name returns the name of the oracle, as it is given in the config.
+	Name() string
+	// Open returns a new and empty namespace, which will hold a single table.
+	Open(ctx context.Context) (Namespace, error)
+	// SQLite returns whether statements must be written in SQLite's dialect. Otherwise, they are written in MySQL's.
+	SQLite() bool
+	// IsConstraintError returns whether the error is due to a row violating a constraint, such as a duplicate key.
+	IsConstraintError(err error) bool
+}
+
+// Namespace is where an Oracle holds a single table. Statements only see the tables within their own namespace.
+type Namespace interface {
+	// ExecContext executes the given statement within the namespace.
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	// QueryContext runs the given query within the namespace. The rows must be closed once they are no longer needed.
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	// QueryRow runs the given query within the namespace, and scans its first row into the destinations. Returns
+	// sql.ErrNoRows if the query returned no rows.
+	QueryRow(ctx context.Context, query string, dest ...interface{}) error
+	// Release deletes the namespace along with everything in it, and frees its resources.
+	Release()
+}
+
+// oracle is the Oracle that is used for all newly created table data.
+var oracle Oracle = &SQLiteOracle{}
+
+// SetOracle sets the Oracle that will be used by all table data that is created afterward. Existing table data
+// continues to use the Oracle that it was created with.
+func SetOracle(o Oracle) {
+	oracle = o
+}
+
+// NewOracle returns the Oracle with the given name. The data source name is only used by oracles that connect to an
+// external server.
+func NewOracle(name string, dsn string) (Oracle, error) {
+	switch strings.ToLower(name) {
+	case "", "sqlite":
+		return &SQLiteOracle{}, nil
+	case "mysql":
+		return NewMySQLOracle(dsn)
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown oracle: %s", name))
+	}
+}
+
+// SQLiteOracle holds the internal data in SQLite. Every connection to an in-memory SQLite database has its own
+// database, so each table is trivially isolated. SQLite is fast and requires no setup, however its semantics diverge
+// from MySQL's for some types, such as strings with collations.
+type SQLiteOracle struct{}
+
+var _ Oracle = (*SQLiteOracle)(nil)
+
+var sqliteDb *sql.DB
+
+func init() {
+	var err error
+	sqliteDb, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Name implements the interface Oracle.
+func (o *SQLiteOracle) Name() string {
+	return "sqlite"
+}
+
+// Open implements the interface Oracle.
+func (o *SQLiteOracle) Open(ctx context.Context) (Namespace, error) {
+	conn, err := sqliteDb.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return sqliteNamespace{conn}, nil
+}
+
+// SQLite implements the interface Oracle.
+func (o *SQLiteOracle) SQLite() bool {
+	return true
+}
+
+// IsConstraintError implements the interface Oracle.
+func (o *SQLiteOracle) IsConstraintError(err error) bool {
+	sqliteErr, ok := err.(sqlite3.Error)
+	return ok && sqliteErr.Code == sqlite3.ErrConstraint
+}
+
+// sqliteNamespace is a connection to its own in-memory SQLite database.
+type sqliteNamespace struct {
+	*sql.Conn
+}
+
+var _ Namespace = sqliteNamespace{}
+
+// QueryRow implements the interface Namespace.
+func (n sqliteNamespace) QueryRow(ctx context.Context, query string, dest ...interface{}) error {
+	return n.QueryRowContext(ctx, query).Scan(dest...)
+}
+
+// Release implements the interface Namespace. The in-memory database is deleted once its connection is closed.
+func (n sqliteNamespace) Release() {
+	_ = n.Close()
+}
+
+// mysqlIdleConnections is the number of idle connections that the MySQL Oracle keeps open for reuse.
+const mysqlIdleConnections = 8
+
+// MySQLOracle holds the internal data in a MySQL server, which gives the internal data the same semantics as those
+// that Dolt is expected to have. Each table is held in its own database, which is dropped once the table data is
+// closed. Every database shares the same pool of connections, as a cycle keeps the table data of every commit until
+// it ends, which could otherwise exceed the server's connection limit. The server is not managed by the fuzzer, and
+// must be running before the cycle starts.
+type MySQLOracle struct {
+	db            *sql.DB
+	databaseCount uint64
+}
+
+var _ Oracle = (*MySQLOracle)(nil)
+
+// NewMySQLOracle returns a new *MySQLOracle that connects using the given data source name, such as
+// "root:password@tcp(127.0.0.1:3306)/".
+func NewMySQLOracle(dsn string) (*MySQLOracle, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	err = db.Ping()
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err)
+	}
+	db.SetMaxIdleConns(mysqlIdleConnections)
+	return &MySQLOracle{db: db}, nil
+}
+
+// Name implements the interface Oracle.
+func (o *MySQLOracle) Name() string {
+	return "mysql"
+}
+
+// Open implements the interface Oracle.
+func (o *MySQLOracle) Open(ctx context.Context) (Namespace, error) {
+	// The process ID is included so that multiple fuzzers may share the same server
+	dbName := fmt.Sprintf("fuzzer_oracle_%d_%d", os.Getpid(), atomic.AddUint64(&o.databaseCount, 1))
+	_, err := o.db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE `%s`;", dbName))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &mysqlNamespace{db: o.db, dbName: dbName}, nil
+}
+
+// SQLite implements the interface Oracle.
+func (o *MySQLOracle) SQLite() bool {
+	return false
+}
+
+// IsConstraintError implements the interface Oracle.
+func (o *MySQLOracle) IsConstraintError(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	// 1062 is ER_DUP_ENTRY
+	return ok && mysqlErr.Number == 1062
+}
+
+// mysqlNamespace is a database on the MySQL server. Each statement runs on a connection from the shared pool, which
+// switches to the database beforehand. An explicit transaction must run on a single connection, so the connection that
+// begins a transaction is held by the namespace until the transaction ends.
+type mysqlNamespace struct {
+	db          *sql.DB
+	dbName      string
+	transaction *sql.Conn
+}
+
+var _ Namespace = (*mysqlNamespace)(nil)
+
+// ExecContext implements the interface Namespace.
+func (n *mysqlNamespace) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	conn, err := n.conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	result, err := conn.ExecContext(ctx, query, args...)
+	switch strings.ToUpper(strings.TrimRight(strings.TrimSpace(query), ";")) {
+	case "BEGIN", "START TRANSACTION":
+		if err == nil {
+			n.transaction = conn
+		}
+	case "COMMIT", "ROLLBACK":
+		n.transaction = nil
+	}
+	if conn != n.transaction {
+		_ = conn.Close()
+	}
+	return result, err
+}
+
+// QueryContext implements the interface Namespace.
+func (n *mysqlNamespace) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	conn, err := n.conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	rows, err := conn.QueryContext(ctx, query, args...)
+	n.release(conn)
+	return rows, err
+}
+
+// QueryRow implements the interface Namespace.
+func (n *mysqlNamespace) QueryRow(ctx context.Context, query string, dest ...interface{}) error {
+	conn, err := n.conn(ctx)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	err = conn.QueryRowContext(ctx, query).Scan(dest...)
+	if conn != n.transaction {
+		_ = conn.Close()
+	}
+	return err
+}
+
+// Release implements the interface Namespace. An open transaction is rolled back, so that its connection does not
+// return to the pool within the transaction.
+func (n *mysqlNamespace) Release() {
+	if n.transaction != nil {
+		_, _ = n.transaction.ExecContext(context.Background(), "ROLLBACK;")
+		_ = n.transaction.Close()
+		n.transaction = nil
+	}
+	_, _ = n.db.ExecContext(context.Background(), fmt.Sprintf("DROP DATABASE `%s`;", n.dbName))
+}
+
+// conn returns the connection that holds the open transaction, or otherwise a connection from the pool that has
+// switched to the namespace's database.
+func (n *mysqlNamespace) conn(ctx context.Context) (*sql.Conn, error) {
+	if n.transaction != nil {
+		return n.transaction, nil
+	}
+	conn, err := n.db.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if _, err = conn.ExecContext(ctx, fmt.Sprintf("USE `%s`;", n.dbName)); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err)
+	}
+	return conn, nil
+}
+
+// release returns a connection from conn to the pool once its rows have been closed, unless it holds the open
+// transaction. Closing a connection waits for its rows to be closed, so this happens in the background, as the rows are
+// still being read by the caller.
+func (n *mysqlNamespace) release(conn *sql.Conn) {
+	if conn != n.transaction {
+		go func() {
+			_ = conn.Close()
+		}()
+	}
+}
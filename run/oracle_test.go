@@ -0,0 +1,47 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestNewOracle(t *testing.T) {
+	for _, name := range []string{"", "sqlite", "SQLite"} {
+		o, err := NewOracle(name, "")
+		require.NoError(t, err)
+		require.IsType(t, &SQLiteOracle{}, o)
+	}
+	_, err := NewOracle("postgres", "")
+	require.Error(t, err)
+}
+
+func TestTableDataConstraintError(t *testing.T) {
+	table, err := NewTable(&Commit{}, "constrained", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, nil, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+
+	row := Row{Values: []types.Value{types.IntValue{Int32Value: 1}}, PkColsLen: 1}
+	require.NoError(t, table.Data.Exec("INSERT INTO `constrained` VALUES ("+table.Data.RowString(row)+");"))
+	err = table.Data.Exec("INSERT INTO `constrained` VALUES (" + table.Data.RowString(row) + ");")
+	require.Error(t, err)
+	require.True(t, table.Data.IsConstraintError(err))
+}
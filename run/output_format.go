@@ -0,0 +1,309 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// OutputFormat is a result format of `dolt sql`, given by its -r argument.
+type OutputFormat string
+
+const (
+	OutputFormat_JSON    OutputFormat = "json"
+	OutputFormat_CSV     OutputFormat = "csv"
+	OutputFormat_Tabular OutputFormat = "tabular"
+)
+
+// OutputFormats are all of the result formats that are compared. The first format is the reference that all others are
+// compared against, as it is the only one that unambiguously distinguishes NULL from every string.
+var OutputFormats = []OutputFormat{OutputFormat_JSON, OutputFormat_CSV, OutputFormat_Tabular}
+
+// unalignedTabular is the error message when a tabular result cannot be split into cells, which happens when a value
+// contains a line break or characters that are wider than a single column.
+const unalignedTabular = "tabular output is not aligned"
+
+// ValidateOutputFormats reads the table from `dolt sql` in every format from OutputFormats, and verifies that each
+// format represents the same rows as the reference format. Columns using binary, bit, or spatial types are not compared,
+// as each format intentionally encodes their bytes differently. Tabular output that cannot be split into cells is
+// skipped, and noted in the log.
+func ValidateOutputFormats(c *Cycle, table *Table) error {
+	columnNames := table.ColumnNames()
+	var comparable []bool
+	for _, col := range append(append([]*Column{}, table.PKCols...), table.NonPKCols...) {
+		comparable = append(comparable, isOutputFormatComparable(col))
+	}
+	expected, err := table.GetDoltOutputFormatRows(c, OutputFormats[0])
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, format := range OutputFormats[1:] {
+		actual, err := table.GetDoltOutputFormatRows(c, format)
+		if err != nil && strings.Contains(err.Error(), unalignedTabular) {
+			err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Skipping the %s output of table `%s`: %s",
+				format, table.Name, unalignedTabular))
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if err = compareOutputFormats(format, expected, actual, columnNames, comparable); err != nil {
+			return errors.New(fmt.Sprintf("On table `%s`: %s", table.Name, err.Error()))
+		}
+	}
+	return nil
+}
+
+// isOutputFormatComparable returns whether the column's values should render identically in every format.
+func isOutputFormatComparable(col *Column) bool {
+	switch col.Type.(type) {
+	case *types.BinaryInstance, *types.VarbinaryInstance, *types.TinyblobInstance, *types.BlobInstance,
+		*types.MediumblobInstance, *types.LongblobInstance, *types.BitInstance, *types.LinestringInstance,
+		*types.PolygonInstance:
+		return false
+	default:
+		return true
+	}
+}
+
+// parseOutputFormat parses the output of `dolt sql` in the given format into rows of values, with a nil value for NULL.
+// The column names give the order of each row's values.
+func parseOutputFormat(format OutputFormat, out string, columnNames []string) ([][]*string, error) {
+	switch format {
+	case OutputFormat_JSON:
+		return parseJSONOutput(out, columnNames)
+	case OutputFormat_CSV:
+		return parseCSVOutput(out, columnNames)
+	case OutputFormat_Tabular:
+		return parseTabularOutput(out, columnNames)
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown output format: %s", format))
+	}
+}
+
+// parseJSONOutput parses JSON output, which is an object holding an array of rows, with each row being an object keyed
+// by column name. NULL values are either null or absent from the row.
+func parseJSONOutput(out string, columnNames []string) ([][]*string, error) {
+	if len(out) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(out))
+	decoder.UseNumber()
+	var result struct {
+		Rows []map[string]interface{} `json:"rows"`
+	}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	rows := make([][]*string, len(result.Rows))
+	for i, jsonRow := range result.Rows {
+		rows[i] = make([]*string, len(columnNames))
+		for j, colName := range columnNames {
+			var value string
+			switch jsonValue := jsonRow[colName].(type) {
+			case nil:
+				continue
+			case string:
+				value = jsonValue
+			case json.Number:
+				value = jsonValue.String()
+			case bool:
+				value = strconv.FormatBool(jsonValue)
+			default:
+				encoded, err := json.Marshal(jsonValue)
+				if err != nil {
+					return nil, errors.Wrap(err)
+				}
+				value = string(encoded)
+			}
+			rows[i][j] = &value
+		}
+	}
+	return rows, nil
+}
+
+// parseCSVOutput parses CSV output, which begins with a header of the column names. CSV does not distinguish NULL from
+// an empty string, so both are returned as an empty string.
+func parseCSVOutput(out string, columnNames []string) ([][]*string, error) {
+	if len(out) == 0 {
+		return nil, nil
+	}
+	reader := csv.NewReader(strings.NewReader(out))
+	reader.FieldsPerRecord = len(columnNames)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if strings.Join(header, ",") != strings.Join(columnNames, ",") {
+		return nil, errors.New(fmt.Sprintf("header lists the columns [%s]", strings.Join(header, ", ")))
+	}
+	var rows [][]*string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		row := make([]*string, len(record))
+		for i := range record {
+			row[i] = &record[i]
+		}
+		rows = append(rows, row)
+	}
+}
+
+// parseTabularOutput parses tabular output, which draws a grid around the header and the rows. Cells are found using
+// the positions of the grid's borders, and the padding around each value is removed. NULL is written as the word NULL,
+// and is returned as nil. Returns an error containing unalignedTabular if a row does not line up with the borders.
+func parseTabularOutput(out string, columnNames []string) ([][]*string, error) {
+	if len(out) == 0 {
+		return nil, nil
+	}
+	lines := strings.Split(strings.ReplaceAll(out, "\r\n", "\n"), "\n")
+	if len(lines) < 3 {
+		return nil, errors.New(fmt.Sprintf("expected at least 3 lines but found %d", len(lines)))
+	}
+	var borders []int
+	for i, r := range []rune(lines[0]) {
+		if r == '+' {
+			borders = append(borders, i)
+		}
+	}
+	if len(borders) != len(columnNames)+1 {
+		return nil, errors.New(fmt.Sprintf("expected %d columns but found %d", len(columnNames), len(borders)-1))
+	}
+	split := func(line string) ([]string, error) {
+		lineRunes := []rune(line)
+		if utf8.RuneCountInString(line) != utf8.RuneCountInString(lines[0]) {
+			return nil, errors.New(unalignedTabular)
+		}
+		cells := make([]string, len(columnNames))
+		for i := range cells {
+			if lineRunes[borders[i]] != '|' || lineRunes[borders[i+1]] != '|' {
+				return nil, errors.New(unalignedTabular)
+			}
+			cells[i] = strings.TrimSpace(string(lineRunes[borders[i]+1 : borders[i+1]]))
+		}
+		return cells, nil
+	}
+	header, err := split(lines[1])
+	if err != nil {
+		return nil, err
+	}
+	if strings.Join(header, ",") != strings.Join(columnNames, ",") {
+		return nil, errors.New(fmt.Sprintf("header lists the columns [%s]", strings.Join(header, ", ")))
+	}
+	if lines[2] != lines[0] || lines[len(lines)-1] != lines[0] {
+		return nil, errors.New(unalignedTabular)
+	}
+	// A result without any rows only has the header's grid
+	if len(lines) == 3 {
+		return nil, nil
+	}
+	var rows [][]*string
+	for _, line := range lines[3 : len(lines)-1] {
+		cells, err := split(line)
+		if err != nil {
+			return nil, err
+		}
+		row := make([]*string, len(cells))
+		for i := range cells {
+			if cells[i] != "NULL" {
+				row[i] = &cells[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// compareOutputFormats returns an error if the actual rows, read using the given format, differ from the expected rows
+// of the reference format. Only the columns marked as comparable are checked. Values are compared as each format is
+// able to represent them: CSV cannot distinguish NULL from an empty string, while tabular output cannot distinguish NULL
+// from the string NULL, nor does it keep whitespace at either end of a value. Numbers are equal if they represent the
+// same number, regardless of how they are written.
+func compareOutputFormats(format OutputFormat, expected [][]*string, actual [][]*string, columnNames []string,
+	comparable []bool) error {
+	if len(expected) != len(actual) {
+		return errors.New(fmt.Sprintf("%s output contains %d rows but %s output contains %d",
+			OutputFormats[0], len(expected), format, len(actual)))
+	}
+	for i := range expected {
+		for j := range columnNames {
+			if !comparable[j] {
+				continue
+			}
+			expectedValue, actualValue := expected[i][j], actual[i][j]
+			switch format {
+			case OutputFormat_CSV:
+				if expectedValue == nil {
+					empty := ""
+					expectedValue = &empty
+				}
+			case OutputFormat_Tabular:
+				if expectedValue != nil {
+					trimmed := strings.TrimSpace(*expectedValue)
+					if trimmed == "NULL" {
+						continue
+					}
+					expectedValue = &trimmed
+				}
+			}
+			if outputFormatValuesEqual(expectedValue, actualValue) {
+				continue
+			}
+			return errors.New(fmt.Sprintf("row %d of column `%s` is %s in %s output but %s in %s output",
+				i, columnNames[j], outputFormatValueString(expectedValue), OutputFormats[0],
+				outputFormatValueString(actualValue), format))
+		}
+	}
+	return nil
+}
+
+// outputFormatValuesEqual returns whether both values are NULL, are the same string, or are the same number.
+func outputFormatValuesEqual(v1 *string, v2 *string) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == nil && v2 == nil
+	}
+	if *v1 == *v2 {
+		return true
+	}
+	f1, err1 := strconv.ParseFloat(*v1, 64)
+	f2, err2 := strconv.ParseFloat(*v2, 64)
+	return err1 == nil && err2 == nil && f1 == f2
+}
+
+// outputFormatValueString returns the value for use in an error message, quoting it so that whitespace and empty strings
+// are visible.
+func outputFormatValueString(value *string) string {
+	if value == nil {
+		return "NULL"
+	}
+	return strconv.Quote(*value)
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputFormats(t *testing.T) {
+	columnNames := []string{"pk", "v1", "v2"}
+	str := func(s string) *string { return &s }
+	expected := [][]*string{
+		{str("1"), str("abc"), nil},
+		{str("2"), str("ünï"), str("")},
+		{str("3"), str(" padded "), str("1.5")},
+	}
+
+	jsonRows, err := parseOutputFormat(OutputFormat_JSON, `{"rows": [{"pk":1,"v1":"abc"},`+
+		`{"pk":2,"v1":"ünï","v2":""},{"pk":3,"v1":" padded ","v2":1.5}]}`, columnNames)
+	require.NoError(t, err)
+	require.Equal(t, expected, jsonRows)
+
+	csvRows, err := parseOutputFormat(OutputFormat_CSV, "pk,v1,v2\n1,abc,\n2,ünï,\"\"\n3, padded ,1.50", columnNames)
+	require.NoError(t, err)
+	require.Len(t, csvRows, 3)
+	require.NoError(t, compareOutputFormats(OutputFormat_CSV, jsonRows, csvRows, columnNames, []bool{true, true, true}))
+
+	tabular := `+----+----------+------+
+| pk | v1       | v2   |
++----+----------+------+
+| 1  | abc      | NULL |
+| 2  | ünï      |      |
+| 3  |  padded  | 1.5  |
++----+----------+------+`
+	tabularRows, err := parseOutputFormat(OutputFormat_Tabular, tabular, columnNames)
+	require.NoError(t, err)
+	require.Nil(t, tabularRows[0][2])
+	require.NoError(t, compareOutputFormats(OutputFormat_Tabular, jsonRows, tabularRows, columnNames,
+		[]bool{true, true, true}))
+
+	// A differing value is only reported for comparable columns
+	tabularRows[1][1] = str("uni")
+	require.Error(t, compareOutputFormats(OutputFormat_Tabular, jsonRows, tabularRows, columnNames,
+		[]bool{true, true, true}))
+	require.NoError(t, compareOutputFormats(OutputFormat_Tabular, jsonRows, tabularRows, columnNames,
+		[]bool{true, false, true}))
+	require.Error(t, compareOutputFormats(OutputFormat_Tabular, jsonRows, tabularRows[:2], columnNames,
+		[]bool{true, true, true}))
+
+	// Values with line breaks cannot be split into cells
+	_, err = parseOutputFormat(OutputFormat_Tabular, `+----+-----+------+
+| pk | v1  | v2   |
++----+-----+------+
+| 1  | a
+b | NULL |
++----+-----+------+`, columnNames)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), unalignedTabular)
+
+	// Empty results have no rows in every format
+	for _, format := range OutputFormats {
+		out := map[OutputFormat]string{
+			OutputFormat_JSON:    `{"rows": []}`,
+			OutputFormat_CSV:     "pk,v1,v2",
+			OutputFormat_Tabular: "+----+----+----+\n| pk | v1 | v2 |\n+----+----+----+",
+		}[format]
+		rows, err := parseOutputFormat(format, out, columnNames)
+		require.NoError(t, err)
+		require.Empty(t, rows)
+	}
+}
+
+func TestInterfaceParity(t *testing.T) {
+	str := func(s string) *string {
+		return &s
+	}
+	columnNames := []string{"pk", "v1", "v2"}
+	serverRows := [][]*string{{str("1"), str("1.5"), nil}, {str("2"), str(" abc"), str("")}}
+	cliRows := [][]*string{{str("1"), str("1.50"), nil}, {str("2"), str(" abc"), str("")}}
+	require.NoError(t, compareInterfaceRows(serverRows, cliRows, columnNames, []bool{true, true, true}))
+
+	// Unlike CSV, NULL and the empty string are never equal, nor is whitespace trimmed
+	cliRows[1][2] = nil
+	require.Error(t, compareInterfaceRows(serverRows, cliRows, columnNames, []bool{true, true, true}))
+	require.NoError(t, compareInterfaceRows(serverRows, cliRows, columnNames, []bool{true, true, false}))
+	cliRows[1][1] = str("abc")
+	require.Error(t, compareInterfaceRows(serverRows, cliRows, columnNames, []bool{true, true, false}))
+	require.Error(t, compareInterfaceRows(serverRows, cliRows[:1], columnNames, []bool{true, true, true}))
+}
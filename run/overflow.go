@@ -0,0 +1,219 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// overflowAttempts is the number of rows that are generated for an overflow probe before giving up, as each row must
+// be insertable once its out-of-range value has been clamped.
+const overflowAttempts = 100
+
+// integerRange is the smallest and largest value that an integer type may hold.
+type integerRange struct {
+	min *big.Int
+	max *big.Int
+}
+
+// newIntegerRange returns the range of the given type. Returns false if the type is not an integer type.
+func newIntegerRange(typ types.TypeInstance) (integerRange, bool) {
+	signed := func(min int64, max int64) (integerRange, bool) {
+		return integerRange{big.NewInt(min), big.NewInt(max)}, true
+	}
+	unsigned := func(max uint64) (integerRange, bool) {
+		return integerRange{big.NewInt(0), new(big.Int).SetUint64(max)}, true
+	}
+	switch typ.(type) {
+	case *types.TinyintInstance:
+		return signed(math.MinInt8, math.MaxInt8)
+	case *types.TinyintUnsignedInstance:
+		return unsigned(math.MaxUint8)
+	case *types.SmallintInstance:
+		return signed(math.MinInt16, math.MaxInt16)
+	case *types.SmallintUnsignedInstance:
+		return unsigned(math.MaxUint16)
+	case *types.MediumintInstance:
+		return signed(-1<<23, 1<<23-1)
+	case *types.MediumintUnsignedInstance:
+		return unsigned(1<<24 - 1)
+	case *types.IntInstance:
+		return signed(math.MinInt32, math.MaxInt32)
+	case *types.IntUnsignedInstance:
+		return unsigned(math.MaxUint32)
+	case *types.BigintInstance:
+		return signed(math.MinInt64, math.MaxInt64)
+	case *types.BigintUnsignedInstance:
+		return unsigned(math.MaxUint64)
+	default:
+		return integerRange{}, false
+	}
+}
+
+// overflow returns a literal that is just outside of the range, along with the bound that MySQL clamps it to. The
+// literal is below the range when below is true, and above it otherwise.
+func (r integerRange) overflow(below bool) (literal string, bound string) {
+	if below {
+		return new(big.Int).Sub(r.min, big.NewInt(1)).String(), r.min.String()
+	}
+	return new(big.Int).Add(r.max, big.NewInt(1)).String(), r.max.String()
+}
+
+// probeOverflow inserts a row holding an integer literal just outside of its column's range at the probability
+// Options.Overflow_Probability, which is run in place of the next statement. Under a strict sql_mode, Dolt must reject
+// the row, which is an expected failure that leaves the table unchanged. Otherwise, Dolt must clamp the literal to the
+// nearest bound of the column's range, which is how the row is written to the internal data. Tables involved in a
+// foreign key are skipped, as the row could be rejected for violating the foreign key instead. Returns whether the
+// probe was run.
+func probeOverflow(c *Cycle, table *Table) (bool, error) {
+	probability := c.Planner.Base.Options.OverflowProbability
+	if probability <= 0 || c.Planner.Base.Arguments.SkipValidation || table.HasForeignKeys() {
+		return false, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	if float64(roll) >= probability*math.MaxUint64 {
+		return false, nil
+	}
+	columns := append(append([]*Column{}, table.PKCols...), table.NonPKCols...)
+	var candidates []int
+	for i, col := range columns {
+		if _, ok := newIntegerRange(col.Type); ok {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+	roll, err = rand.StructureUint64()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	colIdx := candidates[roll%uint64(len(candidates))]
+	intRange, _ := newIntegerRange(columns[colIdx].Type)
+	literal, bound := intRange.overflow(roll&(1<<32) != 0)
+	clamped, err := columns[colIdx].Type.TypeValue().Convert(bound)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+
+	// The internal data holds the clamped row within a transaction, so that it may be discarded if Dolt rejects the row
+	if err = table.Data.Exec("BEGIN;"); err != nil {
+		return false, errors.Wrap(err)
+	}
+	row, ok, err := newOverflowRow(table, colIdx, clamped)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	if !ok {
+		if err = table.Data.Exec("ROLLBACK;"); err != nil {
+			return false, errors.Wrap(err)
+		}
+		return false, nil
+	}
+	vals := make([]string, len(row.Values))
+	for i, val := range row.Values {
+		vals[i] = types.ValueListString(val)
+	}
+	vals[colIdx] = literal
+	statement := fmt.Sprintf("INSERT INTO `%s`%s VALUES (%s);", table.Name, table.valuesColumnList(), strings.Join(vals, ","))
+	c.delayStatement()
+	execErr := c.SqlServer(statement)
+
+	// The session variables were set before the statement, so the statement ran under the session's sql_mode
+	strict, err := strictSQLMode(c)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	if !strict {
+		if execErr != nil {
+			return false, errors.New(fmt.Sprintf("On table `%s`, Dolt rejected an out-of-range value for column `%s` "+
+				"rather than clamping it to %s: %s\n%s", table.Name, columns[colIdx].Name, bound, statement, execErr.Error()))
+		}
+		if err = table.Data.Exec("COMMIT;"); err != nil {
+			return false, errors.Wrap(err)
+		}
+		return true, nil
+	}
+	if err = table.Data.Exec("ROLLBACK;"); err != nil {
+		return false, errors.Wrap(err)
+	}
+	if execErr == nil {
+		return false, errors.New(fmt.Sprintf("On table `%s`, Dolt accepted an out-of-range value for column `%s` "+
+			"under a strict sql_mode: %s", table.Name, columns[colIdx].Name, statement))
+	}
+	if err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Expected failure: %s", execErr.Error())); err != nil {
+		return false, errors.Wrap(err)
+	}
+	return true, nil
+}
+
+// newOverflowRow writes a random row to the internal data, with the value at the given index replaced by the clamped
+// value. Returns false if every attempt violated a constraint, in which case nothing was written.
+func newOverflowRow(table *Table, colIdx int, clamped types.Value) (Row, bool, error) {
+	for i := 0; i < overflowAttempts; i++ {
+		row, err := NewRow(table)
+		if err != nil {
+			return Row{}, false, errors.Wrap(err)
+		}
+		row.Values[colIdx] = clamped
+		err = table.Data.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, table.Data.RowString(row)))
+		if err != nil {
+			if table.Data.IsConstraintError(err) {
+				continue
+			}
+			return Row{}, false, errors.Wrap(err)
+		}
+		return row, true, nil
+	}
+	return Row{}, false, nil
+}
+
+// strictSQLMode returns whether Dolt's session uses a strict sql_mode, which rejects out-of-range values rather than
+// clamping them. The mode is read from the dedicated session that generated statements run on, which is the session
+// that ran the INSERT, as the pooled connections do not hold its session variables.
+func strictSQLMode(c *Cycle) (bool, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	var mode string
+	err = dc.Session.QueryRowContext(context.Background(), "SELECT @@SESSION.sql_mode;").Scan(&mode)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return isStrictSQLMode(mode), nil
+}
+
+// isStrictSQLMode returns whether the given sql_mode contains either of the strict modes.
+func isStrictSQLMode(mode string) bool {
+	for _, m := range strings.Split(strings.ToUpper(mode), ",") {
+		if m == "STRICT_TRANS_TABLES" || m == "STRICT_ALL_TABLES" {
+			return true
+		}
+	}
+	return false
+}
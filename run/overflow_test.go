@@ -0,0 +1,90 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		typ        types.TypeInstance
+		belowLit   string
+		belowBound string
+		aboveLit   string
+		aboveBound string
+	}{
+		{&types.TinyintInstance{}, "-129", "-128", "128", "127"},
+		{&types.TinyintUnsignedInstance{}, "-1", "0", "256", "255"},
+		{&types.SmallintInstance{}, "-32769", "-32768", "32768", "32767"},
+		{&types.MediumintInstance{}, "-8388609", "-8388608", "8388608", "8388607"},
+		{&types.MediumintUnsignedInstance{}, "-1", "0", "16777216", "16777215"},
+		{&types.IntUnsignedInstance{}, "-1", "0", "4294967296", "4294967295"},
+		{&types.BigintInstance{}, "-9223372036854775809", "-9223372036854775808", "9223372036854775808", "9223372036854775807"},
+		{&types.BigintUnsignedInstance{}, "-1", "0", "18446744073709551616", "18446744073709551615"},
+	}
+	for _, test := range tests {
+		intRange, ok := newIntegerRange(test.typ)
+		require.True(t, ok)
+		literal, bound := intRange.overflow(true)
+		require.Equal(t, test.belowLit, literal)
+		require.Equal(t, test.belowBound, bound)
+		literal, bound = intRange.overflow(false)
+		require.Equal(t, test.aboveLit, literal)
+		require.Equal(t, test.aboveBound, bound)
+		// The bounds must be representable by the type's values, as they are written to the internal data
+		for _, b := range []string{test.belowBound, test.aboveBound} {
+			val, err := test.typ.TypeValue().Convert(b)
+			require.NoError(t, err)
+			require.Equal(t, b, val.MySQLString())
+		}
+	}
+	_, ok := newIntegerRange(&types.VarcharInstance{})
+	require.False(t, ok)
+
+	require.True(t, isStrictSQLMode("ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION"))
+	require.True(t, isStrictSQLMode("strict_all_tables"))
+	require.False(t, isStrictSQLMode(""))
+	require.False(t, isStrictSQLMode("NO_ENGINE_SUBSTITUTION"))
+}
+
+func TestOverflowRow(t *testing.T) {
+	table, err := NewTable(&Commit{}, "overflow", []*Column{
+		{Name: "pk", Type: &types.TinyintInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+
+	clamped, err := (&types.TinyintInstance{}).TypeValue().Convert("127")
+	require.NoError(t, err)
+	row, ok, err := newOverflowRow(table, 0, clamped)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "127", row.Values[0].MySQLString())
+
+	// The clamped key is already taken, so no other row can be written
+	_, ok, err = newOverflowRow(table, 0, clamped)
+	require.NoError(t, err)
+	require.False(t, ok)
+	count, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
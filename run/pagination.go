@@ -0,0 +1,116 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+// doltPageIterator is a RowIterator over every row of a table in Dolt, which reads the rows one page at a time using
+// LIMIT and OFFSET. The iterator ends once a page holds fewer rows than the page size.
+type doltPageIterator struct {
+	c        *Cycle
+	table    *Table
+	pageSize uint64
+	offset   uint64
+	// pageRows is the number of rows that have been read from the current page.
+	pageRows uint64
+	cursor   *DoltDataCursor
+	done     bool
+}
+
+var _ RowIterator = (*doltPageIterator)(nil)
+
+// NextRow implements the interface RowIterator.
+func (dpi *doltPageIterator) NextRow() (Row, bool, error) {
+	for !dpi.done {
+		if dpi.cursor == nil {
+			cursor, err := dpi.table.GetDoltPageCursor(dpi.c, dpi.pageSize, dpi.offset)
+			if err != nil {
+				return Row{}, false, errors.Wrap(err)
+			}
+			dpi.cursor = cursor
+			dpi.pageRows = 0
+		}
+		row, ok, err := dpi.cursor.NextRow()
+		if err != nil {
+			return Row{}, false, errors.Wrap(err)
+		}
+		if ok {
+			dpi.pageRows++
+			if dpi.pageRows > dpi.pageSize {
+				return Row{}, false, errors.New(fmt.Sprintf("the page at offset %d of table `%s` returned more than "+
+					"its limit of %d rows", dpi.offset, dpi.table.Name, dpi.pageSize))
+			}
+			return row, true, nil
+		}
+		if err = dpi.cursor.Close(); err != nil {
+			return Row{}, false, errors.Wrap(err)
+		}
+		dpi.cursor = nil
+		dpi.offset += dpi.pageSize
+		// A short page is the last page, while a full page may be followed by more rows
+		dpi.done = dpi.pageRows < dpi.pageSize
+	}
+	return Row{}, false, nil
+}
+
+// Close closes the cursor of the current page, if there is one.
+func (dpi *doltPageIterator) Close() error {
+	if dpi.cursor == nil {
+		return nil
+	}
+	return dpi.cursor.Close()
+}
+
+// ValidatePagination validates that reading every row of the table from Dolt one page at a time, using LIMIT and
+// OFFSET with the given page size, returns exactly the rows of the internal data in primary key order. Any gap or
+// duplicate between pages is reported as a divergence. Keyless tables have no order to page through, so they are not
+// validated.
+func ValidatePagination(c *Cycle, branchName string, table *Table, pageSize uint64) error {
+	if table.IsKeyless() {
+		return nil
+	}
+	if pageSize == 0 {
+		return errors.New("the page size must be greater than zero")
+	}
+	internalCursor, err := table.Data.GetRowCursor()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer internalCursor.Close()
+	pageIter := &doltPageIterator{c: c, table: table, pageSize: pageSize}
+	defer func() {
+		_ = pageIter.Close()
+	}()
+
+	recorder := NewDivergenceRecorder(table.Name, branchName, table.ColumnNames())
+	return CompareRowIterators(c, recorder, internalCursor, pageIter, func(kind DivergenceKind, iRow Row, dRow Row) error {
+		page := fmt.Sprintf("the page at offset %d with a limit of %d", pageIter.offset, pageSize)
+		switch kind {
+		case DivergenceKind_MissingFromDolt:
+			return errors.New(fmt.Sprintf("On table `%s`, paging through Dolt ended at %s, while the internal data "+
+				"contains more rows: [%s]", table.Name, page, iRow.MySQLString()))
+		case DivergenceKind_MissingFromInternal:
+			return errors.New(fmt.Sprintf("On table `%s`, paging through Dolt returned more rows than the internal "+
+				"data, beginning with [%s] from %s", table.Name, dRow.MySQLString(), page))
+		default:
+			return errors.New(fmt.Sprintf("On table `%s`, %s differs from the internal data\nInternal data contains "+
+				"[%s]\nDolt contains [%s]", table.Name, page, iRow.MySQLString(), dRow.MySQLString()))
+		}
+	})
+}
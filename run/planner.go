@@ -18,7 +18,10 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/dolthub/fuzzer/errors"
 	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/types"
 )
 
 // Planner is the entry point that commands may use to hook into the various points of a cycle. It also creates each
@@ -26,20 +29,50 @@ import (
 type Planner struct {
 	Hooks            *Hooks
 	Base             *parameters.Base
+	Interface        Interface
 	lastRunStartTime time.Time
 }
 
 // NewPlanner returns a new *Planner from the given parameters.Base.
 func NewPlanner(base *parameters.Base) (*Planner, error) {
+	rand.SetStringEscapes(base.Options.StringEscapes)
+	types.SetMaxValueBytes(base.Options.MaxValueBytes)
+	types.SetInlineBoundary(base.Options.InlineBoundary, base.Options.InlineBoundaryProbability)
+	types.SetModernCollations(base.Options.ModernCollationProbability, base.Options.CollationVariantProbability)
+	internalOracle, err := NewOracle(base.Options.Oracle, base.Options.OracleDSN)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	SetOracle(internalOracle)
 	hooks := &Hooks{}
 	(&BlueprintManager{}).Register(hooks)
-	(&RepositoryManager{}).Register(hooks)
+	repositoryManager := &RepositoryManager{}
+	repositoryManager.Register(hooks)
+	if len(base.SessionVariables) > 0 {
+		(&SessionVariableManager{}).Register(hooks)
+	}
+	storageManager := &StorageManager{}
+	if base.Options.StorageMetrics {
+		storageManager.Register(hooks)
+	}
 	if base.Options.ManualGC {
 		(&GCManager{}).Register(hooks)
 	}
+	if base.Options.StorageMetrics {
+		// Registered after the GCManager, so that the final garbage collection has already run
+		hooks.RepositoryFinished(storageManager.Collected)
+	}
+	if base.Options.RestartValidation {
+		// Registered after the GCManager, so that the data is validated once the final garbage collection has run
+		hooks.RepositoryFinished(repositoryManager.ValidateAfterRestart)
+	}
+	if base.Options.StatusCheckInterval > 0 {
+		(&StatusManager{}).Register(hooks)
+	}
 	return &Planner{
 		Hooks:            hooks,
 		Base:             base,
+		Interface:        &DoltInterface{},
 		lastRunStartTime: time.Unix(0, 0),
 	}, nil
 }
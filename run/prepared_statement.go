@@ -0,0 +1,100 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/ranges"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// PreparedStatement is a Statement that is run as a prepared statement, with its values bound as arguments rather than
+// written as literals. This exercises the binary protocol of the server, which parses and converts values separately
+// from the text protocol.
+type PreparedStatement interface {
+	Statement
+	// GeneratePrepared takes the given table and generates a random prepared statement, in the same way as
+	// GenerateStatement.
+	GeneratePrepared(table *Table) (Prepared, error)
+}
+
+// Prepared is a statement with "?" placeholders, along with the values that are bound to each placeholder.
+type Prepared struct {
+	Query string
+	Args  []types.Value
+	// Literal is the equivalent statement with every value written as a literal, which is used for logging and by
+	// interfaces that cannot bind arguments.
+	Literal string
+}
+
+// PreparedInsertStatement returns random INSERT statements that are run as prepared statements. Spatial values cannot
+// be bound directly, as they must be constructed from their text, so they are written as literals within the query.
+type PreparedInsertStatement struct {
+	r ranges.Int
+}
+
+var _ PreparedStatement = (*PreparedInsertStatement)(nil)
+
+// GetOccurrenceRate implements the interface ranges.Distributable.
+func (s *PreparedInsertStatement) GetOccurrenceRate() (int64, error) {
+	return s.r.RandomValue()
+}
+
+// GenerateStatement implements the interface Statement. Returns the literal form of the prepared statement.
+func (s *PreparedInsertStatement) GenerateStatement(table *Table) (string, error) {
+	prepared, err := s.GeneratePrepared(table)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return prepared.Literal, nil
+}
+
+// GeneratePrepared implements the interface PreparedStatement.
+func (s *PreparedInsertStatement) GeneratePrepared(table *Table) (Prepared, error) {
+	for i := 0; i < 10000000; i++ {
+		row, err := NewRow(table)
+		if err != nil {
+			return Prepared{}, errors.Wrap(err)
+		}
+		err = table.Data.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, table.Data.RowString(row)))
+		if err != nil {
+			if table.Data.IsConstraintError(err) {
+				continue
+			}
+			return Prepared{}, errors.Wrap(err)
+		}
+		placeholders := make([]string, len(row.Values))
+		var args []types.Value
+		for colIdx, val := range row.Values {
+			switch val.(type) {
+			case types.LinestringValue, types.PolygonValue:
+				placeholders[colIdx] = val.MySQLString()
+			default:
+				placeholders[colIdx] = "?"
+				args = append(args, val)
+			}
+		}
+		return Prepared{
+			Query: fmt.Sprintf("INSERT INTO `%s`%s VALUES (%s);", table.Name, table.valuesColumnList(),
+				strings.Join(placeholders, ",")),
+			Args:    args,
+			Literal: fmt.Sprintf("INSERT INTO `%s`%s VALUES (%s);", table.Name, table.valuesColumnList(), row.ValueListString()),
+		}, nil
+	}
+	return Prepared{}, errors.New("10 million consecutive collisions on attempted prepared INSERT, aborting cycle")
+}
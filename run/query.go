@@ -0,0 +1,308 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+	"github.com/dolthub/fuzzer/utils"
+)
+
+// Query is a random SELECT statement over a single table, written for both MySQL and SQLite. The query is run against
+// the internal data using the dialect of its Oracle, which then acts as an oracle for Dolt's query engine.
+//
+// Only integer columns are used in WHERE clauses, ORDER BY clauses, and aggregates. Strings are compared using
+// collations in MySQL but bytes in SQLite, and floating-point values do not round-trip exactly, so both would lead to
+// false positives. BIGINT UNSIGNED is also excluded, as SQLite stores it as a string.
+type Query struct {
+	MySQL  string
+	SQLite string
+	// Aggregate is true when the query returns a single aggregated value, rather than rows of the table.
+	Aggregate bool
+	// Ordered is true when the query orders the rows by the full primary key, so that the rows must be returned in the
+	// same order. Otherwise, the rows may be returned in any order.
+	Ordered bool
+}
+
+// queryOperators are the comparison operators used in WHERE clauses.
+var queryOperators = []string{"=", "<>", "<", ">", "<=", ">="}
+
+// queryAggregates are the aggregate functions that may be used in place of returning rows.
+var queryAggregates = []string{"COUNT", "SUM", "MIN", "MAX"}
+
+// NewQuery returns a random query over the given table.
+func NewQuery(table *Table) (Query, error) {
+	var intCols []int
+	allCols := append(append([]*Column{}, table.PKCols...), table.NonPKCols...)
+	for i, col := range allCols {
+		if isQueryableColumn(col) {
+			intCols = append(intCols, i)
+		}
+	}
+
+	var query Query
+	mysqlWhere, sqliteWhere, err := newQueryWhere(table, allCols, intCols)
+	if err != nil {
+		return Query{}, errors.Wrap(err)
+	}
+	aggregateRoll, err := rand.StructureUint64()
+	if err != nil {
+		return Query{}, errors.Wrap(err)
+	}
+	// Roughly a third of all queries are aggregates
+	if aggregateRoll%3 == 0 {
+		aggregate := queryAggregates[aggregateRoll/3%uint64(len(queryAggregates))]
+		target := "*"
+		if aggregate != "COUNT" {
+			var targetCols []int
+			for _, colIdx := range intCols {
+				// SQLite errors when a SUM overflows a 64-bit integer, while MySQL does not
+				if _, ok := allCols[colIdx].Type.(*types.BigintInstance); ok && aggregate == "SUM" {
+					continue
+				}
+				targetCols = append(targetCols, colIdx)
+			}
+			if len(targetCols) == 0 {
+				aggregate = "COUNT"
+			} else {
+				target = fmt.Sprintf("`%s`", allCols[targetCols[aggregateRoll%uint64(len(targetCols))]].Name)
+			}
+		}
+		query.Aggregate = true
+		query.MySQL = fmt.Sprintf("SELECT %s(%s) FROM `%s`%s;", aggregate, target, table.Name, mysqlWhere)
+		query.SQLite = fmt.Sprintf("SELECT %s(%s) FROM `%s`%s;", aggregate, target, table.Name, sqliteWhere)
+		return query, nil
+	}
+
+	// The rows may only be ordered when every primary key column may be ordered
+	orderBy := ""
+	if len(table.PKCols) > 0 {
+		query.Ordered = true
+		for _, col := range table.PKCols {
+			query.Ordered = query.Ordered && isQueryableColumn(col)
+		}
+	}
+	if query.Ordered {
+		direction, err := rand.Bool()
+		if err != nil {
+			return Query{}, errors.Wrap(err)
+		}
+		directionStr := " ASC"
+		if direction {
+			directionStr = " DESC"
+		}
+		orderBys := make([]string, len(table.PKCols))
+		for i, col := range table.PKCols {
+			orderBys[i] = fmt.Sprintf("`%s`%s", col.Name, directionStr)
+		}
+		orderBy = " ORDER BY " + strings.Join(orderBys, ", ")
+		useLimit, err := rand.Bool()
+		if err != nil {
+			return Query{}, errors.Wrap(err)
+		}
+		if useLimit {
+			limit, err := rand.Uint16()
+			if err != nil {
+				return Query{}, errors.Wrap(err)
+			}
+			offset, err := rand.Uint16()
+			if err != nil {
+				return Query{}, errors.Wrap(err)
+			}
+			orderBy += fmt.Sprintf(" LIMIT %d OFFSET %d", limit%100, offset%100)
+		}
+	}
+	query.MySQL = fmt.Sprintf("SELECT %s FROM `%s`%s%s;", table.SelectColumns(), table.Name, mysqlWhere, orderBy)
+	query.SQLite = fmt.Sprintf("SELECT * FROM `%s`%s%s;", table.Name, sqliteWhere, orderBy)
+	return query, nil
+}
+
+// CompareQuery runs the query against both Dolt and the internal data, returning an error if the results differ.
+func (t *Table) CompareQuery(c *Cycle, query Query) error {
+	if query.Aggregate {
+		dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		var doltResult sql.NullString
+		err = dc.Conn.QueryRowContext(context.Background(), query.MySQL).Scan(&doltResult)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		internalResult, err := t.Data.QueryScalar(t.Data.Dialect(query.MySQL, query.SQLite))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if doltResult != internalResult {
+			return errors.New(fmt.Sprintf("Query on table `%s` returned different results\nQuery: %s\nInternal: %s\nDolt: %s",
+				t.Name, query.MySQL, nullStringToString(internalResult), nullStringToString(doltResult)))
+		}
+		return nil
+	}
+
+	doltCursor, err := t.GetDoltQueryCursor(c, query)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer func() {
+		_ = doltCursor.Close()
+	}()
+	internalCursor, err := t.Data.GetQueryCursor(t.Data.Dialect(query.MySQL, query.SQLite))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer internalCursor.Close()
+
+	mismatch := func(internalRow Row, doltRow Row) error {
+		return errors.New(fmt.Sprintf("Query on table `%s` returned different rows\nQuery: %s\nInternal: [%s]\nDolt: [%s]",
+			t.Name, query.MySQL, internalRow.MySQLString(), doltRow.MySQLString()))
+	}
+	// Rows that are not ordered are counted by their hash, so that each row is matched regardless of order
+	unmatched := make(map[utils.Hash]int)
+	unmatchedRows := make(map[utils.Hash]Row)
+	for {
+		iRow, iOk, err := internalCursor.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		dRow, dOk, err := doltCursor.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if !iOk && !dOk {
+			break
+		}
+		if query.Ordered {
+			if iOk != dOk || !iRow.Equals(dRow) {
+				return mismatch(iRow, dRow)
+			}
+			continue
+		}
+		if iOk {
+			hash := iRow.Hash()
+			unmatched[hash]++
+			unmatchedRows[hash] = iRow
+		}
+		if dOk {
+			hash := dRow.Hash()
+			unmatched[hash]--
+			unmatchedRows[hash] = dRow
+		}
+	}
+	for hash, count := range unmatched {
+		if count > 0 {
+			return mismatch(unmatchedRows[hash], Row{})
+		} else if count < 0 {
+			return mismatch(Row{}, unmatchedRows[hash])
+		}
+	}
+	return nil
+}
+
+// GetDoltQueryCursor returns a cursor over the results of the given non-aggregate query, using the current branch.
+func (t *Table) GetDoltQueryCursor(c *Cycle, query Query) (*DoltDataCursor, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), query.MySQL)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &DoltDataCursor{
+		rows:     outRows,
+		template: t.Data.ConstructTemplateRow(),
+		once:     &sync.Once{},
+	}, nil
+}
+
+// newQueryWhere returns a random WHERE clause for both MySQL and SQLite, which may be empty.
+func newQueryWhere(table *Table, allCols []*Column, intCols []int) (string, string, error) {
+	if len(intCols) == 0 {
+		return "", "", nil
+	}
+	predicateCount, err := rand.Uint8()
+	if err != nil {
+		return "", "", errors.Wrap(err)
+	}
+	predicateCount %= 3
+	if predicateCount == 0 {
+		return "", "", nil
+	}
+	// Literals are usually taken from an existing row, so that the predicates are likely to match some rows
+	row, hasRow, err := table.Data.GetRandomRow()
+	if err != nil {
+		return "", "", errors.Wrap(err)
+	}
+	var mysqlPredicates []string
+	var sqlitePredicates []string
+	for i := uint8(0); i < predicateCount; i++ {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return "", "", errors.Wrap(err)
+		}
+		colIdx := intCols[roll%uint64(len(intCols))]
+		col := allCols[colIdx]
+		operator := queryOperators[roll/uint64(len(intCols))%uint64(len(queryOperators))]
+		var val types.Value
+		if hasRow && roll%4 != 0 {
+			val = row.Values[colIdx]
+		} else {
+			val, err = col.Type.Get()
+			if err != nil {
+				return "", "", errors.Wrap(err)
+			}
+		}
+		mysqlPredicates = append(mysqlPredicates, fmt.Sprintf("`%s` %s %s", col.Name, operator, val.MySQLString()))
+		sqlitePredicates = append(sqlitePredicates, fmt.Sprintf("`%s` %s %s", col.Name, operator, val.SQLiteString()))
+	}
+	conjunction, err := rand.Bool()
+	if err != nil {
+		return "", "", errors.Wrap(err)
+	}
+	joiner := " AND "
+	if conjunction {
+		joiner = " OR "
+	}
+	return " WHERE " + strings.Join(mysqlPredicates, joiner), " WHERE " + strings.Join(sqlitePredicates, joiner), nil
+}
+
+// isQueryableColumn returns whether the column may be used in WHERE clauses, ORDER BY clauses, and aggregates.
+func isQueryableColumn(col *Column) bool {
+	switch col.Type.(type) {
+	case *types.TinyintInstance, *types.SmallintInstance, *types.MediumintInstance, *types.IntInstance,
+		*types.BigintInstance, *types.TinyintUnsignedInstance, *types.SmallintUnsignedInstance,
+		*types.MediumintUnsignedInstance, *types.IntUnsignedInstance:
+		return true
+	default:
+		return false
+	}
+}
+
+// nullStringToString returns the string, or NULL if the string is not valid.
+func nullStringToString(s sql.NullString) string {
+	if !s.Valid {
+		return "NULL"
+	}
+	return s.String
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestNewQuery(t *testing.T) {
+	commit := &Commit{}
+	table, err := NewTable(commit, "queried", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.BigintInstance{}},
+		{Name: "v2", Type: &types.VarcharInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	commit.Tables = []*Table{table}
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	allRows, err := table.Data.GetAllRows()
+	require.NoError(t, err)
+
+	// Every query must be valid SQLite, and must never reference the string column
+	for i := 0; i < 200; i++ {
+		query, err := NewQuery(table)
+		require.NoError(t, err)
+		require.NotContains(t, query.MySQL, "`v2`")
+		require.False(t, query.Aggregate && query.Ordered)
+		if query.Aggregate {
+			require.NotContains(t, query.SQLite, "SUM(`v1`)")
+			_, err = table.Data.QueryScalar(query.SQLite)
+			require.NoError(t, err)
+			continue
+		}
+		require.True(t, query.Ordered)
+		cursor, err := table.Data.GetQueryCursor(query.SQLite)
+		require.NoError(t, err)
+		rowCount := 0
+		var previous Row
+		for {
+			row, ok, err := cursor.NextRow()
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			if rowCount > 0 {
+				if strings.Contains(query.SQLite, "DESC") {
+					require.Equal(t, 1, previous.PKCompare(row))
+				} else {
+					require.Equal(t, -1, previous.PKCompare(row))
+				}
+			}
+			previous = row
+			rowCount++
+		}
+		cursor.Close()
+		require.LessOrEqual(t, rowCount, len(allRows))
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+// GetDoltBranchHashes returns the commit hash that each branch points to, keyed by the branch name. The hashes are
+// read from the "dolt_branches" system table.
+func GetDoltBranchHashes(c *Cycle) (map[string]string, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	rows, err := dc.Conn.QueryContext(context.Background(), "SELECT `name`, `hash` FROM `dolt_branches`;")
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer rows.Close()
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var name, hash string
+		if err = rows.Scan(&name, &hash); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		hashes[name] = hash
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return hashes, nil
+}
+
+// IsDoltMerging returns whether the current branch has a merge in progress, according to the "dolt_merge_status"
+// system table.
+func IsDoltMerging(c *Cycle) (bool, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	var isMerging bool
+	err = dc.Conn.QueryRowContext(context.Background(), "SELECT `is_merging` FROM `dolt_merge_status`;").Scan(&isMerging)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return isMerging, nil
+}
+
+// GetDoltMergeBase returns the hash of the merge base of the two given revisions, as resolved by Dolt's
+// "DOLT_MERGE_BASE" function.
+func GetDoltMergeBase(c *Cycle, ours string, theirs string) (string, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	var hash string
+	err = dc.Conn.QueryRowContext(context.Background(), fmt.Sprintf("SELECT DOLT_MERGE_BASE('%s', '%s');", ours, theirs)).Scan(&hash)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return hash, nil
+}
+
+// GetDoltConflictedTables returns the name of every table on the current branch that has conflicts, according to the
+// "dolt_conflicts" system table.
+func GetDoltConflictedTables(c *Cycle) ([]string, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	rows, err := dc.Conn.QueryContext(context.Background(), "SELECT `table` FROM `dolt_conflicts`;")
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer rows.Close()
+	var tableNames []string
+	for rows.Next() {
+		var tableName string
+		if err = rows.Scan(&tableName); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return tableNames, nil
+}
@@ -18,13 +18,18 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/dolthub/fuzzer/errors"
-	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
 	"github.com/dolthub/fuzzer/utils"
 )
 
+// indexValidationSampleSize is the number of rows that are looked up through each index during validation.
+const indexValidationSampleSize = 10
+
 // RepositoryManager handles the general repository generation commands throughout the cycle.
 type RepositoryManager struct {
 	clearedBranches   map[string]struct{}
@@ -74,46 +79,61 @@ func (m *RepositoryManager) MainLoop(c *Cycle) error {
 	branches := c.GetBranchNames()
 
 	// Check if we create a new table or branch
-	probabilityVal, err := rand.Uint64()
-	if err != nil {
-		return errors.Wrap(err)
-	}
-	if currentBranch.Name == "main" && uint64(len(tables)) < c.Blueprint.TableCount &&
-		probabilityVal < m.tableProbability {
-		_, err := currentBranch.NewTable(c)
+	if currentBranch.Name == "main" && uint64(len(tables)) < c.Blueprint.TableCount {
+		newTable, err := c.DecideChance("new-table", m.tableProbability)
 		if err != nil {
 			return errors.Wrap(err)
 		}
-		c.QueueAction(m.MainLoop)
-		return nil
+		if newTable {
+			_, err = currentBranch.NewTable(c)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			c.QueueAction(m.MainLoop)
+			return nil
+		}
 	}
-	if uint64(len(branches)) < c.Blueprint.BranchCount && probabilityVal < m.branchProbability {
-		_, err = currentBranch.Commit(c, false)
+	if uint64(len(branches)) < c.Blueprint.BranchCount {
+		newBranch, err := c.DecideChance("new-branch", m.branchProbability)
 		if err != nil {
 			return errors.Wrap(err)
 		}
-		_, err = currentBranch.NewBranch(c)
-		if err != nil {
-			return errors.Wrap(err)
+		if newBranch {
+			_, err = currentBranch.Commit(c, false)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			_, err = currentBranch.NewBranch(c)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			c.QueueAction(m.MainLoop)
+			return nil
 		}
-		c.QueueAction(m.MainLoop)
-		return nil
 	}
 
 	// Get an unfinished table, if there is one
-	tableRandArray, err := utils.NewRandomArray(int64(len(tables)))
-	if err != nil {
-		return errors.Wrap(err)
+	var unfinishedTables []*Table
+	for _, t := range tables {
+		rowCount, err := t.Data.GetRowCount()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if uint64(rowCount) < c.Blueprint.TargetRowCount[currentBranch.Name][t.Name] ||
+			c.Blueprint.BranchWrites[currentBranch.Name][t.Name] > 0 {
+			unfinishedTables = append(unfinishedTables, t)
+		}
 	}
 	var table *Table
-	for i, ok := tableRandArray.NextIndex(); ok; i, ok = tableRandArray.NextIndex() {
-		rowCount, err := tables[i].Data.GetRowCount()
+	var err error
+	if len(unfinishedTables) > 0 {
+		tableIdx, err := c.DecideIndex("table", len(unfinishedTables))
 		if err != nil {
 			return errors.Wrap(err)
 		}
-		if uint64(rowCount) < c.Blueprint.TargetRowCount[currentBranch.Name][tables[i].Name] {
-			table = tables[i]
-			break
+		table = unfinishedTables[tableIdx]
+		if c.Blueprint.BranchWrites[currentBranch.Name][table.Name] > 0 {
+			c.Blueprint.BranchWrites[currentBranch.Name][table.Name]--
 		}
 	}
 
@@ -148,53 +168,142 @@ func (m *RepositoryManager) MainLoop(c *Cycle) error {
 			return errors.Wrap(err)
 		}
 		m.clearedBranches[currentBranch.Name] = struct{}{}
-		branchRandArray, err := utils.NewRandomArray(int64(len(branches)))
-		if err != nil {
-			return errors.Wrap(err)
+		var unclearedBranches []string
+		for _, branchName := range branches {
+			if _, ok := m.clearedBranches[branchName]; !ok {
+				unclearedBranches = append(unclearedBranches, branchName)
+			}
 		}
-		for i, ok := branchRandArray.NextIndex(); ok; i, ok = branchRandArray.NextIndex() {
-			if _, ok := m.clearedBranches[branches[i]]; !ok {
-				err = c.SwitchCurrentBranch(branches[i])
-				if err != nil {
-					return errors.Wrap(err)
-				}
-				c.QueueAction(m.MainLoop)
-				return nil
+		if len(unclearedBranches) > 0 {
+			branchIdx, err := c.DecideIndex("switch-branch", len(unclearedBranches))
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			err = c.SwitchCurrentBranch(unclearedBranches[branchIdx])
+			if err != nil {
+				return errors.Wrap(err)
 			}
+			c.QueueAction(m.MainLoop)
+			return nil
 		}
 		c.QueueAction(m.ValidateRows)
 		return nil
 	}
 
-	// Execute the next statement
-	statement, err := c.statementDist.Get(1)
+	// Make a schema change in place of the next statement, if one was chosen
+	schemaChange, err := nextSchemaChange(c)
 	if err != nil {
 		return errors.Wrap(err)
 	}
-	statementStr, err := statement.(Statement).GenerateStatement(table)
+	if schemaChange != nil {
+		statementStr, err := schemaChange.GenerateSchemaChange(c, table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		// An empty statement means that the schema change could not be made, so we execute a statement instead
+		if statementStr != "" {
+			c.delayStatement()
+			err = c.SqlServer(statementStr)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			c.QueueAction(m.MainLoop)
+			return nil
+		}
+	}
+
+	// Insert an out-of-range integer in place of the next statement, if one was chosen
+	probed, err := probeOverflow(c, table)
 	if err != nil {
 		return errors.Wrap(err)
 	}
-	err = c.SqlServer(statementStr)
+	if probed {
+		c.QueueAction(m.MainLoop)
+		return nil
+	}
+
+	// Run several statements within an explicit transaction, if one was chosen
+	transaction, err := c.nextTransaction()
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	if transaction {
+		if err = c.RunTransaction(table); err != nil {
+			return errors.Wrap(err)
+		}
+		c.QueueAction(m.MainLoop)
+		return nil
+	}
+
+	// Execute the next statement
+	if err = c.RunStatement(table); err != nil {
+		return errors.Wrap(err)
+	}
 
 	c.QueueAction(m.MainLoop)
 	return nil
 }
 
-// ValidateRows validates all rows of each table on each branch according to the stored data.
+// ValidateRows validates all rows of each table on each branch according to the stored data. When validation is
+// skipped, the repository is never read, so the RepositoryFinished hooks are skipped as well.
 func (m *RepositoryManager) ValidateRows(c *Cycle) error {
+	if c.Planner.Base.Arguments.SkipValidation {
+		return nil
+	}
 	err := c.Logger.WriteLine(LogType_INFO,
 		fmt.Sprintf("Validating Data: %s", time.Now().Format("2006-01-02 15:04:05")))
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	if err = m.validateBranches(c); err != nil {
+		return errors.Wrap(err)
+	}
 
+	if err = c.Planner.Hooks.RunHook(Hook{
+		Type:  HookType_RepositoryFinished,
+		Cycle: c,
+	}); err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// ValidateAfterRestart stops every Dolt process, and validates all rows of each table on each branch again using a
+// freshly started server. This is run once the final garbage collection has finished, so that any data that was only
+// held in the memory of a process, rather than persisted, is caught.
+func (m *RepositoryManager) ValidateAfterRestart(c *Cycle) error {
+	// There are no processes to restart when statements are only being written out
+	if _, ok := c.Planner.Interface.(*DoltInterface); !ok {
+		return nil
+	}
+	if !c.Planner.Base.Options.ManualGC {
+		if _, err := c.CliQuery("gc"); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	err := c.Logger.WriteLine(LogType_INFO,
+		fmt.Sprintf("Restarting Dolt: %s", time.Now().Format("2006-01-02 15:04:05")))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if err = connection.StopDoltServers(c.Planner.Base.Options.Port); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = c.Logger.WriteLine(LogType_INFO,
+		fmt.Sprintf("Validating Data After Restart: %s", time.Now().Format("2006-01-02 15:04:05"))); err != nil {
+		return errors.Wrap(err)
+	}
+	if err = m.validateBranches(c); err != nil {
+		return errors.WrapWithMessage(err, "After restarting Dolt:")
+	}
+	return nil
+}
+
+// validateBranches validates the schema, rows, and indexes of each table on each branch according to the stored data.
+func (m *RepositoryManager) validateBranches(c *Cycle) error {
 	for _, branchName := range c.GetBranchNames() {
 		// Using a function here to make use of the defer
-		err = func() (err error) {
+		err := func() (err error) {
 			err = c.SwitchCurrentBranch(branchName)
 			if err != nil {
 				return errors.Wrap(err)
@@ -211,49 +320,15 @@ func (m *RepositoryManager) ValidateRows(c *Cycle) error {
 			}()
 
 			for _, table := range currentCommitTables {
-				// Using a function here to make use of the defer
-				err = (func() error {
-					internalCursor, err := table.Data.GetRowCursor()
-					if err != nil {
-						return errors.Wrap(err)
-					}
-					defer internalCursor.Close()
-					doltCursor, err := table.GetDoltCursor(c)
-					if err != nil {
-						return errors.Wrap(err)
-					}
-					defer func() {
-						_ = doltCursor.Close()
-					}()
-
-					var iRow Row
-					var ok bool
-					for iRow, ok, err = internalCursor.NextRow(); ok && err == nil; iRow, ok, err = internalCursor.NextRow() {
-						dRow, ok, err := doltCursor.NextRow()
-						if !ok {
-							return errors.New(fmt.Sprintf("On table `%s`, internal data contains more rows than Dolt", table.Name))
-						}
-						if err != nil {
-							return errors.Wrap(err)
-						}
-						if !iRow.Equals(dRow) {
-							return errors.New(fmt.Sprintf("On table `%s`, internal data contains [%s]\nDolt contains [%s]",
-								table.Name, iRow.MySQLString(), dRow.MySQLString()))
-						}
-					}
-					if err != nil {
-						return errors.Wrap(err)
-					}
-
-					_, ok, err = doltCursor.NextRow()
-					if ok {
-						return errors.New(fmt.Sprintf("On table `%s`, Dolt contains more rows than internal data", table.Name))
-					}
-					if err != nil {
-						return errors.Wrap(err)
-					}
-					return nil
-				})()
+				err = validateSchema(c, table)
+				if err != nil {
+					return errors.Wrap(err)
+				}
+				err = ValidateTableRows(c, branchName, table)
+				if err != nil {
+					return errors.Wrap(err)
+				}
+				err = m.validateIndexes(c, table)
 				if err != nil {
 					return errors.Wrap(err)
 				}
@@ -264,16 +339,236 @@ func (m *RepositoryManager) ValidateRows(c *Cycle) error {
 			return errors.Wrap(err)
 		}
 	}
+	return nil
+}
 
-	if err = c.Planner.Hooks.RunHook(Hook{
-		Type:  HookType_RepositoryFinished,
-		Cycle: c,
-	}); err != nil {
+// ValidateTableRows validates that every row of the table in Dolt matches the internal data, using the current branch.
+func ValidateTableRows(c *Cycle, branchName string, table *Table) error {
+	if c.Planner.Base.Options.ChecksumValidation {
+		matches, err := checksumsMatch(c, table)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if matches {
+			return nil
+		}
+	}
+	if table.IsKeyless() {
+		return validateKeylessTableRows(c, branchName, table)
+	}
+	internalCursor, err := table.Data.GetRowCursor()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer internalCursor.Close()
+	doltCursor, err := table.GetDoltCursor(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer func() {
+		_ = doltCursor.Close()
+	}()
+
+	recorder := NewDivergenceRecorder(table.Name, branchName, table.ColumnNames())
+	return CompareRowIterators(c, recorder, internalCursor, doltCursor, describeTableRowDivergence(table))
+}
+
+// describeTableRowDivergence returns the RowDivergenceDescriber for validating the rows of the given table.
+func describeTableRowDivergence(table *Table) RowDivergenceDescriber {
+	return func(kind DivergenceKind, iRow Row, dRow Row) error {
+		switch kind {
+		case DivergenceKind_MissingFromDolt:
+			return errors.New(fmt.Sprintf("On table `%s`, internal data contains more rows than Dolt", table.Name))
+		case DivergenceKind_MissingFromInternal:
+			return errors.New(fmt.Sprintf("On table `%s`, Dolt contains more rows than internal data", table.Name))
+		default:
+			return errors.New(fmt.Sprintf("On table `%s`, internal data contains [%s]\nDolt contains [%s]%s",
+				table.Name, iRow.MySQLString(), dRow.MySQLString(), describeNullMismatches(table.ColumnNames(), iRow, dRow)))
+		}
+	}
+}
+
+// describeNullMismatches describes each column where one row is NULL while the other row holds an empty string, as
+// both look alike at a glance. Returns an empty string when there are no such columns.
+func describeNullMismatches(columns []string, internalRow Row, doltRow Row) string {
+	if len(internalRow.Values) != len(doltRow.Values) || len(columns) != len(internalRow.Values) {
+		return ""
+	}
+	sb := strings.Builder{}
+	for i, colName := range columns {
+		_, internalNull := internalRow.Values[i].(types.NilValue)
+		_, doltNull := doltRow.Values[i].(types.NilValue)
+		if internalNull && !doltNull && len(doltRow.Values[i].ToBytes()) == 0 {
+			sb.WriteString(fmt.Sprintf("\nColumn `%s` is NULL in the internal data but an empty string in Dolt", colName))
+		} else if doltNull && !internalNull && len(internalRow.Values[i].ToBytes()) == 0 {
+			sb.WriteString(fmt.Sprintf("\nColumn `%s` is an empty string in the internal data but NULL in Dolt", colName))
+		}
+	}
+	return sb.String()
+}
+
+// validateKeylessTableRows validates that Dolt's table holds the same multiset of rows as the internal data, using the
+// current branch. Keyless tables have no primary key to order both sides by, and Dolt and the Oracle may order the
+// values of some types differently, so Dolt's rows are first copied into table data from the same Oracle. Both sides
+// are then read in the same order, and are compared by position.
+func validateKeylessTableRows(c *Cycle, branchName string, table *Table) error {
+	doltCursor, err := table.GetDoltCursor(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer func() {
+		_ = doltCursor.Close()
+	}()
+	doltData, err := CreateTableData(table.Name, table.PKCols, table.NonPKCols)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer doltData.Close()
+	if err = doltData.InsertRows(doltCursor); err != nil {
+		return errors.Wrap(err)
+	}
+	internalOrdered, err := table.Data.GetFullyOrderedCursor()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer internalOrdered.Close()
+	doltOrdered, err := doltData.GetFullyOrderedCursor()
+	if err != nil {
 		return errors.Wrap(err)
 	}
+	defer doltOrdered.Close()
+
+	recorder := NewDivergenceRecorder(table.Name, branchName, table.ColumnNames())
+	return CompareRowIterators(c, recorder, internalOrdered, doltOrdered, describeTableRowDivergence(table))
+}
+
+// validateSchema validates that the columns of Dolt's table match the internal table, in both name and order. As the
+// columns are read using `SELECT *`, invisible columns must be excluded.
+func validateSchema(c *Cycle, table *Table) error {
+	doltColNames, err := table.GetDoltColumnNames(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	internalColNames := table.VisibleColumnNames()
+	matches := len(doltColNames) == len(internalColNames)
+	for i := 0; matches && i < len(doltColNames); i++ {
+		matches = doltColNames[i] == internalColNames[i]
+	}
+	if !matches {
+		return errors.New(fmt.Sprintf("On table `%s`, internal columns are [%s]\nDolt columns are [%s]",
+			table.Name, strings.Join(internalColNames, ","), strings.Join(doltColNames, ",")))
+	}
 	return nil
 }
 
+// validateIndexes validates that each index on the table is reported by `SHOW INDEX`, and is able to find a random
+// sample of the rows from the internal data. As prefix indexes only store a portion of each value, this ensures that
+// Dolt correctly filters the prefix matches down to the full value. Some collations treat distinct strings as equal, so
+// a lookup may return more than one row, therefore the internal row only needs to be present within the returned rows.
+// Indexes with descending or prefixed columns are also read in their own order, which must match the same order from
+// the internal data.
+func (m *RepositoryManager) validateIndexes(c *Cycle, table *Table) error {
+	// Every index must be reported with its columns in order, including indexes that are redundant with one another
+	doltIndexes, err := table.GetDoltIndexes(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, index := range table.Indexes {
+		doltColumns, ok := doltIndexes[strings.ToLower(index.Name)]
+		if !ok {
+			return errors.New(fmt.Sprintf("On table `%s`, SHOW INDEX is missing index `%s`\nIndex definition: %s",
+				table.Name, index.Name, index.String()))
+		}
+		if strings.Join(doltColumns, ",") != strings.Join(index.Columns, ",") {
+			return errors.New(fmt.Sprintf("On table `%s`, SHOW INDEX reports columns [%s] for index `%s`\nIndex definition: %s",
+				table.Name, strings.Join(doltColumns, ","), index.Name, index.String()))
+		}
+	}
+	for _, index := range table.Indexes {
+		if index.HasDescending() || index.HasPrefix() {
+			if err = m.validateIndexOrder(c, table, index); err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+	for _, index := range table.Indexes {
+		for i := 0; i < indexValidationSampleSize; i++ {
+			iRow, ok, err := table.Data.GetRandomRow()
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			if !ok {
+				return nil
+			}
+			err = func() error {
+				doltCursor, ok, err := table.GetDoltIndexLookupCursor(c, index, iRow)
+				if err != nil {
+					return errors.Wrap(err)
+				}
+				if !ok {
+					return nil
+				}
+				defer func() {
+					_ = doltCursor.Close()
+				}()
+				var dRow Row
+				for dRow, ok, err = doltCursor.NextRow(); ok && err == nil; dRow, ok, err = doltCursor.NextRow() {
+					if iRow.Equals(dRow) {
+						return nil
+					}
+				}
+				if err != nil {
+					return errors.Wrap(err)
+				}
+				return errors.New(fmt.Sprintf("On table `%s`, index `%s` could not find the row [%s]\nIndex definition: %s",
+					table.Name, index.Name, iRow.MySQLString(), index.String()))
+			}()
+			if err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateIndexOrder validates that the index's columns are returned in the index's order, which is checked against the
+// internal data ordered the same way. Indexes that cannot be ordered identically by both are skipped.
+func (m *RepositoryManager) validateIndexOrder(c *Cycle, table *Table, index *Index) error {
+	query, internalQuery, template, ok := table.IndexOrderQuery(index)
+	if !ok {
+		return nil
+	}
+	doltCursor, err := table.GetDoltProjectionCursor(c, query, template)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer func() {
+		_ = doltCursor.Close()
+	}()
+	internalCursor, err := table.Data.GetProjectionCursor(internalQuery, template)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer internalCursor.Close()
+	for rowIdx := 0; ; rowIdx++ {
+		iRow, iOk, err := internalCursor.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		dRow, dOk, err := doltCursor.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if !iOk && !dOk {
+			return nil
+		}
+		if iOk != dOk || !iRow.Equals(dRow) {
+			return errors.New(fmt.Sprintf("On table `%s`, index `%s` returned a different row at position %d\nQuery: %s\nInternal: [%s]\nDolt: [%s]\nIndex definition: %s",
+				table.Name, index.Name, rowIdx, query, iRow.MySQLString(), dRow.MySQLString(), index.String()))
+		}
+	}
+}
+
 // exportTableData exports the data for each given table.
 func (m *RepositoryManager) exportTableData(c *Cycle, tables ...*Table) error {
 	internalDataPath := c.Planner.Base.Arguments.RepoWorkingPath + c.Name + "/internal_data"
@@ -15,6 +15,7 @@
 package run
 
 import (
+	"encoding/binary"
 	"strings"
 
 	"github.com/dolthub/fuzzer/errors"
@@ -91,6 +92,16 @@ func (r Row) MySQLString() string {
 	return strings.Join(vals, ",")
 }
 
+// ValueListString returns the row as a comma-separated string, with each value written as it may appear within the
+// value list of an INSERT or REPLACE. Intended for MySQL usage.
+func (r Row) ValueListString() string {
+	vals := make([]string, len(r.Values))
+	for i := 0; i < len(vals); i++ {
+		vals[i] = types.ValueListString(r.Values[i])
+	}
+	return strings.Join(vals, ",")
+}
+
 // SQLiteString returns the row as a comma-separated string. Intended for SQLite usage.
 func (r Row) SQLiteString() string {
 	vals := make([]string, len(r.Values))
@@ -100,11 +111,12 @@ func (r Row) SQLiteString() string {
 	return strings.Join(vals, ",")
 }
 
-// CSVString returns the row as a comma-separated string. Intended for CSV usage.
+// CSVString returns the row as a comma-separated string, following RFC 4180. Intended for CSV usage. Quoted fields may
+// contain line breaks, so the row may span several lines.
 func (r Row) CSVString() string {
 	vals := make([]string, len(r.Values))
 	for i := 0; i < len(vals); i++ {
-		vals[i] = r.Values[i].CSVString()
+		vals[i] = csvField(r.Values[i])
 	}
 	return strings.Join(vals, ",")
 }
@@ -147,7 +159,8 @@ func (r Row) Compare(otherRow Row) int {
 }
 
 // PKCompare returns an integer indicating the ordering of this row in relation to the given row. This evaluates only
-// the primary keys. Empty rows will always return a greater value than non-empty rows.
+// the primary keys. Rows without a primary key are identified by all of their values, so the entire row is compared.
+// Empty rows will always return a greater value than non-empty rows.
 func (r Row) PKCompare(otherRow Row) int {
 	if len(r.Values) != len(otherRow.Values) {
 		if len(r.Values) == 0 {
@@ -165,6 +178,9 @@ func (r Row) PKCompare(otherRow Row) int {
 		}
 		return 1
 	}
+	if r.PkColsLen == 0 {
+		return r.Compare(otherRow)
+	}
 	for i := int32(0); i < r.PkColsLen; i++ {
 		valComp := r.Values[i].Compare(otherRow.Values[i])
 		if valComp == -1 {
@@ -186,11 +202,21 @@ func (r Row) Copy() Row {
 	}
 }
 
-// Hash returns a string hash of the values of this row.
+// Hash returns a string hash of the values of this row. Each value is written with a marker for NULL and its length
+// otherwise, so that NULL, an empty string, and values that merely shift bytes between columns all hash differently.
 func (r Row) Hash() utils.Hash {
 	hasher := utils.NewHasher()
+	lengthBytes := make([]byte, 9)
 	for i := 0; i < len(r.Values); i++ {
-		hasher.Write(r.Values[i].ToBytes())
+		if _, ok := r.Values[i].(types.NilValue); ok {
+			hasher.Write([]byte{0})
+			continue
+		}
+		valBytes := r.Values[i].ToBytes()
+		lengthBytes[0] = 1
+		binary.BigEndian.PutUint64(lengthBytes[1:], uint64(len(valBytes)))
+		hasher.Write(lengthBytes)
+		hasher.Write(valBytes)
 	}
 	return hasher.Hash()
 }
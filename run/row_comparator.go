@@ -0,0 +1,171 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// rowCountSourceColumn is the column of a RowCountCursor's table data that holds the index of each row's source.
+const rowCountSourceColumn = "fuzzer_row_source"
+
+// RowDivergenceDescriber returns the error describing a divergence of the given kind. The internal row is empty when
+// the kind is DivergenceKind_MissingFromInternal, and the Dolt row is empty when the kind is DivergenceKind_MissingFromDolt.
+type RowDivergenceDescriber func(kind DivergenceKind, internalRow Row, doltRow Row) error
+
+// RowSliceIterator is a RowIterator over rows that are already in memory.
+type RowSliceIterator struct {
+	rows []Row
+	idx  int
+}
+
+var _ RowIterator = (*RowSliceIterator)(nil)
+
+// NewRowSliceIterator returns a new *RowSliceIterator over the given rows.
+func NewRowSliceIterator(rows []Row) *RowSliceIterator {
+	return &RowSliceIterator{rows: rows}
+}
+
+// NextRow implements the interface RowIterator.
+func (rsi *RowSliceIterator) NextRow() (Row, bool, error) {
+	if rsi.idx >= len(rsi.rows) {
+		return Row{}, false, nil
+	}
+	row := rsi.rows[rsi.idx]
+	rsi.idx++
+	return row, true, nil
+}
+
+// CompareRowIterators walks both iterators in lockstep, which must return their rows in the same order, and returns an
+// error for the first divergence found. Only the current rows and the rows kept by the recorder for context are held
+// in memory, so the memory used does not grow with the number of rows. Divergences are reported through the recorder,
+// using the describer to construct the error. Returns nil if both iterators return identical rows.
+func CompareRowIterators(c *Cycle, recorder *DivergenceRecorder, internalIter RowIterator, doltIter RowIterator,
+	describe RowDivergenceDescriber) error {
+	for {
+		iRow, iOk, err := internalIter.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		dRow, dOk, err := doltIter.NextRow()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		switch {
+		case !iOk && !dOk:
+			return nil
+		case !dOk:
+			return recorder.Fail(c, DivergenceKind_MissingFromDolt, iRow, Row{}, internalIter, nil,
+				describe(DivergenceKind_MissingFromDolt, iRow, Row{}))
+		case !iOk:
+			return recorder.Fail(c, DivergenceKind_MissingFromInternal, Row{}, dRow, nil, doltIter,
+				describe(DivergenceKind_MissingFromInternal, Row{}, dRow))
+		case !iRow.Equals(dRow):
+			return recorder.Fail(c, DivergenceKind_RowMismatch, iRow, dRow, internalIter, doltIter,
+				describe(DivergenceKind_RowMismatch, iRow, dRow))
+		}
+		recorder.Matched(iRow)
+	}
+}
+
+// RowCountCursor returns every distinct row of several sources, along with the number of times that the row appears in
+// each source. This is how the rows of keyless tables are compared, as a row is identified by all of its values. The
+// rows of every source are first copied into table data, where the Oracle groups and orders them, so that only the
+// current row is held in memory. Rows are returned in the same order as TableData.GetFullyOrderedCursor.
+type RowCountCursor struct {
+	data    *TableData
+	cursor  *TableDataCursor
+	colsLen int
+}
+
+// NewRowCountCursor returns a new *RowCountCursor over the given sources, which must all return rows with the given
+// columns. Each source is read until it is exhausted.
+func NewRowCountCursor(tableName string, pkCols []*Column, nonPKCols []*Column, sources ...RowIterator) (*RowCountCursor, error) {
+	sourceCol := &Column{Name: rowCountSourceColumn, Type: &types.IntInstance{}}
+	data, err := CreateTableData(tableName, pkCols, append(append([]*Column{}, nonPKCols...), sourceCol))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	for i, source := range sources {
+		row, ok, err := source.NextRow()
+		for ; err == nil && ok; row, ok, err = source.NextRow() {
+			values := append(append([]types.Value{}, row.Values...), types.IntValue{Int32Value: types.Int32Value(i)})
+			err = data.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", tableName, data.RowString(Row{Values: values})))
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			data.Close()
+			return nil, errors.Wrap(err)
+		}
+	}
+
+	cols := append(append([]*Column{}, pkCols...), nonPKCols...)
+	order := data.columnOrder(cols)
+	selects := make([]string, 0, len(cols)+len(sources))
+	template := Row{Values: make([]types.Value, 0, len(cols)+len(sources)), PkColsLen: int32(len(pkCols))}
+	for _, col := range cols {
+		selects = append(selects, data.Dialect(fmt.Sprintf("ANY_VALUE(`%s`)", col.Name), fmt.Sprintf("`%s`", col.Name)))
+		template.Values = append(template.Values, col.Type.TypeValue())
+	}
+	for i := range sources {
+		selects = append(selects, data.Dialect(
+			fmt.Sprintf("CAST(SUM(`%s` = %d) AS SIGNED)", rowCountSourceColumn, i),
+			fmt.Sprintf("SUM(`%s` = %d)", rowCountSourceColumn, i)))
+		template.Values = append(template.Values, types.BigintValue{})
+	}
+	cursor, err := data.GetProjectionCursor(fmt.Sprintf("SELECT %s FROM `%s` GROUP BY %s ORDER BY %s;",
+		strings.Join(selects, ", "), tableName, strings.Join(order, ", "), strings.Join(order, ", ")), template)
+	if err != nil {
+		data.Close()
+		return nil, errors.Wrap(err)
+	}
+	return &RowCountCursor{
+		data:    data,
+		cursor:  cursor,
+		colsLen: len(cols),
+	}, nil
+}
+
+// NextRow returns the next distinct row, along with the number of times that it appears in each source, in the order
+// that the sources were given. If there are no more rows to return, returns false.
+func (rcc *RowCountCursor) NextRow() (Row, []int64, bool, error) {
+	row, ok, err := rcc.cursor.NextRow()
+	if err != nil || !ok {
+		return Row{}, nil, false, err
+	}
+	counts := make([]int64, len(row.Values)-rcc.colsLen)
+	for i := range counts {
+		count, ok := row.Values[rcc.colsLen+i].(types.BigintValue)
+		if !ok {
+			return Row{}, nil, false, errors.New(fmt.Sprintf("row count has the unexpected type %T",
+				row.Values[rcc.colsLen+i]))
+		}
+		counts[i] = int64(count.Int64Value)
+	}
+	row.Values = row.Values[:rcc.colsLen]
+	return row, counts, true, nil
+}
+
+// Close closes the cursor, and deletes the copied rows.
+func (rcc *RowCountCursor) Close() {
+	rcc.cursor.Close()
+	rcc.data.Close()
+}
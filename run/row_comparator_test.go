@@ -0,0 +1,93 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestCompareRowIterators(t *testing.T) {
+	c := &Cycle{Planner: &Planner{Base: &parameters.Base{}}}
+	rows := func(vals ...int64) []Row {
+		out := make([]Row, len(vals))
+		for i, val := range vals {
+			out[i] = Row{Values: []types.Value{types.BigintValue{Int64Value: types.Int64Value(val)}}, PkColsLen: 1}
+		}
+		return out
+	}
+	compare := func(internal []Row, dolt []Row) (DivergenceKind, bool) {
+		var found DivergenceKind
+		err := CompareRowIterators(c, NewDivergenceRecorder("t", "main", []string{"pk"}),
+			NewRowSliceIterator(internal), NewRowSliceIterator(dolt),
+			func(kind DivergenceKind, internalRow Row, doltRow Row) error {
+				found = kind
+				return errors.New(string(kind))
+			})
+		return found, err != nil
+	}
+
+	_, failed := compare(rows(1, 2, 3), rows(1, 2, 3))
+	require.False(t, failed)
+	_, failed = compare(nil, nil)
+	require.False(t, failed)
+	kind, failed := compare(rows(1, 2, 3), rows(1, 2))
+	require.True(t, failed)
+	require.Equal(t, DivergenceKind_MissingFromDolt, kind)
+	kind, failed = compare(rows(1, 2), rows(1, 2, 3))
+	require.True(t, failed)
+	require.Equal(t, DivergenceKind_MissingFromInternal, kind)
+	kind, failed = compare(rows(1, 2, 3), rows(1, 4, 3))
+	require.True(t, failed)
+	require.Equal(t, DivergenceKind_RowMismatch, kind)
+}
+
+func TestRowCountCursor(t *testing.T) {
+	cols := []*Column{{Name: "v1", Type: &types.BigintInstance{}}, {Name: "v2", Type: &types.VarcharInstance{}}}
+	row := func(v1 int64, v2 string) Row {
+		return Row{Values: []types.Value{types.BigintValue{Int64Value: types.Int64Value(v1)},
+			types.VarcharValue{StringValue: types.StringValue(v2)}}}
+	}
+	counts, err := NewRowCountCursor("counted", nil, cols,
+		NewRowSliceIterator([]Row{row(2, "b"), row(1, "a"), row(2, "b"), row(1, "A")}),
+		NewRowSliceIterator([]Row{row(1, "a"), row(3, "c"), row(1, "a")}))
+	require.NoError(t, err)
+	defer counts.Close()
+
+	// Each distinct row is returned once and in order, with the number of times that it appears in each source
+	for _, expected := range []struct {
+		row    Row
+		counts []int64
+	}{
+		{row(1, "A"), []int64{1, 0}},
+		{row(1, "a"), []int64{1, 2}},
+		{row(2, "b"), []int64{2, 0}},
+		{row(3, "c"), []int64{0, 1}},
+	} {
+		actual, actualCounts, ok, err := counts.NextRow()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.True(t, expected.row.Equals(actual), "expected [%s] but found [%s]", expected.row.MySQLString(), actual.MySQLString())
+		require.Equal(t, expected.counts, actualCounts)
+	}
+	_, _, ok, err := counts.NextRow()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestRowHashEmptyStringAndNull(t *testing.T) {
+	null := types.NilValue{}
+	empty := types.VarcharValue{StringValue: ""}
+	a := types.VarcharValue{StringValue: "a"}
+	rows := []Row{
+		{Values: []types.Value{null, a}},
+		{Values: []types.Value{empty, a}},
+		{Values: []types.Value{a, empty}},
+		{Values: []types.Value{a, null}},
+		{Values: []types.Value{types.VarcharValue{StringValue: "\x00a"}, empty}},
+	}
+	for i := range rows {
+		for j := range rows {
+			if i != j {
+				require.NotEqual(t, rows[i].Hash(), rows[j].Hash(), "rows %d and %d", i, j)
+				require.False(t, rows[i].Equals(rows[j]), "rows %d and %d", i, j)
+			}
+		}
+	}
+
+	mismatches := describeNullMismatches([]string{"v1", "v2"}, rows[0], rows[1])
+	require.Equal(t, "\nColumn `v1` is NULL in the internal data but an empty string in Dolt", mismatches)
+	mismatches = describeNullMismatches([]string{"v1", "v2"}, rows[2], rows[3])
+	require.Equal(t, "\nColumn `v2` is an empty string in the internal data but NULL in Dolt", mismatches)
+	require.Empty(t, describeNullMismatches([]string{"v1", "v2"}, rows[1], rows[2]))
+}
@@ -0,0 +1,315 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// SchemaChange generates a random change to a table's schema. The internal table is modified to match, and the
+// statement that makes the same change in Dolt is returned.
+type SchemaChange interface {
+	// Probability returns the probability, from 0 to 1, that this schema change is made in place of a statement.
+	Probability() float64
+	// GenerateSchemaChange modifies the given table and returns the statement that applies the same change to Dolt.
+	// Returns an empty string if the schema change cannot be made to the given table.
+	GenerateSchemaChange(c *Cycle, table *Table) (string, error)
+}
+
+// RenameColumnSchemaChange renames a random column of the table.
+type RenameColumnSchemaChange struct {
+	probability float64
+}
+
+var _ SchemaChange = (*RenameColumnSchemaChange)(nil)
+
+// Probability implements the interface SchemaChange.
+func (s *RenameColumnSchemaChange) Probability() float64 {
+	return s.probability
+}
+
+// GenerateSchemaChange implements the interface SchemaChange.
+func (s *RenameColumnSchemaChange) GenerateSchemaChange(c *Cycle, table *Table) (string, error) {
+	colNames := table.ColumnNames()
+	idx, err := rand.StructureUint64()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	oldName := colNames[idx%uint64(len(colNames))]
+	newName, err := newColumnName(c)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	c.usedNames[newName] = struct{}{}
+	err = table.RenameColumn(oldName, newName)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("ALTER TABLE `%s` RENAME COLUMN `%s` TO `%s`;", table.Name, oldName, newName), nil
+}
+
+// AddColumnSchemaChange adds a random non-primary key column to the table. Existing rows are given a random default
+// value. The default is written as an expression, since MySQL does not allow literal defaults on some types (such as
+// BLOB and TEXT). Columns are not added past the upper bound of the column amount.
+type AddColumnSchemaChange struct {
+	probability float64
+}
+
+var _ SchemaChange = (*AddColumnSchemaChange)(nil)
+
+// Probability implements the interface SchemaChange.
+func (s *AddColumnSchemaChange) Probability() float64 {
+	return s.probability
+}
+
+// GenerateSchemaChange implements the interface SchemaChange.
+func (s *AddColumnSchemaChange) GenerateSchemaChange(c *Cycle, table *Table) (string, error) {
+	if int64(len(table.PKCols)+len(table.NonPKCols)) >= c.Planner.Base.Amounts.Columns.Upperbound {
+		return "", nil
+	}
+	fullType, err := c.nonPkTypeDist.Get(1)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	typeInstance, err := fullType.(types.Type).Instance()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	defaultValue, err := typeInstance.Get()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	colName, err := newColumnName(c)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	c.usedNames[colName] = struct{}{}
+	err = table.AddColumn(&Column{Name: colName, Type: typeInstance}, defaultValue)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s DEFAULT (%s);",
+		table.Name, colName, typeInstance.Name(false), defaultValue.MySQLString()), nil
+}
+
+// DropColumnSchemaChange drops a random non-primary key column from the table. Columns that are referenced by an index
+// or foreign key are never dropped, and columns are not dropped past the lower bound of the column amount.
+type DropColumnSchemaChange struct {
+	probability float64
+}
+
+var _ SchemaChange = (*DropColumnSchemaChange)(nil)
+
+// Probability implements the interface SchemaChange.
+func (s *DropColumnSchemaChange) Probability() float64 {
+	return s.probability
+}
+
+// GenerateSchemaChange implements the interface SchemaChange.
+func (s *DropColumnSchemaChange) GenerateSchemaChange(c *Cycle, table *Table) (string, error) {
+	colCount := int64(len(table.PKCols) + len(table.NonPKCols))
+	if colCount <= 1 || colCount <= c.Planner.Base.Amounts.Columns.Lowerbound {
+		return "", nil
+	}
+	var candidates []string
+	for _, col := range table.NonPKCols {
+		if table.CanDropColumn(col.Name) {
+			candidates = append(candidates, col.Name)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	idx, err := rand.StructureUint64()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	colName := candidates[idx%uint64(len(candidates))]
+	err = table.DropColumn(colName)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`;", table.Name, colName), nil
+}
+
+// ModifyColumnSchemaChange changes the type of a random column of the table. Only the configured conversions are made,
+// and each conversion must be able to hold every value of the original type. This means that no data is lost, and the
+// existing values are only converted to the new type. Indexed columns of string and binary types are never modified, as
+// widening them may exceed the maximum key length of their indexes.
+type ModifyColumnSchemaChange struct {
+	probability float64
+	conversions [][2]string
+}
+
+var _ SchemaChange = (*ModifyColumnSchemaChange)(nil)
+
+// Probability implements the interface SchemaChange.
+func (s *ModifyColumnSchemaChange) Probability() float64 {
+	return s.probability
+}
+
+// GenerateSchemaChange implements the interface SchemaChange.
+func (s *ModifyColumnSchemaChange) GenerateSchemaChange(c *Cycle, table *Table) (string, error) {
+	type candidate struct {
+		col *Column
+		to  string
+	}
+	var candidates []candidate
+	for _, col := range append(append([]*Column{}, table.PKCols...), table.NonPKCols...) {
+		if _, ok := col.Type.(types.PrefixableTypeInstance); ok && table.isIndexed(col.Name) {
+			continue
+		}
+		typeName := types.TypeInstanceName(col.Type)
+		for _, conversion := range s.conversions {
+			if conversion[0] == typeName {
+				candidates = append(candidates, candidate{col, conversion[1]})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	idx, err := rand.StructureUint64()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	chosen := candidates[idx%uint64(len(candidates))]
+	newType, err := types.ModifyTypeInstance(chosen.col.Type, chosen.to)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	// Lengths cannot be increased past their maximum, in which case there is nothing to modify
+	if newType.Name(false) == chosen.col.Type.Name(false) {
+		return "", nil
+	}
+	err = table.ModifyColumn(chosen.col.Name, newType)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	// MODIFY COLUMN replaces the entire definition, so an invisible column must be declared invisible again
+	modified := &Column{Name: chosen.col.Name, Type: newType, Invisible: chosen.col.Invisible}
+	return fmt.Sprintf("ALTER TABLE `%s` MODIFY COLUMN %s;", table.Name, modified.Definition(false)), nil
+}
+
+// AddPrimaryKeySchemaChange adds a primary key to a keyless table, using the smallest number of leading columns that
+// can hold the upper bound of `Amounts.Rows` (as determined by the PK saturation divisor). Only integer and VARBINARY
+// columns are used, as MySQL and SQLite agree on which of their values are duplicates. When the existing rows hold
+// duplicates (or NULLs) within the chosen columns, the statement is still run against Dolt, but it is expected to fail
+// and the table is left unchanged.
+type AddPrimaryKeySchemaChange struct {
+	probability float64
+}
+
+var _ SchemaChange = (*AddPrimaryKeySchemaChange)(nil)
+
+// Probability implements the interface SchemaChange.
+func (s *AddPrimaryKeySchemaChange) Probability() float64 {
+	return s.probability
+}
+
+// GenerateSchemaChange implements the interface SchemaChange.
+func (s *AddPrimaryKeySchemaChange) GenerateSchemaChange(c *Cycle, table *Table) (string, error) {
+	if !table.IsKeyless() || table.HasForeignKeys() {
+		return "", nil
+	}
+	pkCount := 0
+	pkNames := make([]string, 0, len(table.NonPKCols))
+	valueCombinations := float64(1)
+	for _, col := range table.NonPKCols {
+		switch types.TypeInstanceName(col.Type) {
+		case "", "VARCHAR":
+			return "", nil
+		}
+		pkCount++
+		pkNames = append(pkNames, fmt.Sprintf("`%s`", col.Name))
+		valueCombinations *= col.Type.MaxValueCount()
+		if valueCombinations/c.Planner.Base.Options.PKSaturationDivisor > float64(c.Planner.Base.Amounts.RowsUpperbound()) {
+			break
+		}
+	}
+	if valueCombinations/c.Planner.Base.Options.PKSaturationDivisor <= float64(c.Planner.Base.Amounts.RowsUpperbound()) {
+		return "", nil
+	}
+	statement := fmt.Sprintf("ALTER TABLE `%s` ADD PRIMARY KEY (%s);", table.Name, strings.Join(pkNames, ", "))
+	ok, err := table.Data.CanSetPrimaryKey(pkCount)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if !ok {
+		// Dolt must reject the primary key, after which a statement is executed in place of the schema change
+		if err = c.SqlServer(statement); err == nil {
+			return "", errors.New(fmt.Sprintf("On table `%s`, Dolt added a primary key over columns holding NULLs or "+
+				"duplicate values: %s", table.Name, statement))
+		}
+		if err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Expected failure: %s", err.Error())); err != nil {
+			return "", errors.Wrap(err)
+		}
+		return "", nil
+	}
+	err = table.AddPrimaryKey(pkCount)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return statement, nil
+}
+
+// DropPrimaryKeySchemaChange drops the primary key of a table, leaving it keyless. Tables that have a foreign key, or
+// are referenced by one, are skipped, as the primary key may be required by the foreign key.
+type DropPrimaryKeySchemaChange struct {
+	probability float64
+}
+
+var _ SchemaChange = (*DropPrimaryKeySchemaChange)(nil)
+
+// Probability implements the interface SchemaChange.
+func (s *DropPrimaryKeySchemaChange) Probability() float64 {
+	return s.probability
+}
+
+// GenerateSchemaChange implements the interface SchemaChange.
+func (s *DropPrimaryKeySchemaChange) GenerateSchemaChange(c *Cycle, table *Table) (string, error) {
+	if table.IsKeyless() || table.HasForeignKeys() {
+		return "", nil
+	}
+	err := table.DropPrimaryKey()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("ALTER TABLE `%s` DROP PRIMARY KEY;", table.Name), nil
+}
+
+// nextSchemaChange returns the schema change that should be made in place of the next statement. Returns nil if a
+// statement should be executed instead.
+func nextSchemaChange(c *Cycle) (SchemaChange, error) {
+	for _, schemaChange := range c.schemaChanges {
+		if schemaChange.Probability() <= 0 {
+			continue
+		}
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if float64(roll) < schemaChange.Probability()*math.MaxUint64 {
+			return schemaChange, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+// WriteSchemaDump writes the schema of every table on the current branch to "schemas.txt" in the cycle's directory,
+// with the `SHOW CREATE TABLE` output from Dolt alongside the internal data's `CREATE TABLE`, so that a failed cycle's
+// schemas may be compared at a glance. Tables that exist in only one of the two are included as well. A table whose
+// schema cannot be read from Dolt has the error written in place of its schema, so that the rest are still written.
+func WriteSchemaDump(c *Cycle) error {
+	if _, ok := c.Planner.Interface.(*DoltInterface); !ok || len(c.branches) == 0 {
+		return nil
+	}
+	branch := c.GetCurrentBranch()
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	doltTables, err := schemaDumpTableNames(dc)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("Branch \"%s\"\n", branch.Name))
+	internalTables := make(map[string]bool)
+	for _, table := range branch.GetWorkingSet().Tables {
+		internalTables[table.Name] = true
+		sb.WriteString(fmt.Sprintf("\n=== Table `%s` ===\n", table.Name))
+		sb.WriteString("--- Dolt ---\n")
+		sb.WriteString(schemaDumpDoltCreate(dc, table.Name))
+		sb.WriteString("\n--- Fuzzer ---\n")
+		sb.WriteString(table.CreateString(false, false))
+		sb.WriteString("\n")
+	}
+	for _, tableName := range doltTables {
+		if internalTables[tableName] {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n=== Table `%s` ===\n", tableName))
+		sb.WriteString("--- Dolt ---\n")
+		sb.WriteString(schemaDumpDoltCreate(dc, tableName))
+		sb.WriteString("\n--- Fuzzer ---\n(table does not exist)\n")
+	}
+	err = os.WriteFile(c.Planner.Base.Arguments.RepoWorkingPath+c.Name+"/schemas.txt", []byte(sb.String()), 0777)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// schemaDumpTableNames returns the name of every table in Dolt, leaving out views.
+func schemaDumpTableNames(dc *connection.DoltConnection) ([]string, error) {
+	rows, err := dc.Conn.QueryContext(context.Background(), "SHOW FULL TABLES WHERE Table_type = 'BASE TABLE';")
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer rows.Close()
+	var tableNames []string
+	for rows.Next() {
+		var tableName, tableType string
+		if err = rows.Scan(&tableName, &tableType); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return tableNames, nil
+}
+
+// schemaDumpDoltCreate returns the `SHOW CREATE TABLE` output of the given table from Dolt, or the error if it could
+// not be read.
+func schemaDumpDoltCreate(dc *connection.DoltConnection, tableName string) string {
+	var createStatement string
+	err := dc.Conn.QueryRowContext(context.Background(), fmt.Sprintf("SHOW CREATE TABLE `%s`;", tableName)).
+		Scan(&tableName, &createStatement)
+	if err != nil {
+		return fmt.Sprintf("(error: %s)", err.Error())
+	}
+	return createStatement
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+const (
+	// maxTableColumns is the largest number of columns that a table may have.
+	maxTableColumns = 4096
+	// maxRowBytes is the largest combined size of a row's columns, excluding the TEXT and BLOB families, which are
+	// stored separately from the row.
+	maxRowBytes = 65535
+	// rowBytesMargin is how far from maxRowBytes a row size probe is. The size of a row includes overhead that differs
+	// between implementations, so probes avoid the exact boundary.
+	rowBytesMargin = maxRowBytes / 10
+	// maxVarcharChars is the longest VARCHAR using utf8mb4, which takes 4 bytes per character.
+	maxVarcharChars = 16383
+)
+
+// schemaLimitProbe is a table definition at or beyond one of the schema limits.
+type schemaLimitProbe struct {
+	description string
+	columns     []string
+	accepted    bool
+}
+
+// probeSchemaLimits creates a table that is near or beyond the column count or row size limits at the probability
+// Options.Schema_Limit_Probability, and verifies that Dolt accepts it only when it is within the limits. The table is
+// never part of the repository, as it is dropped if it was accepted, so it is written to the log for reference rather
+// than as a statement that may be replayed.
+func probeSchemaLimits(c *Cycle) error {
+	probability := c.Planner.Base.Options.SchemaLimitProbability
+	if probability <= 0 || c.Planner.Base.Arguments.SkipValidation {
+		return nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if float64(roll) >= probability*math.MaxUint64 {
+		return nil
+	}
+	probe, err := newSchemaLimitProbe(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	tableName, err := newTableName(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	c.usedNames[tableName] = struct{}{}
+	statement := fmt.Sprintf("CREATE TABLE `%s` (%s);", tableName, strings.Join(probe.columns, ", "))
+	err = c.Logger.WriteLine(LogType_INFO, fmt.Sprintf("Probing schema limits with %s: %s", probe.description, statement))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	_, createErr := dc.Conn.ExecContext(context.Background(), statement)
+	if probe.accepted && createErr != nil {
+		return errors.New(fmt.Sprintf("table with %s is within the schema limits, but was rejected: %s",
+			probe.description, createErr.Error()))
+	}
+	var tableCount int
+	err = dc.Conn.QueryRowContext(context.Background(), fmt.Sprintf("SELECT COUNT(*) FROM `information_schema`.`tables` "+
+		"WHERE `TABLE_SCHEMA` = DATABASE() AND `TABLE_NAME` = '%s';", tableName)).Scan(&tableCount)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if !probe.accepted {
+		if createErr == nil || tableCount != 0 {
+			return errors.New(fmt.Sprintf("table with %s is beyond the schema limits, but was created", probe.description))
+		}
+		return nil
+	}
+	if tableCount != 1 {
+		return errors.New(fmt.Sprintf("table with %s was accepted, but does not exist", probe.description))
+	}
+	if _, err = dc.Conn.ExecContext(context.Background(), fmt.Sprintf("DROP TABLE `%s`;", tableName)); err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// newSchemaLimitProbe returns a random probe, which either has exactly the maximum number of columns or one more, or
+// has a row size that is just within or just beyond the maximum row size.
+func newSchemaLimitProbe(c *Cycle) (schemaLimitProbe, error) {
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return schemaLimitProbe{}, errors.Wrap(err)
+	}
+	probe := schemaLimitProbe{accepted: roll%2 == 0}
+	newColumn := func(typeName string) (string, error) {
+		colName, err := newColumnName(c)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		c.usedNames[colName] = struct{}{}
+		return fmt.Sprintf("`%s` %s", colName, typeName), nil
+	}
+
+	if roll%4 < 2 {
+		columnCount := maxTableColumns
+		if !probe.accepted {
+			columnCount++
+		}
+		probe.description = fmt.Sprintf("%d columns", columnCount)
+		probe.columns = make([]string, columnCount)
+		for i := range probe.columns {
+			typeName := "TINYINT"
+			if i == 0 {
+				typeName = "TINYINT PRIMARY KEY"
+			}
+			if probe.columns[i], err = newColumn(typeName); err != nil {
+				return schemaLimitProbe{}, errors.Wrap(err)
+			}
+		}
+		return probe, nil
+	}
+
+	targetBytes := int64(maxRowBytes - rowBytesMargin)
+	if !probe.accepted {
+		targetBytes = int64(maxRowBytes + rowBytesMargin)
+	}
+	probe.description = fmt.Sprintf("a row size of %d bytes", targetBytes)
+	pkCol, err := newColumn("INT PRIMARY KEY")
+	if err != nil {
+		return schemaLimitProbe{}, errors.Wrap(err)
+	}
+	probe.columns = append(probe.columns, pkCol)
+	for rowBytes := int64(4); rowBytes < targetBytes; {
+		chars, err := rand.StructureUint64()
+		if err != nil {
+			return schemaLimitProbe{}, errors.Wrap(err)
+		}
+		// Each column needs a 2 byte length prefix, along with 4 bytes for each character
+		chars = 1 + chars%maxVarcharChars
+		if remaining := uint64(targetBytes-rowBytes-2) / 4; chars > remaining {
+			chars = remaining
+		}
+		if chars == 0 {
+			break
+		}
+		col, err := newColumn(fmt.Sprintf("VARCHAR(%d) COLLATE utf8mb4_0900_ai_ci", chars))
+		if err != nil {
+			return schemaLimitProbe{}, errors.Wrap(err)
+		}
+		probe.columns = append(probe.columns, col)
+		rowBytes += 2 + 4*int64(chars)
+	}
+	return probe, nil
+}
@@ -0,0 +1,276 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+	"github.com/dolthub/fuzzer/types"
+)
+
+// copySeedRepo copies the Dolt database at the seed path into the cycle's directory, so that the seed itself is never
+// modified.
+func copySeedRepo(seedPath string, cycleDir string) error {
+	if info, err := os.Stat(filepath.Join(seedPath, ".dolt")); err != nil || !info.IsDir() {
+		return errors.New(fmt.Sprintf("seed repository `%s` is not a Dolt database", seedPath))
+	}
+	return filepath.Walk(seedPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		relPath, err := filepath.Rel(seedPath, path)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		// Only the database itself is copied, ignoring anything else beside it
+		if relPath != "." && relPath != ".dolt" && !strings.HasPrefix(relPath, ".dolt"+string(filepath.Separator)) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		targetPath := filepath.Join(cycleDir, relPath)
+		if info.IsDir() {
+			if err = os.MkdirAll(targetPath, os.ModeDir|0777); err != nil {
+				return errors.Wrap(err)
+			}
+			return nil
+		}
+		source, err := os.Open(path)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		defer func() {
+			_ = source.Close()
+		}()
+		target, err := os.OpenFile(targetPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0777)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if _, err = io.Copy(target, source); err != nil {
+			_ = target.Close()
+			return errors.Wrap(err)
+		}
+		if err = target.Close(); err != nil {
+			return errors.Wrap(err)
+		}
+		return nil
+	})
+}
+
+// prepareSeedRepo readies the copied seed repository for the cycle. The main branch is checked out, and every other
+// branch is deleted, as the fuzzer only knows of the branches that it created. The seed must not have any uncommitted
+// changes, as the working set must match its last commit.
+func prepareSeedRepo(c *Cycle) error {
+	currentBranchName, err := c.CliQuery("branch", "--show-current")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if currentBranchName != "main" {
+		if _, err = c.CliQuery("checkout", "main"); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	repoStatus, err := c.CliQuery("status")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if !strings.Contains(repoStatus, "nothing to commit") {
+		return errors.New("the seed repository has uncommitted changes, which must be committed before it may be used")
+	}
+	branchList, err := c.CliQuery("branch")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, line := range strings.Split(branchList, "\n") {
+		branchName := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if branchName == "" || branchName == "main" {
+			continue
+		}
+		if _, err = c.CliQuery("branch", "-D", branchName); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// loadSeedTables reads the schema and rows of every table from the seed repository into the main branch. The tables
+// are loaded into the branch's initial commit, which is the seed's last commit, while the working set receives a copy
+// of them. History before the seed's last commit is not loaded. Returns the tables of the working set.
+func loadSeedTables(c *Cycle, mainBranch *Branch) ([]*Table, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	var tableNames []string
+	if err = func() error {
+		rows, err := dc.Conn.QueryContext(context.Background(), "SHOW TABLES;")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var tableName string
+			if err = rows.Scan(&tableName); err != nil {
+				return errors.Wrap(err)
+			}
+			tableNames = append(tableNames, tableName)
+		}
+		if err = rows.Err(); err != nil {
+			return errors.Wrap(err)
+		}
+		return nil
+	}(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	head := mainBranch.Commits[0]
+	for _, tableName := range tableNames {
+		table, err := loadSeedTable(c, dc, head, tableName)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to load table `%s` from the seed repository: %s",
+				tableName, err.Error()))
+		}
+		head.Tables = append(head.Tables, table)
+	}
+	workingSet, err := head.Copy()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	workingSet.Hash = ""
+	workingSet.Parents = []*Commit{head}
+	mainBranch.Commits[len(mainBranch.Commits)-1] = workingSet
+	return workingSet.Tables, nil
+}
+
+// loadSeedTable reads the schema, indexes, and rows of the given table from Dolt into a new table on the given commit.
+// Tables with foreign keys, unique indexes, or primary key columns that are not the leading columns in the order of
+// the primary key, cannot be represented, and return an error. Indexes are loaded in order of their names, so that the
+// table is identical on every run.
+func loadSeedTable(c *Cycle, dc *connection.DoltConnection, parent *Commit, tableName string) (*Table, error) {
+	var createStatement string
+	err := dc.Conn.QueryRowContext(context.Background(), fmt.Sprintf("SHOW CREATE TABLE `%s`;", tableName)).
+		Scan(&tableName, &createStatement)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if strings.Contains(strings.ToUpper(createStatement), "FOREIGN KEY") {
+		return nil, errors.New("foreign keys are not supported")
+	}
+	sqlNode, err := parse.Parse(sql.NewEmptyContext(), createStatement)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	planCreateTable, ok := sqlNode.(*plan.CreateTable)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unexpected statement: %s", createStatement))
+	}
+	schema := planCreateTable.Schema()
+	tPKCols, tNonPKCols, err := types.ConvertGMSSchemaToFuzzerSchema(schema)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	pkCols := make([]*Column, len(tPKCols))
+	for i, tCol := range tPKCols {
+		if !schema[i].PrimaryKey {
+			return nil, errors.New("primary key columns must come before all other columns")
+		}
+		pkCols[i] = &Column{Name: tCol.Name, Type: tCol.Type}
+		c.usedNames[tCol.Name] = struct{}{}
+	}
+	nonPKCols := make([]*Column, len(tNonPKCols))
+	for i, tCol := range tNonPKCols {
+		nonPKCols[i] = &Column{Name: tCol.Name, Type: tCol.Type}
+		c.usedNames[tCol.Name] = struct{}{}
+	}
+
+	statistics, err := readInformationSchemaStatistics(dc, tableName)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	indexNames := make([]string, 0, len(statistics))
+	for indexName := range statistics {
+		indexNames = append(indexNames, indexName)
+	}
+	sort.Strings(indexNames)
+	var indexes []*Index
+	for _, indexName := range indexNames {
+		indexCols := statistics[indexName]
+		if indexName == "primary" {
+			for i, indexCol := range indexCols {
+				if i >= len(pkCols) || !strings.EqualFold(indexCol.ColumnName, pkCols[i].Name) {
+					return nil, errors.New("primary key columns must be in the same order as the table's columns")
+				}
+			}
+			continue
+		}
+		// Nothing generates values that respect a unique index, nor models the rows that REPLACE deletes through one
+		if len(indexCols) > 0 && indexCols[0].NonUnique == 0 {
+			return nil, errors.New(fmt.Sprintf("unique index `%s` is not supported", indexName))
+		}
+		colNames := make([]string, len(indexCols))
+		prefixLengths := make([]int64, len(indexCols))
+		descending := make([]bool, len(indexCols))
+		for i, indexCol := range indexCols {
+			colNames[i] = indexCol.ColumnName
+			prefixLengths[i] = indexCol.SubPart.Int64
+			descending[i] = indexCol.Collation == "D"
+		}
+		index := NewIndex(indexName, colNames, prefixLengths, false)
+		index.Descending = descending
+		indexes = append(indexes, index)
+		c.usedNames[indexName] = struct{}{}
+	}
+	table, err := NewTable(parent, tableName, pkCols, nonPKCols, indexes)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	c.usedNames[tableName] = struct{}{}
+
+	cursor, err := table.GetDoltCursor(c)
+	if err != nil {
+		table.Data.Close()
+		return nil, errors.Wrap(err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+	for {
+		row, ok, err := cursor.NextRow()
+		if err != nil {
+			table.Data.Close()
+			return nil, errors.Wrap(err)
+		}
+		if !ok {
+			return table, nil
+		}
+		if _, err = table.Data.Replace(row); err != nil {
+			table.Data.Close()
+			return nil, errors.Wrap(err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopySeedRepo(t *testing.T) {
+	seedDir := t.TempDir()
+	cycleDir := t.TempDir()
+	require.Error(t, copySeedRepo(seedDir, cycleDir))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(seedDir, ".dolt", "noms"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(seedDir, ".dolt", "repo_state.json"), []byte("state"), 0666))
+	require.NoError(t, os.WriteFile(filepath.Join(seedDir, ".dolt", "noms", "manifest"), []byte("manifest"), 0666))
+	require.NoError(t, os.MkdirAll(filepath.Join(seedDir, "other"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(seedDir, "other", "file"), []byte("other"), 0666))
+	require.NoError(t, os.WriteFile(filepath.Join(seedDir, "log.txt"), []byte("log"), 0666))
+	require.NoError(t, copySeedRepo(seedDir, cycleDir))
+
+	contents, err := os.ReadFile(filepath.Join(cycleDir, ".dolt", "repo_state.json"))
+	require.NoError(t, err)
+	require.Equal(t, "state", string(contents))
+	contents, err = os.ReadFile(filepath.Join(cycleDir, ".dolt", "noms", "manifest"))
+	require.NoError(t, err)
+	require.Equal(t, "manifest", string(contents))
+	// Only the database is copied
+	_, err = os.Stat(filepath.Join(cycleDir, "other"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cycleDir, "log.txt"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// SessionVariableManager sets random session variables whenever a new SQL session is started. Session variables are
+// set through the server, so they are logged alongside every other statement and will be replayed. Generated
+// statements all run on the server connection's dedicated session, so the variables apply to every statement until the
+// connection is reopened, and never leak into the pooled connections that are used for reads and separate sessions.
+//
+// The internal data never enforces foreign keys, so statements that are only valid with `foreign_key_checks` disabled
+// are always allowed internally. Disabling `autocommit` would leave every statement in an open transaction that is
+// discarded once the session closes, so an explicit COMMIT follows each statement while it is disabled, except for
+// statements within an explicit transaction.
+type SessionVariableManager struct {
+	session    interface{}
+	autocommit bool
+	applying   bool
+}
+
+var _ HookRegistrant = (*SessionVariableManager)(nil)
+
+// Register implements the HookRegistrant interface.
+func (m *SessionVariableManager) Register(hooks *Hooks) {
+	hooks.CycleInitialized(m.Initialize)
+	hooks.SQLStatementPreExecution(m.StartSession)
+	hooks.SQLStatementPostExecution(m.FinishStatement)
+}
+
+// Initialize resets the state of SessionVariableManager.
+func (m *SessionVariableManager) Initialize(c *Cycle) error {
+	m.session = nil
+	m.autocommit = true
+	m.applying = false
+	return nil
+}
+
+// StartSession sets the session variables if the statement is about to run on a new session.
+func (m *SessionVariableManager) StartSession(c *Cycle, statement string) error {
+	// Replayed logs already contain the statements that set the session variables
+	if m.applying || c.Planner.Base.Arguments.DontGenRandomData {
+		return nil
+	}
+	session, err := c.Planner.Interface.Session(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if session == m.session {
+		return nil
+	}
+	m.session = session
+	m.autocommit = true
+	m.applying = true
+	defer func() {
+		m.applying = false
+	}()
+
+	// Map iteration order is random, so we sort the names to keep the random choices reproducible for a given seed
+	names := make([]string, 0, len(c.Planner.Base.SessionVariables))
+	for name := range c.Planner.Base.SessionVariables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := c.Planner.Base.SessionVariables[name]
+		idx, err := rand.StructureUint64()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		value := values[idx%uint64(len(values))]
+		// Numeric values are left unquoted, as some variables (such as booleans) do not accept them as strings
+		valueStr := fmt.Sprintf("'%s'", value)
+		if _, err = strconv.ParseInt(value, 10, 64); err == nil {
+			valueStr = value
+		}
+		err = c.SqlServer(fmt.Sprintf("SET @@SESSION.%s = %s;", name, valueStr))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if strings.ToLower(name) == "autocommit" {
+			m.autocommit = !(value == "0" || strings.ToUpper(value) == "OFF")
+		}
+	}
+	return nil
+}
+
+// FinishStatement commits the statement's transaction when autocommit has been disabled. Statements within an explicit
+// transaction are left for the transaction to commit or roll back.
+func (m *SessionVariableManager) FinishStatement(c *Cycle, statement string) error {
+	if m.applying || m.autocommit || c.InTransaction() || c.Planner.Base.Arguments.DontGenRandomData {
+		return nil
+	}
+	m.applying = true
+	defer func() {
+		m.applying = false
+	}()
+	return c.SqlServer("COMMIT;")
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+)
+
+// Snapshot is a point-in-time capture of a commit on a branch. Its tables are deep copies of the commit's tables, and
+// therefore remain unchanged regardless of what later happens to the branch, including the working set being modified
+// or the branch being reset or deleted. Only the hashes of the commit's parents are kept, so that a snapshot does not
+// hold onto the branch's history.
+type Snapshot struct {
+	Branch       string
+	Hash         string
+	ParentHashes []string
+	Tables       []*Table
+	ForeignKeys  []*ForeignKey
+}
+
+// NewSnapshot returns a snapshot of the given commit, which belongs to the given branch.
+func NewSnapshot(branchName string, commit *Commit) (*Snapshot, error) {
+	commitCopy, err := commit.Copy()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	parentHashes := make([]string, len(commit.Parents))
+	for i, parent := range commit.Parents {
+		parentHashes[i] = parent.Hash
+	}
+	return &Snapshot{
+		Branch:       branchName,
+		Hash:         commit.Hash,
+		ParentHashes: parentHashes,
+		Tables:       commitCopy.Tables,
+		ForeignKeys:  commitCopy.ForeignKeys,
+	}, nil
+}
+
+// SnapshotHead returns a snapshot of the branch's last commit. Returns an error if the branch has not been committed.
+func (b *Branch) SnapshotHead() (*Snapshot, error) {
+	workingSet := b.GetWorkingSet()
+	if len(workingSet.Parents) == 0 {
+		return nil, errors.New(fmt.Sprintf("cannot snapshot branch `%s` without a commit", b.Name))
+	}
+	return NewSnapshot(b.Name, workingSet.Parents[0])
+}
+
+// SnapshotWorkingSet returns a snapshot of the branch's working set, which has no hash. The working set's parent is
+// recorded as the snapshot's only parent.
+func (b *Branch) SnapshotWorkingSet() (*Snapshot, error) {
+	return NewSnapshot(b.Name, b.GetWorkingSet())
+}
+
+// GetTable returns the table from this snapshot, or nil if it does not exist. Case-insensitive.
+func (s *Snapshot) GetTable(tableName string) *Table {
+	tableName = strings.ToLower(tableName)
+	for _, table := range s.Tables {
+		if strings.ToLower(table.Name) == tableName {
+			return table
+		}
+	}
+	return nil
+}
+
+// TableNames returns the names of every table in this snapshot.
+func (s *Snapshot) TableNames() []string {
+	tableNames := make([]string, len(s.Tables))
+	for i, table := range s.Tables {
+		tableNames[i] = table.Name
+	}
+	return tableNames
+}
+
+// GetAllRows returns every row of the given table in this snapshot. Returns an error if the table does not exist.
+func (s *Snapshot) GetAllRows(tableName string) ([]Row, error) {
+	table := s.GetTable(tableName)
+	if table == nil {
+		return nil, errors.New(fmt.Sprintf("table `%s` does not exist in the snapshot of %s", tableName, s.String()))
+	}
+	rows, err := table.Data.GetAllRows()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return rows, nil
+}
+
+// String returns the branch and hash of this snapshot, for use in messages.
+func (s *Snapshot) String() string {
+	if len(s.Hash) == 0 {
+		return fmt.Sprintf("the working set of branch `%s`", s.Branch)
+	}
+	return fmt.Sprintf("commit %s on branch `%s`", s.Hash, s.Branch)
+}
+
+// Close releases the data of every table in this snapshot. The snapshot may not be used afterward.
+func (s *Snapshot) Close() {
+	for _, table := range s.Tables {
+		table.Data.Close()
+	}
+}
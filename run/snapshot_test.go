@@ -0,0 +1,82 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	root := &Commit{Hash: "root"}
+	head := &Commit{Hash: "head", Parents: []*Commit{root}}
+	table, err := NewTable(head, "snapped", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	head.Tables = []*Table{table}
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	workingSet, err := head.Copy()
+	require.NoError(t, err)
+	workingSet.Hash = ""
+	workingSet.Parents = []*Commit{head}
+	defer workingSet.Tables[0].Data.Close()
+	branch := &Branch{Name: "main", Commits: []*Commit{root, head, workingSet}}
+
+	snapshot, err := branch.SnapshotHead()
+	require.NoError(t, err)
+	defer snapshot.Close()
+	require.Equal(t, "head", snapshot.Hash)
+	require.Equal(t, []string{"root"}, snapshot.ParentHashes)
+	require.Equal(t, []string{"snapped"}, snapshot.TableNames())
+	require.NotNil(t, snapshot.GetTable("SNAPPED"))
+	require.Nil(t, snapshot.GetTable("missing"))
+	_, err = snapshot.GetAllRows("missing")
+	require.Error(t, err)
+	expected, err := table.Data.GetAllRows()
+	require.NoError(t, err)
+
+	// Changes to the branch, including its commits, must not affect the snapshot
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+		_, err = (&DeleteStatement{}).GenerateStatement(workingSet.Tables[0])
+		require.NoError(t, err)
+	}
+	require.NoError(t, table.RenameColumn("v1", "v2"))
+	rows, err := snapshot.GetAllRows("snapped")
+	require.NoError(t, err)
+	require.ElementsMatch(t, expected, rows)
+	require.Equal(t, []string{"pk", "v1"}, snapshot.GetTable("snapped").ColumnNames())
+
+	workingSnapshot, err := branch.SnapshotWorkingSet()
+	require.NoError(t, err)
+	defer workingSnapshot.Close()
+	require.Empty(t, workingSnapshot.Hash)
+	require.Equal(t, []string{"head"}, workingSnapshot.ParentHashes)
+	require.Contains(t, workingSnapshot.String(), "working set")
+
+	_, err = (&Branch{Name: "empty", Commits: []*Commit{{}}}).SnapshotHead()
+	require.Error(t, err)
+}
@@ -16,10 +16,9 @@ package run
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
-	"github.com/dolthub/go-sqlite3"
-
 	"github.com/dolthub/fuzzer/errors"
 	"github.com/dolthub/fuzzer/rand"
 	"github.com/dolthub/fuzzer/ranges"
@@ -35,9 +34,16 @@ type Statement interface {
 	GenerateStatement(table *Table) (string, error)
 }
 
-// InsertStatement returns random statements that are all INSERT statements.
+// InsertStatement returns random statements that are all INSERT statements. Some statements may list the columns in a
+// random order, which is controlled by the probability columnListProbability. ENUM values may be written to Dolt using
+// the names of their elements, which is controlled by the probability enumNameProbability, while the internal data
+// always uses their indexes. At the probability keyCollisionProbability, the statement instead reuses the primary key
+// of an existing row, and is written as an INSERT IGNORE so that the collision leaves the existing row untouched.
 type InsertStatement struct {
-	r ranges.Int
+	r                       ranges.Int
+	columnListProbability   float64
+	enumNameProbability     float64
+	keyCollisionProbability float64
 }
 
 var _ Statement = (*InsertStatement)(nil)
@@ -49,26 +55,132 @@ func (s *InsertStatement) GetOccurrenceRate() (int64, error) {
 
 // GenerateStatement implements the interface Statement.
 func (s *InsertStatement) GenerateStatement(table *Table) (string, error) {
+	key, ok, err := collidingKey(table, s.keyCollisionProbability)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if ok {
+		return s.insertIgnoreKey(table, key)
+	}
 	for i := 0; i < 10000000; i++ {
 		row, err := NewRow(table)
 		if err != nil {
 			return "", errors.Wrap(err)
 		}
-		err = table.Data.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, row.SQLiteString()))
+		columnList, err := s.newColumnList(table)
 		if err != nil {
-			if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.Code == sqlite3.ErrConstraint {
+			return "", errors.Wrap(err)
+		}
+		// The values are reordered to match the column list, so both Dolt and the internal data must map them back to
+		// the columns by name
+		columns := table.valuesColumnList()
+		if len(columnList) > 0 {
+			names := table.ColumnNames()
+			quotedNames := make([]string, len(columnList))
+			values := make([]types.Value, len(columnList))
+			for i, colIdx := range columnList {
+				quotedNames[i] = fmt.Sprintf("`%s`", names[colIdx])
+				values[i] = row.Values[colIdx]
+			}
+			columns = fmt.Sprintf(" (%s)", strings.Join(quotedNames, ","))
+			row = Row{Values: values}
+		}
+		err = table.Data.Exec(fmt.Sprintf("INSERT INTO `%s`%s VALUES (%s);", table.Name, columns, table.Data.RowString(row)))
+		if err != nil {
+			if table.Data.IsConstraintError(err) {
 				continue
 			}
 			return "", errors.Wrap(err)
 		}
-		return fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", table.Name, row.MySQLString()), nil
+		values, err := s.mysqlValues(row)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		return fmt.Sprintf("INSERT INTO `%s`%s VALUES (%s);", table.Name, columns, values), nil
 	}
 	return "", errors.New("10 million consecutive collisions on attempted INSERT, aborting cycle")
 }
 
-// ReplaceStatement returns random statements that are all REPLACE statements.
+// insertIgnoreKey returns an INSERT IGNORE statement that writes a new row with the given primary key. The key belongs
+// to an existing row, so the statement should not change the table.
+func (s *InsertStatement) insertIgnoreKey(table *Table, key []types.Value) (string, error) {
+	row, err := NewRow(table)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	copy(row.Values, key)
+	err = table.Data.Exec(fmt.Sprintf("%s INTO `%s` VALUES (%s);",
+		table.Data.Dialect("INSERT IGNORE", "INSERT OR IGNORE"), table.Name, table.Data.RowString(row)))
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	values, err := s.mysqlValues(row)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("INSERT IGNORE INTO `%s`%s VALUES (%s);", table.Name, table.valuesColumnList(), values), nil
+}
+
+// mysqlValues returns the row's values as a comma-separated list of MySQL literals, where each ENUM value is written
+// using the name of its element at the probability enumNameProbability.
+func (s *InsertStatement) mysqlValues(row Row) (string, error) {
+	if s.enumNameProbability <= 0 {
+		return row.ValueListString(), nil
+	}
+	vals := make([]string, len(row.Values))
+	for i, val := range row.Values {
+		vals[i] = types.ValueListString(val)
+		enumVal, ok := val.(types.EnumValue)
+		if !ok {
+			continue
+		}
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		if float64(roll) < s.enumNameProbability*math.MaxUint64 {
+			vals[i] = enumVal.ElementName()
+		}
+	}
+	return strings.Join(vals, ","), nil
+}
+
+// newColumnList returns the position of each column in the order that they should be listed in an INSERT, which is a
+// random permutation. Returns nil when the columns should not be listed, in which case the values are given in the
+// table's column order.
+func (s *InsertStatement) newColumnList(table *Table) ([]int, error) {
+	if s.columnListProbability <= 0 {
+		return nil, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if float64(roll) >= s.columnListProbability*math.MaxUint64 {
+		return nil, nil
+	}
+	columnList := make([]int, len(table.PKCols)+len(table.NonPKCols))
+	for i := range columnList {
+		columnList[i] = i
+	}
+	for i := len(columnList) - 1; i > 0; i-- {
+		roll, err = rand.StructureUint64()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		j := int(roll % uint64(i+1))
+		columnList[i], columnList[j] = columnList[j], columnList[i]
+	}
+	return columnList, nil
+}
+
+// ReplaceStatement returns random statements that are all REPLACE statements. REPLACE deletes any row with the same
+// primary key before inserting the new row, which is how the internal data models it as well. The row may use one of the
+// table's hot keys, or reuse the primary key of an existing row at the probability keyCollisionProbability.
 type ReplaceStatement struct {
-	r ranges.Int
+	r                       ranges.Int
+	hotKeys                 hotKeySettings
+	keyCollisionProbability float64
 }
 
 var _ Statement = (*ReplaceStatement)(nil)
@@ -80,24 +192,80 @@ func (s *ReplaceStatement) GetOccurrenceRate() (int64, error) {
 
 // GenerateStatement implements the interface Statement.
 func (s *ReplaceStatement) GenerateStatement(table *Table) (string, error) {
+	hotKey, ok, err := s.hotKeys.next(table)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if ok {
+		return replaceKey(table, hotKey)
+	}
+	key, ok, err := collidingKey(table, s.keyCollisionProbability)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if ok {
+		return replaceKey(table, key)
+	}
 	row, err := NewRow(table)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
-	err = table.Data.Exec(fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", table.Name, row.SQLiteString()))
+	_, err = table.Data.Replace(row)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
-	return fmt.Sprintf("REPLACE INTO `%s` VALUES (%s);", table.Name, row.MySQLString()), nil
+	return fmt.Sprintf("REPLACE INTO `%s`%s VALUES (%s);", table.Name, table.valuesColumnList(), row.ValueListString()), nil
+}
+
+// replaceKey returns a REPLACE statement that writes a new row with the given primary key.
+func replaceKey(table *Table, key []types.Value) (string, error) {
+	row, err := NewRow(table)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	copy(row.Values, key)
+	_, err = table.Data.Replace(row)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return fmt.Sprintf("REPLACE INTO `%s`%s VALUES (%s);", table.Name, table.valuesColumnList(), row.ValueListString()), nil
+}
+
+// collidingKey returns the primary key of a random existing row when the next write should collide with it, which is
+// decided using the given probability. Returns false for keyless tables and empty tables, as they have no key to
+// collide with.
+func collidingKey(table *Table, probability float64) ([]types.Value, bool, error) {
+	if probability <= 0 || table.IsKeyless() {
+		return nil, false, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	if float64(roll) >= probability*math.MaxUint64 {
+		return nil, false, nil
+	}
+	row, ok, err := table.Data.GetRandomRow()
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	return row.Key(), true, nil
 }
 
 // UpdateStatement returns random statements that are usually UPDATE statements. In the event that an UPDATE statement
-// cannot be generated (such as with an empty table), a REPLACE statement is generated instead.
+// cannot be generated (such as with an empty table), a REPLACE statement is generated instead. Tables with only a
+// primary key always receive a REPLACE of an existing row, as there are no columns to update. Keyless tables find the
+// row using its integer columns, which updates every row sharing those values, and receive a REPLACE (which is an
+// INSERT for keyless tables) when they have no integer columns. The updated row may be one of the table's hot keys.
 type UpdateStatement struct {
-	r ranges.Int
+	r       ranges.Int
+	hotKeys hotKeySettings
 }
 
-//TODO: Add OR/LIKE/etc. to the WHERE clause
+// TODO: Add OR/LIKE/etc. to the WHERE clause
 var _ Statement = (*UpdateStatement)(nil)
 
 // GetOccurrenceRate implements the interface ranges.Distributable.
@@ -107,16 +275,44 @@ func (s *UpdateStatement) GetOccurrenceRate() (int64, error) {
 
 // GenerateStatement implements the interface Statement.
 func (s *UpdateStatement) GenerateStatement(table *Table) (string, error) {
-	row, ok, err := table.Data.GetRandomRow()
+	hotKey, isHot, err := s.hotKeys.next(table)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
+	var row Row
+	var ok bool
+	if isHot {
+		row, ok, err = table.Data.GetRow(hotKey)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		// The hot row has been deleted, so we restore it
+		if !ok {
+			return replaceKey(table, hotKey)
+		}
+	} else {
+		row, ok, err = table.Data.GetRandomRow()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+	}
 	// If there are no rows then we switch to a REPLACE.
-	// TODO: remove restriction for keyed tables only
 	// TODO: allow updating primary keys
-	if !ok || len(table.PKCols) == 0 || len(table.NonPKCols) == 0 {
+	if !ok {
 		return (&ReplaceStatement{}).GenerateStatement(table)
 	}
+	whereCols, whereVals := table.identifyingColumns(row)
+	if len(whereCols) == 0 {
+		return (&ReplaceStatement{}).GenerateStatement(table)
+	}
+	// Tables with only a primary key have nothing to SET, so we rewrite the existing row in place instead.
+	if table.IsPKOnly() {
+		_, err = table.Data.Replace(row)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		return fmt.Sprintf("REPLACE INTO `%s`%s VALUES (%s);", table.Name, table.valuesColumnList(), row.ValueListString()), nil
+	}
 	modifiedRow, err := row.NewRowValue(table)
 	if err != nil {
 		return "", errors.Wrap(err)
@@ -139,20 +335,20 @@ func (s *UpdateStatement) GenerateStatement(table *Table) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
-	setsSQLite, err := GenerateColumnEqualsSQLite(table.NonPKCols[:cut], row.Value()[:cut])
+	setsInternal, err := table.Data.ColumnEquals(table.NonPKCols[:cut], row.Value()[:cut])
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
-	wheres, err := GenerateColumnEquals(table.PKCols, row.Key())
+	wheres, err := GenerateColumnEquals(whereCols, whereVals)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
-	wheresSQLite, err := GenerateColumnEqualsSQLite(table.PKCols, row.Key())
+	wheresInternal, err := table.Data.ColumnEquals(whereCols, whereVals)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
 	err = table.Data.Exec(
-		fmt.Sprintf("UPDATE `%s` SET %s WHERE %s;", table.Name, strings.Join(setsSQLite, ","), strings.Join(wheresSQLite, " AND ")),
+		fmt.Sprintf("UPDATE `%s` SET %s WHERE %s;", table.Name, strings.Join(setsInternal, ","), strings.Join(wheresInternal, " AND ")),
 	)
 	if err != nil {
 		return "", errors.Wrap(err)
@@ -161,7 +357,8 @@ func (s *UpdateStatement) GenerateStatement(table *Table) (string, error) {
 }
 
 // DeleteStatement returns random statements that are usually DELETE statements. In the event that a DELETE statement
-// cannot be generated (such as with an empty table), a REPLACE statement is generated instead.
+// cannot be generated (such as with an empty table), a REPLACE statement is generated instead. Keyless tables are
+// handled the same as in UpdateStatement, so a DELETE may remove several rows.
 type DeleteStatement struct {
 	r ranges.Int
 }
@@ -180,20 +377,23 @@ func (s *DeleteStatement) GenerateStatement(table *Table) (string, error) {
 		return "", errors.Wrap(err)
 	}
 	// If there are no rows then we switch to a REPLACE.
-	// TODO: remove restriction for keyed tables only
-	if !ok || len(table.PKCols) == 0 {
+	if !ok {
+		return (&ReplaceStatement{}).GenerateStatement(table)
+	}
+	whereCols, whereVals := table.identifyingColumns(row)
+	if len(whereCols) == 0 {
 		return (&ReplaceStatement{}).GenerateStatement(table)
 	}
 
-	wheres, err := GenerateColumnEquals(table.PKCols, row.Key())
+	wheres, err := GenerateColumnEquals(whereCols, whereVals)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
-	wheresSQLite, err := GenerateColumnEqualsSQLite(table.PKCols, row.Key())
+	wheresInternal, err := table.Data.ColumnEquals(whereCols, whereVals)
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
-	err = table.Data.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE %s;", table.Name, strings.Join(wheresSQLite, " AND ")))
+	err = table.Data.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE %s;", table.Name, strings.Join(wheresInternal, " AND ")))
 	if err != nil {
 		return "", errors.Wrap(err)
 	}
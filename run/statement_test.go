@@ -0,0 +1,342 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/ranges"
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestPKOnlyTableStatements(t *testing.T) {
+	table, err := NewTable(&Commit{}, "pkonly", []*Column{
+		{Name: "pk1", Type: &types.TinyintInstance{}},
+		{Name: "pk2", Type: &types.TinyintInstance{}},
+	}, nil, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	require.True(t, table.IsPKOnly())
+
+	rowCount := func() int64 {
+		count, err := table.Data.GetRowCount()
+		require.NoError(t, err)
+		return count
+	}
+
+	for i := 0; i < 200; i++ {
+		stmt, err := (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(stmt, "INSERT INTO `pkonly` VALUES ("))
+	}
+	require.Equal(t, int64(200), rowCount())
+
+	for i := 0; i < 100; i++ {
+		before := rowCount()
+		_, err = (&ReplaceStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+		after := rowCount()
+		require.True(t, after == before || after == before+1)
+
+		// Updates on a PK-only table rewrite an existing row, so the row count never changes
+		before = after
+		stmt, err := (&UpdateStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(stmt, "REPLACE INTO `pkonly` VALUES ("))
+		require.Equal(t, before, rowCount())
+
+		stmt, err = (&DeleteStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(stmt, "DELETE FROM `pkonly` WHERE "))
+		require.Equal(t, before-1, rowCount())
+	}
+
+	// The cursor must return every row in primary key order, with each row consisting only of its key
+	copiedData, err := table.Data.Copy()
+	require.NoError(t, err)
+	defer copiedData.Close()
+	cursor, err := table.Data.GetRowCursor()
+	require.NoError(t, err)
+	defer cursor.Close()
+	copiedCursor, err := copiedData.GetRowCursor()
+	require.NoError(t, err)
+	defer copiedCursor.Close()
+
+	var previousRow Row
+	readRows := int64(0)
+	for {
+		row, ok, err := cursor.NextRow()
+		require.NoError(t, err)
+		copiedRow, copiedOk, err := copiedCursor.NextRow()
+		require.NoError(t, err)
+		require.Equal(t, ok, copiedOk)
+		if !ok {
+			break
+		}
+		readRows++
+		require.Len(t, row.Values, 2)
+		require.Equal(t, int32(2), row.PkColsLen)
+		require.Empty(t, row.Value())
+		require.True(t, row.Equals(copiedRow))
+		if readRows > 1 {
+			require.Equal(t, -1, previousRow.PKCompare(row))
+		}
+		previousRow = row
+	}
+	require.Equal(t, rowCount(), readRows)
+}
+
+func TestKeylessTableStatements(t *testing.T) {
+	table, err := NewTable(&Commit{}, "keyless", nil, []*Column{
+		{Name: "v1", Type: &types.TinyintInstance{}},
+		{Name: "v2", Type: &types.TinyintInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	require.True(t, table.IsKeyless())
+
+	rowCount := func() int64 {
+		count, err := table.Data.GetRowCount()
+		require.NoError(t, err)
+		return count
+	}
+
+	// Keyless tables allow duplicate rows, so every INSERT adds a row
+	for i := 0; i < 200; i++ {
+		_, err := (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(200), rowCount())
+	require.NoError(t, table.Data.Exec("INSERT INTO `keyless` VALUES (1,2),(1,2),(1,2);"))
+
+	for i := 0; i < 50; i++ {
+		before := rowCount()
+		stmt, err := (&UpdateStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(stmt, "UPDATE `keyless` SET "))
+		require.Contains(t, stmt, " WHERE `v1` = ")
+		require.Equal(t, before, rowCount())
+
+		stmt, err = (&DeleteStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(stmt, "DELETE FROM `keyless` WHERE `v1` = "))
+		require.Less(t, rowCount(), before)
+	}
+
+	// Duplicates are counted rather than collapsed
+	require.NoError(t, table.Data.Exec("DELETE FROM `keyless`; INSERT INTO `keyless` VALUES (1,2),(1,2),(3,4);"))
+	require.Equal(t, map[string]int64{"1,2": 2, "3,4": 1}, countRows(t, table.Data))
+}
+
+func TestInsertColumnList(t *testing.T) {
+	pkCols := []*Column{{Name: "pk", Type: &types.IntInstance{}}}
+	nonPKCols := []*Column{{Name: "v1", Type: &types.TinyintInstance{}}, {Name: "v2", Type: &types.IntInstance{}}}
+	table, err := NewTable(&Commit{}, "listed", pkCols, nonPKCols, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	replayed, err := NewTable(&Commit{}, "listed", pkCols, nonPKCols, nil)
+	require.NoError(t, err)
+	defer replayed.Data.Close()
+
+	// Running the MySQL statements against another table must produce the same rows, which only happens when both
+	// statements map each value to the column that it was listed with
+	insert := &InsertStatement{columnListProbability: 1}
+	for i := 0; i < 100; i++ {
+		stmt, err := insert.GenerateStatement(table)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(stmt, "INSERT INTO `listed` (`"))
+		require.NoError(t, replayed.Data.Exec(stmt))
+	}
+	equal, err := tableRowsEqual(table, replayed)
+	require.NoError(t, err)
+	require.True(t, equal)
+}
+
+func TestReplaceDeletesThenInserts(t *testing.T) {
+	table, err := NewTable(&Commit{}, "replaced", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+
+	newRow := func(pk int32, v1 int32) Row {
+		return Row{Values: []types.Value{types.IntValue{Int32Value: types.Int32Value(pk)},
+			types.IntValue{Int32Value: types.Int32Value(v1)}}, PkColsLen: 1}
+	}
+	rowID := func(pk int32) int64 {
+		var id int64
+		require.NoError(t, table.Data.namespace.QueryRow(context.Background(),
+			fmt.Sprintf("SELECT rowid FROM `replaced` WHERE `pk` = %d;", pk), &id))
+		return id
+	}
+
+	for pk := int32(1); pk <= 2; pk++ {
+		deleted, err := table.Data.Replace(newRow(pk, pk))
+		require.NoError(t, err)
+		require.False(t, deleted)
+	}
+	// An upsert would update the row in place, keeping its position, while a delete and insert gives it a new one
+	originalID := rowID(1)
+	deleted, err := table.Data.Replace(newRow(1, 10))
+	require.NoError(t, err)
+	require.True(t, deleted)
+	require.NotEqual(t, originalID, rowID(1))
+
+	count, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	rows, err := table.Data.GetAllRows()
+	require.NoError(t, err)
+	// The rows are read in the order that they were inserted, so the replaced row now comes last
+	require.True(t, rows[0].Equals(newRow(2, 2)))
+	require.True(t, rows[1].Equals(newRow(1, 10)))
+}
+
+func TestHotKeys(t *testing.T) {
+	table, err := NewTable(&Commit{}, "hot", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+
+	hotKeys := hotKeySettings{count: 2, probability: 1}
+	for i := 0; i < 50; i++ {
+		_, err = (&UpdateStatement{hotKeys: hotKeys}).GenerateStatement(table)
+		require.NoError(t, err)
+		_, err = (&ReplaceStatement{hotKeys: hotKeys}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	require.Len(t, table.hotKeys, 2)
+	// Every write reused an existing key, so no rows were added
+	count, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(20), count)
+
+	// A deleted hot row is restored by an update
+	require.NoError(t, table.Data.Exec("DELETE FROM `hot`;"))
+	_, err = (&UpdateStatement{hotKeys: hotKeys}).GenerateStatement(table)
+	require.NoError(t, err)
+	count, err = table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestInsertEnumNames(t *testing.T) {
+	enumType, err := (&types.Enum{
+		Collations:        []string{"utf8mb4_0900_ai_ci"},
+		ElementNameLength: ranges.NewInt([]int64{2, 8}),
+		NumberOfElements:  ranges.NewInt([]int64{5, 5}),
+	}).Instance()
+	require.NoError(t, err)
+	pkCols := []*Column{{Name: "pk", Type: &types.IntInstance{}}}
+	nonPKCols := []*Column{{Name: "v1", Type: enumType}}
+	table, err := NewTable(&Commit{}, "enums", pkCols, nonPKCols, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+
+	// Every element's name is unique, so the statement must name the element that the internal data holds the index of
+	insert := &InsertStatement{enumNameProbability: 1}
+	for i := 0; i < 20; i++ {
+		stmt, err := insert.GenerateStatement(table)
+		require.NoError(t, err)
+		pk := stmt[strings.Index(stmt, "(")+1 : strings.Index(stmt, ",")]
+		rows, err := table.Data.GetAllRows()
+		require.NoError(t, err)
+		var enumVal types.EnumValue
+		for _, row := range rows {
+			if row.Values[0].MySQLString() == pk {
+				enumVal = row.Values[1].(types.EnumValue)
+			}
+		}
+		require.Equal(t, fmt.Sprintf("INSERT INTO `enums` VALUES (%s,%s);", pk, enumVal.ElementName()), stmt)
+		require.NotEqual(t, enumVal.String(), enumVal.ElementName())
+	}
+}
+
+func TestPreparedInsertStatement(t *testing.T) {
+	table, err := NewTable(&Commit{}, "prepared", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+
+	insert := &PreparedInsertStatement{}
+	for i := 0; i < 50; i++ {
+		prepared, err := insert.GeneratePrepared(table)
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO `prepared` VALUES (?,?);", prepared.Query)
+		require.Len(t, prepared.Args, 2)
+		literals := make([]string, len(prepared.Args))
+		for j, arg := range prepared.Args {
+			literals[j] = arg.MySQLString()
+		}
+		require.Equal(t, fmt.Sprintf("INSERT INTO `prepared` VALUES (%s);", strings.Join(literals, ",")), prepared.Literal)
+	}
+	count, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(50), count)
+}
+
+func TestKeyCollisions(t *testing.T) {
+	table, err := NewTable(&Commit{}, "collide", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	replayed, err := NewTable(&Commit{}, "collide", table.PKCols, table.NonPKCols, nil)
+	require.NoError(t, err)
+	defer replayed.Data.Close()
+
+	// An empty table has nothing to collide with, so the INSERT writes a new row
+	insert := &InsertStatement{keyCollisionProbability: 1}
+	stmt, err := insert.GenerateStatement(table)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(stmt, "INSERT INTO `collide` VALUES ("))
+	require.NoError(t, replayed.Data.Exec(stmt))
+
+	for i := 0; i < 50; i++ {
+		stmt, err = insert.GenerateStatement(table)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(stmt, "INSERT IGNORE INTO `collide` VALUES ("))
+		require.NoError(t, replayed.Data.Exec(strings.Replace(stmt, "INSERT IGNORE", "INSERT OR IGNORE", 1)))
+		stmt, err = (&ReplaceStatement{keyCollisionProbability: 1}).GenerateStatement(table)
+		require.NoError(t, err)
+		require.NoError(t, replayed.Data.Exec(stmt))
+	}
+	// Every write reused the only key, so no rows were added
+	count, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	equal, err := tableRowsEqual(table, replayed)
+	require.NoError(t, err)
+	require.True(t, equal)
+}
@@ -0,0 +1,197 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/utils"
+)
+
+// StatusManager periodically compares the tables that `dolt status` reports as changed against the tables that have
+// changed in the internal data since the current branch's last commit.
+type StatusManager struct {
+	statementsSinceLastCheck int64
+}
+
+var _ HookRegistrant = (*StatusManager)(nil)
+
+// Register implements the HookRegistrant interface.
+func (m *StatusManager) Register(hooks *Hooks) {
+	hooks.CycleInitialized(m.Initialize)
+	hooks.SQLStatementPostExecution(m.Counter)
+}
+
+// Initialize resets the state of StatusManager.
+func (m *StatusManager) Initialize(c *Cycle) error {
+	m.statementsSinceLastCheck = 0
+	return nil
+}
+
+// Counter checks the status once enough statements have been run since the last check.
+func (m *StatusManager) Counter(c *Cycle, statement string) error {
+	if c.Planner.Base.Arguments.SkipValidation {
+		return nil
+	}
+	m.statementsSinceLastCheck++
+	// The status is checked once the transaction has finished, as it is not visible to the CLI until then
+	if m.statementsSinceLastCheck < c.Planner.Base.Options.StatusCheckInterval || c.InTransaction() {
+		return nil
+	}
+	m.statementsSinceLastCheck = 0
+	return ValidateStatus(c)
+}
+
+// ValidateStatus compares the tables that `dolt status` reports as changed against the tables whose schema or rows
+// differ from the current branch's last commit in the internal data.
+func ValidateStatus(c *Cycle) error {
+	out, err := c.CliQuery("status")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	doltChanged := parseStatusTables(out)
+	branch := c.GetCurrentBranch()
+	internalChanged, err := changedTables(branch.GetWorkingSet())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	var mismatches []string
+	for tableName, status := range doltChanged {
+		if _, ok := internalChanged[tableName]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("`%s` is %s according to dolt status, but is unchanged internally",
+				tableName, status))
+		}
+	}
+	for tableName, status := range internalChanged {
+		if _, ok := doltChanged[tableName]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("`%s` is %s internally, but is unchanged according to dolt status",
+				tableName, status))
+		}
+	}
+	if len(mismatches) > 0 {
+		sort.Strings(mismatches)
+		return errors.New(fmt.Sprintf("Status of branch `%s` does not match the internal data:\n%s\n\n%s",
+			branch.Name, strings.Join(mismatches, "\n"), out))
+	}
+	return nil
+}
+
+// parseStatusTables returns each table listed in the output of `dolt status`, along with its status, such as
+// "modified" or "new table". Staged and unstaged changes are treated the same.
+func parseStatusTables(out string) map[string]string {
+	tables := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		// Tables are indented by a tab, while hints such as `(use "dolt add <table>" ...)` are indented by spaces
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+		status := strings.TrimSpace(line[:colonIdx])
+		tableName := strings.TrimSpace(line[colonIdx+1:])
+		if status != "" && tableName != "" {
+			tables[tableName] = status
+		}
+	}
+	return tables
+}
+
+// changedTables returns each table of the working set that differs from the working set's parent commit, along with
+// how it differs.
+func changedTables(workingSet *Commit) (map[string]string, error) {
+	parentTables := make(map[string]*Table)
+	if len(workingSet.Parents) > 0 {
+		for _, table := range workingSet.Parents[0].Tables {
+			parentTables[table.Name] = table
+		}
+	}
+	changed := make(map[string]string)
+	for _, table := range workingSet.Tables {
+		parentTable, ok := parentTables[table.Name]
+		delete(parentTables, table.Name)
+		if !ok {
+			changed[table.Name] = "new table"
+			continue
+		}
+		if table.CreateString(false, false) != parentTable.CreateString(false, false) {
+			changed[table.Name] = "modified"
+			continue
+		}
+		sameRows, err := tableRowsEqual(table, parentTable)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if !sameRows {
+			changed[table.Name] = "modified"
+		}
+	}
+	for tableName := range parentTables {
+		changed[tableName] = "deleted"
+	}
+	return changed, nil
+}
+
+// tableRowsEqual returns whether both tables contain the same rows, regardless of order.
+func tableRowsEqual(table *Table, otherTable *Table) (bool, error) {
+	rowCount, err := table.Data.GetRowCount()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	otherRowCount, err := otherTable.Data.GetRowCount()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	if rowCount != otherRowCount {
+		return false, nil
+	}
+	counts := make(map[utils.Hash]int64)
+	for i, t := range []*Table{table, otherTable} {
+		cursor, err := t.Data.GetRowCursor()
+		if err != nil {
+			return false, errors.Wrap(err)
+		}
+		err = func() error {
+			defer cursor.Close()
+			for {
+				row, ok, err := cursor.NextRow()
+				if err != nil {
+					return errors.Wrap(err)
+				}
+				if !ok {
+					return nil
+				}
+				if i == 0 {
+					counts[row.Hash()]++
+				} else {
+					counts[row.Hash()]--
+				}
+			}
+		}()
+		if err != nil {
+			return false, errors.Wrap(err)
+		}
+	}
+	for _, count := range counts {
+		if count != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
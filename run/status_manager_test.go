@@ -0,0 +1,44 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatusTables(t *testing.T) {
+	out := "On branch main\n" +
+		"\n" +
+		"Changes to be committed:\n" +
+		"  (use \"dolt reset <table>...\" to unstage)\n" +
+		"\tdeleted:          staged_table\n" +
+		"\n" +
+		"Changes not staged for commit:\n" +
+		"  (use \"dolt add <table>\" to update what will be committed)\n" +
+		"  (use \"dolt checkout <table>\" to discard changes in working directory)\n" +
+		"\tmodified:         abc_123\n" +
+		"\n" +
+		"Untracked tables:\n" +
+		"  (use \"dolt add <table>\" to include in what will be committed)\n" +
+		"\tnew table:        Xy_z\n"
+	require.Equal(t, map[string]string{
+		"staged_table": "deleted",
+		"abc_123":      "modified",
+		"Xy_z":         "new table",
+	}, parseStatusTables(out))
+	require.Empty(t, parseStatusTables("On branch main\nnothing to commit, working tree clean"))
+}
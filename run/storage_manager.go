@@ -0,0 +1,81 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/utils/file"
+)
+
+// StorageMetrics are the sizes in bytes of a cycle's `.dolt` directory, measured at different points of the cycle.
+type StorageMetrics struct {
+	Cycle string
+	// Initialized is measured once the repository has been created, before any data has been written.
+	Initialized int64
+	// Generated is measured once all of the data has been written, before the final garbage collection.
+	Generated int64
+	// Collected is measured after the final garbage collection. This matches Generated when manual GC is disabled.
+	Collected int64
+	// Ended is measured once the cycle has ended, after any command has run.
+	Ended int64
+}
+
+// StorageManager measures the size of the `.dolt` directory throughout each cycle, which is stored on the cycle.
+type StorageManager struct{}
+
+var _ HookRegistrant = (*StorageManager)(nil)
+
+// Register implements the HookRegistrant interface. Collected is not registered, as it must be registered after the
+// GCManager.
+func (m *StorageManager) Register(hooks *Hooks) {
+	hooks.CycleStarted(m.Initialized)
+	hooks.RepositoryFinished(m.Generated)
+	hooks.CycleEnded(m.Ended)
+}
+
+// Initialized measures the size of the newly created repository.
+func (m *StorageManager) Initialized(c *Cycle) error {
+	c.Storage = &StorageMetrics{Cycle: c.Name}
+	return m.measure(c, &c.Storage.Initialized)
+}
+
+// Generated measures the size of the repository once all of the data has been written.
+func (m *StorageManager) Generated(c *Cycle) error {
+	return m.measure(c, &c.Storage.Generated)
+}
+
+// Collected measures the size of the repository after the final garbage collection.
+func (m *StorageManager) Collected(c *Cycle) error {
+	return m.measure(c, &c.Storage.Collected)
+}
+
+// Ended measures the size of the repository at the end of the cycle. The repository may not have been fully generated
+// if the cycle failed, in which case the cycle's metrics are left as they are.
+func (m *StorageManager) Ended(c *Cycle) error {
+	if c.Storage == nil {
+		return nil
+	}
+	return m.measure(c, &c.Storage.Ended)
+}
+
+// measure writes the current size of the `.dolt` directory to the given destination.
+func (m *StorageManager) measure(c *Cycle, dest *int64) error {
+	size, err := file.DirSize(c.Planner.Base.Arguments.RepoWorkingPath + c.Name + "/.dolt")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	*dest = size
+	return nil
+}
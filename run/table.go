@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -34,6 +35,9 @@ type Table struct {
 	NonPKCols []*Column
 	Indexes   []*Index
 	Data      *TableData
+	// hotKeys are the primary keys that UPDATE and REPLACE statements concentrate their writes on, which are recorded
+	// as they are first needed.
+	hotKeys [][]types.Value
 }
 
 // DoltDataCursor returns a Dolt repository's data, one row at a time.
@@ -53,7 +57,7 @@ func NewTable(parent *Commit, name string, pkCols []*Column, nonPKCols []*Column
 		Indexes:   indexes,
 	}
 	var err error
-	table.Data, err = CreateTableData(name, table.CreateString(false, true), pkCols, nonPKCols)
+	table.Data, err = CreateTableData(name, pkCols, nonPKCols)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -63,35 +67,57 @@ func NewTable(parent *Commit, name string, pkCols []*Column, nonPKCols []*Column
 // CreateString returns the table as a `CREATE TABLE` string. Setting `columnOnly` to true leaves only the column name,
 // type, and primary key. Setting `sqlite` to true removes collations and other MySQL-specific strings that SQLite fails on.
 func (t *Table) CreateString(columnOnly bool, sqlite bool) string {
-	needComma := false
 	sb := strings.Builder{}
 	sb.Grow(512)
+	writeCreateTableColumns(&sb, t.Name, t.PKCols, t.NonPKCols, func(col *Column) string {
+		return col.Definition(sqlite)
+	})
+	if !columnOnly {
+		if !sqlite {
+			// SQLite does not support inline index definitions, and it does not need them since it only holds the data
+			for _, index := range t.Indexes {
+				sb.WriteString(", ")
+				sb.WriteString(index.String())
+			}
+			for _, fk := range t.Parent.ForeignKeys {
+				if fk.TableName != t.Name {
+					continue
+				}
+				sb.WriteString(", ")
+				sb.WriteString(fk.String())
+			}
+		}
+	}
+	sb.WriteString(");")
+	return sb.String()
+}
+
+// writeCreateTableColumns writes the start of a CREATE TABLE statement, containing each column's definition (as returned
+// by the given function) followed by the primary key. The column list is left open, so that any remaining definitions
+// may be written before it is closed.
+func writeCreateTableColumns(sb *strings.Builder, name string, pkCols []*Column, nonPKCols []*Column,
+	definition func(*Column) string) {
+	needComma := false
 	sb.WriteString("CREATE TABLE `")
-	sb.WriteString(t.Name)
+	sb.WriteString(name)
 	sb.WriteString("` (")
-	for _, col := range t.PKCols {
+	for _, col := range pkCols {
 		if needComma {
 			sb.WriteString(", ")
 		}
 		needComma = true
-		sb.WriteRune('`')
-		sb.WriteString(col.Name)
-		sb.WriteString("` ")
-		sb.WriteString(col.Type.Name(sqlite))
+		sb.WriteString(definition(col))
 	}
-	for _, col := range t.NonPKCols {
+	for _, col := range nonPKCols {
 		if needComma {
 			sb.WriteString(", ")
 		}
 		needComma = true
-		sb.WriteRune('`')
-		sb.WriteString(col.Name)
-		sb.WriteString("` ")
-		sb.WriteString(col.Type.Name(sqlite))
+		sb.WriteString(definition(col))
 	}
-	if len(t.PKCols) > 0 {
+	if len(pkCols) > 0 {
 		sb.WriteString(", PRIMARY KEY (")
-		for i, col := range t.PKCols {
+		for i, col := range pkCols {
 			if i > 0 {
 				sb.WriteString(", ")
 			}
@@ -101,23 +127,6 @@ func (t *Table) CreateString(columnOnly bool, sqlite bool) string {
 		}
 		sb.WriteRune(')')
 	}
-	if !columnOnly {
-		for _, index := range t.Indexes {
-			sb.WriteString(", ")
-			sb.WriteString(index.String())
-		}
-		if !sqlite {
-			for _, fk := range t.Parent.ForeignKeys {
-				if fk.TableName != t.Name {
-					continue
-				}
-				sb.WriteString(", ")
-				sb.WriteString(fk.String())
-			}
-		}
-	}
-	sb.WriteString(");")
-	return sb.String()
 }
 
 // DoltTableHasConflicts returns whether the Dolt table has any conflicts.
@@ -135,6 +144,185 @@ func (t *Table) GetDoltCursor(c *Cycle) (*DoltDataCursor, error) {
 	if err != nil {
 		return nil, err
 	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), t.orderedSelect())
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &DoltDataCursor{
+		rows:     outRows,
+		template: t.Data.ConstructTemplateRow(),
+		once:     &sync.Once{},
+	}, nil
+}
+
+// GetDoltOutputFormatRows returns every row of the table as read by `dolt sql -q` using the given result format, which
+// must be one of the formats in OutputFormats. Rows are ordered by the primary key (or by every column for keyless
+// tables), and each value is written exactly as the format rendered it, with a nil value for NULL.
+func (t *Table) GetDoltOutputFormatRows(c *Cycle, format OutputFormat) ([][]*string, error) {
+	out, err := c.CliQuery("sql", "-q", t.fullyOrderedSelect(), "-r", string(format))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	rows, err := parseOutputFormat(format, out, t.ColumnNames())
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to parse the %s output of table `%s`: %s", format, t.Name, err.Error()))
+	}
+	return rows, nil
+}
+
+// GetDoltServerStringRows returns every row of the table as read over the server connection, using the same query as
+// GetDoltOutputFormatRows. Rows are ordered by the primary key (or by every column for keyless tables), and each value
+// is the text that the server sent, with a nil value for NULL.
+func (t *Table) GetDoltServerStringRows(c *Cycle) ([][]*string, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), t.fullyOrderedSelect())
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer outRows.Close()
+	columnCount := len(t.PKCols) + len(t.NonPKCols)
+	var rows [][]*string
+	for outRows.Next() {
+		values := make([]sql.NullString, columnCount)
+		scanned := make([]interface{}, columnCount)
+		for i := range values {
+			scanned[i] = &values[i]
+		}
+		if err = outRows.Scan(scanned...); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		row := make([]*string, columnCount)
+		for i := range values {
+			if values[i].Valid {
+				row[i] = &values[i].String
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err = outRows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return rows, nil
+}
+
+// GetDoltSessionCursor returns a cursor over Dolt's stored table data, as seen by the given session. Unlike
+// GetDoltCursor, the rows are read within the session's transaction, if it has one open.
+func (t *Table) GetDoltSessionCursor(session *sql.Conn) (*DoltDataCursor, error) {
+	outRows, err := session.QueryContext(context.Background(), t.orderedSelect())
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &DoltDataCursor{
+		rows:     outRows,
+		template: t.Data.ConstructTemplateRow(),
+		once:     &sync.Once{},
+	}, nil
+}
+
+// GetDoltPageCursor returns a cursor over a single page of Dolt's stored table data, ordered by the primary key. The
+// page holds at most limit rows, beginning after the first offset rows.
+func (t *Table) GetDoltPageCursor(c *Cycle, limit uint64, offset uint64) (*DoltDataCursor, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), t.orderedPageSelect(limit, offset))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &DoltDataCursor{
+		rows:     outRows,
+		template: t.Data.ConstructTemplateRow(),
+		once:     &sync.Once{},
+	}, nil
+}
+
+// orderedSelect returns a query that selects every row of the table, ordered by the primary key.
+func (t *Table) orderedSelect() string {
+	return fmt.Sprintf("SELECT %s FROM `%s`%s;", t.SelectColumns(), t.Name, t.orderByPrimaryKey())
+}
+
+// fullyOrderedSelect returns a query that selects every row of the table in the same order each time that it is run,
+// so that separate reads of the same rows may be compared by position. Rows are ordered by the primary key, while
+// keyless tables are ordered by every column. This leaves only the rows that compare as equal on every column in the
+// order that Dolt stores them, such as rows that differ only by case under a case-insensitive collation.
+func (t *Table) fullyOrderedSelect() string {
+	if !t.IsKeyless() {
+		return t.orderedSelect()
+	}
+	orderBy := make([]string, len(t.PKCols)+len(t.NonPKCols))
+	for i := range orderBy {
+		orderBy[i] = strconv.Itoa(i + 1)
+	}
+	return fmt.Sprintf("SELECT %s FROM `%s` ORDER BY %s;", t.SelectColumns(), t.Name, strings.Join(orderBy, ", "))
+}
+
+// orderedPageSelect returns a query that selects a single page of the table's rows, ordered by the primary key.
+func (t *Table) orderedPageSelect(limit uint64, offset uint64) string {
+	return fmt.Sprintf("SELECT %s FROM `%s`%s LIMIT %d OFFSET %d;", t.SelectColumns(), t.Name, t.orderByPrimaryKey(),
+		limit, offset)
+}
+
+// HasInvisibleColumns returns whether any of the table's columns are invisible.
+func (t *Table) HasInvisibleColumns() bool {
+	for _, col := range t.NonPKCols {
+		if col.Invisible {
+			return true
+		}
+	}
+	for _, col := range t.PKCols {
+		if col.Invisible {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectColumns returns the columns to select when reading every column of the table from Dolt. This is `*` unless the
+// table has an invisible column, as `SELECT *` would exclude it, in which case every column is listed in order.
+func (t *Table) SelectColumns() string {
+	if !t.HasInvisibleColumns() {
+		return "*"
+	}
+	names := t.ColumnNames()
+	for i := range names {
+		names[i] = fmt.Sprintf("`%s`", names[i])
+	}
+	return strings.Join(names, ",")
+}
+
+// valuesColumnList returns the column list of an INSERT or REPLACE that gives a value for every column in the table's
+// order, including a leading space. The values of a statement without a column list only cover the visible columns,
+// so the list is empty unless the table has an invisible column.
+func (t *Table) valuesColumnList() string {
+	if !t.HasInvisibleColumns() {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", t.SelectColumns())
+}
+
+// VisibleColumnNames returns the names of the columns that are returned by `SELECT *`, in order.
+func (t *Table) VisibleColumnNames() []string {
+	names := make([]string, 0, len(t.PKCols)+len(t.NonPKCols))
+	for _, col := range t.PKCols {
+		if !col.Invisible {
+			names = append(names, col.Name)
+		}
+	}
+	for _, col := range t.NonPKCols {
+		if !col.Invisible {
+			names = append(names, col.Name)
+		}
+	}
+	return names
+}
+
+// orderByPrimaryKey returns the ORDER BY clause that orders rows by the primary key, including a leading space. Returns
+// an empty string for keyless tables.
+func (t *Table) orderByPrimaryKey() string {
 	orderBy := ""
 	for i := 1; i <= len(t.PKCols); i++ {
 		if i == 1 {
@@ -143,14 +331,124 @@ func (t *Table) GetDoltCursor(c *Cycle) (*DoltDataCursor, error) {
 			orderBy += fmt.Sprintf(", %d", i)
 		}
 	}
-	outRows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s`%s;", t.Name, orderBy))
+	return orderBy
+}
+
+// GetDoltIndexLookupCursor returns a cursor over Dolt's stored table data, limited to the rows that match the given
+// row on the columns of the given index. This is intended to have Dolt use the index for the lookup, which includes
+// filtering prefix matches down to the full value for prefix indexes. Floating-point columns are not matched against,
+// as their literals do not round-trip exactly. Returns false if none of the index's columns may be matched against.
+func (t *Table) GetDoltIndexLookupCursor(c *Cycle, index *Index, row Row) (*DoltDataCursor, bool, error) {
+	var wheres []string
+	for _, colName := range index.Columns {
+		colIdx, col := t.getColumn(colName)
+		if col == nil {
+			return nil, false, errors.New(fmt.Sprintf("index `%s` on table `%s` references missing column `%s`",
+				index.Name, t.Name, colName))
+		}
+		switch col.Type.(type) {
+		case *types.FloatInstance, *types.DoubleInstance:
+			continue
+		}
+		wheres = append(wheres, fmt.Sprintf("`%s` <=> %s", colName, row.Values[colIdx].MySQLString()))
+	}
+	if len(wheres) == 0 {
+		return nil, false, nil
+	}
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
 	if err != nil {
-		return nil, errors.Wrap(err)
+		return nil, false, errors.Wrap(err)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(),
+		fmt.Sprintf("SELECT %s FROM `%s` WHERE %s;", t.SelectColumns(), t.Name, strings.Join(wheres, " AND ")))
+	if err != nil {
+		return nil, false, errors.Wrap(err)
 	}
 	return &DoltDataCursor{
 		rows:     outRows,
 		template: t.Data.ConstructTemplateRow(),
 		once:     &sync.Once{},
+	}, true, nil
+}
+
+// IndexOrderQuery returns a query that selects the columns of the given index, ordered in the same direction as each
+// column of the index, along with a template row for the selected columns. A column that uses a prefix only selects and
+// orders by its prefix, as that is all that the index holds. As only the index's columns are selected, and strings that
+// are equal under their collation are further ordered by their bytes, rows that tie on every column are
+// indistinguishable, so the results are fully deterministic. The Dolt query forces the use of the index, while the
+// internal query is otherwise identical, as the internal data does not have the index. This returns false when any
+// column cannot be ordered identically by both Dolt and the internal data. SQLite orders strings by their bytes, so
+// prefixes are only ordered by a MySQL Oracle.
+func (t *Table) IndexOrderQuery(index *Index) (string, string, Row, bool) {
+	sqlite := t.Data.SQLite()
+	cols := make([]string, len(index.Columns))
+	orderBys := make([]string, 0, len(index.Columns))
+	template := Row{Values: make([]types.Value, len(index.Columns))}
+	for i, colName := range index.Columns {
+		_, col := t.getColumn(colName)
+		prefixLength := int64(0)
+		if i < len(index.PrefixLengths) {
+			prefixLength = index.PrefixLengths[i]
+		}
+		if col == nil || !(isOrderableColumn(col, sqlite) || (prefixLength > 0 && !sqlite)) {
+			return "", "", Row{}, false
+		}
+		cols[i] = fmt.Sprintf("`%s`", colName)
+		if prefixLength > 0 {
+			cols[i] = fmt.Sprintf("LEFT(`%s`, %d)", colName, prefixLength)
+		}
+		direction := ""
+		if i < len(index.Descending) && index.Descending[i] {
+			direction = " DESC"
+		}
+		orderBys = append(orderBys, cols[i]+direction)
+		if _, ok := col.Type.(types.PrefixableTypeInstance); ok && !sqlite {
+			orderBys = append(orderBys, fmt.Sprintf("CAST(%s AS BINARY)%s", cols[i], direction))
+		}
+		template.Values[i] = col.Type.TypeValue()
+	}
+	doltQuery := fmt.Sprintf("SELECT %s FROM `%s` FORCE INDEX (`%s`) ORDER BY %s;",
+		strings.Join(cols, ","), t.Name, index.Name, strings.Join(orderBys, ","))
+	internalQuery := fmt.Sprintf("SELECT %s FROM `%s` ORDER BY %s;",
+		strings.Join(cols, ","), t.Name, strings.Join(orderBys, ","))
+	return doltQuery, internalQuery, template, true
+}
+
+// isOrderableColumn returns whether the column's values are ordered identically by Dolt and the internal data. SQLite
+// stores many types as strings, so only integers, along with the types whose strings sort in the same order as their
+// values, are orderable there. A MySQL Oracle orders every type the same way as Dolt, except for the types that have no
+// meaningful order, or that may only be indexed using a prefix.
+func isOrderableColumn(col *Column, sqlite bool) bool {
+	switch col.Type.(type) {
+	case *types.TinyintInstance, *types.SmallintInstance, *types.MediumintInstance, *types.IntInstance,
+		*types.BigintInstance, *types.TinyintUnsignedInstance, *types.SmallintUnsignedInstance,
+		*types.MediumintUnsignedInstance, *types.IntUnsignedInstance, *types.YearInstance, *types.TimeInstance,
+		*types.DateInstance, *types.DatetimeInstance, *types.TimestampInstance, *types.EnumInstance:
+		return true
+	case *types.BigintUnsignedInstance, *types.DecimalInstance, *types.FloatInstance, *types.DoubleInstance,
+		*types.BitInstance, *types.CharInstance, *types.VarcharInstance, *types.BinaryInstance,
+		*types.VarbinaryInstance, *types.SetInstance:
+		return !sqlite
+	default:
+		return false
+	}
+}
+
+// GetDoltProjectionCursor returns a cursor over the results of the given query from Dolt, which may select any
+// columns. The template must have a value for each selected column, in the same order.
+func (t *Table) GetDoltProjectionCursor(c *Cycle, query string, template Row) (*DoltDataCursor, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &DoltDataCursor{
+		rows:     outRows,
+		template: template,
+		once:     &sync.Once{},
 	}, nil
 }
 
@@ -234,9 +532,333 @@ func (t *Table) Copy() (*Table, error) {
 		NonPKCols: nonPKCols,
 		Indexes:   indexes,
 		Data:      newData,
+		hotKeys:   append([][]types.Value{}, t.hotKeys...),
 	}, nil
 }
 
+// ColumnNames returns the names of all columns, with the primary key columns first, matching the order of a row.
+func (t *Table) ColumnNames() []string {
+	names := make([]string, 0, len(t.PKCols)+len(t.NonPKCols))
+	for _, col := range t.PKCols {
+		names = append(names, col.Name)
+	}
+	for _, col := range t.NonPKCols {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// RenameColumn renames the given column, which includes updating the indexes, foreign keys, and data that reference
+// the column.
+func (t *Table) RenameColumn(oldName string, newName string) error {
+	_, col := t.getColumn(oldName)
+	if col == nil {
+		return errors.New(fmt.Sprintf("cannot rename missing column `%s` on table `%s`", oldName, t.Name))
+	}
+	err := t.Data.RenameColumn(oldName, newName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// The data may share its columns with the table, so the column may have already been renamed
+	col.Name = newName
+	for _, index := range t.Indexes {
+		for i := range index.Columns {
+			if index.Columns[i] == oldName {
+				index.Columns[i] = newName
+			}
+		}
+	}
+	for _, fk := range t.Parent.ForeignKeys {
+		if fk.TableName == t.Name {
+			for i := range fk.TableCols {
+				if fk.TableCols[i] == oldName {
+					fk.TableCols[i] = newName
+				}
+			}
+		}
+		if fk.ReferencedTableName == t.Name {
+			for i := range fk.ReferencedTableCols {
+				if fk.ReferencedTableCols[i] == oldName {
+					fk.ReferencedTableCols[i] = newName
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// AddColumn adds the given non-primary key column after all other columns. Existing rows are set to the given value.
+func (t *Table) AddColumn(col *Column, defaultValue types.Value) error {
+	if _, existing := t.getColumn(col.Name); existing != nil {
+		return errors.New(fmt.Sprintf("cannot add column `%s` to table `%s` as it already exists", col.Name, t.Name))
+	}
+	err := t.Data.AddColumn(col, defaultValue)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// The columns may be shared with the data, so we create a new slice rather than appending in place
+	nonPKCols := make([]*Column, len(t.NonPKCols), len(t.NonPKCols)+1)
+	copy(nonPKCols, t.NonPKCols)
+	t.NonPKCols = append(nonPKCols, col)
+	return nil
+}
+
+// ModifyColumn changes the type of the given column, converting all existing values to the new type. The new type must
+// be able to hold every value of the current type.
+func (t *Table) ModifyColumn(name string, newType types.TypeInstance) error {
+	colIdx, col := t.getColumn(name)
+	if col == nil {
+		return errors.New(fmt.Sprintf("cannot modify missing column `%s` on table `%s`", name, t.Name))
+	}
+	err := t.Data.ModifyColumn(name, newType)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// The data may share its columns with the table, so the column may have already been modified
+	col.Type = newType
+	// Hot keys hold values of the old type, so they are recorded again using the new type
+	if colIdx < len(t.PKCols) {
+		t.hotKeys = nil
+	}
+	return nil
+}
+
+// DropColumn drops the given non-primary key column. Columns that are referenced by an index or foreign key cannot be
+// dropped.
+func (t *Table) DropColumn(name string) error {
+	if !t.CanDropColumn(name) {
+		return errors.New(fmt.Sprintf("cannot drop column `%s` from table `%s`", name, t.Name))
+	}
+	err := t.Data.DropColumn(name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	t.NonPKCols = removeColumn(t.NonPKCols, name)
+	return nil
+}
+
+// AddPrimaryKey makes the first pkCount columns of a keyless table its primary key. The existing rows must not hold
+// any NULLs or duplicates within those columns.
+func (t *Table) AddPrimaryKey(pkCount int) error {
+	if !t.IsKeyless() {
+		return errors.New(fmt.Sprintf("cannot add a primary key to table `%s` as it already has one", t.Name))
+	}
+	return t.setPrimaryKey(pkCount)
+}
+
+// DropPrimaryKey removes the primary key, leaving a keyless table. Every primary key column becomes a non-primary key
+// column, keeping its position.
+func (t *Table) DropPrimaryKey() error {
+	if t.IsKeyless() {
+		return errors.New(fmt.Sprintf("cannot drop the primary key of table `%s` as it does not have one", t.Name))
+	}
+	return t.setPrimaryKey(0)
+}
+
+// setPrimaryKey makes the first pkCount columns the primary key, for both the table and its data.
+func (t *Table) setPrimaryKey(pkCount int) error {
+	err := t.Data.SetPrimaryKey(pkCount)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// The columns may be shared with the data, so we create new slices rather than reslicing in place
+	allCols := append(append([]*Column{}, t.PKCols...), t.NonPKCols...)
+	t.PKCols = append([]*Column{}, allCols[:pkCount]...)
+	t.NonPKCols = append([]*Column{}, allCols[pkCount:]...)
+	t.hotKeys = nil
+	return nil
+}
+
+// CanDropColumn returns whether the given column may be dropped. Only non-primary key columns that are not referenced
+// by any indexes or foreign keys may be dropped.
+func (t *Table) CanDropColumn(name string) bool {
+	colIdx, col := t.getColumn(name)
+	if col == nil || colIdx < len(t.PKCols) {
+		return false
+	}
+	// A table must always have at least one visible column
+	if !col.Invisible && len(t.VisibleColumnNames()) == 1 {
+		return false
+	}
+	for _, index := range t.Indexes {
+		for _, indexCol := range index.Columns {
+			if indexCol == name {
+				return false
+			}
+		}
+	}
+	for _, fk := range t.Parent.ForeignKeys {
+		if fk.TableName == t.Name {
+			for _, fkCol := range fk.TableCols {
+				if fkCol == name {
+					return false
+				}
+			}
+		}
+		if fk.ReferencedTableName == t.Name {
+			for _, fkCol := range fk.ReferencedTableCols {
+				if fkCol == name {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// isIndexed returns whether the given column is a primary key column, or is referenced by any index.
+func (t *Table) isIndexed(name string) bool {
+	colIdx, col := t.getColumn(name)
+	if col != nil && colIdx < len(t.PKCols) {
+		return true
+	}
+	for _, index := range t.Indexes {
+		for _, indexCol := range index.Columns {
+			if indexCol == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasForeignKeys returns whether the table has a foreign key, or is referenced by a foreign key.
+func (t *Table) HasForeignKeys() bool {
+	for _, fk := range t.Parent.ForeignKeys {
+		if fk.TableName == t.Name || fk.ReferencedTableName == t.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDoltColumnNames returns the names of the columns of Dolt's table that are returned by `SELECT *`, in the order
+// that they are returned in a row. Invisible columns are not included.
+func (t *Table) GetDoltColumnNames(c *Cycle) ([]string, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s` LIMIT 0;", t.Name))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer outRows.Close()
+	colNames, err := outRows.Columns()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return colNames, nil
+}
+
+// GetDoltIndexes returns the columns of each index that Dolt reports for this table using `SHOW INDEX`, keyed by the
+// lowercase index name. The columns are in the order that they appear in the index. The primary key is included.
+func (t *Table) GetDoltIndexes(c *Cycle) (map[string][]string, error) {
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	outRows, err := dc.Conn.QueryContext(context.Background(), fmt.Sprintf("SHOW INDEX FROM `%s`;", t.Name))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	defer outRows.Close()
+	colNames, err := outRows.Columns()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	keyNameIdx, seqIdx, colNameIdx := -1, -1, -1
+	for i, colName := range colNames {
+		switch strings.ToLower(colName) {
+		case "key_name":
+			keyNameIdx = i
+		case "seq_in_index":
+			seqIdx = i
+		case "column_name":
+			colNameIdx = i
+		}
+	}
+	if keyNameIdx == -1 || seqIdx == -1 || colNameIdx == -1 {
+		return nil, errors.New(fmt.Sprintf("SHOW INDEX on table `%s` returned unexpected columns: [%s]",
+			t.Name, strings.Join(colNames, ",")))
+	}
+	vals := make([]sql.RawBytes, len(colNames))
+	scanVals := make([]interface{}, len(colNames))
+	for i := range vals {
+		scanVals[i] = &vals[i]
+	}
+	indexes := make(map[string][]string)
+	for outRows.Next() {
+		if err = outRows.Scan(scanVals...); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		seq, err := strconv.Atoi(string(vals[seqIdx]))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if seq < 1 {
+			return nil, errors.New(fmt.Sprintf("SHOW INDEX on table `%s` returned an invalid Seq_in_index: %d", t.Name, seq))
+		}
+		keyName := strings.ToLower(string(vals[keyNameIdx]))
+		cols := indexes[keyName]
+		for len(cols) < seq {
+			cols = append(cols, "")
+		}
+		cols[seq-1] = string(vals[colNameIdx])
+		indexes[keyName] = cols
+	}
+	if err = outRows.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return indexes, nil
+}
+
+// IsPKOnly returns whether the table has a primary key and no other columns.
+func (t *Table) IsPKOnly() bool {
+	return len(t.PKCols) > 0 && len(t.NonPKCols) == 0
+}
+
+// IsKeyless returns whether the table has no primary key. Such tables may hold duplicate rows, so a row is identified by
+// all of its values rather than by a key.
+func (t *Table) IsKeyless() bool {
+	return len(t.PKCols) == 0
+}
+
+// identifyingColumns returns the columns and values that are used to find the given row in a WHERE clause. This is the
+// primary key for most tables. Keyless tables instead use their integer columns, as other types are not compared the
+// same way by MySQL and SQLite, so every duplicate of the row (and any row sharing those values) is matched by both.
+// Returns no columns for a keyless table without any integer columns.
+func (t *Table) identifyingColumns(row Row) ([]*Column, []types.Value) {
+	if !t.IsKeyless() {
+		return t.PKCols, row.Key()
+	}
+	var cols []*Column
+	var vals []types.Value
+	for i, col := range t.NonPKCols {
+		if isQueryableColumn(col) {
+			cols = append(cols, col)
+			vals = append(vals, row.Values[i])
+		}
+	}
+	return cols, vals
+}
+
+// getColumn returns the column with the given name, along with its position in a row. Returns a nil column if the
+// name could not be found.
+func (t *Table) getColumn(name string) (int, *Column) {
+	for i, col := range t.PKCols {
+		if col.Name == name {
+			return i, col
+		}
+	}
+	for i, col := range t.NonPKCols {
+		if col.Name == name {
+			return len(t.PKCols) + i, col
+		}
+	}
+	return -1, nil
+}
+
 // NextRow returns the next row from the cursor. If there are no more rows to return, returns false.
 func (ddc *DoltDataCursor) NextRow() (Row, bool, error) {
 	if ddc.rows.Next() {
@@ -272,12 +894,41 @@ type Column struct {
 	//TODO: allow some non-pk columns to be non-nullable
 	Name string
 	Type types.TypeInstance
+	// Invisible is whether the column is declared INVISIBLE, which excludes it from `SELECT *`.
+	Invisible bool
 }
 
 // Copy returns a deep copy of the column.
 func (c *Column) Copy() *Column {
 	return &Column{
-		Name: c.Name,
-		Type: c.Type,
+		Name:      c.Name,
+		Type:      c.Type,
+		Invisible: c.Invisible,
+	}
+}
+
+// Definition returns the column's definition as it is written in a CREATE TABLE or ALTER TABLE statement. SQLite does
+// not support invisible columns, so they are only declared for MySQL.
+func (c *Column) Definition(sqlite bool) string {
+	if c.Invisible && !sqlite {
+		return c.dataDefinition(sqlite) + " INVISIBLE"
+	}
+	return c.dataDefinition(sqlite)
+}
+
+// dataDefinition returns the column's definition as it is written for the internal data. Columns are never invisible
+// there, as the internal data must return every column from `SELECT *`.
+func (c *Column) dataDefinition(sqlite bool) string {
+	return fmt.Sprintf("`%s` %s", c.Name, c.Type.Name(sqlite))
+}
+
+// removeColumn returns a new slice containing all of the given columns except the one with the given name.
+func removeColumn(cols []*Column, name string) []*Column {
+	newCols := make([]*Column, 0, len(cols))
+	for _, col := range cols {
+		if col.Name != name {
+			newCols = append(newCols, col)
+		}
 	}
+	return newCols
 }
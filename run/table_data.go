@@ -19,30 +19,21 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
-
-	_ "github.com/dolthub/go-sqlite3"
+	"strings"
 
 	"github.com/dolthub/fuzzer/errors"
 	"github.com/dolthub/fuzzer/rand"
 	"github.com/dolthub/fuzzer/types"
 )
 
-var sqliteDb *sql.DB
-
-func init() {
-	var err error
-	sqliteDb, err = sql.Open("sqlite3", ":memory:")
-	if err != nil {
-		panic(err)
-	}
-}
-
-// TableData represents a table's data, along with its index data.
+// TableData represents a table's data, along with its index data. The data is held by the Oracle that was set when the
+// table data was created, and all statements given to the table data must use that Oracle's dialect.
 type TableData struct {
-	tableName  string
-	pkCols     []*Column
-	nonPKCols  []*Column
-	connection *sql.Conn
+	tableName string
+	pkCols    []*Column
+	nonPKCols []*Column
+	oracle    Oracle
+	namespace Namespace
 }
 
 // TableDataCursor returns a table's data, one row at a time.
@@ -52,26 +43,249 @@ type TableDataCursor struct {
 	td       *TableData
 }
 
-// CreateTableData creates a new TableData and returns it.
-func CreateTableData(tableName, createTableStatement string, pkCols, nonPKCols []*Column) (*TableData, error) {
-	conn, err := sqliteDb.Conn(context.Background())
+// CreateTableData creates a new TableData using the current Oracle and returns it.
+func CreateTableData(tableName string, pkCols, nonPKCols []*Column) (*TableData, error) {
+	namespace, err := oracle.Open(context.Background())
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	_, err = conn.ExecContext(context.Background(), createTableStatement)
+	td := &TableData{tableName, pkCols, nonPKCols, oracle, namespace}
+	err = td.Exec(td.createTableStatement())
 	if err != nil {
-		_ = conn.Close()
+		namespace.Release()
 		return nil, errors.Wrap(err)
 	}
-	return &TableData{tableName, pkCols, nonPKCols, conn}, nil
+	return td, nil
 }
 
 // Exec executes the given statement.
 func (td *TableData) Exec(statement string) error {
-	_, err := td.connection.ExecContext(context.Background(), statement)
+	_, err := td.namespace.ExecContext(context.Background(), statement)
 	return err
 }
 
+// SQLite returns whether statements must be written in SQLite's dialect. Otherwise, they must be written in MySQL's.
+func (td *TableData) SQLite() bool {
+	return td.oracle.SQLite()
+}
+
+// Dialect returns whichever of the given statements matches the dialect of the Oracle.
+func (td *TableData) Dialect(mysql string, sqlite string) string {
+	if td.oracle.SQLite() {
+		return sqlite
+	}
+	return mysql
+}
+
+// IsConstraintError returns whether the error, as returned from Exec, is due to a row violating a constraint.
+func (td *TableData) IsConstraintError(err error) bool {
+	return td.oracle.IsConstraintError(err)
+}
+
+// RowString returns the row's values as a comma-separated list of literals, using the dialect of the Oracle.
+func (td *TableData) RowString(row Row) string {
+	return td.Dialect(row.MySQLString(), row.SQLiteString())
+}
+
+// ColumnEquals is equivalent to GenerateColumnEquals, except that the literals use the dialect of the Oracle.
+func (td *TableData) ColumnEquals(colNames []*Column, vals []types.Value) ([]string, error) {
+	if td.oracle.SQLite() {
+		return GenerateColumnEqualsSQLite(colNames, vals)
+	}
+	return GenerateColumnEquals(colNames, vals)
+}
+
+// Replace models a REPLACE of the given row. Just as in MySQL, any existing row with the same primary key is deleted
+// before the row is inserted, rather than being updated in place. Returns whether an existing row was deleted. Keyless
+// tables have nothing to conflict with, so the row is always inserted.
+func (td *TableData) Replace(row Row) (bool, error) {
+	deleted := false
+	if len(td.pkCols) > 0 {
+		wheres, err := td.ColumnEquals(td.pkCols, row.Key())
+		if err != nil {
+			return false, errors.Wrap(err)
+		}
+		result, err := td.namespace.ExecContext(context.Background(),
+			fmt.Sprintf("DELETE FROM `%s` WHERE %s;", td.tableName, strings.Join(wheres, " AND ")))
+		if err != nil {
+			return false, errors.Wrap(err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return false, errors.Wrap(err)
+		}
+		deleted = affected > 0
+	}
+	err := td.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", td.tableName, td.RowString(row)))
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return deleted, nil
+}
+
+// RenameColumn renames the given column.
+func (td *TableData) RenameColumn(oldName string, newName string) error {
+	err := td.Exec(fmt.Sprintf("ALTER TABLE `%s` RENAME COLUMN `%s` TO `%s`;", td.tableName, oldName, newName))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, col := range td.pkCols {
+		if col.Name == oldName {
+			col.Name = newName
+		}
+	}
+	for _, col := range td.nonPKCols {
+		if col.Name == oldName {
+			col.Name = newName
+		}
+	}
+	return nil
+}
+
+// AddColumn adds the given column after all other columns, setting the column to the given value on all existing rows.
+func (td *TableData) AddColumn(col *Column, defaultValue types.Value) error {
+	err := td.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s DEFAULT %s;",
+		td.tableName, col.Name, col.Type.Name(td.SQLite()), td.Dialect(defaultValue.MySQLString(), defaultValue.SQLiteString())))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	// The columns may be shared with the table, so we create a new slice rather than appending in place
+	nonPKCols := make([]*Column, len(td.nonPKCols), len(td.nonPKCols)+1)
+	copy(nonPKCols, td.nonPKCols)
+	td.nonPKCols = append(nonPKCols, col)
+	return nil
+}
+
+// DropColumn drops the given non-primary key column.
+func (td *TableData) DropColumn(name string) error {
+	err := td.Exec(fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`;", td.tableName, name))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	td.nonPKCols = removeColumn(td.nonPKCols, name)
+	return nil
+}
+
+// ModifyColumn changes the type of the given column, converting every existing value to the new type. SQLite cannot
+// change the type of a column, so the table is recreated using the converted rows. The same is done for every Oracle,
+// so that the conversion always follows types.ConvertValue.
+func (td *TableData) ModifyColumn(name string, newType types.TypeInstance) error {
+	// The current columns are used to read the existing rows, so the modified column is replaced rather than changed
+	allCols := append(append([]*Column{}, td.pkCols...), td.nonPKCols...)
+	colIdx := -1
+	for i, col := range allCols {
+		if col.Name == name {
+			colIdx = i
+			allCols[i] = &Column{Name: col.Name, Type: newType, Invisible: col.Invisible}
+		}
+	}
+	if colIdx == -1 {
+		return errors.New(fmt.Sprintf("cannot modify missing column `%s` on table `%s`", name, td.tableName))
+	}
+	pkCount := len(td.pkCols)
+	return td.recreate(allCols[:pkCount:pkCount], allCols[pkCount:], func(row Row) (Row, error) {
+		var err error
+		row.Values[colIdx], err = types.ConvertValue(row.Values[colIdx], newType)
+		return row, err
+	})
+}
+
+// SetPrimaryKey makes the first pkCount columns the primary key, with all remaining columns becoming non-primary key
+// columns. A count of zero leaves the table keyless. The columns keep their order, so existing rows are unchanged, but
+// the table is recreated as SQLite cannot change the primary key of a table. The existing rows must not hold any NULLs
+// or duplicates within the new primary key, which may be checked using CanSetPrimaryKey.
+func (td *TableData) SetPrimaryKey(pkCount int) error {
+	allCols := append(append([]*Column{}, td.pkCols...), td.nonPKCols...)
+	if pkCount < 0 || pkCount > len(allCols) {
+		return errors.New(fmt.Sprintf("cannot set a primary key of %d columns on table `%s`", pkCount, td.tableName))
+	}
+	return td.recreate(allCols[:pkCount:pkCount], allCols[pkCount:], func(row Row) (Row, error) {
+		row.PkColsLen = int32(pkCount)
+		return row, nil
+	})
+}
+
+// CanSetPrimaryKey returns whether the first pkCount columns may become the primary key, meaning that no row holds a
+// NULL in those columns, and no two rows share the same values for all of those columns.
+func (td *TableData) CanSetPrimaryKey(pkCount int) (bool, error) {
+	allCols := append(append([]*Column{}, td.pkCols...), td.nonPKCols...)
+	if pkCount <= 0 || pkCount > len(allCols) {
+		return false, nil
+	}
+	names := make([]string, pkCount)
+	nullChecks := make([]string, pkCount)
+	for i, col := range allCols[:pkCount] {
+		names[i] = fmt.Sprintf("`%s`", col.Name)
+		nullChecks[i] = fmt.Sprintf("`%s` IS NULL", col.Name)
+	}
+	nulls, err := td.QueryScalar(fmt.Sprintf("SELECT COUNT(*) FROM `%s` WHERE %s;",
+		td.tableName, strings.Join(nullChecks, " OR ")))
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	if nulls.String != "0" {
+		return false, nil
+	}
+	duplicates, err := td.QueryScalar(fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM `%s` GROUP BY %s HAVING COUNT(*) > 1) AS `duplicates`;",
+		td.tableName, strings.Join(names, ", ")))
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return duplicates.String == "0", nil
+}
+
+// recreate replaces the table with a new table using the given columns, in a new namespace from the same Oracle. Every
+// row is read in its stored order using the current columns, given to the conversion, and then written to the new
+// table, so that only a single row is held in memory at a time. The new table stores the rows in the same order.
+func (td *TableData) recreate(pkCols []*Column, nonPKCols []*Column, convert func(Row) (Row, error)) error {
+	cursor, err := td.GetQueryCursor(fmt.Sprintf("SELECT * FROM `%s`;", td.tableName))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer cursor.Close()
+	namespace, err := td.oracle.Open(context.Background())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	recreated := &TableData{td.tableName, pkCols, nonPKCols, td.oracle, namespace}
+	if err = recreated.Exec(recreated.createTableStatement()); err != nil {
+		namespace.Release()
+		return errors.Wrap(err)
+	}
+	row, ok, err := cursor.NextRow()
+	for ; err == nil && ok; row, ok, err = cursor.NextRow() {
+		if row, err = convert(row); err != nil {
+			break
+		}
+		err = recreated.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", recreated.tableName, recreated.RowString(row)))
+		if err != nil {
+			break
+		}
+	}
+	if err != nil {
+		recreated.Close()
+		return errors.Wrap(err)
+	}
+	cursor.Close()
+	td.Close()
+	td.pkCols = pkCols
+	td.nonPKCols = nonPKCols
+	td.namespace = namespace
+	return nil
+}
+
+// createTableStatement returns the `CREATE TABLE` statement for the current columns, using the dialect of the Oracle.
+// Indexes are not included, as the internal data only needs to enforce the primary key.
+func (td *TableData) createTableStatement() string {
+	sb := strings.Builder{}
+	sb.Grow(512)
+	writeCreateTableColumns(&sb, td.tableName, td.pkCols, td.nonPKCols, func(col *Column) string {
+		return col.dataDefinition(td.SQLite())
+	})
+	sb.WriteString(");")
+	return sb.String()
+}
+
 // ConstructTemplateRow creates a row with each value set to the equivalent types.ValuePrimitive for that position relative to its
 // column on the table. This is intended to be used as a destination row for reading from table data.
 func (td *TableData) ConstructTemplateRow() Row {
@@ -92,9 +306,8 @@ func (td *TableData) ConstructTemplateRow() Row {
 
 // GetRowCount returns the number of rows in the table.
 func (td *TableData) GetRowCount() (int64, error) {
-	rows := td.connection.QueryRowContext(context.Background(), fmt.Sprintf("SELECT COUNT(*) FROM `%s`;", td.tableName))
 	count := int64(0)
-	err := rows.Scan(&count)
+	err := td.namespace.QueryRow(context.Background(), fmt.Sprintf("SELECT COUNT(*) FROM `%s`;", td.tableName), &count)
 	if err != nil {
 		return 0, errors.Wrap(err)
 	}
@@ -114,20 +327,41 @@ func (td *TableData) GetRandomRow() (Row, bool, error) {
 	if err != nil {
 		return Row{}, false, errors.Wrap(err)
 	}
-	outRow := td.connection.QueryRowContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s` LIMIT 1 OFFSET %d;", td.tableName, randVal%rowCount))
 	row := td.ConstructTemplateRow()
 	iVals := make([]interface{}, len(row.Values))
 	for i := range row.Values {
 		iVals[i] = types.NewValueScanner(&row.Values[i])
 	}
-	err = outRow.Scan(iVals...)
+	err = td.namespace.QueryRow(context.Background(), fmt.Sprintf("SELECT * FROM `%s` LIMIT 1 OFFSET %d;", td.tableName, randVal%rowCount), iVals...)
 	if err != nil {
 		return Row{}, false, errors.Wrap(err)
 	}
 	return row, true, nil
 }
 
-// GetAllRows returns all of the rows in the table.
+// GetRow returns the row with the given primary key. Returns true only if the row exists.
+func (td *TableData) GetRow(key []types.Value) (Row, bool, error) {
+	wheres, err := td.ColumnEquals(td.pkCols, key)
+	if err != nil {
+		return Row{}, false, errors.Wrap(err)
+	}
+	row := td.ConstructTemplateRow()
+	iVals := make([]interface{}, len(row.Values))
+	for i := range row.Values {
+		iVals[i] = types.NewValueScanner(&row.Values[i])
+	}
+	err = td.namespace.QueryRow(context.Background(),
+		fmt.Sprintf("SELECT * FROM `%s` WHERE %s LIMIT 1;", td.tableName, strings.Join(wheres, " AND ")), iVals...)
+	if err == sql.ErrNoRows {
+		return Row{}, false, nil
+	} else if err != nil {
+		return Row{}, false, errors.Wrap(err)
+	}
+	return row, true, nil
+}
+
+// GetAllRows returns all of the rows in the table. Every row is held in memory, so a cursor from GetRowCursor should
+// be preferred for tables that may be large.
 func (td *TableData) GetAllRows() ([]Row, error) {
 	rowCount, err := td.GetRowCount()
 	if err != nil {
@@ -136,7 +370,7 @@ func (td *TableData) GetAllRows() ([]Row, error) {
 	if rowCount == 0 {
 		return nil, nil
 	}
-	outRows, err := td.connection.QueryContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s`;", td.tableName))
+	outRows, err := td.namespace.QueryContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s`;", td.tableName))
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -175,7 +409,7 @@ func (td *TableData) GetRowCursor() (*TableDataCursor, error) {
 			orderBy += fmt.Sprintf(", %d", i)
 		}
 	}
-	outRows, err := td.connection.QueryContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s`%s;", td.tableName, orderBy))
+	outRows, err := td.namespace.QueryContext(context.Background(), fmt.Sprintf("SELECT * FROM `%s`%s;", td.tableName, orderBy))
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -186,14 +420,80 @@ func (td *TableData) GetRowCursor() (*TableDataCursor, error) {
 	}, nil
 }
 
-// Copy returns an exact copy of the contained table and index data.
-func (td *TableData) Copy() (*TableData, error) {
-	outCreateTableStmt := td.connection.QueryRowContext(context.Background(), fmt.Sprintf("SELECT sql FROM sqlite_master WHERE name = '%s';", td.tableName))
-	createTableStmt := ""
-	err := outCreateTableStmt.Scan(&createTableStmt)
+// GetFullyOrderedCursor returns a cursor over every row of the table data, ordered by every column. The order is total,
+// and is the same for all table data that have the same columns and Oracle, so that the rows of keyless tables may be
+// compared by position.
+func (td *TableData) GetFullyOrderedCursor() (*TableDataCursor, error) {
+	order := td.columnOrder(append(append([]*Column{}, td.pkCols...), td.nonPKCols...))
+	return td.GetQueryCursor(fmt.Sprintf("SELECT * FROM `%s` ORDER BY %s;", td.tableName, strings.Join(order, ", ")))
+}
+
+// columnOrder returns an expression for each of the given columns that orders and groups its values. MySQL compares
+// strings using their collations, under which distinct strings may be equal, so each value is compared by its bytes
+// instead.
+func (td *TableData) columnOrder(cols []*Column) []string {
+	exprs := make([]string, len(cols))
+	for i, col := range cols {
+		exprs[i] = td.Dialect(fmt.Sprintf("CAST(`%s` AS BINARY)", col.Name), fmt.Sprintf("`%s`", col.Name))
+	}
+	return exprs
+}
+
+// InsertRows inserts every row returned by the iterator, one row at a time.
+func (td *TableData) InsertRows(iter RowIterator) error {
+	row, ok, err := iter.NextRow()
+	for ; err == nil && ok; row, ok, err = iter.NextRow() {
+		err = td.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", td.tableName, td.RowString(row)))
+		if err != nil {
+			break
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// GetQueryCursor returns a cursor over the results of the given query, which must select every column of the table.
+func (td *TableData) GetQueryCursor(query string) (*TableDataCursor, error) {
+	outRows, err := td.namespace.QueryContext(context.Background(), query)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
+	return &TableDataCursor{
+		rows:     outRows,
+		template: td.ConstructTemplateRow(),
+		td:       td,
+	}, nil
+}
+
+// GetProjectionCursor returns a cursor over the results of the given query, which may select any columns. The template
+// must have a value for each selected column, in the same order.
+func (td *TableData) GetProjectionCursor(query string, template Row) (*TableDataCursor, error) {
+	outRows, err := td.namespace.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &TableDataCursor{
+		rows:     outRows,
+		template: template,
+		td:       td,
+	}, nil
+}
+
+// QueryScalar returns the single value returned by the given query, such as the result of an aggregate function.
+func (td *TableData) QueryScalar(query string) (sql.NullString, error) {
+	var result sql.NullString
+	err := td.namespace.QueryRow(context.Background(), query, &result)
+	if err != nil {
+		return sql.NullString{}, errors.Wrap(err)
+	}
+	return result, nil
+}
+
+// Copy returns an exact copy of the contained table and index data. The copy uses the current Oracle, which is the
+// same as this table data's Oracle unless it has since been changed.
+func (td *TableData) Copy() (*TableData, error) {
 	pkCols := make([]*Column, len(td.pkCols))
 	for i := 0; i < len(td.pkCols); i++ {
 		pkCols[i] = td.pkCols[i].Copy()
@@ -202,7 +502,7 @@ func (td *TableData) Copy() (*TableData, error) {
 	for i := 0; i < len(td.nonPKCols); i++ {
 		nonPKCols[i] = td.nonPKCols[i].Copy()
 	}
-	newTableData, err := CreateTableData(td.tableName, createTableStmt, pkCols, nonPKCols)
+	newTableData, err := CreateTableData(td.tableName, pkCols, nonPKCols)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -214,7 +514,7 @@ func (td *TableData) Copy() (*TableData, error) {
 
 	row, ok, err := oldDataCursor.NextRow()
 	for ; err == nil && ok; row, ok, err = oldDataCursor.NextRow() {
-		err = newTableData.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", newTableData.tableName, row.SQLiteString()))
+		err = newTableData.Exec(fmt.Sprintf("INSERT INTO `%s` VALUES (%s);", newTableData.tableName, newTableData.RowString(row)))
 		if err != nil {
 			return nil, errors.Wrap(err)
 		}
@@ -225,8 +525,15 @@ func (td *TableData) Copy() (*TableData, error) {
 	return newTableData, nil
 }
 
+// ExportToCSV writes every row of the table data to the given file. Keyless rows are written in the order of
+// GetFullyOrderedCursor, so that they may be compared by position against another table's ordered rows.
 func (td *TableData) ExportToCSV(filePath string) (err error) {
-	rowCursor, err := td.GetRowCursor()
+	var rowCursor *TableDataCursor
+	if len(td.pkCols) == 0 {
+		rowCursor, err = td.GetFullyOrderedCursor()
+	} else {
+		rowCursor, err = td.GetRowCursor()
+	}
 	if err != nil {
 		return errors.Wrap(err)
 	}
@@ -294,13 +601,13 @@ func (td *TableData) ExportToCSV(filePath string) (err error) {
 	return nil
 }
 
-// Close closes the underlying connection and frees resources. Cannot panic.
+// Close deletes the table along with its namespace, and frees resources. Cannot panic.
 func (td *TableData) Close() {
 	defer func() {
 		_ = recover()
 	}()
 	_ = td.Exec(fmt.Sprintf("DROP TABLE `%s`;", td.tableName))
-	_ = td.connection.Close()
+	td.namespace.Release()
 }
 
 // NextRow returns the next row from the cursor. If there are no more rows to return, returns false.
@@ -0,0 +1,336 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestRenameColumn(t *testing.T) {
+	commit := &Commit{}
+	table, err := NewTable(commit, "renamed", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+		{Name: "v2", Type: &types.IntInstance{}},
+	}, []*Index{NewIndex("idx", []string{"v1", "v2"}, nil, false)})
+	require.NoError(t, err)
+	defer table.Data.Close()
+	commit.Tables = []*Table{table}
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+
+	// Renaming the working set's copy must not affect the original, as commits hold immutable snapshots
+	workingSet, err := commit.Copy()
+	require.NoError(t, err)
+	renamed := workingSet.Tables[0]
+	defer renamed.Data.Close()
+	require.NoError(t, renamed.RenameColumn("v1", "v3"))
+	require.Equal(t, []string{"pk", "v3", "v2"}, renamed.ColumnNames())
+	require.Equal(t, []string{"v3", "v2"}, renamed.Indexes[0].Columns)
+	require.Equal(t, []string{"pk", "v1", "v2"}, table.ColumnNames())
+	require.Equal(t, []string{"v1", "v2"}, table.Indexes[0].Columns)
+	require.Error(t, renamed.RenameColumn("v1", "v4"))
+
+	// Statements that reference columns by name must use the new name internally
+	for i := 0; i < 20; i++ {
+		_, err = (&UpdateStatement{}).GenerateStatement(renamed)
+		require.NoError(t, err)
+	}
+	rowCount, err := renamed.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(20), rowCount)
+}
+
+func TestAddAndDropColumn(t *testing.T) {
+	commit := &Commit{}
+	table, err := NewTable(commit, "altered", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+		{Name: "v2", Type: &types.IntInstance{}},
+	}, []*Index{NewIndex("idx", []string{"v1"}, nil, false)})
+	require.NoError(t, err)
+	defer table.Data.Close()
+	commit.Tables = []*Table{table}
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+
+	// Existing rows must all receive the default value of the new column
+	defaultValue := types.IntValue{Int32Value: 37}
+	require.NoError(t, table.AddColumn(&Column{Name: "v3", Type: &types.IntInstance{}}, defaultValue))
+	require.Equal(t, []string{"pk", "v1", "v2", "v3"}, table.ColumnNames())
+	rows, err := table.Data.GetAllRows()
+	require.NoError(t, err)
+	require.Len(t, rows, 20)
+	for _, row := range rows {
+		require.Len(t, row.Values, 4)
+		require.Equal(t, types.Value(defaultValue), row.Values[3])
+	}
+	require.Error(t, table.AddColumn(&Column{Name: "v3", Type: &types.IntInstance{}}, defaultValue))
+
+	// Primary key and indexed columns cannot be dropped
+	require.False(t, table.CanDropColumn("pk"))
+	require.False(t, table.CanDropColumn("v1"))
+	require.Error(t, table.DropColumn("v1"))
+	require.NoError(t, table.DropColumn("v2"))
+	require.Equal(t, []string{"pk", "v1", "v3"}, table.ColumnNames())
+	rows, err = table.Data.GetAllRows()
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.Len(t, row.Values, 3)
+		require.Equal(t, types.Value(defaultValue), row.Values[2])
+	}
+	for i := 0; i < 20; i++ {
+		_, err = (&UpdateStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+}
+
+func TestModifyColumn(t *testing.T) {
+	table, err := NewTable(&Commit{}, "modified", []*Column{
+		{Name: "pk", Type: &types.TinyintInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntUnsignedInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	before, err := table.Data.GetAllRows()
+	require.NoError(t, err)
+
+	require.NoError(t, table.ModifyColumn("pk", &types.SmallintInstance{}))
+	require.NoError(t, table.ModifyColumn("v1", &types.BigintUnsignedInstance{}))
+	after, err := table.Data.GetAllRows()
+	require.NoError(t, err)
+	require.Len(t, after, len(before))
+	for i := range after {
+		require.IsType(t, types.SmallintValue{}, after[i].Values[0])
+		require.IsType(t, types.BigintUnsignedValue{}, after[i].Values[1])
+		require.Equal(t, before[i].Values[0].String(), after[i].Values[0].String())
+		require.Equal(t, before[i].Values[1].String(), after[i].Values[1].String())
+	}
+
+	// Newly generated values must use the new types
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	rowCount, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(40), rowCount)
+
+	// Widening an indexed VARCHAR may exceed the maximum key length, so such columns are never modified
+	indexed, err := NewTable(&Commit{}, "indexed", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.VarcharInstance{}},
+	}, []*Index{NewIndex("idx", []string{"v1"}, nil, false)})
+	require.NoError(t, err)
+	defer indexed.Data.Close()
+	schemaChange := &ModifyColumnSchemaChange{1, [][2]string{{"VARCHAR", "VARCHAR"}}}
+	statement, err := schemaChange.GenerateSchemaChange(nil, indexed)
+	require.NoError(t, err)
+	require.Empty(t, statement)
+}
+
+func TestIndexOrderQuery(t *testing.T) {
+	index := NewIndex("idx", []string{"v1", "pk"}, nil, false)
+	index.Descending = []bool{true, false}
+	table, err := NewTable(&Commit{}, "ordered", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+		{Name: "v2", Type: &types.VarcharInstance{}},
+	}, []*Index{index})
+	require.NoError(t, err)
+	defer table.Data.Close()
+	require.Equal(t, "INDEX `idx` (`v1` DESC,`pk`)", index.String())
+
+	doltQuery, query, template, ok := table.IndexOrderQuery(index)
+	require.True(t, ok)
+	require.Equal(t, "SELECT `v1`,`pk` FROM `ordered` FORCE INDEX (`idx`) ORDER BY `v1` DESC,`pk`;", doltQuery)
+	require.Equal(t, "SELECT `v1`,`pk` FROM `ordered` ORDER BY `v1` DESC,`pk`;", query)
+	require.Len(t, template.Values, 2)
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	cursor, err := table.Data.GetProjectionCursor(query, template)
+	require.NoError(t, err)
+	defer cursor.Close()
+	previous, ok, err := cursor.NextRow()
+	require.NoError(t, err)
+	require.True(t, ok)
+	for row, ok, err := cursor.NextRow(); ok; row, ok, err = cursor.NextRow() {
+		require.NoError(t, err)
+		_, previousIsNil := previous.Values[0].(types.NilValue)
+		_, isNil := row.Values[0].(types.NilValue)
+		if !previousIsNil && !isNil {
+			require.NotEqual(t, -1, previous.Values[0].Compare(row.Values[0]))
+		}
+		previous = row
+	}
+
+	// String columns are not ordered identically by Dolt and SQLite, while they are by a MySQL Oracle
+	_, _, _, ok = table.IndexOrderQuery(NewIndex("idx2", []string{"v2"}, nil, false))
+	require.False(t, ok)
+	require.True(t, isOrderableColumn(table.NonPKCols[1], false))
+	// Dates are stored as strings by SQLite, which sort in the same order as the dates
+	require.True(t, isOrderableColumn(&Column{Name: "d", Type: &types.DateInstance{}}, true))
+
+	// Prefixed columns only select and order by their prefix, which is only ordered identically by a MySQL Oracle
+	prefixed := NewIndex("idx3", []string{"v3", "pk"}, []int64{4, 0}, false)
+	prefixed.Descending = []bool{true, false}
+	_, _, _, ok = table.IndexOrderQuery(prefixed)
+	require.False(t, ok)
+	mysqlTable := &Table{Name: "ordered", PKCols: table.PKCols, NonPKCols: []*Column{
+		{Name: "v3", Type: &types.TextInstance{}},
+	}, Data: &TableData{oracle: &MySQLOracle{}}}
+	doltQuery, query, template, ok = mysqlTable.IndexOrderQuery(prefixed)
+	require.True(t, ok)
+	require.Equal(t, "SELECT LEFT(`v3`, 4),`pk` FROM `ordered` FORCE INDEX (`idx3`) "+
+		"ORDER BY LEFT(`v3`, 4) DESC,CAST(LEFT(`v3`, 4) AS BINARY) DESC,`pk`;", doltQuery)
+	require.Equal(t, "SELECT LEFT(`v3`, 4),`pk` FROM `ordered` "+
+		"ORDER BY LEFT(`v3`, 4) DESC,CAST(LEFT(`v3`, 4) AS BINARY) DESC,`pk`;", query)
+	require.Len(t, template.Values, 2)
+}
+
+func TestPrimaryKeyChanges(t *testing.T) {
+	table, err := NewTable(&Commit{}, "rekeyed", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.TinyintInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	before := countRows(t, table.Data)
+
+	require.NoError(t, table.DropPrimaryKey())
+	require.True(t, table.IsKeyless())
+	require.Len(t, table.NonPKCols, 2)
+	require.Equal(t, before, countRows(t, table.Data))
+	require.Equal(t, int32(0), table.Data.ConstructTemplateRow().PkColsLen)
+
+	// A duplicate row prevents the key from being added back
+	ok, err := table.Data.CanSetPrimaryKey(1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	row, _, err := table.Data.GetRandomRow()
+	require.NoError(t, err)
+	require.NoError(t, table.Data.Exec(fmt.Sprintf("INSERT INTO `rekeyed` VALUES (%s);", table.Data.RowString(row))))
+	ok, err = table.Data.CanSetPrimaryKey(1)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, table.Data.Exec("DELETE FROM `rekeyed`;"))
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+
+	ok, err = table.Data.CanSetPrimaryKey(2)
+	require.NoError(t, err)
+	if ok {
+		require.NoError(t, table.AddPrimaryKey(2))
+		require.Len(t, table.PKCols, 2)
+		require.Len(t, table.NonPKCols, 0)
+		cursor, err := table.Data.GetRowCursor()
+		require.NoError(t, err)
+		defer cursor.Close()
+		row, ok, err := cursor.NextRow()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, int32(2), row.PkColsLen)
+	}
+}
+
+func TestOrderedPageSelect(t *testing.T) {
+	table, err := NewTable(&Commit{}, "pages", []*Column{
+		{Name: "pk1", Type: &types.IntInstance{}},
+		{Name: "pk2", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	require.Equal(t, "SELECT * FROM `pages` ORDER BY 1, 2;", table.orderedSelect())
+	require.Equal(t, "SELECT * FROM `pages` ORDER BY 1, 2 LIMIT 10 OFFSET 30;", table.orderedPageSelect(10, 30))
+	require.Equal(t, table.orderedSelect(), table.fullyOrderedSelect())
+}
+
+func TestInvisibleColumns(t *testing.T) {
+	commit := &Commit{}
+	table, err := NewTable(commit, "hidden", nil, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+		{Name: "v2", Type: &types.IntInstance{}, Invisible: true},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	commit.Tables = []*Table{table}
+
+	require.Equal(t, "CREATE TABLE `hidden` (`v1` INT, `v2` INT INVISIBLE);", table.CreateString(false, false))
+	require.NotContains(t, table.CreateString(false, true), "INVISIBLE")
+	// The internal data must return every column from `SELECT *`, so its columns are never invisible
+	require.NotContains(t, table.Data.createTableStatement(), "INVISIBLE")
+	require.Equal(t, "`v1`,`v2`", table.SelectColumns())
+	require.Equal(t, []string{"v1"}, table.VisibleColumnNames())
+	require.Equal(t, "SELECT `v1`,`v2` FROM `hidden`;", table.orderedSelect())
+	// Keyless rows have no primary key to order by, so reads that are compared by position order by every column
+	require.Equal(t, "SELECT `v1`,`v2` FROM `hidden` ORDER BY 1, 2;", table.fullyOrderedSelect())
+	// The only visible column cannot be dropped, while the invisible column may be
+	require.False(t, table.CanDropColumn("v1"))
+	require.True(t, table.CanDropColumn("v2"))
+
+	statement, err := (&InsertStatement{}).GenerateStatement(table)
+	require.NoError(t, err)
+	require.Contains(t, statement, "INSERT INTO `hidden` (`v1`,`v2`) VALUES")
+
+	table.NonPKCols[1].Invisible = false
+	require.Equal(t, "*", table.SelectColumns())
+	require.Equal(t, "", table.valuesColumnList())
+}
+
+// countRows returns the number of times that each distinct row of the table data appears, keyed by the row's values.
+func countRows(t *testing.T, data *TableData) map[string]int64 {
+	cursor, err := data.GetRowCursor()
+	require.NoError(t, err)
+	defer cursor.Close()
+	counts, err := NewRowCountCursor(data.tableName, data.pkCols, data.nonPKCols, cursor)
+	require.NoError(t, err)
+	defer counts.Close()
+	rowCounts := make(map[string]int64)
+	row, rowCount, ok, err := counts.NextRow()
+	for ; err == nil && ok; row, rowCount, ok, err = counts.NextRow() {
+		rowCounts[row.MySQLString()] = rowCount[0]
+	}
+	require.NoError(t, err)
+	return rowCounts
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"math"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// transactionSettings controls how often statements are grouped into an explicit transaction, and how those
+// transactions end.
+type transactionSettings struct {
+	probability         float64
+	rollbackProbability float64
+	maxStatements       int64
+}
+
+// InTransaction returns whether an explicit transaction is currently open on the server. Anything that would end the
+// transaction early, such as running a CLI command (which closes the server), must wait until it has finished.
+func (c *Cycle) InTransaction() bool {
+	return c.transaction
+}
+
+// nextTransaction returns whether the next statements should be run in an explicit transaction.
+func (c *Cycle) nextTransaction() (bool, error) {
+	if c.transactions.probability <= 0 || c.transactions.maxStatements <= 0 {
+		return false, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return float64(roll) < c.transactions.probability*math.MaxUint64, nil
+}
+
+// RunTransaction runs random statements against the table within an explicit transaction, which is randomly either
+// committed or rolled back. The internal data runs the same transaction on its own connection, as each statement is
+// applied to the internal data as it is generated, so a rollback discards the changes from both. Every statement of the
+// transaction must reach Dolt on the same session, which the Interface provides until the server connection is
+// reopened, so the transaction fails if its session changes before it has ended.
+func (c *Cycle) RunTransaction(table *Table) error {
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	statementCount := 1 + int64(roll%uint64(c.transactions.maxStatements))
+	roll, err = rand.StructureUint64()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	rollback := float64(roll) < c.transactions.rollbackProbability*math.MaxUint64
+
+	if err = table.Data.Exec("BEGIN;"); err != nil {
+		return errors.Wrap(err)
+	}
+	c.transaction = true
+	if err = c.SqlServer("BEGIN;"); err != nil {
+		return errors.Wrap(err)
+	}
+	session, err := c.Planner.Interface.Session(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for i := int64(0); i < statementCount; i++ {
+		if err = c.RunStatement(table); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	current, err := c.Planner.Interface.Session(c)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if current != session {
+		return errors.New("the SQL session changed within an explicit transaction, so its statements did not all run on one session")
+	}
+
+	// The internal data finishes first, so that any hooks that run after the final statement see the same data as Dolt
+	end := "COMMIT;"
+	if rollback {
+		end = "ROLLBACK;"
+	}
+	if err = table.Data.Exec(end); err != nil {
+		return errors.Wrap(err)
+	}
+	c.transaction = false
+	if err = c.SqlServer(end); err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
+}
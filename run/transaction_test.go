@@ -0,0 +1,81 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/parameters"
+	"github.com/dolthub/fuzzer/ranges"
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestRunTransaction(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	statementDist, err := ranges.NewDistributionCenter(&InsertStatement{r: ranges.NewInt([]int64{1, 1})})
+	require.NoError(t, err)
+	c := &Cycle{
+		Planner: &Planner{
+			Base:      &parameters.Base{},
+			Hooks:     &Hooks{},
+			Interface: NewWriterInterface(buffer),
+		},
+		Name:          "db",
+		Logger:        &fakeLogger{},
+		statementDist: statementDist,
+		branches:      []*Branch{{Name: "main"}},
+	}
+	table, err := NewTable(&Commit{}, "txn", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+
+	var inTransaction []bool
+	c.Planner.Hooks.SQLStatementPostExecution(func(c *Cycle, statement string) error {
+		inTransaction = append(inTransaction, c.InTransaction())
+		return nil
+	})
+
+	// Committed transactions keep their rows, while rolled back transactions discard them
+	c.transactions = transactionSettings{maxStatements: 5}
+	require.NoError(t, c.RunTransaction(table))
+	committed, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, committed, int64(1))
+	c.transactions.rollbackProbability = 1
+	require.NoError(t, c.RunTransaction(table))
+	count, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, committed, count)
+	require.False(t, c.InTransaction())
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Equal(t, "BEGIN;", lines[0])
+	require.Equal(t, "COMMIT;", lines[committed+1])
+	require.Equal(t, "BEGIN;", lines[committed+2])
+	require.Equal(t, "ROLLBACK;", lines[len(lines)-1])
+	// Only the statements that end each transaction are run outside of it
+	require.Len(t, inTransaction, len(lines))
+	require.False(t, inTransaction[committed+1])
+	require.False(t, inTransaction[len(lines)-1])
+	require.True(t, inTransaction[len(lines)-2])
+}
@@ -0,0 +1,207 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+// triggerEvents are every combination of timing and event that a trigger may fire on.
+var triggerEvents = []string{
+	"BEFORE INSERT",
+	"AFTER INSERT",
+	"BEFORE UPDATE",
+	"AFTER UPDATE",
+	"BEFORE DELETE",
+	"AFTER DELETE",
+}
+
+// TriggerAudit is a set of triggers on a table, where each trigger counts the number of times that it has fired within
+// an audit table. The same triggers and audit table are created within the table's internal data, so that the oracle
+// fires them for the same rows as Dolt, and the audit tables must then have the same counts. REPLACE is modeled as a
+// DELETE followed by an INSERT, which fires the same triggers as a REPLACE in MySQL. The internal triggers and audit
+// table are lost whenever the internal data is copied or recreated, such as by a commit or schema change, so the audit
+// must be dropped beforehand.
+type TriggerAudit struct {
+	Name   string
+	Table  *Table
+	Events []string
+}
+
+// NewTriggerAudit returns a new audit over the given table, where each trigger from triggerEvents is chosen at the
+// given probability. At least one trigger is always chosen. The audit has not yet been created.
+func NewTriggerAudit(c *Cycle, table *Table, probability float64) (*TriggerAudit, error) {
+	auditName, err := newTableName(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	c.usedNames[auditName] = struct{}{}
+	var events []string
+	for _, event := range triggerEvents {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if float64(roll) < probability*math.MaxUint64 {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		events = append(events, triggerEvents[roll%uint64(len(triggerEvents))])
+	}
+	return &TriggerAudit{
+		Name:   auditName,
+		Table:  table,
+		Events: events,
+	}, nil
+}
+
+// TriggerName returns the name of the trigger for the given event.
+func (ta *TriggerAudit) TriggerName(event string) string {
+	return fmt.Sprintf("%s_%s", ta.Name, strings.ToLower(strings.ReplaceAll(event, " ", "_")))
+}
+
+// CreateStrings returns the statements that create the audit table and its triggers. SQLite requires the body of a
+// trigger to be within BEGIN and END, which MySQL allows but does not require.
+func (ta *TriggerAudit) CreateStrings(sqlite bool) []string {
+	rows := make([]string, len(ta.Events))
+	for i, event := range ta.Events {
+		rows[i] = fmt.Sprintf("('%s',0)", event)
+	}
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE `%s` (`event` VARCHAR(32) PRIMARY KEY, `fired` BIGINT NOT NULL);", ta.Name),
+		fmt.Sprintf("INSERT INTO `%s` VALUES %s;", ta.Name, strings.Join(rows, ",")),
+	}
+	for _, event := range ta.Events {
+		body := fmt.Sprintf("UPDATE `%s` SET `fired` = `fired` + 1 WHERE `event` = '%s'", ta.Name, event)
+		if sqlite {
+			body = fmt.Sprintf("BEGIN %s; END", body)
+		}
+		statements = append(statements, fmt.Sprintf("CREATE TRIGGER `%s` %s ON `%s` FOR EACH ROW %s;",
+			ta.TriggerName(event), event, ta.Table.Name, body))
+	}
+	return statements
+}
+
+// DropStrings returns the statements that drop the triggers and the audit table.
+func (ta *TriggerAudit) DropStrings() []string {
+	var statements []string
+	for _, event := range ta.Events {
+		statements = append(statements, fmt.Sprintf("DROP TRIGGER `%s`;", ta.TriggerName(event)))
+	}
+	return append(statements, fmt.Sprintf("DROP TABLE `%s`;", ta.Name))
+}
+
+// Create creates the audit table and triggers in both Dolt and the table's internal data.
+func (ta *TriggerAudit) Create(c *Cycle) error {
+	for _, statement := range ta.CreateStrings(ta.Table.Data.SQLite()) {
+		if err := ta.Table.Data.Exec(statement); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	for _, statement := range ta.CreateStrings(false) {
+		if err := c.SqlServer(statement); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Drop drops the triggers and audit table from both Dolt and the table's internal data.
+func (ta *TriggerAudit) Drop(c *Cycle) error {
+	for _, statement := range ta.DropStrings() {
+		if err := ta.Table.Data.Exec(statement); err != nil {
+			return errors.Wrap(err)
+		}
+		if err := c.SqlServer(statement); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Compare returns an error if any trigger has fired a different number of times in Dolt than in the internal data.
+func (ta *TriggerAudit) Compare(c *Cycle) error {
+	query := fmt.Sprintf("SELECT `event`, `fired` FROM `%s` ORDER BY `event`;", ta.Name)
+	internalCounts := make(map[string]int64)
+	internalRows, err := ta.Table.Data.namespace.QueryContext(context.Background(), query)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer internalRows.Close()
+	for internalRows.Next() {
+		var event string
+		var fired int64
+		if err = internalRows.Scan(&event, &fired); err != nil {
+			return errors.Wrap(err)
+		}
+		internalCounts[event] = fired
+	}
+	if err = internalRows.Err(); err != nil {
+		return errors.Wrap(err)
+	}
+
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	doltCounts := make(map[string]int64)
+	doltRows, err := dc.Conn.QueryContext(context.Background(), query)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer doltRows.Close()
+	for doltRows.Next() {
+		var event string
+		var fired int64
+		if err = doltRows.Scan(&event, &fired); err != nil {
+			return errors.Wrap(err)
+		}
+		doltCounts[event] = fired
+	}
+	if err = doltRows.Err(); err != nil {
+		return errors.Wrap(err)
+	}
+
+	for _, event := range ta.Events {
+		if internalCounts[event] != doltCounts[event] {
+			return errors.New(fmt.Sprintf("On table `%s`, trigger `%s` on %s fired %d times but expected %d",
+				ta.Table.Name, ta.TriggerName(event), event, doltCounts[event], internalCounts[event]))
+		}
+	}
+	return nil
+}
+
+// ValidateDoltSchemas returns an error if the "dolt_schemas" system table does not list every trigger when they should
+// exist, or lists any of them when they should not.
+func (ta *TriggerAudit) ValidateDoltSchemas(c *Cycle, exists bool) error {
+	for _, event := range ta.Events {
+		if err := validateDoltSchemas(c, "trigger", ta.TriggerName(event), exists); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestTriggerAudit(t *testing.T) {
+	c := &Cycle{
+		nameRegexes: &nameRegexes{Tables: regexp.MustCompile("^$")},
+		usedNames:   map[string]struct{}{"tbl": {}},
+	}
+	table, err := NewTable(&Commit{}, "tbl", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+
+	audit, err := NewTriggerAudit(c, table, 1)
+	require.NoError(t, err)
+	require.Equal(t, triggerEvents, audit.Events)
+	for _, statement := range audit.CreateStrings(table.Data.SQLite()) {
+		require.NoError(t, table.Data.Exec(statement))
+	}
+	fired := func() map[string]int64 {
+		counts := make(map[string]int64)
+		rows, err := table.Data.namespace.QueryContext(context.Background(),
+			"SELECT `event`, `fired` FROM `"+audit.Name+"`;")
+		require.NoError(t, err)
+		defer rows.Close()
+		for rows.Next() {
+			var event string
+			var count int64
+			require.NoError(t, rows.Scan(&event, &count))
+			counts[event] = count
+		}
+		return counts
+	}
+
+	for i := 0; i < 10; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+	require.Equal(t, map[string]int64{"BEFORE INSERT": 10, "AFTER INSERT": 10, "BEFORE UPDATE": 0, "AFTER UPDATE": 0,
+		"BEFORE DELETE": 0, "AFTER DELETE": 0}, fired())
+
+	// Replacing an existing row deletes it before inserting the new row
+	row, ok, err := table.Data.GetRandomRow()
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, err = replaceKey(table, row.Key())
+	require.NoError(t, err)
+	_, err = (&UpdateStatement{}).GenerateStatement(table)
+	require.NoError(t, err)
+	_, err = (&DeleteStatement{}).GenerateStatement(table)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"BEFORE INSERT": 11, "AFTER INSERT": 11, "BEFORE UPDATE": 1, "AFTER UPDATE": 1,
+		"BEFORE DELETE": 2, "AFTER DELETE": 2}, fired())
+
+	for _, statement := range audit.DropStrings() {
+		require.NoError(t, table.Data.Exec(statement))
+	}
+	rowCount, err := table.Data.GetRowCount()
+	require.NoError(t, err)
+	require.Equal(t, int64(9), rowCount)
+
+	// A single trigger is always chosen
+	audit, err = NewTriggerAudit(c, table, 0)
+	require.NoError(t, err)
+	require.Len(t, audit.Events, 1)
+}
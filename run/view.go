@@ -0,0 +1,111 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/run/connection"
+)
+
+// View is a view over a single table, defined by a random query from NewQuery. Views are not tracked as part of a
+// commit, so they should be dropped once they have been tested.
+type View struct {
+	Name  string
+	Table *Table
+	Query Query
+}
+
+// NewView returns a new view with a unique name over the given table. The view has not yet been created in Dolt.
+func NewView(c *Cycle, table *Table) (*View, error) {
+	query, err := NewQuery(table)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	viewName, err := newTableName(c)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	c.usedNames[viewName] = struct{}{}
+	return &View{
+		Name:  viewName,
+		Table: table,
+		Query: query,
+	}, nil
+}
+
+// CreateString returns the CREATE VIEW statement for this view.
+func (v *View) CreateString() string {
+	return fmt.Sprintf("CREATE VIEW `%s` AS %s;", v.Name, strings.TrimSuffix(v.Query.MySQL, ";"))
+}
+
+// DropString returns the DROP VIEW statement for this view.
+func (v *View) DropString() string {
+	return fmt.Sprintf("DROP VIEW `%s`;", v.Name)
+}
+
+// Compare selects every row from the view, returning an error if the rows differ from those returned by the view's
+// query on the internal data. Rows may be returned in any order, as a view does not guarantee the order of its query,
+// although a LIMIT within the view still limits the view to the same rows.
+func (v *View) Compare(c *Cycle) error {
+	err := v.Table.CompareQuery(c, Query{
+		MySQL:     fmt.Sprintf("SELECT * FROM `%s`;", v.Name),
+		SQLite:    v.Query.SQLite,
+		Aggregate: v.Query.Aggregate,
+		Ordered:   false,
+	})
+	if err != nil {
+		return errors.New(fmt.Sprintf("On view `%s` defined as `%s`: %s", v.Name, v.Query.MySQL, err.Error()))
+	}
+	return nil
+}
+
+// ValidateDoltSchemas returns an error if the "dolt_schemas" system table does not list the view when it should exist,
+// or lists it when it should not.
+func (v *View) ValidateDoltSchemas(c *Cycle, exists bool) error {
+	return validateDoltSchemas(c, "view", v.Name, exists)
+}
+
+// validateDoltSchemas returns an error if the "dolt_schemas" system table does not list the named fragment of the given
+// type exactly once when it should exist, or lists it at all when it should not. Does nothing when validation is skipped.
+func validateDoltSchemas(c *Cycle, fragmentType string, name string, exists bool) error {
+	if c.Planner.Base.Arguments.SkipValidation {
+		return nil
+	}
+	dc, err := connection.GetDoltConnection(c.Planner.Base.Options.Port, c.Name)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	var count int
+	err = dc.Conn.QueryRowContext(context.Background(), fmt.Sprintf("SELECT COUNT(*) FROM `dolt_schemas` "+
+		"WHERE `type` = '%s' AND LOWER(`name`) = '%s';", fragmentType, strings.ToLower(name))).Scan(&count)
+	if err != nil {
+		// The system table does not exist once there are no views or triggers, which is the same as it being empty
+		if exists || !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return errors.Wrap(err)
+		}
+		count = 0
+	}
+	if exists && count != 1 {
+		return errors.New(fmt.Sprintf("dolt_schemas lists %s `%s` %d times but expected once", fragmentType, name, count))
+	}
+	if !exists && count != 0 {
+		return errors.New(fmt.Sprintf("dolt_schemas still lists %s `%s` after it was dropped", fragmentType, name))
+	}
+	return nil
+}
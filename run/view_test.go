@@ -0,0 +1,56 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/types"
+)
+
+func TestNewView(t *testing.T) {
+	c := &Cycle{
+		nameRegexes: &nameRegexes{Tables: regexp.MustCompile("^$")},
+		usedNames:   map[string]struct{}{"tbl": {}},
+	}
+	commit := &Commit{}
+	table, err := NewTable(commit, "tbl", []*Column{
+		{Name: "pk", Type: &types.IntInstance{}},
+	}, []*Column{
+		{Name: "v1", Type: &types.IntInstance{}},
+	}, nil)
+	require.NoError(t, err)
+	defer table.Data.Close()
+	for i := 0; i < 20; i++ {
+		_, err = (&InsertStatement{}).GenerateStatement(table)
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 20; i++ {
+		view, err := NewView(c, table)
+		require.NoError(t, err)
+		require.NotEqual(t, "tbl", view.Name)
+		require.Contains(t, c.usedNames, view.Name)
+		createString := view.CreateString()
+		require.True(t, strings.HasPrefix(createString, "CREATE VIEW `"+view.Name+"` AS SELECT "))
+		require.True(t, strings.HasSuffix(createString, ";"))
+		require.NotContains(t, strings.TrimSuffix(createString, ";"), ";")
+		require.Equal(t, "DROP VIEW `"+view.Name+"`;", view.DropString())
+	}
+}
@@ -29,6 +29,8 @@ import (
 // Bigint represents the BIGINT MySQL type.
 type Bigint struct {
 	Distribution ranges.Int
+	// DisplayWidthProbability is the probability that a column declares a display width.
+	DisplayWidthProbability float64
 }
 
 var _ Type = (*Bigint)(nil)
@@ -40,11 +42,17 @@ func (b *Bigint) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (b *Bigint) Instance() (TypeInstance, error) {
-	return &BigintInstance{}, nil
+	attributes, err := newIntegerAttributes(b.DisplayWidthProbability, false)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &BigintInstance{attributes}, nil
 }
 
 // BigintInstance is the TypeInstance of Bigint.
-type BigintInstance struct{}
+type BigintInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*BigintInstance)(nil)
 
@@ -61,7 +69,7 @@ func (i *BigintInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *BigintInstance) Name(sqlite bool) string {
-	return "BIGINT"
+	return i.attributeName("BIGINT", false, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
@@ -29,6 +29,8 @@ import (
 // BigintUnsigned represents the BIGINT UNSIGNED MySQL type.
 type BigintUnsigned struct {
 	Distribution ranges.Int
+	// ZerofillProbability is the probability that a column declares a display width and ZEROFILL.
+	ZerofillProbability float64
 }
 
 var _ Type = (*BigintUnsigned)(nil)
@@ -40,11 +42,17 @@ func (b *BigintUnsigned) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (b *BigintUnsigned) Instance() (TypeInstance, error) {
-	return &BigintUnsignedInstance{}, nil
+	attributes, err := newIntegerAttributes(b.ZerofillProbability, true)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &BigintUnsignedInstance{attributes}, nil
 }
 
 // BigintUnsignedInstance is the TypeInstance of BigintUnsigned.
-type BigintUnsignedInstance struct{}
+type BigintUnsignedInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*BigintUnsignedInstance)(nil)
 
@@ -64,7 +72,7 @@ func (i *BigintUnsignedInstance) Name(sqlite bool) string {
 	if sqlite {
 		return "VARCHAR(20)"
 	}
-	return "BIGINT UNSIGNED"
+	return i.attributeName("BIGINT", true, false)
 }
 
 // MaxValueCount implements the TypeInstance interface.
@@ -25,8 +25,9 @@ import (
 
 // Binary represents the BINARY MySQL type.
 type Binary struct {
-	Distribution ranges.Int
-	Length       ranges.Int
+	Distribution    ranges.Int
+	Length          ranges.Int
+	UUIDProbability float64
 }
 
 var _ Type = (*Binary)(nil)
@@ -42,18 +43,34 @@ func (b *Binary) Instance() (TypeInstance, error) {
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	return &BinaryInstance{int(charLength)}, nil
+	uuid, err := isUUIDColumn(b.UUIDProbability)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if uuid {
+		return &BinaryInstance{16, true}, nil
+	}
+	return &BinaryInstance{int(charLength), false}, nil
 }
 
-// BinaryInstance is the TypeInstance of Binary.
+// BinaryInstance is the TypeInstance of Binary. When uuid is true, every value is the 16 bytes of a UUID.
 type BinaryInstance struct {
 	charLength int
+	uuid       bool
 }
 
 var _ TypeInstance = (*BinaryInstance)(nil)
+var _ PrefixableTypeInstance = (*BinaryInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *BinaryInstance) Get() (Value, error) {
+	if i.uuid {
+		uuid, err := rand.UUID()
+		if err != nil {
+			return NilValue{}, errors.Wrap(err)
+		}
+		return BinaryValue{StringValue(uuid)}, nil
+	}
 	v, err := rand.String(i.charLength)
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
@@ -76,9 +93,22 @@ func (i *BinaryInstance) Name(sqlite bool) string {
 
 // MaxValueCount implements the TypeInstance interface.
 func (i *BinaryInstance) MaxValueCount() float64 {
+	if i.uuid {
+		return uuidMaxValueCount
+	}
 	return math.Pow(float64(rand.StringCharSize()), float64(i.charLength))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *BinaryInstance) MaxPrefixLength() int64 {
+	return int64(i.charLength)
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *BinaryInstance) RequiresPrefix() bool {
+	return false
+}
+
 // BinaryValue is the Value type of a BinaryInstance.
 type BinaryValue struct {
 	StringValue
@@ -104,17 +134,35 @@ func (v BinaryValue) Name() string {
 	return "BINARY"
 }
 
-// MySQLString implements the Value interface.
+// MySQLString implements the Value interface. Values containing bytes outside of printable ASCII, such as UUIDs, are
+// written as hexadecimal literals.
 func (v BinaryValue) MySQLString() string {
-	return v.String()
+	if !v.isPrintable() {
+		return fmt.Sprintf("0x%X", string(v.StringValue))
+	}
+	return v.MySQLQuoted()
 }
 
-// SQLiteString implements the Value interface.
+// SQLiteString implements the Value interface. Values containing bytes outside of printable ASCII, such as UUIDs, are
+// written as blob literals.
 func (v BinaryValue) SQLiteString() string {
-	return v.String()
+	if !v.isPrintable() {
+		return fmt.Sprintf("X'%X'", string(v.StringValue))
+	}
+	return v.SQLiteQuoted()
+}
+
+// isPrintable returns whether every byte is printable ASCII, or one of the whitespace characters handled by escapes.
+func (v BinaryValue) isPrintable() bool {
+	for i := 0; i < len(v.StringValue); i++ {
+		if c := v.StringValue[i]; (c < 32 || c > 126) && c != '\n' && c != '\t' {
+			return false
+		}
+	}
+	return true
 }
 
 // CSVString implements the interface Value.
 func (v BinaryValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
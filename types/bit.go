@@ -23,6 +23,10 @@ import (
 	"github.com/dolthub/fuzzer/ranges"
 )
 
+// bitBoundaryRate controls how often a boundary value is generated, with one in every bitBoundaryRate values being a
+// boundary value.
+const bitBoundaryRate = 8
+
 // Bit represents the BIT MySQL type.
 type Bit struct {
 	Distribution ranges.Int
@@ -52,13 +56,33 @@ type BitInstance struct {
 
 var _ TypeInstance = (*BitInstance)(nil)
 
-// Get implements the TypeInstance interface.
+// Get implements the TypeInstance interface. Some values are deliberately chosen from the boundaries of the width, such
+// as the value with only the highest bit set, as uniformly random values rarely reach them for wide columns.
 func (i *BitInstance) Get() (Value, error) {
 	v, err := rand.Uint64()
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	if v%bitBoundaryRate == 0 {
+		boundaries := i.boundaryValues()
+		return BitValue{Uint64Value(boundaries[(v/bitBoundaryRate)%uint64(len(boundaries))])}, nil
+	}
+	return BitValue{Uint64Value(v & i.mask())}, nil
+}
+
+// mask returns the mask that covers every bit of this instance's width.
+func (i *BitInstance) mask() uint64 {
 	if i.width >= 64 {
-		return BitValue{Uint64Value(v)}, nil
+		return math.MaxUint64
 	}
-	return BitValue{Uint64Value(v % (1 << i.width))}, err
+	return (1 << i.width) - 1
+}
+
+// boundaryValues returns the values at the edges of this instance's width: zero, one, the high bit on its own, the
+// high bit with the lowest bit, and every bit set.
+func (i *BitInstance) boundaryValues() []uint64 {
+	highBit := uint64(1) << (i.width - 1)
+	return []uint64{0, 1, highBit, highBit | 1, i.mask()}
 }
 
 // TypeValue implements the TypeInstance interface.
@@ -136,9 +160,10 @@ func (v BitValue) Name() string {
 	return "Bit"
 }
 
-// MySQLString implements the Value interface.
+// MySQLString implements the Value interface. Uses a bit-value literal, as decimal literals greater than the maximum
+// signed integer are not always interpreted as unsigned.
 func (v BitValue) MySQLString() string {
-	return v.String()
+	return fmt.Sprintf("b'%b'", uint64(v.Uint64Value))
 }
 
 // SQLiteString implements the Value interface.
@@ -0,0 +1,61 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBit64HighBit(t *testing.T) {
+	highBit := BitValue{Uint64Value(1 << 63)}
+	require.Equal(t, "b'1"+strings.Repeat("0", 63)+"'", highBit.MySQLString())
+
+	// Dolt returns BIT values as big-endian bytes
+	fromDolt, err := BitValue{}.Convert([]byte{0x80, 0, 0, 0, 0, 0, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, highBit, fromDolt)
+
+	// SQLite stores BIT values as zero-padded strings, which must read back to the same value
+	sqliteStr := highBit.SQLiteString()
+	require.Equal(t, "'09223372036854775808'", sqliteStr)
+	fromSQLite, err := BitValue{}.Convert(sqliteStr[1 : len(sqliteStr)-1])
+	require.NoError(t, err)
+	require.Equal(t, highBit, fromSQLite)
+
+	// Comparisons must be unsigned, so the high bit is greater than every value without it
+	require.Equal(t, 1, highBit.Compare(BitValue{Uint64Value(1<<63 - 1)}))
+	require.Equal(t, -1, BitValue{Uint64Value(1)}.Compare(highBit))
+	require.Equal(t, 0, highBit.Compare(fromDolt))
+	require.Equal(t, -1, highBit.Compare(BitValue{Uint64Value(1<<64 - 1)}))
+}
+
+func TestBitBoundaryValues(t *testing.T) {
+	for _, width := range []uint64{1, 8, 63, 64} {
+		instance := &BitInstance{width}
+		boundaries := instance.boundaryValues()
+		require.Contains(t, boundaries, uint64(1)<<(width-1))
+		for _, boundary := range boundaries {
+			require.Equal(t, boundary, boundary&instance.mask())
+		}
+		for i := 0; i < 100; i++ {
+			v, err := instance.Get()
+			require.NoError(t, err)
+			require.Equal(t, uint64(v.(BitValue).Uint64Value), uint64(v.(BitValue).Uint64Value)&instance.mask())
+		}
+	}
+}
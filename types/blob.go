@@ -47,6 +47,7 @@ type BlobInstance struct {
 }
 
 var _ TypeInstance = (*BlobInstance)(nil)
+var _ PrefixableTypeInstance = (*BlobInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *BlobInstance) Get() (Value, error) {
@@ -54,7 +55,11 @@ func (i *BlobInstance) Get() (Value, error) {
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	n, err = boundaryLength(n, 65535)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -79,6 +84,16 @@ func (i *BlobInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *BlobInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *BlobInstance) RequiresPrefix() bool {
+	return true
+}
+
 // BlobValue is the Value type of a BlobInstance.
 type BlobValue struct {
 	StringValue
@@ -106,15 +121,15 @@ func (v BlobValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v BlobValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v BlobValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v BlobValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
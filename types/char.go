@@ -28,9 +28,10 @@ import (
 
 // Char represents the CHAR MySQL type.
 type Char struct {
-	Collations   []string
-	Distribution ranges.Int
-	Length       ranges.Int
+	Collations      []string
+	Distribution    ranges.Int
+	Length          ranges.Int
+	UUIDProbability float64
 }
 
 var _ Type = (*Char)(nil)
@@ -46,33 +47,48 @@ func (c *Char) Instance() (TypeInstance, error) {
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	colPos, err := rand.Uint64()
+	collation, err := randomCollation(c.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	colPos %= uint64(len(c.Collations))
-	collation, err := sql.ParseCollation(nil, &c.Collations[colPos], false)
+	uuid, err := isUUIDColumn(c.UUIDProbability)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	return &CharInstance{int(charLength), collation}, nil
+	if uuid {
+		return &CharInstance{36, collation, true, nil}, nil
+	}
+	return &CharInstance{int(charLength), collation, false, newCollationVariants(collation)}, nil
 }
 
-// CharInstance is the TypeInstance of Char.
+// CharInstance is the TypeInstance of Char. When uuid is true, every value is a UUID in its canonical form. Otherwise,
+// values may be variants of recent values, which is controlled by SetModernCollations.
 type CharInstance struct {
 	charLength int
 	collation  sql.Collation
+	uuid       bool
+	variants   *collationVariants
 }
 
 var _ TypeInstance = (*CharInstance)(nil)
+var _ PrefixableTypeInstance = (*CharInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *CharInstance) Get() (Value, error) {
-	v, err := rand.StringExtendedAlphanumeric(i.charLength)
+	if i.uuid {
+		uuid, err := rand.UUID()
+		if err != nil {
+			return NilValue{}, errors.Wrap(err)
+		}
+		return CharValue{StringValue(uuidString(uuid))}, nil
+	}
+	v, err := i.variants.get(func() (string, error) {
+		return rand.StringExtendedAlphanumeric(i.charLength)
+	})
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	return CharValue{StringValue(v)}, err
+	return CharValue{StringValue(v)}, nil
 }
 
 // TypeValue implements the TypeInstance interface.
@@ -90,9 +106,22 @@ func (i *CharInstance) Name(sqlite bool) string {
 
 // MaxValueCount implements the TypeInstance interface.
 func (i *CharInstance) MaxValueCount() float64 {
+	if i.uuid {
+		return uuidMaxValueCount
+	}
 	return math.Pow(float64(rand.StringExtendedAlphanumericCharSize()), float64(i.charLength))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *CharInstance) MaxPrefixLength() int64 {
+	return int64(i.charLength)
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *CharInstance) RequiresPrefix() bool {
+	return false
+}
+
 // CharValue is the Value type of a CharInstance.
 type CharValue struct {
 	StringValue
@@ -130,5 +159,5 @@ func (v CharValue) SQLiteString() string {
 
 // CSVString implements the interface Value.
 func (v CharValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
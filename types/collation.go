@@ -0,0 +1,78 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// forcedCollation is the name of the collation used by every new string column, in place of the collations from the
+// config file. An empty name does not force a collation.
+var forcedCollation string
+
+// SetForcedCollation sets the collation used by every new string column, regardless of the collations configured for
+// each type, which makes it simple to reproduce a bug that is specific to a single collation. An empty name restores
+// the configured collations. This is not safe for concurrent use, and should only be called before any types are
+// instanced.
+func SetForcedCollation(name string) error {
+	if name != "" {
+		if _, err := sql.ParseCollation(nil, &name, false); err != nil {
+			return errors.Wrap(err)
+		}
+	}
+	forcedCollation = name
+	return nil
+}
+
+// isExactCollation returns whether the collation compares strings by their exact bytes. The "_bin" collations other
+// than the "_0900_bin" collations are PAD SPACE, and therefore ignore trailing spaces.
+func isExactCollation(collation sql.Collation) bool {
+	name := collation.String()
+	return name == "binary" || strings.HasSuffix(name, "_0900_bin")
+}
+
+// randomCollation returns a random collation from the given collations, or the collation set by SetForcedCollation.
+// A random value is consumed even when a collation is forced, so that the rest of a seeded run is unchanged. At the
+// probability set by SetModernCollations, one of the modern collations is returned in place of the given collations.
+func randomCollation(collations []string) (sql.Collation, error) {
+	colPos, err := rand.StructureUint64()
+	if err != nil {
+		return sql.Collation_Default, errors.Wrap(err)
+	}
+	if modernCollationProbability > 0 {
+		roll, err := rand.StructureUint64()
+		if err != nil {
+			return sql.Collation_Default, errors.Wrap(err)
+		}
+		if float64(roll) < modernCollationProbability*math.MaxUint64 {
+			collations = modernCollations
+		}
+	}
+	name := forcedCollation
+	if name == "" {
+		name = collations[colPos%uint64(len(collations))]
+	}
+	collation, err := sql.ParseCollation(nil, &name, false)
+	if err != nil {
+		return sql.Collation_Default, errors.Wrap(err)
+	}
+	return collation, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+func TestForcedCollation(t *testing.T) {
+	require.Error(t, SetForcedCollation("not_a_collation"))
+	require.NoError(t, SetForcedCollation("utf8mb4_0900_bin"))
+	defer func() {
+		require.NoError(t, SetForcedCollation(""))
+	}()
+
+	stringTypes := []Type{
+		&Char{Collations: []string{"utf8mb4_0900_ai_ci"}, Length: ranges.NewInt([]int64{1, 10})},
+		&Varchar{Collations: []string{"utf8mb4_0900_ai_ci", "latin1_swedish_ci"}, Length: ranges.NewInt([]int64{1, 10})},
+	}
+	for _, stringType := range stringTypes {
+		for i := 0; i < 10; i++ {
+			instance, err := stringType.Instance()
+			require.NoError(t, err)
+			require.Contains(t, instance.Name(false), "COLLATE utf8mb4_0900_bin")
+		}
+	}
+	enum, err := (&Enum{
+		Collations:        []string{"utf8mb4_0900_ai_ci"},
+		ElementNameLength: ranges.NewInt([]int64{1, 5}),
+		NumberOfElements:  ranges.NewInt([]int64{1, 5}),
+	}).Instance()
+	require.NoError(t, err)
+	require.Equal(t, sql.Collation_utf8mb4_0900_bin.Name, enum.(*EnumInstance).collation.Name)
+
+	require.NoError(t, SetForcedCollation(""))
+	collation, err := randomCollation([]string{"latin1_swedish_ci"})
+	require.NoError(t, err)
+	require.Equal(t, sql.Collation_latin1_swedish_ci.Name, collation.Name)
+}
+
+func TestExactCollations(t *testing.T) {
+	for name, exact := range map[string]bool{
+		"binary":             true,
+		"utf8mb4_0900_bin":   true,
+		"utf8mb4_bin":        false,
+		"utf8mb4_0900_ai_ci": false,
+		"utf8mb4_0900_as_cs": false,
+		"latin1_swedish_ci":  false,
+	} {
+		collation, err := sql.ParseCollation(nil, &name, false)
+		require.NoError(t, err)
+		require.Equal(t, exact, isExactCollation(collation), name)
+	}
+}
+
+func TestModernCollations(t *testing.T) {
+	SetModernCollations(1, 1)
+	defer SetModernCollations(0, 0)
+
+	for i := 0; i < 20; i++ {
+		instance, err := (&Varchar{Collations: []string{"latin1_swedish_ci"}, Length: ranges.NewInt([]int64{1, 10})}).Instance()
+		require.NoError(t, err)
+		require.Contains(t, modernCollations, instance.(*VarcharInstance).collation.String())
+	}
+
+	// After the first value, every value is a variant of an earlier one, so they all match while ignoring case and accents
+	ci, err := sql.ParseCollation(nil, &[]string{"utf8mb4_0900_ai_ci"}[0], false)
+	require.NoError(t, err)
+	instance := &VarcharInstance{ranges.NewInt([]int64{5, 10}), ci, newCollationVariants(ci)}
+	unaccent := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ö", "o", "ü", "u", "ñ", "n", "ç", "c",
+		"Á", "A", "É", "E", "Í", "I", "Ö", "O", "Ü", "U", "Ñ", "N", "Ç", "C")
+	first, err := instance.Get()
+	require.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		val, err := instance.Get()
+		require.NoError(t, err)
+		require.True(t, strings.EqualFold(unaccent.Replace(first.String()), unaccent.Replace(val.String())))
+	}
+
+	// Variants are only generated for collations that ignore case or accents
+	bin, err := sql.ParseCollation(nil, &[]string{"utf8mb4_0900_bin"}[0], false)
+	require.NoError(t, err)
+	require.Nil(t, newCollationVariants(bin))
+	asCI, err := sql.ParseCollation(nil, &[]string{"utf8mb4_0900_as_ci"}[0], false)
+	require.NoError(t, err)
+	variants := newCollationVariants(asCI)
+	require.True(t, variants.caseInsensitive)
+	require.False(t, variants.accentInsensitive)
+	variant, err := variants.variant("abcABC")
+	require.NoError(t, err)
+	require.True(t, strings.EqualFold("abcABC", variant))
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// modernCollations are the newer utf8mb4 collations that are chosen at the probability set by SetModernCollations.
+// These are mostly case or accent insensitive, including language-specific collations with their own rules for
+// letters such as "ñ" and "ı", which is where comparison bugs tend to be found.
+var modernCollations = []string{
+	"utf8mb4_0900_ai_ci",
+	"utf8mb4_0900_as_ci",
+	"utf8mb4_0900_as_cs",
+	"utf8mb4_es_0900_ai_ci",
+	"utf8mb4_sv_0900_ai_ci",
+	"utf8mb4_tr_0900_ai_ci",
+}
+
+// maxRecentValues is the number of recent values of each column that variants are generated from.
+const maxRecentValues = 16
+
+// accentedLetters maps each letter that has an accented form to that form.
+var accentedLetters = map[byte]string{
+	'a': "á", 'e': "é", 'i': "í", 'o': "ö", 'u': "ü", 'n': "ñ", 'c': "ç",
+	'A': "Á", 'E': "É", 'I': "Í", 'O': "Ö", 'U': "Ü", 'N': "Ñ", 'C': "Ç",
+}
+
+// modernCollationProbability is the probability that a new string column uses one of the modern collations.
+var modernCollationProbability float64
+
+// collationVariantProbability is the probability that a value of a case or accent insensitive string column is a
+// variant of one of the column's recent values.
+var collationVariantProbability float64
+
+// SetModernCollations sets the probability that each new CHAR or VARCHAR column uses one of the modern collations in
+// place of its configured collations, along with the probability that each value of a case or accent insensitive column
+// only differs from one of the column's recent values by case or accents. A probability of 0 disables each. This is not
+// safe for concurrent use, and should only be called before any types are instanced.
+func SetModernCollations(probability float64, variantProbability float64) {
+	modernCollationProbability = probability
+	collationVariantProbability = variantProbability
+}
+
+// collationVariants generates values for a string column that the column's collation may consider equal to one of its
+// recent values, as they only differ by case or accents.
+type collationVariants struct {
+	caseInsensitive   bool
+	accentInsensitive bool
+	recent            []string
+	next              int
+}
+
+// newCollationVariants returns the variants for a column using the given collation. Returns nil when variants are
+// disabled, or when the collation is sensitive to both case and accents.
+func newCollationVariants(collation sql.Collation) *collationVariants {
+	if collationVariantProbability <= 0 {
+		return nil
+	}
+	name := collation.String()
+	cv := &collationVariants{
+		caseInsensitive:   strings.HasSuffix(name, "_ci"),
+		accentInsensitive: strings.Contains(name, "_ai_"),
+	}
+	if !cv.caseInsensitive && !cv.accentInsensitive {
+		return nil
+	}
+	return cv
+}
+
+// get returns a variant of a recent value at the variant probability, and otherwise returns a value from generate. Every
+// returned value becomes a recent value. Variants never exceed the size set by SetMaxValueBytes.
+func (cv *collationVariants) get(generate func() (string, error)) (string, error) {
+	if cv == nil {
+		return generate()
+	}
+	roll, err := rand.Uint64()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	var value string
+	if len(cv.recent) > 0 && float64(roll) < collationVariantProbability*math.MaxUint64 {
+		value, err = cv.variant(cv.recent[roll%uint64(len(cv.recent))])
+	} else {
+		value, err = generate()
+	}
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if len(cv.recent) < maxRecentValues {
+		cv.recent = append(cv.recent, value)
+	} else {
+		cv.recent[cv.next] = value
+		cv.next = (cv.next + 1) % maxRecentValues
+	}
+	return value, nil
+}
+
+// variant returns the value with the case of its letters randomly swapped when the collation is case insensitive, and
+// with its letters randomly accented when the collation is accent insensitive. Each accent adds a byte to the value.
+func (cv *collationVariants) variant(value string) (string, error) {
+	extraBytes := int64(-1)
+	if maxValueBytes > 0 {
+		extraBytes = maxValueBytes - int64(len(value))
+	}
+	sb := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		roll, err := rand.Uint64()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		if cv.caseInsensitive && roll&1 == 1 {
+			switch {
+			case ch >= 'a' && ch <= 'z':
+				ch -= 'a' - 'A'
+			case ch >= 'A' && ch <= 'Z':
+				ch += 'a' - 'A'
+			}
+		}
+		if accented, ok := accentedLetters[ch]; ok && cv.accentInsensitive && roll&2 == 2 && extraBytes != 0 {
+			sb.WriteString(accented)
+			extraBytes--
+			continue
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String(), nil
+}
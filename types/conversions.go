@@ -73,22 +73,25 @@ func ConvertGMSSchemaToFuzzerSchema(schema sql.Schema) (pkCols []Column, nonPKCo
 			fuzzerCol.Type = &DecimalInstance{int(decType.Precision()), int(decType.Scale())}
 		case sqltypes.Text:
 			stringType := sqlCol.Type.(sql.StringType)
-			fuzzerCol.Type = &TextInstance{ranges.NewInt([]int64{0, stringType.MaxByteLength()}), stringType.Collation()}
+			fuzzerCol.Type = &TextInstance{ranges.NewInt([]int64{0, stringType.MaxByteLength()}), stringType.Collation(),
+				stringType.MaxByteLength(), 0}
 		case sqltypes.Blob:
 			stringType := sqlCol.Type.(sql.StringType)
 			fuzzerCol.Type = &BlobInstance{ranges.NewInt([]int64{0, stringType.MaxByteLength()})}
 		case sqltypes.VarChar:
 			stringType := sqlCol.Type.(sql.StringType)
-			fuzzerCol.Type = &VarcharInstance{ranges.NewInt([]int64{0, stringType.MaxByteLength()}), stringType.Collation()}
+			fuzzerCol.Type = &VarcharInstance{ranges.NewInt([]int64{0, stringType.MaxByteLength()}), stringType.Collation(),
+				newCollationVariants(stringType.Collation())}
 		case sqltypes.VarBinary:
 			stringType := sqlCol.Type.(sql.StringType)
 			fuzzerCol.Type = &VarbinaryInstance{ranges.NewInt([]int64{0, stringType.MaxByteLength()})}
 		case sqltypes.Char:
 			stringType := sqlCol.Type.(sql.StringType)
-			fuzzerCol.Type = &CharInstance{int(stringType.MaxByteLength()), stringType.Collation()}
+			fuzzerCol.Type = &CharInstance{int(stringType.MaxByteLength()), stringType.Collation(), false,
+				newCollationVariants(stringType.Collation())}
 		case sqltypes.Binary:
 			stringType := sqlCol.Type.(sql.StringType)
-			fuzzerCol.Type = &BinaryInstance{int(stringType.MaxByteLength())}
+			fuzzerCol.Type = &BinaryInstance{int(stringType.MaxByteLength()), false}
 		case sqltypes.Bit:
 			bitType := sqlCol.Type.(sql.BitType)
 			fuzzerCol.Type = &BitInstance{uint64(bitType.NumberOfBits())}
@@ -26,6 +26,19 @@ import (
 	"github.com/dolthub/fuzzer/ranges"
 )
 
+// doubleEdgeValues are finite DOUBLE values that are stored and compared in subtle ways. These are both zeros, along
+// with the smallest and largest denormals, and the smallest normal value.
+var doubleEdgeValues = []Float64Value{
+	0,
+	Float64Value(math.Copysign(0, -1)),
+	math.SmallestNonzeroFloat64,
+	-math.SmallestNonzeroFloat64,
+	Float64Value(math.Float64frombits(0x000fffffffffffff)),
+	Float64Value(math.Float64frombits(0x800fffffffffffff)),
+	Float64Value(math.Float64frombits(0x0010000000000000)),
+	Float64Value(math.Float64frombits(0x8010000000000000)),
+}
+
 // Double represents the DOUBLE MySQL type.
 type Double struct {
 	Distribution ranges.Int
@@ -50,6 +63,13 @@ var _ TypeInstance = (*DoubleInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *DoubleInstance) Get() (Value, error) {
+	roll, err := rand.Uint64()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if roll%floatEdgeValueRarity == 0 {
+		return DoubleValue{doubleEdgeValues[(roll/floatEdgeValueRarity)%uint64(len(doubleEdgeValues))]}, nil
+	}
 	v, err := rand.Float64()
 	return DoubleValue{Float64Value(v)}, err
 }
@@ -119,6 +139,10 @@ func (v DoubleValue) MySQLString() string {
 // SQLiteString implements the Value interface.
 func (v DoubleValue) SQLiteString() string {
 	// SQLite doesn't store floats correctly, so we convert to bits and store those.
+	// MySQL treats negative zero as equal to positive zero, so they're stored the same to match uniqueness checks
+	if v.Float64Value == 0 {
+		v.Float64Value = 0
+	}
 	bitPattern := *(*uint64)(unsafe.Pointer(&v.Float64Value))
 	// Negative numbers sort after positive and in reverse, so this ensures correct sorting
 	bitPattern ^= (0xffffffffffffffff * (bitPattern >> 63)) + (0x8000000000000000 - (0x8000000000000000 * (bitPattern >> 63)))
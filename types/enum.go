@@ -44,12 +44,7 @@ func (e *Enum) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (e *Enum) Instance() (TypeInstance, error) {
-	colPos, err := rand.Uint64()
-	if err != nil {
-		return nil, errors.Wrap(err)
-	}
-	colPos %= uint64(len(e.Collations))
-	collation, err := sql.ParseCollation(nil, &e.Collations[colPos], false)
+	collation, err := randomCollation(e.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -159,6 +154,17 @@ func (v EnumValue) Convert(val interface{}) (Value, error) {
 	return v, nil
 }
 
+// ElementName returns the name of the element that the value refers to, quoted for use in MySQL. As names are unique,
+// MySQL resolves the name to the same index as the value.
+func (v EnumValue) ElementName() string {
+	for name, idx := range *v.elementMap {
+		if idx == uint16(v.Uint16Value) {
+			return StringValue(name).MySQLQuoted()
+		}
+	}
+	return v.String()
+}
+
 // Name implements the Value interface.
 func (v EnumValue) Name() string {
 	return "ENUM"
@@ -26,6 +26,23 @@ import (
 	"github.com/dolthub/fuzzer/ranges"
 )
 
+// floatEdgeValueRarity is the inverse of the probability that a FLOAT or DOUBLE value is chosen from its edge values,
+// rather than being randomly generated.
+const floatEdgeValueRarity = 32
+
+// floatEdgeValues are finite FLOAT values that are stored and compared in subtle ways. These are both zeros, along with
+// the smallest and largest denormals, and the smallest normal value.
+var floatEdgeValues = []Float32Value{
+	0,
+	Float32Value(math.Copysign(0, -1)),
+	math.SmallestNonzeroFloat32,
+	-math.SmallestNonzeroFloat32,
+	Float32Value(math.Float32frombits(0x007fffff)),
+	Float32Value(math.Float32frombits(0x807fffff)),
+	Float32Value(math.Float32frombits(0x00800000)),
+	Float32Value(math.Float32frombits(0x80800000)),
+}
+
 // Float represents the FLOAT MySQL type.
 type Float struct {
 	Distribution ranges.Int
@@ -50,6 +67,13 @@ var _ TypeInstance = (*FloatInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *FloatInstance) Get() (Value, error) {
+	roll, err := rand.Uint64()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if roll%floatEdgeValueRarity == 0 {
+		return FloatValue{floatEdgeValues[(roll/floatEdgeValueRarity)%uint64(len(floatEdgeValues))]}, nil
+	}
 	v, err := rand.Float32()
 	return FloatValue{Float32Value(v)}, err
 }
@@ -119,6 +143,10 @@ func (v FloatValue) MySQLString() string {
 // SQLiteString implements the Value interface.
 func (v FloatValue) SQLiteString() string {
 	// SQLite is weird with floats, so we convert to bits and store those.
+	// MySQL treats negative zero as equal to positive zero, so they're stored the same to match uniqueness checks
+	if v.Float32Value == 0 {
+		v.Float32Value = 0
+	}
 	bitPattern := *(*uint32)(unsafe.Pointer(&v.Float32Value))
 	// Negative numbers sort after positive and in reverse, so this ensures correct sorting
 	bitPattern ^= (0xffffffff * (bitPattern >> 31)) + (0x80000000 - (0x80000000 * (bitPattern >> 31)))
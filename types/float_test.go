@@ -0,0 +1,80 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloatNegativeZero(t *testing.T) {
+	negZero := FloatValue{Float32Value(math.Copysign(0, -1))}
+	posZero := FloatValue{Float32Value(0)}
+	require.Equal(t, 0, negZero.Compare(posZero))
+	require.Equal(t, posZero.ToBytes(), negZero.ToBytes())
+	require.Equal(t, posZero.SQLiteString(), negZero.SQLiteString())
+
+	// Validation compares the value read from SQLite against the value returned by Dolt, which may keep the sign
+	sqliteStr := negZero.SQLiteString()
+	fromSQLite, err := FloatValue{}.Convert(sqliteStr[1 : len(sqliteStr)-1])
+	require.NoError(t, err)
+	fromDolt, err := FloatValue{}.Convert([]byte("-0"))
+	require.NoError(t, err)
+	require.True(t, fromSQLite == fromDolt)
+	require.Equal(t, 0, fromSQLite.Compare(fromDolt))
+	require.Equal(t, fromSQLite.ToBytes(), fromDolt.ToBytes())
+}
+
+func TestDoubleNegativeZero(t *testing.T) {
+	negZero := DoubleValue{Float64Value(math.Copysign(0, -1))}
+	posZero := DoubleValue{Float64Value(0)}
+	require.Equal(t, 0, negZero.Compare(posZero))
+	require.Equal(t, posZero.ToBytes(), negZero.ToBytes())
+	require.Equal(t, posZero.SQLiteString(), negZero.SQLiteString())
+
+	sqliteStr := negZero.SQLiteString()
+	fromSQLite, err := DoubleValue{}.Convert(sqliteStr[1 : len(sqliteStr)-1])
+	require.NoError(t, err)
+	fromDolt, err := DoubleValue{}.Convert([]byte("-0"))
+	require.NoError(t, err)
+	require.True(t, fromSQLite == fromDolt)
+	require.Equal(t, 0, fromSQLite.Compare(fromDolt))
+	require.Equal(t, fromSQLite.ToBytes(), fromDolt.ToBytes())
+}
+
+func TestFloatEdgeValuesRoundTrip(t *testing.T) {
+	// Denormals must read back from SQLite unchanged, as their bits are stored directly
+	for _, edgeValue := range floatEdgeValues {
+		v := FloatValue{edgeValue}
+		sqliteStr := v.SQLiteString()
+		fromSQLite, err := FloatValue{}.Convert(sqliteStr[1 : len(sqliteStr)-1])
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Compare(fromSQLite))
+		// Dolt returns the shortest representation of the FLOAT
+		fromDolt, err := FloatValue{}.Convert([]byte(strconv.FormatFloat(float64(edgeValue), 'g', -1, 32)))
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Compare(fromDolt))
+	}
+	for _, edgeValue := range doubleEdgeValues {
+		v := DoubleValue{edgeValue}
+		sqliteStr := v.SQLiteString()
+		fromSQLite, err := DoubleValue{}.Convert(sqliteStr[1 : len(sqliteStr)-1])
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Compare(fromSQLite))
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// inlineBoundarySpread is the largest distance, in bytes, between the inline boundary and the length of a value that is
+// generated around it.
+const inlineBoundarySpread = 16
+
+// inlineBoundary is the size, in bytes, at which Dolt stores a BLOB or TEXT value out-of-line rather than inline. A
+// value of 0 does not generate values around a boundary.
+var inlineBoundary int64
+
+// inlineBoundaryProbability is the probability that a BLOB or TEXT value is generated around the inline boundary.
+var inlineBoundaryProbability float64
+
+// SetInlineBoundary sets the size, in bytes, at which Dolt stores BLOB and TEXT values out-of-line, along with the
+// probability that each such value is generated with a length just below or just above that size. A boundary or
+// probability of 0 disables this. This is not safe for concurrent use, and should only be called before any values are
+// generated.
+func SetInlineBoundary(boundary int64, probability float64) {
+	inlineBoundary = boundary
+	inlineBoundaryProbability = probability
+}
+
+// boundaryLength returns a length within inlineBoundarySpread of the inline boundary at the boundary probability, and
+// otherwise returns the given length. Columns whose maximum length cannot hold every value around the boundary always
+// use the given length, as they cannot cross it.
+func boundaryLength(n int64, maxLength int64) (int64, error) {
+	if inlineBoundary <= 0 || inlineBoundaryProbability <= 0 || inlineBoundary+inlineBoundarySpread > maxLength {
+		return n, nil
+	}
+	roll, err := rand.Uint64()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	if float64(roll) >= inlineBoundaryProbability*math.MaxUint64 {
+		return n, nil
+	}
+	offset, err := rand.Uint64()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	n = inlineBoundary - inlineBoundarySpread + int64(offset%(2*inlineBoundarySpread+1))
+	if n < 0 {
+		n = 0
+	}
+	return n, nil
+}
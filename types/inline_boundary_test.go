@@ -0,0 +1,56 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+func TestInlineBoundary(t *testing.T) {
+	SetInlineBoundary(100, 1)
+	defer SetInlineBoundary(0, 0)
+
+	blob, err := (&Blob{Length: ranges.NewInt([]int64{1, 10})}).Instance()
+	require.NoError(t, err)
+	sawBelow, sawAbove := false, false
+	for i := 0; i < 200; i++ {
+		v, err := blob.Get()
+		require.NoError(t, err)
+		n := len(v.(BlobValue).StringValue)
+		require.GreaterOrEqual(t, n, 100-inlineBoundarySpread)
+		require.LessOrEqual(t, n, 100+inlineBoundarySpread)
+		sawBelow = sawBelow || n < 100
+		sawAbove = sawAbove || n > 100
+	}
+	require.True(t, sawBelow)
+	require.True(t, sawAbove)
+
+	// TINYTEXT cannot hold a value beyond a larger boundary, so its lengths are unaffected
+	SetInlineBoundary(1000, 1)
+	tinytext, err := (&Tinytext{
+		Collations: []string{"utf8mb4_0900_ai_ci"},
+		Length:     ranges.NewInt([]int64{1, 10}),
+	}).Instance()
+	require.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		v, err := tinytext.Get()
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(v.(TinytextValue).StringValue), 10)
+	}
+}
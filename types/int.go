@@ -29,6 +29,8 @@ import (
 // Int represents the INT MySQL type.
 type Int struct {
 	Distribution ranges.Int
+	// DisplayWidthProbability is the probability that a column declares a display width.
+	DisplayWidthProbability float64
 }
 
 var _ Type = (*Int)(nil)
@@ -40,11 +42,17 @@ func (i *Int) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (i *Int) Instance() (TypeInstance, error) {
-	return &IntInstance{}, nil
+	attributes, err := newIntegerAttributes(i.DisplayWidthProbability, false)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &IntInstance{attributes}, nil
 }
 
 // IntInstance is the TypeInstance of Int.
-type IntInstance struct{}
+type IntInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*IntInstance)(nil)
 
@@ -61,7 +69,7 @@ func (i *IntInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *IntInstance) Name(sqlite bool) string {
-	return "INT"
+	return i.attributeName("INT", false, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
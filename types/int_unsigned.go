@@ -29,6 +29,8 @@ import (
 // IntUnsigned represents the INT UNSIGNED MySQL type.
 type IntUnsigned struct {
 	Distribution ranges.Int
+	// ZerofillProbability is the probability that a column declares a display width and ZEROFILL.
+	ZerofillProbability float64
 }
 
 var _ Type = (*IntUnsigned)(nil)
@@ -40,11 +42,17 @@ func (i *IntUnsigned) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (i *IntUnsigned) Instance() (TypeInstance, error) {
-	return &IntUnsignedInstance{}, nil
+	attributes, err := newIntegerAttributes(i.ZerofillProbability, true)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &IntUnsignedInstance{attributes}, nil
 }
 
 // IntUnsignedInstance is the TypeInstance of IntUnsigned.
-type IntUnsignedInstance struct{}
+type IntUnsignedInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*IntUnsignedInstance)(nil)
 
@@ -61,7 +69,7 @@ func (i *IntUnsignedInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *IntUnsignedInstance) Name(sqlite bool) string {
-	return "INT UNSIGNED"
+	return i.attributeName("INT", true, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
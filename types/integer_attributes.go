@@ -0,0 +1,75 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// maxDisplayWidth is the largest display width that MySQL allows on an integer type.
+const maxDisplayWidth = 255
+
+// integerAttributes are the display width and ZEROFILL attributes of an integer type. Both are deprecated in MySQL, and
+// neither changes which values may be stored, but ZEROFILL pads the returned values with leading zeros up to the
+// display width. Values are parsed back as integers, so padding does not affect validation.
+type integerAttributes struct {
+	// displayWidth is the declared display width, with 0 meaning that no width is declared.
+	displayWidth uint64
+	// zerofill is only valid for unsigned types, as MySQL implicitly makes ZEROFILL columns unsigned.
+	zerofill bool
+}
+
+// newIntegerAttributes returns random attributes, which are used with the given probability. For unsigned types, the
+// attributes include ZEROFILL, while signed types only declare a display width.
+func newIntegerAttributes(probability float64, unsigned bool) (integerAttributes, error) {
+	if probability <= 0 {
+		return integerAttributes{}, nil
+	}
+	roll, err := rand.StructureUint64()
+	if err != nil {
+		return integerAttributes{}, errors.Wrap(err)
+	}
+	if float64(roll) >= probability*math.MaxUint64 {
+		return integerAttributes{}, nil
+	}
+	width, err := rand.StructureUint64()
+	if err != nil {
+		return integerAttributes{}, errors.Wrap(err)
+	}
+	return integerAttributes{
+		displayWidth: (width % maxDisplayWidth) + 1,
+		zerofill:     unsigned,
+	}, nil
+}
+
+// attributeName returns the name of the integer type with its attributes, such as "INT(8) UNSIGNED ZEROFILL". SQLite
+// does not need the attributes, so they are omitted.
+func (a integerAttributes) attributeName(typeName string, unsigned bool, sqlite bool) string {
+	name := typeName
+	if a.displayWidth > 0 && !sqlite {
+		name += fmt.Sprintf("(%d)", a.displayWidth)
+	}
+	if unsigned {
+		name += " UNSIGNED"
+	}
+	if a.zerofill && !sqlite {
+		name += " ZEROFILL"
+	}
+	return name
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegerAttributes(t *testing.T) {
+	signed := &IntInstance{integerAttributes{displayWidth: 8}}
+	assert.Equal(t, "INT(8)", signed.Name(false))
+	assert.Equal(t, "INT", signed.Name(true))
+	unsigned := &SmallintUnsignedInstance{integerAttributes{displayWidth: 3, zerofill: true}}
+	assert.Equal(t, "SMALLINT(3) UNSIGNED ZEROFILL", unsigned.Name(false))
+	assert.Equal(t, "SMALLINT UNSIGNED", unsigned.Name(true))
+	assert.Equal(t, "BIGINT(20) UNSIGNED ZEROFILL", (&BigintUnsignedInstance{integerAttributes{displayWidth: 20, zerofill: true}}).Name(false))
+	assert.Equal(t, "VARCHAR(20)", (&BigintUnsignedInstance{integerAttributes{displayWidth: 20, zerofill: true}}).Name(true))
+	assert.Equal(t, "TINYINT", (&TinyintInstance{}).Name(false))
+
+	for _, unsignedType := range []bool{false, true} {
+		for i := 0; i < 100; i++ {
+			attributes, err := newIntegerAttributes(1, unsignedType)
+			require.NoError(t, err)
+			assert.True(t, attributes.displayWidth >= 1 && attributes.displayWidth <= maxDisplayWidth)
+			assert.Equal(t, unsignedType, attributes.zerofill)
+		}
+		attributes, err := newIntegerAttributes(0, unsignedType)
+		require.NoError(t, err)
+		assert.Equal(t, integerAttributes{}, attributes)
+	}
+}
+
+func TestZerofillConvert(t *testing.T) {
+	// ZEROFILL values are returned padded with leading zeros, which must parse to the same value
+	tests := []struct {
+		value    Value
+		padded   string
+		expected Value
+	}{
+		{TinyintUnsignedValue{}, "007", TinyintUnsignedValue{Uint8Value(7)}},
+		{SmallintUnsignedValue{}, "00042", SmallintUnsignedValue{Uint16Value(42)}},
+		{MediumintUnsignedValue{}, "00000000", MediumintUnsignedValue{Uint32Value(0)}},
+		{IntUnsignedValue{}, "0004294967295", IntUnsignedValue{Uint32Value(4294967295)}},
+		{BigintUnsignedValue{}, "00000000000000000001", BigintUnsignedValue{Uint64Value(1)}},
+	}
+	for _, test := range tests {
+		converted, err := test.value.Convert([]uint8(test.padded))
+		require.NoError(t, err)
+		assert.Equal(t, 0, converted.Compare(test.expected), "%s: %s", test.value.Name(), test.padded)
+	}
+}
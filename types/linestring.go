@@ -0,0 +1,138 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+// Linestring represents the LINESTRING MySQL type.
+type Linestring struct {
+	Distribution   ranges.Int
+	NumberOfPoints ranges.Int
+	SRIDs          []int64
+}
+
+var _ Type = (*Linestring)(nil)
+
+// GetOccurrenceRate implements the ranges.Distributable interface.
+func (l *Linestring) GetOccurrenceRate() (int64, error) {
+	return l.Distribution.RandomValue()
+}
+
+// Instance implements the Type interface.
+func (l *Linestring) Instance() (TypeInstance, error) {
+	srid, err := newSpatialSRID(l.SRIDs)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &LinestringInstance{l.NumberOfPoints, srid}, nil
+}
+
+// LinestringInstance is the TypeInstance of Linestring.
+type LinestringInstance struct {
+	numberOfPoints ranges.Int
+	srid           spatialSRID
+}
+
+var _ TypeInstance = (*LinestringInstance)(nil)
+
+// Get implements the TypeInstance interface.
+func (i *LinestringInstance) Get() (Value, error) {
+	n, err := i.numberOfPoints.RandomValue()
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	bounds := i.srid.bounds()
+	points := make([]spatialPoint, n)
+	for j := range points {
+		points[j].x, err = spatialCoordinate(bounds.x)
+		if err != nil {
+			return NilValue{}, errors.Wrap(err)
+		}
+		points[j].y, err = spatialCoordinate(bounds.y)
+		if err != nil {
+			return NilValue{}, errors.Wrap(err)
+		}
+	}
+	return LinestringValue{StringValue(spatialWithSRID("LINESTRING"+spatialRingString(points), i.srid.srid))}, nil
+}
+
+// TypeValue implements the TypeInstance interface.
+func (i *LinestringInstance) TypeValue() Value {
+	return LinestringValue{StringValue("")}
+}
+
+// Name implements the TypeInstance interface.
+func (i *LinestringInstance) Name(sqlite bool) string {
+	if sqlite {
+		return "TEXT"
+	}
+	return i.srid.name("LINESTRING")
+}
+
+// MaxValueCount implements the TypeInstance interface.
+func (i *LinestringInstance) MaxValueCount() float64 {
+	return math.MaxFloat64
+}
+
+// LinestringValue is the Value type of a LinestringInstance. The value is stored as WKT, which is also its canonical
+// form for comparisons. Values with a nonzero SRID are prefixed by their SRID.
+type LinestringValue struct {
+	StringValue
+}
+
+var _ Value = LinestringValue{}
+
+// Convert implements the Value interface.
+func (v LinestringValue) Convert(val interface{}) (Value, error) {
+	switch val := val.(type) {
+	case string:
+		v.StringValue = StringValue(val)
+	case []byte:
+		wkt, err := spatialWKBToWKT(val, wkbLineString)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		v.StringValue = StringValue(wkt)
+	default:
+		return nil, errors.New(fmt.Sprintf("cannot convert %T to %T", val, v.Name()))
+	}
+	return v, nil
+}
+
+// Name implements the Value interface.
+func (v LinestringValue) Name() string {
+	return "LINESTRING"
+}
+
+// MySQLString implements the Value interface.
+func (v LinestringValue) MySQLString() string {
+	return spatialMySQLString(string(v.StringValue))
+}
+
+// SQLiteString implements the Value interface.
+func (v LinestringValue) SQLiteString() string {
+	return v.String()
+}
+
+// CSVString implements the interface Value.
+func (v LinestringValue) CSVString() string {
+	return v.StringTerminating(34)
+}
@@ -47,6 +47,7 @@ type LongblobInstance struct {
 }
 
 var _ TypeInstance = (*LongblobInstance)(nil)
+var _ PrefixableTypeInstance = (*LongblobInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *LongblobInstance) Get() (Value, error) {
@@ -54,7 +55,11 @@ func (i *LongblobInstance) Get() (Value, error) {
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	n, err = boundaryLength(n, maxPracticalTextLength)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -79,6 +84,16 @@ func (i *LongblobInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *LongblobInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *LongblobInstance) RequiresPrefix() bool {
+	return true
+}
+
 // LongblobValue is the Value type of a LongblobInstance.
 type LongblobValue struct {
 	StringValue
@@ -106,15 +121,15 @@ func (v LongblobValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v LongblobValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v LongblobValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v LongblobValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
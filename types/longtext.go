@@ -23,13 +23,15 @@ import (
 	"github.com/dolthub/fuzzer/errors"
 	"github.com/dolthub/fuzzer/rand"
 	"github.com/dolthub/fuzzer/ranges"
+	"github.com/dolthub/fuzzer/utils"
 )
 
 // Longtext represents the LONGTEXT MySQL type.
 type Longtext struct {
-	Collations   []string
-	Distribution ranges.Int
-	Length       ranges.Int
+	Collations           []string
+	Distribution         ranges.Int
+	Length               ranges.Int
+	MaxLengthProbability float64
 }
 
 var _ Type = (*Longtext)(nil)
@@ -41,33 +43,36 @@ func (l *Longtext) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (l *Longtext) Instance() (TypeInstance, error) {
-	colPos, err := rand.Uint64()
+	collation, err := randomCollation(l.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-	colPos %= uint64(len(l.Collations))
-	collation, err := sql.ParseCollation(nil, &l.Collations[colPos], false)
-	if err != nil {
-		return nil, errors.Wrap(err)
-	}
-	return &LongtextInstance{l.Length, collation}, nil
+	maxLength := utils.MinInt64(4294967295/collation.CharSet.MaxLength(), maxPracticalTextLength)
+	return &LongtextInstance{l.Length, collation, maxLength, l.MaxLengthProbability}, nil
 }
 
 // LongtextInstance is the TypeInstance of Longtext.
 type LongtextInstance struct {
-	length    ranges.Int
-	collation sql.Collation
+	length               ranges.Int
+	collation            sql.Collation
+	maxLength            int64
+	maxLengthProbability float64
 }
 
 var _ TypeInstance = (*LongtextInstance)(nil)
+var _ PrefixableTypeInstance = (*LongtextInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *LongtextInstance) Get() (Value, error) {
-	n, err := i.length.RandomValue()
+	n, err := textLength(i.length, i.maxLength, i.maxLengthProbability)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	n, err = boundaryLength(n, i.maxLength)
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -89,6 +94,16 @@ func (i *LongtextInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *LongtextInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *LongtextInstance) RequiresPrefix() bool {
+	return true
+}
+
 // LongtextValue is the Value type of a LongtextInstance.
 type LongtextValue struct {
 	StringValue
@@ -116,15 +131,15 @@ func (v LongtextValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v LongtextValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v LongtextValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v LongtextValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
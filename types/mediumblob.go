@@ -47,6 +47,7 @@ type MediumblobInstance struct {
 }
 
 var _ TypeInstance = (*MediumblobInstance)(nil)
+var _ PrefixableTypeInstance = (*MediumblobInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *MediumblobInstance) Get() (Value, error) {
@@ -54,7 +55,11 @@ func (i *MediumblobInstance) Get() (Value, error) {
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	n, err = boundaryLength(n, maxPracticalTextLength)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -79,6 +84,16 @@ func (i *MediumblobInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *MediumblobInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *MediumblobInstance) RequiresPrefix() bool {
+	return true
+}
+
 // MediumblobValue is the Value type of a MediumblobInstance.
 type MediumblobValue struct {
 	StringValue
@@ -106,15 +121,15 @@ func (v MediumblobValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v MediumblobValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v MediumblobValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v MediumblobValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
@@ -27,6 +27,8 @@ import (
 // Mediumint represents the MEDIUMINT MySQL type.
 type Mediumint struct {
 	Distribution ranges.Int
+	// DisplayWidthProbability is the probability that a column declares a display width.
+	DisplayWidthProbability float64
 }
 
 var _ Type = (*Mediumint)(nil)
@@ -38,11 +40,17 @@ func (m *Mediumint) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (m *Mediumint) Instance() (TypeInstance, error) {
-	return &MediumintInstance{}, nil
+	attributes, err := newIntegerAttributes(m.DisplayWidthProbability, false)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &MediumintInstance{attributes}, nil
 }
 
 // MediumintInstance is the TypeInstance of Mediumint.
-type MediumintInstance struct{}
+type MediumintInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*MediumintInstance)(nil)
 
@@ -59,7 +67,7 @@ func (i *MediumintInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *MediumintInstance) Name(sqlite bool) string {
-	return "MEDIUMINT"
+	return i.attributeName("MEDIUMINT", false, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
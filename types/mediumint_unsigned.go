@@ -27,6 +27,8 @@ import (
 // MediumintUnsigned represents the MEDIUMINT UNSIGNED MySQL type.
 type MediumintUnsigned struct {
 	Distribution ranges.Int
+	// ZerofillProbability is the probability that a column declares a display width and ZEROFILL.
+	ZerofillProbability float64
 }
 
 var _ Type = (*MediumintUnsigned)(nil)
@@ -38,11 +40,17 @@ func (m *MediumintUnsigned) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (m *MediumintUnsigned) Instance() (TypeInstance, error) {
-	return &MediumintUnsignedInstance{}, nil
+	attributes, err := newIntegerAttributes(m.ZerofillProbability, true)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &MediumintUnsignedInstance{attributes}, nil
 }
 
 // MediumintUnsignedInstance is the TypeInstance of MediumintUnsigned.
-type MediumintUnsignedInstance struct{}
+type MediumintUnsignedInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*MediumintUnsignedInstance)(nil)
 
@@ -59,7 +67,7 @@ func (i *MediumintUnsignedInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *MediumintUnsignedInstance) Name(sqlite bool) string {
-	return "MEDIUMINT UNSIGNED"
+	return i.attributeName("MEDIUMINT", true, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
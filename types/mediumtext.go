@@ -28,9 +28,10 @@ import (
 
 // Mediumtext represents the MEDIUMTEXT MySQL type.
 type Mediumtext struct {
-	Collations   []string
-	Distribution ranges.Int
-	Length       ranges.Int
+	Collations           []string
+	Distribution         ranges.Int
+	Length               ranges.Int
+	MaxLengthProbability float64
 }
 
 var _ Type = (*Mediumtext)(nil)
@@ -42,34 +43,37 @@ func (m *Mediumtext) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (m *Mediumtext) Instance() (TypeInstance, error) {
-	colPos, err := rand.Uint64()
-	if err != nil {
-		return nil, errors.Wrap(err)
-	}
-	colPos %= uint64(len(m.Collations))
-	collation, err := sql.ParseCollation(nil, &m.Collations[colPos], false)
+	collation, err := randomCollation(m.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
 	charLength := utils.MinInt64(m.Length.Upperbound, 16777215/collation.CharSet.MaxLength())
-	return &MediumtextInstance{ranges.NewInt([]int64{m.Length.Lowerbound, charLength}), collation}, nil
+	maxLength := utils.MinInt64(16777215/collation.CharSet.MaxLength(), maxPracticalTextLength)
+	return &MediumtextInstance{ranges.NewInt([]int64{m.Length.Lowerbound, charLength}), collation, maxLength, m.MaxLengthProbability}, nil
 }
 
 // MediumtextInstance is the TypeInstance of Mediumtext.
 type MediumtextInstance struct {
-	length    ranges.Int
-	collation sql.Collation
+	length               ranges.Int
+	collation            sql.Collation
+	maxLength            int64
+	maxLengthProbability float64
 }
 
 var _ TypeInstance = (*MediumtextInstance)(nil)
+var _ PrefixableTypeInstance = (*MediumtextInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *MediumtextInstance) Get() (Value, error) {
-	n, err := i.length.RandomValue()
+	n, err := textLength(i.length, i.maxLength, i.maxLengthProbability)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	n, err = boundaryLength(n, i.maxLength)
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -91,6 +95,16 @@ func (i *MediumtextInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *MediumtextInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *MediumtextInstance) RequiresPrefix() bool {
+	return true
+}
+
 // MediumtextValue is the Value type of a MediumtextInstance.
 type MediumtextValue struct {
 	StringValue
@@ -118,15 +132,15 @@ func (v MediumtextValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v MediumtextValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v MediumtextValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v MediumtextValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
@@ -0,0 +1,174 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/ranges"
+	"github.com/dolthub/fuzzer/utils"
+)
+
+// integerRanks are the integer types that may be modified into one another, ordered by their storage size. The
+// unsigned types use the same ranks as their signed counterparts.
+var integerRanks = map[string]int{
+	"TINYINT":            1,
+	"SMALLINT":           2,
+	"MEDIUMINT":          3,
+	"INT":                4,
+	"BIGINT":             5,
+	"TINYINT_UNSIGNED":   1,
+	"SMALLINT_UNSIGNED":  2,
+	"MEDIUMINT_UNSIGNED": 3,
+	"INT_UNSIGNED":       4,
+	"BIGINT_UNSIGNED":    5,
+}
+
+// unsignedIntegers are the unsigned integer types.
+var unsignedIntegers = map[string]struct{}{
+	"TINYINT_UNSIGNED":   {},
+	"SMALLINT_UNSIGNED":  {},
+	"MEDIUMINT_UNSIGNED": {},
+	"INT_UNSIGNED":       {},
+	"BIGINT_UNSIGNED":    {},
+}
+
+// TypeInstanceName returns the name of the given TypeInstance's type, as used in the config file (such as
+// "INT_UNSIGNED"). Returns an empty string for instances that cannot be modified.
+func TypeInstanceName(typeInstance TypeInstance) string {
+	switch typeInstance.(type) {
+	case *TinyintInstance:
+		return "TINYINT"
+	case *SmallintInstance:
+		return "SMALLINT"
+	case *MediumintInstance:
+		return "MEDIUMINT"
+	case *IntInstance:
+		return "INT"
+	case *BigintInstance:
+		return "BIGINT"
+	case *TinyintUnsignedInstance:
+		return "TINYINT_UNSIGNED"
+	case *SmallintUnsignedInstance:
+		return "SMALLINT_UNSIGNED"
+	case *MediumintUnsignedInstance:
+		return "MEDIUMINT_UNSIGNED"
+	case *IntUnsignedInstance:
+		return "INT_UNSIGNED"
+	case *BigintUnsignedInstance:
+		return "BIGINT_UNSIGNED"
+	case *VarcharInstance:
+		return "VARCHAR"
+	case *VarbinaryInstance:
+		return "VARBINARY"
+	default:
+		return ""
+	}
+}
+
+// IsLosslessModification returns whether a column of the first type may always be modified into the second type without
+// losing any data. Integers may be widened, with unsigned integers also being widened into larger signed integers.
+// VARCHAR and VARBINARY columns may only be modified into a longer length of the same type.
+func IsLosslessModification(from string, to string) bool {
+	if from == "VARCHAR" || from == "VARBINARY" {
+		return from == to
+	}
+	fromRank, ok := integerRanks[from]
+	if !ok {
+		return false
+	}
+	toRank, ok := integerRanks[to]
+	if !ok || toRank <= fromRank {
+		return false
+	}
+	_, fromUnsigned := unsignedIntegers[from]
+	_, toUnsigned := unsignedIntegers[to]
+	return fromUnsigned || !toUnsigned
+}
+
+// ModifyTypeInstance returns a TypeInstance of the given type that is able to hold every value of the given instance.
+// The given type must be a lossless modification of the instance's type.
+func ModifyTypeInstance(from TypeInstance, to string) (TypeInstance, error) {
+	if !IsLosslessModification(TypeInstanceName(from), to) {
+		return nil, errors.New(fmt.Sprintf("cannot losslessly modify %s into %s", from.Name(false), to))
+	}
+	switch from := from.(type) {
+	case *VarcharInstance:
+		upperbound, err := widenLength(from.length.Upperbound, 65535/from.collation.CharSet.MaxLength())
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		return &VarcharInstance{ranges.NewInt([]int64{from.length.Lowerbound, upperbound}), from.collation,
+			newCollationVariants(from.collation)}, nil
+	case *VarbinaryInstance:
+		upperbound, err := widenLength(from.length.Upperbound, 65535)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		return &VarbinaryInstance{ranges.NewInt([]int64{from.length.Lowerbound, upperbound})}, nil
+	}
+	switch to {
+	case "SMALLINT":
+		return &SmallintInstance{}, nil
+	case "MEDIUMINT":
+		return &MediumintInstance{}, nil
+	case "INT":
+		return &IntInstance{}, nil
+	case "BIGINT":
+		return &BigintInstance{}, nil
+	case "SMALLINT_UNSIGNED":
+		return &SmallintUnsignedInstance{}, nil
+	case "MEDIUMINT_UNSIGNED":
+		return &MediumintUnsignedInstance{}, nil
+	case "INT_UNSIGNED":
+		return &IntUnsignedInstance{}, nil
+	case "BIGINT_UNSIGNED":
+		return &BigintUnsignedInstance{}, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("cannot modify into %s", to))
+	}
+}
+
+// ConvertValue converts the given value into the Value type of the given TypeInstance. NULL values remain NULL.
+func ConvertValue(val Value, typeInstance TypeInstance) (Value, error) {
+	if _, ok := val.Primitive().(NilValue); ok {
+		return NilValue{}, nil
+	}
+	valuer, ok := val.Primitive().(driver.Valuer)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("cannot convert %s to %s", val.Name(), typeInstance.Name(false)))
+	}
+	driverVal, err := valuer.Value()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return typeInstance.TypeValue().Convert(driverVal)
+}
+
+// widenLength returns a random length that is larger than the given length, without exceeding the maximum. Returns the
+// given length if it is already at the maximum.
+func widenLength(length int64, maxLength int64) (int64, error) {
+	if length >= maxLength {
+		return length, nil
+	}
+	v, err := rand.StructureUint64()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	return utils.MinInt64(length+int64(v%uint64(length))+1, maxLength), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+func TestIsLosslessModification(t *testing.T) {
+	require.True(t, IsLosslessModification("TINYINT", "BIGINT"))
+	require.True(t, IsLosslessModification("INT_UNSIGNED", "BIGINT"))
+	require.True(t, IsLosslessModification("INT_UNSIGNED", "BIGINT_UNSIGNED"))
+	require.True(t, IsLosslessModification("VARCHAR", "VARCHAR"))
+	require.False(t, IsLosslessModification("INT", "INT"))
+	require.False(t, IsLosslessModification("BIGINT", "INT"))
+	require.False(t, IsLosslessModification("INT", "BIGINT_UNSIGNED"))
+	require.False(t, IsLosslessModification("INT_UNSIGNED", "INT"))
+	require.False(t, IsLosslessModification("VARCHAR", "VARBINARY"))
+	require.False(t, IsLosslessModification("FLOAT", "DOUBLE"))
+}
+
+func TestModifyTypeInstance(t *testing.T) {
+	newType, err := ModifyTypeInstance(&IntUnsignedInstance{}, "BIGINT")
+	require.NoError(t, err)
+	require.IsType(t, &BigintInstance{}, newType)
+	v, err := ConvertValue(IntUnsignedValue{Uint32Value(4294967295)}, newType)
+	require.NoError(t, err)
+	require.Equal(t, BigintValue{Int64Value(4294967295)}, v)
+	v, err = ConvertValue(NilValue{}, newType)
+	require.NoError(t, err)
+	require.Equal(t, NilValue{}, v)
+
+	varbinary := &VarbinaryInstance{ranges.NewInt([]int64{1, 10})}
+	newType, err = ModifyTypeInstance(varbinary, "VARBINARY")
+	require.NoError(t, err)
+	require.Greater(t, newType.(*VarbinaryInstance).length.Upperbound, int64(10))
+	require.LessOrEqual(t, newType.(*VarbinaryInstance).length.Upperbound, int64(20))
+
+	_, err = ModifyTypeInstance(&BigintInstance{}, "INT")
+	require.Error(t, err)
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+// Polygon represents the POLYGON MySQL type.
+type Polygon struct {
+	Distribution   ranges.Int
+	NumberOfPoints ranges.Int
+	SRIDs          []int64
+}
+
+var _ Type = (*Polygon)(nil)
+
+// GetOccurrenceRate implements the ranges.Distributable interface.
+func (p *Polygon) GetOccurrenceRate() (int64, error) {
+	return p.Distribution.RandomValue()
+}
+
+// Instance implements the Type interface.
+func (p *Polygon) Instance() (TypeInstance, error) {
+	srid, err := newSpatialSRID(p.SRIDs)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &PolygonInstance{p.NumberOfPoints, srid}, nil
+}
+
+// PolygonInstance is the TypeInstance of Polygon.
+type PolygonInstance struct {
+	numberOfPoints ranges.Int
+	srid           spatialSRID
+}
+
+var _ TypeInstance = (*PolygonInstance)(nil)
+
+// Get implements the TypeInstance interface. Polygons consist of a single ring, with its vertices placed at sorted
+// angles around a center point. This guarantees a ring that does not intersect itself. The ring is closed by repeating
+// the first vertex at the end, therefore the ring contains one more point than the number of vertices.
+func (i *PolygonInstance) Get() (Value, error) {
+	n, err := i.numberOfPoints.RandomValue()
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	// The radius is limited so that every vertex stays within the bounds of the SRID
+	bounds := i.srid.bounds()
+	maxRadius := math.Min(1000, math.Floor(bounds.y/10))
+	center := spatialPoint{}
+	center.x, err = spatialCoordinate(bounds.x - maxRadius)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	center.y, err = spatialCoordinate(bounds.y - maxRadius)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	angles := make([]float64, n)
+	for j := range angles {
+		v, err := rand.Uint64()
+		if err != nil {
+			return NilValue{}, errors.Wrap(err)
+		}
+		angles[j] = (float64(v) / math.MaxUint64) * 2 * math.Pi
+	}
+	sort.Float64s(angles)
+	points := make([]spatialPoint, n+1)
+	for j, angle := range angles {
+		v, err := rand.Uint64()
+		if err != nil {
+			return NilValue{}, errors.Wrap(err)
+		}
+		radius := float64(v%uint64(maxRadius)) + 1
+		points[j].x = roundSpatialCoordinate(center.x + radius*math.Cos(angle))
+		points[j].y = roundSpatialCoordinate(center.y + radius*math.Sin(angle))
+	}
+	points[n] = points[0]
+	return PolygonValue{StringValue(spatialWithSRID("POLYGON("+spatialRingString(points)+")", i.srid.srid))}, nil
+}
+
+// TypeValue implements the TypeInstance interface.
+func (i *PolygonInstance) TypeValue() Value {
+	return PolygonValue{StringValue("")}
+}
+
+// Name implements the TypeInstance interface.
+func (i *PolygonInstance) Name(sqlite bool) string {
+	if sqlite {
+		return "TEXT"
+	}
+	return i.srid.name("POLYGON")
+}
+
+// MaxValueCount implements the TypeInstance interface.
+func (i *PolygonInstance) MaxValueCount() float64 {
+	return math.MaxFloat64
+}
+
+// PolygonValue is the Value type of a PolygonInstance. The value is stored as WKT, which is also its canonical form for
+// comparisons. Values with a nonzero SRID are prefixed by their SRID.
+type PolygonValue struct {
+	StringValue
+}
+
+var _ Value = PolygonValue{}
+
+// Convert implements the Value interface.
+func (v PolygonValue) Convert(val interface{}) (Value, error) {
+	switch val := val.(type) {
+	case string:
+		v.StringValue = StringValue(val)
+	case []byte:
+		wkt, err := spatialWKBToWKT(val, wkbPolygon)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		v.StringValue = StringValue(wkt)
+	default:
+		return nil, errors.New(fmt.Sprintf("cannot convert %T to %T", val, v.Name()))
+	}
+	return v, nil
+}
+
+// Name implements the Value interface.
+func (v PolygonValue) Name() string {
+	return "POLYGON"
+}
+
+// MySQLString implements the Value interface.
+func (v PolygonValue) MySQLString() string {
+	return spatialMySQLString(string(v.StringValue))
+}
+
+// SQLiteString implements the Value interface.
+func (v PolygonValue) SQLiteString() string {
+	return v.String()
+}
+
+// CSVString implements the interface Value.
+func (v PolygonValue) CSVString() string {
+	return v.StringTerminating(34)
+}
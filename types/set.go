@@ -44,12 +44,7 @@ func (s *Set) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (s *Set) Instance() (TypeInstance, error) {
-	colPos, err := rand.Uint64()
-	if err != nil {
-		return nil, errors.Wrap(err)
-	}
-	colPos %= uint64(len(s.Collations))
-	collation, err := sql.ParseCollation(nil, &s.Collations[colPos], false)
+	collation, err := randomCollation(s.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -29,6 +29,8 @@ import (
 // Smallint represents the SMALLINT MySQL type.
 type Smallint struct {
 	Distribution ranges.Int
+	// DisplayWidthProbability is the probability that a column declares a display width.
+	DisplayWidthProbability float64
 }
 
 var _ Type = (*Smallint)(nil)
@@ -40,11 +42,17 @@ func (s *Smallint) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (s *Smallint) Instance() (TypeInstance, error) {
-	return &SmallintInstance{}, nil
+	attributes, err := newIntegerAttributes(s.DisplayWidthProbability, false)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &SmallintInstance{attributes}, nil
 }
 
 // SmallintInstance is the TypeInstance of Smallint.
-type SmallintInstance struct{}
+type SmallintInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*SmallintInstance)(nil)
 
@@ -61,7 +69,7 @@ func (i *SmallintInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *SmallintInstance) Name(sqlite bool) string {
-	return "SMALLINT"
+	return i.attributeName("SMALLINT", false, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
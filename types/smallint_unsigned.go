@@ -29,6 +29,8 @@ import (
 // SmallintUnsigned represents the SMALLINT UNSIGNED MySQL type.
 type SmallintUnsigned struct {
 	Distribution ranges.Int
+	// ZerofillProbability is the probability that a column declares a display width and ZEROFILL.
+	ZerofillProbability float64
 }
 
 var _ Type = (*SmallintUnsigned)(nil)
@@ -40,11 +42,17 @@ func (s *SmallintUnsigned) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (s *SmallintUnsigned) Instance() (TypeInstance, error) {
-	return &SmallintUnsignedInstance{}, nil
+	attributes, err := newIntegerAttributes(s.ZerofillProbability, true)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &SmallintUnsignedInstance{attributes}, nil
 }
 
 // SmallintUnsignedInstance is the TypeInstance of SmallintUnsigned.
-type SmallintUnsignedInstance struct{}
+type SmallintUnsignedInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*SmallintUnsignedInstance)(nil)
 
@@ -61,7 +69,7 @@ func (i *SmallintUnsignedInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *SmallintUnsignedInstance) Name(sqlite bool) string {
-	return "SMALLINT UNSIGNED"
+	return i.attributeName("SMALLINT", true, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
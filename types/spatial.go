@@ -0,0 +1,225 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// The geometry type identifiers used in WKB.
+const (
+	wkbLineString uint32 = 2
+	wkbPolygon    uint32 = 3
+)
+
+// IsSpatial returns whether the given type instance is a spatial type. Spatial types may only be indexed using spatial
+// indexes, so they are excluded from regular indexes.
+func IsSpatial(typeInstance TypeInstance) bool {
+	switch typeInstance.(type) {
+	case *LinestringInstance, *PolygonInstance:
+		return true
+	default:
+		return false
+	}
+}
+
+// spatialPoint is a single point within a spatial value.
+type spatialPoint struct {
+	x float64
+	y float64
+}
+
+// String returns the point in the form used within WKT.
+func (p spatialPoint) String() string {
+	return strconv.FormatFloat(p.x, 'f', -1, 64) + " " + strconv.FormatFloat(p.y, 'f', -1, 64)
+}
+
+// spatialSRIDBounds contains the SRIDs that spatial columns may be qualified with, mapped to the largest magnitude of
+// each axis. SRID 4326 is geographic, so its points are a longitude followed by a latitude. SRID 3857 uses the same
+// bounds as SRID 0, which are well within its true bounds.
+var spatialSRIDBounds = map[uint32]spatialPoint{
+	0:    {100000, 100000},
+	3857: {100000, 100000},
+	4326: {180, 90},
+}
+
+// IsSupportedSRID returns whether spatial columns may be qualified with the given SRID.
+func IsSupportedSRID(srid int64) bool {
+	if srid < 0 || srid > math.MaxUint32 {
+		return false
+	}
+	_, ok := spatialSRIDBounds[uint32(srid)]
+	return ok
+}
+
+// spatialSRID is the SRID attribute of a spatial column. Unqualified columns accept values of any SRID, however the
+// fuzzer only generates values with an SRID of 0 for them.
+type spatialSRID struct {
+	srid      uint32
+	qualified bool
+}
+
+// newSpatialSRID returns a random SRID attribute from the given SRIDs. Each SRID has the same chance of being chosen as
+// leaving the column unqualified. If no SRIDs are given, then the column is always unqualified.
+func newSpatialSRID(srids []int64) (spatialSRID, error) {
+	roll, err := rand.Uint64()
+	if err != nil {
+		return spatialSRID{}, errors.Wrap(err)
+	}
+	roll %= uint64(len(srids) + 1)
+	if roll == uint64(len(srids)) {
+		return spatialSRID{}, nil
+	}
+	return spatialSRID{uint32(srids[roll]), true}, nil
+}
+
+// name returns the column type with the SRID attribute, if the column is qualified.
+func (s spatialSRID) name(typeName string) string {
+	if !s.qualified {
+		return typeName
+	}
+	return fmt.Sprintf("%s SRID %d", typeName, s.srid)
+}
+
+// bounds returns the largest magnitude of each axis for values of this SRID.
+func (s spatialSRID) bounds() spatialPoint {
+	return spatialSRIDBounds[s.srid]
+}
+
+// spatialCoordinate returns a random coordinate whose magnitude is less than the given limit. Coordinates are limited
+// to three decimal places so that their textual form is exact, as a value's WKT is used as its canonical form.
+func spatialCoordinate(limit float64) (float64, error) {
+	v, err := rand.Int32()
+	if err != nil {
+		return 0, errors.Wrap(err)
+	}
+	return float64(v%int32(limit*1000)) / 1000, nil
+}
+
+// roundSpatialCoordinate rounds the coordinate to the same precision as spatialCoordinate.
+func roundSpatialCoordinate(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+// spatialRingString returns the points as a parenthesized list, as used within WKT.
+func spatialRingString(points []spatialPoint) string {
+	pointStrs := make([]string, len(points))
+	for i, point := range points {
+		pointStrs[i] = point.String()
+	}
+	return "(" + strings.Join(pointStrs, ",") + ")"
+}
+
+// spatialWithSRID returns the canonical form of a value with the given WKT and SRID. Values with a nonzero SRID are
+// prefixed by their SRID, so that a value that loses its SRID does not compare as equal.
+func spatialWithSRID(wkt string, srid uint32) string {
+	if srid == 0 {
+		return wkt
+	}
+	return fmt.Sprintf("SRID=%d;%s", srid, wkt)
+}
+
+// spatialMySQLString returns the MySQL form of a value's canonical form. Coordinates are always written with the
+// longitude first, as MySQL otherwise expects geographic SRIDs to have the latitude first.
+func spatialMySQLString(canonical string) string {
+	if strings.HasPrefix(canonical, "SRID=") {
+		if sep := strings.IndexByte(canonical, ';'); sep != -1 {
+			srid, err := strconv.ParseUint(canonical[5:sep], 10, 32)
+			if err == nil {
+				return fmt.Sprintf("ST_GeomFromText(%s, %d, 'axis-order=long-lat')",
+					StringValue(canonical[sep+1:]).String(), srid)
+			}
+		}
+	}
+	return fmt.Sprintf("ST_GeomFromText(%s)", StringValue(canonical).String())
+}
+
+// spatialWKBToWKT converts a spatial value returned from Dolt into its canonical form. Dolt returns spatial values in
+// MySQL's internal format, which is a 4-byte little-endian SRID followed by the WKB of the value.
+func spatialWKBToWKT(data []byte, expectedType uint32) (string, error) {
+	if len(data) < 13 {
+		return "", errors.New(fmt.Sprintf("spatial value is too short: %d bytes", len(data)))
+	}
+	srid := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[0] == 0 {
+		order = binary.BigEndian
+	}
+	data = data[1:]
+	readUint32 := func() (uint32, error) {
+		if len(data) < 4 {
+			return 0, errors.New("spatial value ended unexpectedly")
+		}
+		v := order.Uint32(data)
+		data = data[4:]
+		return v, nil
+	}
+	readPoints := func() ([]spatialPoint, error) {
+		count, err := readUint32()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if uint64(len(data)) < uint64(count)*16 {
+			return nil, errors.New("spatial value ended unexpectedly")
+		}
+		points := make([]spatialPoint, count)
+		for i := range points {
+			points[i].x = math.Float64frombits(order.Uint64(data))
+			points[i].y = math.Float64frombits(order.Uint64(data[8:]))
+			data = data[16:]
+		}
+		return points, nil
+	}
+
+	geometryType, err := readUint32()
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if geometryType != expectedType {
+		return "", errors.New(fmt.Sprintf("expected spatial type %d but found %d", expectedType, geometryType))
+	}
+	switch geometryType {
+	case wkbLineString:
+		points, err := readPoints()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		return spatialWithSRID("LINESTRING"+spatialRingString(points), srid), nil
+	case wkbPolygon:
+		ringCount, err := readUint32()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		rings := make([]string, ringCount)
+		for i := range rings {
+			points, err := readPoints()
+			if err != nil {
+				return "", errors.Wrap(err)
+			}
+			rings[i] = spatialRingString(points)
+		}
+		return spatialWithSRID("POLYGON("+strings.Join(rings, ",")+")", srid), nil
+	default:
+		return "", errors.New(fmt.Sprintf("unsupported spatial type %d", geometryType))
+	}
+}
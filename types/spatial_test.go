@@ -0,0 +1,97 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+func TestSpatialWKBToWKT(t *testing.T) {
+	// SRID, byte order, type, number of points, then each point
+	data := make([]byte, 45)
+	data[4] = 1
+	binary.LittleEndian.PutUint32(data[5:], wkbLineString)
+	binary.LittleEndian.PutUint32(data[9:], 2)
+	for i, coord := range []float64{1.5, -2, 3.125, 4} {
+		binary.LittleEndian.PutUint64(data[13+(i*8):], math.Float64bits(coord))
+	}
+	v, err := LinestringValue{}.Convert(data)
+	require.NoError(t, err)
+	require.Equal(t, "LINESTRING(1.5 -2,3.125 4)", string(v.(LinestringValue).StringValue))
+
+	binary.LittleEndian.PutUint32(data, 4326)
+	v, err = LinestringValue{}.Convert(data)
+	require.NoError(t, err)
+	require.Equal(t, "SRID=4326;LINESTRING(1.5 -2,3.125 4)", string(v.(LinestringValue).StringValue))
+
+	_, err = PolygonValue{}.Convert(data)
+	require.Error(t, err)
+	_, err = LinestringValue{}.Convert(data[:20])
+	require.Error(t, err)
+}
+
+func TestPolygonIsClosed(t *testing.T) {
+	polygon, err := (&Polygon{NumberOfPoints: ranges.NewInt([]int64{3, 10})}).Instance()
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		v, err := polygon.Get()
+		require.NoError(t, err)
+		wkt := string(v.(PolygonValue).StringValue)
+		require.True(t, strings.HasPrefix(wkt, "POLYGON(("))
+		points := strings.Split(strings.TrimSuffix(strings.TrimPrefix(wkt, "POLYGON(("), "))"), ",")
+		require.GreaterOrEqual(t, len(points), 4)
+		require.Equal(t, points[0], points[len(points)-1])
+	}
+}
+
+func TestSpatialSRID(t *testing.T) {
+	require.Equal(t, "ST_GeomFromText('LINESTRING(1 2,3 4)')",
+		LinestringValue{StringValue("LINESTRING(1 2,3 4)")}.MySQLString())
+	require.Equal(t, "ST_GeomFromText('LINESTRING(1 2,3 4)', 4326, 'axis-order=long-lat')",
+		LinestringValue{StringValue("SRID=4326;LINESTRING(1 2,3 4)")}.MySQLString())
+
+	srid := spatialSRID{4326, true}
+	linestring := &LinestringInstance{ranges.NewInt([]int64{2, 10}), srid}
+	polygon := &PolygonInstance{ranges.NewInt([]int64{3, 10}), srid}
+	require.Equal(t, "LINESTRING SRID 4326", linestring.Name(false))
+	require.Equal(t, "POLYGON SRID 4326", polygon.Name(false))
+	for _, instance := range []TypeInstance{linestring, polygon} {
+		for i := 0; i < 100; i++ {
+			v, err := instance.Get()
+			require.NoError(t, err)
+			canonical := v.SQLiteString()
+			require.True(t, strings.HasPrefix(canonical, "'SRID=4326;"))
+			// Every point must be a valid longitude and latitude
+			for _, point := range strings.Split(strings.Trim(canonical[strings.Index(canonical, "("):], "()'"), ",") {
+				coords := strings.Split(strings.Trim(point, "()"), " ")
+				require.Len(t, coords, 2)
+				x, err := strconv.ParseFloat(coords[0], 64)
+				require.NoError(t, err)
+				y, err := strconv.ParseFloat(coords[1], 64)
+				require.NoError(t, err)
+				require.LessOrEqual(t, math.Abs(x), 180.0)
+				require.LessOrEqual(t, math.Abs(y), 90.0)
+			}
+		}
+	}
+}
@@ -26,11 +26,16 @@ import (
 	"github.com/dolthub/fuzzer/utils"
 )
 
+// maxPracticalTextLength is the largest length that is used for a value at the column's maximum length. MEDIUMTEXT and
+// LONGTEXT allow values that are far too large to be practical, so they are capped at this length.
+const maxPracticalTextLength = 1 << 18
+
 // Text represents the TEXT MySQL type.
 type Text struct {
-	Collations   []string
-	Distribution ranges.Int
-	Length       ranges.Int
+	Collations           []string
+	Distribution         ranges.Int
+	Length               ranges.Int
+	MaxLengthProbability float64
 }
 
 var _ Type = (*Text)(nil)
@@ -42,34 +47,37 @@ func (t *Text) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (t *Text) Instance() (TypeInstance, error) {
-	colPos, err := rand.Uint64()
-	if err != nil {
-		return nil, errors.Wrap(err)
-	}
-	colPos %= uint64(len(t.Collations))
-	collation, err := sql.ParseCollation(nil, &t.Collations[colPos], false)
+	collation, err := randomCollation(t.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
 	charLength := utils.MinInt64(t.Length.Upperbound, 65535/collation.CharSet.MaxLength())
-	return &TextInstance{ranges.NewInt([]int64{t.Length.Lowerbound, charLength}), collation}, nil
+	maxLength := 65535 / collation.CharSet.MaxLength()
+	return &TextInstance{ranges.NewInt([]int64{t.Length.Lowerbound, charLength}), collation, maxLength, t.MaxLengthProbability}, nil
 }
 
 // TextInstance is the TypeInstance of Text.
 type TextInstance struct {
-	length    ranges.Int
-	collation sql.Collation
+	length               ranges.Int
+	collation            sql.Collation
+	maxLength            int64
+	maxLengthProbability float64
 }
 
 var _ TypeInstance = (*TextInstance)(nil)
+var _ PrefixableTypeInstance = (*TextInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *TextInstance) Get() (Value, error) {
-	n, err := i.length.RandomValue()
+	n, err := textLength(i.length, i.maxLength, i.maxLengthProbability)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	n, err = boundaryLength(n, i.maxLength)
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -91,6 +99,16 @@ func (i *TextInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *TextInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *TextInstance) RequiresPrefix() bool {
+	return true
+}
+
 // TextValue is the Value type of a TextInstance.
 type TextValue struct {
 	StringValue
@@ -118,15 +136,31 @@ func (v TextValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v TextValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v TextValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v TextValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
+}
+
+// textLength returns the length of a new value for a TEXT-family column. The column's maximum length is returned at the
+// given probability, as values at the maximum length stress storage and chunking boundaries. Otherwise, the length is
+// chosen from the given range.
+func textLength(length ranges.Int, maxLength int64, maxLengthProbability float64) (int64, error) {
+	if maxLengthProbability > 0 {
+		roll, err := rand.Uint64()
+		if err != nil {
+			return 0, errors.Wrap(err)
+		}
+		if float64(roll) < maxLengthProbability*math.MaxUint64 {
+			return maxLength, nil
+		}
+	}
+	return length.RandomValue()
 }
@@ -0,0 +1,91 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/fuzzer/ranges"
+)
+
+func TestTextMaxLength(t *testing.T) {
+	longtext, err := (&Longtext{
+		Collations:           []string{"utf8mb4_0900_ai_ci"},
+		Length:               ranges.NewInt([]int64{1, 10}),
+		MaxLengthProbability: 1,
+	}).Instance()
+	require.NoError(t, err)
+	v, err := longtext.Get()
+	require.NoError(t, err)
+	require.Len(t, string(v.(LongtextValue).StringValue), maxPracticalTextLength)
+
+	text, err := (&Text{
+		Collations:           []string{"utf8mb4_0900_ai_ci"},
+		Length:               ranges.NewInt([]int64{1, 10}),
+		MaxLengthProbability: 1,
+	}).Instance()
+	require.NoError(t, err)
+	v, err = text.Get()
+	require.NoError(t, err)
+	require.Len(t, string(v.(TextValue).StringValue), 65535/4)
+
+	text, err = (&Text{
+		Collations: []string{"utf8mb4_0900_ai_ci"},
+		Length:     ranges.NewInt([]int64{1, 10}),
+	}).Instance()
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		v, err = text.Get()
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(v.(TextValue).StringValue), 10)
+	}
+}
+
+func TestMaxValueBytes(t *testing.T) {
+	SetMaxValueBytes(100)
+	defer SetMaxValueBytes(0)
+
+	longtext, err := (&Longtext{
+		Collations:           []string{"utf8mb4_0900_ai_ci"},
+		Length:               ranges.NewInt([]int64{1, 10}),
+		MaxLengthProbability: 1,
+	}).Instance()
+	require.NoError(t, err)
+	v, err := longtext.Get()
+	require.NoError(t, err)
+	require.Len(t, string(v.(LongtextValue).StringValue), 100)
+
+	longblob, err := (&Longblob{Length: ranges.NewInt([]int64{1000, 4294967295})}).Instance()
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		v, err = longblob.Get()
+		require.NoError(t, err)
+		require.Len(t, string(v.(LongblobValue).StringValue), 100)
+	}
+
+	// Values that are already within the limit are unaffected
+	varchar, err := (&Varchar{
+		Collations: []string{"utf8mb4_0900_ai_ci"},
+		Length:     ranges.NewInt([]int64{1, 10}),
+	}).Instance()
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		v, err = varchar.Get()
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(v.(VarcharValue).StringValue), 10)
+	}
+}
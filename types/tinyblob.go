@@ -47,6 +47,7 @@ type TinyblobInstance struct {
 }
 
 var _ TypeInstance = (*TinyblobInstance)(nil)
+var _ PrefixableTypeInstance = (*TinyblobInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *TinyblobInstance) Get() (Value, error) {
@@ -54,7 +55,11 @@ func (i *TinyblobInstance) Get() (Value, error) {
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	n, err = boundaryLength(n, 255)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -79,6 +84,16 @@ func (i *TinyblobInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), 256)
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *TinyblobInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *TinyblobInstance) RequiresPrefix() bool {
+	return true
+}
+
 // TinyblobValue is the Value type of a TinyblobInstance.
 type TinyblobValue struct {
 	StringValue
@@ -106,15 +121,15 @@ func (v TinyblobValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v TinyblobValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v TinyblobValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v TinyblobValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
@@ -29,6 +29,8 @@ import (
 // Tinyint represents the TINYINT MySQL type.
 type Tinyint struct {
 	Distribution ranges.Int
+	// DisplayWidthProbability is the probability that a column declares a display width.
+	DisplayWidthProbability float64
 }
 
 var _ Type = (*Tinyint)(nil)
@@ -40,11 +42,17 @@ func (t *Tinyint) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (t *Tinyint) Instance() (TypeInstance, error) {
-	return &TinyintInstance{}, nil
+	attributes, err := newIntegerAttributes(t.DisplayWidthProbability, false)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &TinyintInstance{attributes}, nil
 }
 
 // TinyintInstance is the TypeInstance of Tinyint.
-type TinyintInstance struct{}
+type TinyintInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*TinyintInstance)(nil)
 
@@ -61,7 +69,7 @@ func (i *TinyintInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *TinyintInstance) Name(sqlite bool) string {
-	return "TINYINT"
+	return i.attributeName("TINYINT", false, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
@@ -29,6 +29,8 @@ import (
 // TinyintUnsigned represents the TINYINT UNSIGNED MySQL type.
 type TinyintUnsigned struct {
 	Distribution ranges.Int
+	// ZerofillProbability is the probability that a column declares a display width and ZEROFILL.
+	ZerofillProbability float64
 }
 
 var _ Type = (*TinyintUnsigned)(nil)
@@ -40,11 +42,17 @@ func (t *TinyintUnsigned) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (t *TinyintUnsigned) Instance() (TypeInstance, error) {
-	return &TinyintUnsignedInstance{}, nil
+	attributes, err := newIntegerAttributes(t.ZerofillProbability, true)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &TinyintUnsignedInstance{attributes}, nil
 }
 
 // TinyintUnsignedInstance is the TypeInstance of TinyintUnsigned.
-type TinyintUnsignedInstance struct{}
+type TinyintUnsignedInstance struct {
+	integerAttributes
+}
 
 var _ TypeInstance = (*TinyintUnsignedInstance)(nil)
 
@@ -61,7 +69,7 @@ func (i *TinyintUnsignedInstance) TypeValue() Value {
 
 // Name implements the TypeInstance interface.
 func (i *TinyintUnsignedInstance) Name(sqlite bool) string {
-	return "TINYINT UNSIGNED"
+	return i.attributeName("TINYINT", true, sqlite)
 }
 
 // MaxValueCount implements the TypeInstance interface.
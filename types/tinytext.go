@@ -28,9 +28,10 @@ import (
 
 // Tinytext represents the TINYTEXT MySQL type.
 type Tinytext struct {
-	Collations   []string
-	Distribution ranges.Int
-	Length       ranges.Int
+	Collations           []string
+	Distribution         ranges.Int
+	Length               ranges.Int
+	MaxLengthProbability float64
 }
 
 var _ Type = (*Tinytext)(nil)
@@ -42,34 +43,37 @@ func (t *Tinytext) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (t *Tinytext) Instance() (TypeInstance, error) {
-	colPos, err := rand.Uint64()
-	if err != nil {
-		return nil, errors.Wrap(err)
-	}
-	colPos %= uint64(len(t.Collations))
-	collation, err := sql.ParseCollation(nil, &t.Collations[colPos], false)
+	collation, err := randomCollation(t.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
 	charLength := utils.MinInt64(t.Length.Upperbound, 255/collation.CharSet.MaxLength())
-	return &TinytextInstance{ranges.NewInt([]int64{t.Length.Lowerbound, charLength}), collation}, nil
+	maxLength := 255 / collation.CharSet.MaxLength()
+	return &TinytextInstance{ranges.NewInt([]int64{t.Length.Lowerbound, charLength}), collation, maxLength, t.MaxLengthProbability}, nil
 }
 
 // TinytextInstance is the TypeInstance of Tinytext.
 type TinytextInstance struct {
-	length    ranges.Int
-	collation sql.Collation
+	length               ranges.Int
+	collation            sql.Collation
+	maxLength            int64
+	maxLengthProbability float64
 }
 
 var _ TypeInstance = (*TinytextInstance)(nil)
+var _ PrefixableTypeInstance = (*TinytextInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *TinytextInstance) Get() (Value, error) {
-	n, err := i.length.RandomValue()
+	n, err := textLength(i.length, i.maxLength, i.maxLengthProbability)
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	n, err = boundaryLength(n, i.maxLength)
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -91,6 +95,16 @@ func (i *TinytextInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *TinytextInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *TinytextInstance) RequiresPrefix() bool {
+	return true
+}
+
 // TinytextValue is the Value type of a TinytextInstance.
 type TinytextValue struct {
 	StringValue
@@ -118,15 +132,15 @@ func (v TinytextValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v TinytextValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v TinytextValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v TinytextValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
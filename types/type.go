@@ -14,10 +14,33 @@
 
 package types
 
-import "github.com/dolthub/fuzzer/ranges"
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/dolthub/fuzzer/ranges"
+)
 
 type Type interface {
 	ranges.Distributable
 	// Instance returns an instance of this type, which can generate random values.
 	Instance() (TypeInstance, error)
 }
+
+// ConfigName returns the name that the config file uses for the given type, such as "BIGINT_UNSIGNED" for
+// *BigintUnsigned. This is derived from the name of the type itself, so every Type is named the same way.
+func ConfigName(t Type) string {
+	goType := reflect.TypeOf(t)
+	for goType.Kind() == reflect.Ptr {
+		goType = goType.Elem()
+	}
+	sb := strings.Builder{}
+	for i, r := range goType.Name() {
+		if i > 0 && unicode.IsUpper(r) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
@@ -27,3 +27,35 @@ type TypeInstance interface {
 	// approximation, as a float64 does not have enough resolution to represent every value exactly.
 	MaxValueCount() float64
 }
+
+// PrefixableTypeInstance is a TypeInstance whose values may be indexed using only a prefix of each value, such as the
+// string and blob types.
+type PrefixableTypeInstance interface {
+	TypeInstance
+	// MaxPrefixLength returns the largest prefix length that is meaningful for this instance, which is the maximum
+	// number of characters (or bytes for binary types) that a value may contain.
+	MaxPrefixLength() int64
+	// RequiresPrefix returns whether an index on this instance must declare a prefix length, as is the case for the
+	// TEXT and BLOB families.
+	RequiresPrefix() bool
+}
+
+// maxValueBytes is the largest size of a value returned from the Get function of a variable-length string or binary
+// type. A value of 0 does not limit the size.
+var maxValueBytes int64
+
+// SetMaxValueBytes sets the largest size, in bytes, of every variable-length string and binary value returned from Get,
+// regardless of the maximum allowed by the type. A value of 0 removes the limit. This is not safe for concurrent use,
+// and should only be called before any values are generated.
+func SetMaxValueBytes(n int64) {
+	maxValueBytes = n
+}
+
+// valueLength returns the given length, limited to the size set by SetMaxValueBytes. Every character of a generated
+// string is a single byte, so the length is also the size of the value.
+func valueLength(n int64) int {
+	if maxValueBytes > 0 && n > maxValueBytes {
+		return int(maxValueBytes)
+	}
+	return int(n)
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/hex"
+	"math"
+
+	"github.com/dolthub/fuzzer/errors"
+	"github.com/dolthub/fuzzer/rand"
+)
+
+// uuidMaxValueCount is the number of distinct version 4 UUIDs, as 6 of their 128 bits are fixed.
+var uuidMaxValueCount = math.Pow(2, 122)
+
+// isUUIDColumn returns whether a new column should hold UUIDs, which is true at the given probability.
+func isUUIDColumn(uuidProbability float64) (bool, error) {
+	if uuidProbability <= 0 {
+		return false, nil
+	}
+	roll, err := rand.Uint64()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	return float64(roll) < uuidProbability*math.MaxUint64, nil
+}
+
+// uuidString returns the canonical 36 character form of the given UUID, using lowercase hexadecimal digits.
+func uuidString(uuid []byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf)
+}
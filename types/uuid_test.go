@@ -0,0 +1,48 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUUIDColumns(t *testing.T) {
+	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	charInstance := &CharInstance{36, sql.Collation_Default, true, nil}
+	binaryInstance := &BinaryInstance{16, true}
+	for i := 0; i < 100; i++ {
+		charVal, err := charInstance.Get()
+		require.NoError(t, err)
+		require.Regexp(t, uuidRegex, string(charVal.(CharValue).StringValue))
+
+		binaryVal, err := binaryInstance.Get()
+		require.NoError(t, err)
+		uuid := []byte(binaryVal.(BinaryValue).StringValue)
+		require.Len(t, uuid, 16)
+		require.Regexp(t, uuidRegex, uuidString(uuid))
+	}
+
+	uuid := []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x42, 0xd3, 0xa4, 0x56, 0x00, 0x0a, 0x27, 0x5c, 0x40, 0xff}
+	require.Equal(t, "123e4567-e89b-42d3-a456-000a275c40ff", uuidString(uuid))
+	binaryVal := BinaryValue{StringValue(uuid)}
+	require.Equal(t, "0x123E4567E89B42D3A456000A275C40FF", binaryVal.MySQLString())
+	require.Equal(t, "X'123E4567E89B42D3A456000A275C40FF'", binaryVal.SQLiteString())
+	// Printable values are still written as strings
+	require.Equal(t, "'abc'", BinaryValue{StringValue("abc")}.MySQLString())
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"unsafe"
 
 	"github.com/dolthub/fuzzer/errors"
@@ -56,6 +57,23 @@ type Value interface {
 	CSVString() string
 }
 
+// ValueListValue is a Value that may be written differently within the value list of an INSERT or REPLACE, where the
+// value is always stored in a column of its own type. Everywhere else, such as in a WHERE clause, the value is written
+// using MySQLString.
+type ValueListValue interface {
+	Value
+	// ValueListString returns the Value as a string for a MySQL INSERT or REPLACE value list.
+	ValueListString() string
+}
+
+// ValueListString returns the value as it is written within a MySQL INSERT or REPLACE value list.
+func ValueListString(v Value) string {
+	if listVal, ok := v.(ValueListValue); ok {
+		return listVal.ValueListString()
+	}
+	return v.MySQLString()
+}
+
 // NilValue is the Value type of a nil. This is a full Value rather than a ValuePrimitive as it should not be built on
 // top of.
 type NilValue struct{}
@@ -95,7 +113,8 @@ func (v NilValue) SQLiteString() string {
 	return v.String()
 }
 
-// CSVString implements the interface Value.
+// CSVString implements the interface Value. NULL is the only value written as an empty field, as all string values are
+// quoted, which allows an empty string to be distinguished from NULL.
 func (v NilValue) CSVString() string {
 	return ""
 }
@@ -522,6 +541,10 @@ func (v Float32Value) Compare(other ValuePrimitive) int {
 
 // ToBytes implements the interface ValuePrimitive.
 func (v Float32Value) ToBytes() []byte {
+	// Negative zero is equal to positive zero, so both must have the same bytes
+	if v == 0 {
+		v = 0
+	}
 	u := *(*uint32)(unsafe.Pointer(&v))
 	return []byte{byte(u), byte(u >> 8), byte(u >> 16), byte(u >> 24)}
 }
@@ -565,6 +588,10 @@ func (v Float64Value) Compare(other ValuePrimitive) int {
 
 // ToBytes implements the interface ValuePrimitive.
 func (v Float64Value) ToBytes() []byte {
+	// Negative zero is equal to positive zero, so both must have the same bytes
+	if v == 0 {
+		v = 0
+	}
 	u := *(*uint64)(unsafe.Pointer(&v))
 	return []byte{byte(u), byte(u >> 8), byte(u >> 16), byte(u >> 24), byte(u >> 32), byte(u >> 40), byte(u >> 48), byte(u >> 56)}
 }
@@ -593,6 +620,47 @@ func (v StringValue) StringTerminating(char byte) string {
 	return *(*string)(unsafe.Pointer(&out))
 }
 
+// stringMySQLEscaper escapes the characters that may not appear directly within a single-quoted MySQL string. Newlines
+// and tabs are escaped so that every statement stays on a single line.
+var stringMySQLEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\t", `\t`)
+
+// stringSQLiteEscaper escapes the characters that may not appear directly within a single-quoted SQLite string. SQLite
+// does not support backslash escapes, so only the quote needs to be escaped.
+var stringSQLiteEscaper = strings.NewReplacer(`'`, `''`)
+
+// stringCSVEscaper escapes the characters that may not appear directly within a double-quoted CSV field. Following
+// RFC 4180, only the quote is escaped by doubling it, while commas, newlines, and all other characters are written as-is
+// within the quotes. A row may therefore span several lines, so CSV files must be read using ScanCSVRecords from the
+// run package, rather than line by line.
+var stringCSVEscaper = strings.NewReplacer(`"`, `""`)
+
+// stringEscapedChars are all of the characters that are escaped by at least one of the escapers.
+const stringEscapedChars = "'\"\\\n\t"
+
+// MySQLQuoted returns the string as a single-quoted MySQL string literal, escaping any characters as needed.
+func (v StringValue) MySQLQuoted() string {
+	if !strings.ContainsAny(string(v), stringEscapedChars) {
+		return v.String()
+	}
+	return "'" + stringMySQLEscaper.Replace(string(v)) + "'"
+}
+
+// SQLiteQuoted returns the string as a single-quoted SQLite string literal, escaping any characters as needed.
+func (v StringValue) SQLiteQuoted() string {
+	if !strings.ContainsAny(string(v), stringEscapedChars) {
+		return v.String()
+	}
+	return "'" + stringSQLiteEscaper.Replace(string(v)) + "'"
+}
+
+// CSVQuoted returns the string as a double-quoted CSV field, escaping any characters as needed.
+func (v StringValue) CSVQuoted() string {
+	if !strings.Contains(string(v), `"`) {
+		return v.StringTerminating(34)
+	}
+	return `"` + stringCSVEscaper.Replace(string(v)) + `"`
+}
+
 // Primitive implements the interface ValuePrimitive.
 func (v StringValue) Primitive() ValuePrimitive {
 	return v
@@ -0,0 +1,51 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringValueQuoted(t *testing.T) {
+	plain := StringValue("abc 123")
+	require.Equal(t, "'abc 123'", plain.MySQLQuoted())
+	require.Equal(t, "'abc 123'", plain.SQLiteQuoted())
+	require.Equal(t, `"abc 123"`, plain.CSVQuoted())
+
+	escaped := StringValue("it's \"a\"\\\n\tb`")
+	require.Equal(t, `'it\'s "a"\\\n\tb`+"`'", escaped.MySQLQuoted())
+	require.Equal(t, "'it''s \"a\"\\\n\tb`'", escaped.SQLiteQuoted())
+	require.Equal(t, "\"it's \"\"a\"\"\\\n\tb`\"", escaped.CSVQuoted())
+	require.Equal(t, escaped.MySQLQuoted(), VarcharValue{escaped}.MySQLString())
+}
+
+func TestEmptyStringIsNotNull(t *testing.T) {
+	// An empty string must be distinguishable from NULL in every representation, including the CSV files, where NULL is
+	// the only value written as an empty field
+	empty := StringValue("")
+	for _, val := range []Value{
+		CharValue{empty}, VarcharValue{empty}, TinytextValue{empty}, TextValue{empty}, MediumtextValue{empty},
+		LongtextValue{empty}, BinaryValue{empty}, VarbinaryValue{empty}, TinyblobValue{empty}, BlobValue{empty},
+		MediumblobValue{empty}, LongblobValue{empty},
+	} {
+		require.Equal(t, `""`, val.CSVString(), val.Name())
+		require.NotEqual(t, NilValue{}.CSVString(), val.CSVString(), val.Name())
+		require.NotEqual(t, NilValue{}.MySQLString(), val.MySQLString(), val.Name())
+		require.NotEqual(t, NilValue{}.SQLiteString(), val.SQLiteString(), val.Name())
+		require.NotEqual(t, 0, NilValue{}.Compare(val), val.Name())
+	}
+}
@@ -47,6 +47,7 @@ type VarbinaryInstance struct {
 }
 
 var _ TypeInstance = (*VarbinaryInstance)(nil)
+var _ PrefixableTypeInstance = (*VarbinaryInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *VarbinaryInstance) Get() (Value, error) {
@@ -54,7 +55,7 @@ func (i *VarbinaryInstance) Get() (Value, error) {
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	v, err := rand.String(int(n))
+	v, err := rand.String(valueLength(n))
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
@@ -79,6 +80,16 @@ func (i *VarbinaryInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *VarbinaryInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *VarbinaryInstance) RequiresPrefix() bool {
+	return false
+}
+
 // VarbinaryValue is the Value type of a VarbinaryInstance.
 type VarbinaryValue struct {
 	StringValue
@@ -106,15 +117,15 @@ func (v VarbinaryValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v VarbinaryValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v VarbinaryValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v VarbinaryValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
@@ -42,38 +42,38 @@ func (v *Varchar) GetOccurrenceRate() (int64, error) {
 
 // Instance implements the Type interface.
 func (v *Varchar) Instance() (TypeInstance, error) {
-	colPos, err := rand.Uint64()
-	if err != nil {
-		return nil, errors.Wrap(err)
-	}
-	colPos %= uint64(len(v.Collations))
-	collation, err := sql.ParseCollation(nil, &v.Collations[colPos], false)
+	collation, err := randomCollation(v.Collations)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
 	charLength := utils.MinInt64(v.Length.Upperbound, 65535/collation.CharSet.MaxLength())
-	return &VarcharInstance{ranges.NewInt([]int64{v.Length.Lowerbound, charLength}), collation}, nil
+	return &VarcharInstance{ranges.NewInt([]int64{v.Length.Lowerbound, charLength}), collation, newCollationVariants(collation)}, nil
 }
 
-// VarcharInstance is the TypeInstance of Varchar.
+// VarcharInstance is the TypeInstance of Varchar. Values may be variants of recent values, which is controlled by
+// SetModernCollations.
 type VarcharInstance struct {
 	length    ranges.Int
 	collation sql.Collation
+	variants  *collationVariants
 }
 
 var _ TypeInstance = (*VarcharInstance)(nil)
+var _ PrefixableTypeInstance = (*VarcharInstance)(nil)
 
 // Get implements the TypeInstance interface.
 func (i *VarcharInstance) Get() (Value, error) {
-	n, err := i.length.RandomValue()
-	if err != nil {
-		return NilValue{}, errors.Wrap(err)
-	}
-	v, err := rand.String(int(n))
+	v, err := i.variants.get(func() (string, error) {
+		n, err := i.length.RandomValue()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		return rand.String(valueLength(n))
+	})
 	if err != nil {
 		return NilValue{}, errors.Wrap(err)
 	}
-	return VarcharValue{StringValue(v)}, err
+	return VarcharValue{StringValue(v)}, nil
 }
 
 // TypeValue implements the TypeInstance interface.
@@ -94,6 +94,22 @@ func (i *VarcharInstance) MaxValueCount() float64 {
 	return math.Pow(float64(rand.StringCharSize()), float64(i.length.Upperbound))
 }
 
+// MaxPrefixLength implements the PrefixableTypeInstance interface.
+func (i *VarcharInstance) MaxPrefixLength() int64 {
+	return i.length.Upperbound
+}
+
+// RequiresPrefix implements the PrefixableTypeInstance interface.
+func (i *VarcharInstance) RequiresPrefix() bool {
+	return false
+}
+
+// ComparesExactly returns whether the column's collation compares values by their exact bytes, so that values are never
+// equal while ignoring case, accents, or trailing spaces.
+func (i *VarcharInstance) ComparesExactly() bool {
+	return isExactCollation(i.collation)
+}
+
 // VarcharValue is the Value type of a VarcharInstance.
 type VarcharValue struct {
 	StringValue
@@ -121,15 +137,15 @@ func (v VarcharValue) Name() string {
 
 // MySQLString implements the Value interface.
 func (v VarcharValue) MySQLString() string {
-	return v.String()
+	return v.MySQLQuoted()
 }
 
 // SQLiteString implements the Value interface.
 func (v VarcharValue) SQLiteString() string {
-	return v.String()
+	return v.SQLiteQuoted()
 }
 
 // CSVString implements the interface Value.
 func (v VarcharValue) CSVString() string {
-	return v.StringTerminating(34)
+	return v.CSVQuoted()
 }
@@ -16,7 +16,6 @@ package types
 
 import (
 	"fmt"
-	"math"
 	"strconv"
 	"unsafe"
 
@@ -26,6 +25,14 @@ import (
 	"github.com/dolthub/fuzzer/ranges"
 )
 
+// yearEdgeValueRarity is the inverse of the probability that a YEAR value is chosen from its edge values, rather than
+// being randomly generated.
+const yearEdgeValueRarity = 16
+
+// yearEdgeValues are the bounds of the YEAR type, along with the years at the edges of the two-digit mapping. Zero is
+// the special year 0000.
+var yearEdgeValues = []Uint16Value{0, 1901, 2155, 1970, 1999, 2000, 2001, 2069}
+
 // Year represents the YEAR MySQL type.
 type Year struct {
 	Distribution ranges.Int
@@ -48,10 +55,20 @@ type YearInstance struct{}
 
 var _ TypeInstance = (*YearInstance)(nil)
 
-// Get implements the TypeInstance interface.
+// Get implements the TypeInstance interface. Years are either 0000, or between 1901 and 2155 (inclusive).
 func (i *YearInstance) Get() (Value, error) {
-	v, err := rand.Uint8()
-	return YearValue{Uint16Value(v%254) + 1901}, err
+	roll, err := rand.Uint64()
+	if err != nil {
+		return NilValue{}, errors.Wrap(err)
+	}
+	if roll%yearEdgeValueRarity == 0 {
+		return YearValue{yearEdgeValues[(roll/yearEdgeValueRarity)%uint64(len(yearEdgeValues))]}, nil
+	}
+	v := (roll / yearEdgeValueRarity) % 256
+	if v == 0 {
+		return YearValue{0}, nil
+	}
+	return YearValue{Uint16Value(v) + 1900}, nil
 }
 
 // TypeValue implements the TypeInstance interface.
@@ -66,7 +83,7 @@ func (i *YearInstance) Name(sqlite bool) string {
 
 // MaxValueCount implements the TypeInstance interface.
 func (i *YearInstance) MaxValueCount() float64 {
-	return float64(math.MaxUint8)
+	return 256
 }
 
 // YearValue is the Value type of a YearInstance.
@@ -122,11 +139,35 @@ func (v YearValue) Name() string {
 	return "YEAR"
 }
 
-// MySQLString implements the Value interface.
+var _ ValueListValue = YearValue{}
+
+// MySQLString implements the Value interface. The four-digit year is always written, as two-digit years are only mapped
+// to four-digit years when they are stored in a YEAR column, and not when they are compared against one.
 func (v YearValue) MySQLString() string {
 	return v.String()
 }
 
+// ValueListString implements the ValueListValue interface. Years between 1970 and 2069 are sometimes written using two
+// digits, as either a number or a string, which MySQL maps to the four-digit year. As the number 0 means the year 0000,
+// the year 2000 is always written as the string '00'. The form depends only on the year, so that a value is always
+// written the same way.
+func (v YearValue) ValueListString() string {
+	year := uint16(v.Uint16Value)
+	if year < 1970 || year > 2069 {
+		return v.String()
+	} else if year == 2000 {
+		return "'00'"
+	}
+	switch year % 3 {
+	case 0:
+		return strconv.Itoa(int(year % 100))
+	case 1:
+		return fmt.Sprintf("'%02d'", year%100)
+	default:
+		return v.String()
+	}
+}
+
 // SQLiteString implements the Value interface.
 func (v YearValue) SQLiteString() string {
 	return v.String()
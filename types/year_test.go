@@ -0,0 +1,82 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mysqlYear returns the year that MySQL stores for the given YEAR literal. Numbers from 1 to 69 map to 2001 through
+// 2069, and 70 to 99 map to 1970 through 1999, while 0 is the year 0000. Strings follow the same rules, except that
+// both '0' and '00' map to 2000.
+func mysqlYear(t *testing.T, literal string) uint16 {
+	quoted := strings.HasPrefix(literal, "'")
+	year, err := strconv.ParseUint(strings.Trim(literal, "'"), 10, 16)
+	require.NoError(t, err)
+	switch {
+	case year == 0 && !quoted:
+		return 0
+	case year < 70:
+		return uint16(year) + 2000
+	case year < 100:
+		return uint16(year) + 1900
+	default:
+		return uint16(year)
+	}
+}
+
+func TestYearTwoDigitMapping(t *testing.T) {
+	require.Equal(t, uint16(2000), mysqlYear(t, "'00'"))
+	require.Equal(t, uint16(0), mysqlYear(t, "0"))
+	require.Equal(t, uint16(2069), mysqlYear(t, "69"))
+	require.Equal(t, uint16(1970), mysqlYear(t, "'70'"))
+	require.Equal(t, uint16(1999), mysqlYear(t, "99"))
+
+	require.Equal(t, "'00'", ValueListString(YearValue{2000}))
+	require.Equal(t, "0", ValueListString(YearValue{0}))
+	require.Equal(t, "1901", ValueListString(YearValue{1901}))
+	require.Equal(t, "2155", ValueListString(YearValue{2155}))
+	twoDigits := 0
+	for year := 1901; year <= 2155; year++ {
+		val := YearValue{Uint16Value(year)}
+		literal := ValueListString(val)
+		require.Equal(t, uint16(year), mysqlYear(t, literal), literal)
+		// Outside of a value list, such as in a WHERE clause, the four-digit year is always written
+		require.Equal(t, strconv.Itoa(year), val.MySQLString())
+		if len(strings.Trim(literal, "'")) <= 2 {
+			twoDigits++
+		}
+		// The internal data always stores the four-digit year
+		require.Equal(t, strconv.Itoa(year), val.SQLiteString())
+	}
+	require.Greater(t, twoDigits, 50)
+}
+
+func TestYearBounds(t *testing.T) {
+	instance := &YearInstance{}
+	seen := make(map[uint16]bool)
+	for i := 0; i < 10000; i++ {
+		val, err := instance.Get()
+		require.NoError(t, err)
+		year := uint16(val.(YearValue).Uint16Value)
+		require.True(t, year == 0 || (year >= 1901 && year <= 2155), "year %d", year)
+		seen[year] = true
+	}
+	require.True(t, seen[0] && seen[1901] && seen[2155])
+}
@@ -131,11 +131,11 @@ func (ap *ArgParser) matchModalOptions(arg string) (matches []*Option, rest stri
 	for kontinue {
 		kontinue = false
 
-		// stop if we see a value option
+		// stop if we see a value option, unless a longer flag also matches (such as "config-print" over "config")
 		for _, vo := range ap.sortedValueOptions() {
 			lv := len(vo)
 			isValOpt := len(rest) >= lv && rest[:lv] == vo
-			if isValOpt {
+			if isValOpt && !hasLongerPrefix(candidateFlagNames, rest, lv) {
 				return matches, rest
 			}
 		}
@@ -164,6 +164,16 @@ func (ap *ArgParser) matchModalOptions(arg string) (matches []*Option, rest stri
 	return matches, rest
 }
 
+// hasLongerPrefix returns whether any of the names is a prefix of the argument, while being longer than the given length.
+func hasLongerPrefix(names []string, arg string, length int) bool {
+	for _, name := range names {
+		if len(name) > length && len(arg) >= len(name) && arg[:len(name)] == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (ap *ArgParser) sortedValueOptions() []string {
 	vos := make([]string, 0, len(ap.Supported))
 	for s, opt := range ap.NameOrAbbrevToOpt {
@@ -20,6 +20,8 @@ package os
 import (
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 )
 
 // DisassociateExec allows for graceful closing of the generated process later on, depending on the operating system.
@@ -30,3 +32,27 @@ func DisassociateExec(cmd *exec.Cmd) {}
 func CloseProcess(process *os.Process) error {
 	return process.Signal(os.Interrupt)
 }
+
+// FindProcesses returns the IDs of all running processes whose arguments, including the program itself, satisfy the
+// given function. Arguments are split on whitespace, so an argument that contains a space is split as well.
+func FindProcesses(match func(args []string) bool) ([]int, error) {
+	out, err := exec.Command("ps", "-axo", "pid=,args=").Output()
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+		if match(fields[1:]) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}